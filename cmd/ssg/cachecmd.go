@@ -1,8 +1,8 @@
 package main
 
 // `ssg cache stats|clean|gc` (GO-091): one CLI over every SSG cache namespace —
-// images, external sources and AI — resolved from the project config the same
-// way a build would resolve them.
+// images, external sources, AI and the incremental dependency graphs —
+// resolved from the project config the same way a build would resolve them.
 
 import (
 	"fmt"
@@ -43,6 +43,9 @@ func cacheNamespaces(cfg *config.Config) []cacheNamespace {
 		{"images", cache.Dir("", "images")},
 		{"external-sources", extDir},
 		{"ai", aiDir},
+		{"deps", cache.Dir("", "deps")},
+		{"versions", cache.Dir("", "versions")},
+		{"git", cache.Dir("", "git")},
 	}
 	if _, err := os.Stat(aiLegacyCacheDir); err == nil {
 		ns = append(ns, cacheNamespace{"ai (legacy)", aiLegacyCacheDir})
@@ -177,6 +180,19 @@ func runCacheGC(namespaces []cacheNamespace, args []string) int {
 			// Image GC needs the build's reference manifest — only a build knows
 			// which variants the site still uses.
 			fmt.Printf("ℹ️  images: run a build with --images-gc (or images_gc: true); GC needs the build manifest\n")
+		case "deps":
+			// One graph per output directory, rewritten by every incremental
+			// build; there is nothing to expire, and a dropped graph only costs
+			// the next build a full render.
+			fmt.Printf("ℹ️  deps: one graph per output directory; use `ssg cache clean --namespace=deps` to force a full render\n")
+		case "versions":
+			// One built copy per `versions:` entry, replaced whenever its ref,
+			// the theme or the config moves; nothing accumulates.
+			fmt.Printf("ℹ️  versions: one copy per configured version; use `ssg cache clean --namespace=versions` to rebuild them\n")
+		case "git":
+			// One shallow repository and checkout per git content source URL,
+			// refetched on every online build.
+			fmt.Printf("ℹ️  git: one checkout per git content source; use `ssg cache clean --namespace=git` to clone them afresh\n")
 		default: // ai + legacy: content-addressed, no expiry metadata
 			fmt.Printf("ℹ️  %s: entries carry no expiry; use `ssg cache clean --namespace=ai` to drop them\n", ns.name)
 		}
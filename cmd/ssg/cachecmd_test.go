@@ -12,7 +12,9 @@ func TestCacheNamespaces(t *testing.T) {
 	t.Chdir(t.TempDir())
 	// Defaults, no config, no legacy dir.
 	ns := cacheNamespaces(nil)
-	if len(ns) != 3 || ns[0].name != "images" || ns[2].dir != filepath.Join(".ssg-cache", "ai") {
+	if len(ns) != 6 || ns[0].name != "images" || ns[2].dir != filepath.Join(".ssg-cache", "ai") ||
+		ns[3].dir != filepath.Join(".ssg-cache", "deps") || ns[4].dir != filepath.Join(".ssg-cache", "versions") ||
+		ns[5].dir != filepath.Join(".ssg-cache", "git") {
 		t.Fatalf("default namespaces = %+v", ns)
 	}
 	// Config overrides win.
@@ -28,7 +30,7 @@ func TestCacheNamespaces(t *testing.T) {
 		t.Fatal(err)
 	}
 	ns = cacheNamespaces(nil)
-	if len(ns) != 4 || ns[3].name != "ai (legacy)" {
+	if len(ns) != 7 || ns[6].name != "ai (legacy)" {
 		t.Fatalf("legacy root not surfaced: %+v", ns)
 	}
 }
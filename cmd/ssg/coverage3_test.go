@@ -35,8 +35,8 @@ func TestParseCheckFlagBareForms(t *testing.T) {
 	}
 }
 
-// TestNotifyHelpers: with a hub running, a successful rebuild broadcasts
-// "reload" and a failed one "builderror" — the browser-side contract of GO-090.
+// TestNotifyHelpers: with a hub running, notifyReload broadcasts "reload" and a
+// failed build "error" — the browser-side contract of GO-090.
 func TestNotifyHelpers(t *testing.T) {
 	old := currentReloadHub()
 	setReloadHub(newLiveReloadHub())
@@ -47,8 +47,8 @@ func TestNotifyHelpers(t *testing.T) {
 		t.Fatalf("reload event = %q", got)
 	}
 	notifyBuildError("boom\r\nline2")
-	if got := <-ch; !strings.Contains(got, "event: builderror") || !strings.Contains(got, "data: line2") {
-		t.Fatalf("builderror event = %q", got)
+	if got := <-ch; !strings.Contains(got, "event: error") || !strings.Contains(got, `"message":"boom\r\nline2"`) {
+		t.Fatalf("error event = %q", got)
 	}
 	currentReloadHub().unsubscribe(ch)
 	currentReloadHub().unsubscribe(ch) // double unsubscribe must be a safe no-op
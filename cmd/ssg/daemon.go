@@ -17,11 +17,12 @@ import (
 	"time"
 
 	"github.com/spagu/ssg/internal/daemon"
+	"github.com/spagu/ssg/internal/watch"
 )
 
-// daemonPollInterval is how often the projects file is checked for edits. A
-// second is imperceptible to a person saving a file and free at this scale —
-// one stat of one file.
+// daemonPollInterval is how often exited projects are looked for. Edits to the
+// projects file arrive as events from the shared watcher (internal/watch), and
+// each project's own `ssg --watch` uses the same watcher for its site.
 const daemonPollInterval = time.Second
 
 // daemonSignals is a seam: tests drive reload and shutdown without raising real
@@ -80,6 +81,8 @@ func superviseProjects(cfg *daemon.Config, flags daemonFlags) int {
 
 	reload, quit := daemonSignals()
 	watcher := newProjectsWatcher(flags.config)
+	events := watch.New([]string{flags.config}, watch.Options{})
+	defer events.Close()
 	ticker := time.NewTicker(daemonPollInterval)
 	defer ticker.Stop()
 
@@ -92,10 +95,11 @@ func superviseProjects(cfg *daemon.Config, flags daemonFlags) int {
 			return 0
 		case <-reload:
 			reloadProjects(sup, flags, "SIGHUP")
-		case <-ticker.C:
+		case <-events.Changes():
 			if watcher.changed() {
 				reloadProjects(sup, flags, flags.config+" changed")
 			}
+		case <-ticker.C:
 			// A project that died on its own comes back: a build that gave up
 			// on a bad config must not silently leave a site unserved.
 			if name := sup.Exited(); name != "" {
@@ -137,8 +141,9 @@ func reportDaemonProjects(cfg *daemon.Config) {
 	}
 }
 
-// projectsWatcher notices edits to the projects file by size and mtime, the
-// same way the build's own watch loop notices content.
+// projectsWatcher decides whether a watcher event on the projects file is an
+// edit, by size and mtime: a rename-save reports the file once it is gone and
+// again once it is back.
 type projectsWatcher struct {
 	path string
 	sig  string
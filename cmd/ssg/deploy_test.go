@@ -1,6 +1,7 @@
 package main
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/spagu/ssg/internal/config"
@@ -19,6 +20,16 @@ func TestRunDeployUnknownProvider(t *testing.T) {
 	}
 }
 
+// A preview build carries unpublished content; deploying it is refused even
+// for a provider that exists.
+func TestRunDeployRefusesDrafts(t *testing.T) {
+	cfg := &config.Config{Deploy: "cloudflare", Drafts: true, OutputDir: t.TempDir(), Quiet: true}
+	err := runDeploy(cfg)
+	if err == nil || !strings.Contains(err.Error(), "--drafts") {
+		t.Errorf("runDeploy in preview mode = %v, want a refusal naming --drafts", err)
+	}
+}
+
 func TestDeployFlagParsing(t *testing.T) {
 	cfg := &config.Config{}
 	for _, arg := range []string{
@@ -20,6 +20,8 @@ func TestValidateTemplateEngine(t *testing.T) {
 		"mustache":   false,
 		"handlebars": false,
 		"hbs":        false, // handlebars alias
+		"liquid":     false,
+		"jekyll":     false, // liquid alias
 		"twig":       true,  // entirely unknown
 	}
 	for eng, wantErr := range wantErrByEngine {
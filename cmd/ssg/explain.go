@@ -0,0 +1,147 @@
+package main
+
+// `ssg explain <url-or-source-file>` answers "why did this page render like
+// that": it builds the site into a scratch directory with the generator's
+// explain recorder on (internal/generator/explain.go) and prints, for the one
+// output asked about, its source, permalink and output files, every template
+// looked up or tried with the reason each lost, the shortcodes its content
+// calls and the keys and types of the data context the template saw.
+//
+// The report is YAML, or JSON with --json / --format=json, on stdout. The
+// build's own progress and warnings go to stderr so the report pipes cleanly.
+// Any build flag works after the query (--engine=liquid, --config=…), and the
+// project's real output directory is never written.
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spagu/ssg/internal/generator"
+	"gopkg.in/yaml.v3"
+)
+
+type explainFlags struct {
+	query  string
+	format string
+	rest   []string // build flags, applied as a build applies them
+}
+
+func runExplain(args []string) int {
+	flags, code := parseExplainFlags(args)
+	if code >= 0 {
+		return code
+	}
+	cfg, err := loadConfigFile(configPathOf(flags.rest))
+	if err != nil {
+		errf("❌ %v\n", err)
+		return 2
+	}
+	parseFlags(flags.rest, cfg)
+	applyMinifyAll(cfg)
+	setupTemplateEngine(cfg)
+	downloadOnlineTheme(cfg)
+	genCfg := createGeneratorConfig(cfg)
+
+	scratch, err := os.MkdirTemp("", "ssg-explain-")
+	if err != nil {
+		errf("❌ %v\n", err)
+		return 1
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+	genCfg.OutputDir = scratch
+	// Only the render is wanted: nothing skipped as current, no hooks run, no
+	// trace written.
+	genCfg.Incremental = false
+	genCfg.Hooks = nil
+	genCfg.Profile = ""
+
+	gen, err := generator.New(genCfg)
+	if err != nil {
+		errf("❌ initializing generator: %v\n", err)
+		return 1
+	}
+	// The build prints to stdout; nothing else runs during it, so pointing
+	// stdout at stderr for its duration keeps the report alone on stdout.
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	ex, err := gen.Explain(flags.query)
+	os.Stdout = stdout
+	if err != nil {
+		errf("❌ %v\n", err)
+		return 1
+	}
+	out, err := formatExplanation(ex, flags.format)
+	if err != nil {
+		errf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Print(out)
+	return 0
+}
+
+// formatExplanation renders the report as YAML or JSON.
+func formatExplanation(ex *generator.Explanation, format string) (string, error) {
+	if format == "json" {
+		raw, err := json.MarshalIndent(ex, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(raw) + "\n", nil
+	}
+	raw, err := yaml.Marshal(ex)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+func parseExplainFlags(args []string) (explainFlags, int) {
+	f := explainFlags{format: "yaml"}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--help" || arg == "-h":
+			printExplainUsage()
+			return f, 0
+		case arg == "--json":
+			f.format = "json"
+		case arg == "--format" && i+1 < len(args):
+			i++
+			f.format = args[i]
+		case strings.HasPrefix(arg, "--format="):
+			f.format = strings.TrimPrefix(arg, "--format=")
+		case f.query == "" && !strings.HasPrefix(arg, "-"):
+			f.query = arg
+		default:
+			f.rest = append(f.rest, arg)
+		}
+	}
+	if f.format != "yaml" && f.format != "json" {
+		errf("❌ unknown --format %q (yaml | json)\n\n", f.format)
+		printExplainUsage()
+		return f, 2
+	}
+	if f.query == "" {
+		errf("❌ explain needs a URL or a content file\n\n")
+		printExplainUsage()
+		return f, 2
+	}
+	return f, -1
+}
+
+func printExplainUsage() {
+	fmt.Print(`usage: ssg explain <url-or-source-file> [--json | --format=yaml|json] [build flags]
+
+   ssg explain /about/                         a page by its URL
+   ssg explain content/site/posts/go/hello.md  a page by its source file
+   ssg explain /tags/go/page/2/                an archive, taxonomy or pager page
+   ssg explain /404.html --json                the not-found page, as JSON
+
+Builds the site into a scratch directory and reports how one output was
+rendered: its source, permalink and output files, every template looked up
+or tried in order with the reason each was passed over, the shortcodes its
+content calls and the keys and types of its template data.
+`)
+}
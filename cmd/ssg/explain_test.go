@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunExplain(t *testing.T) {
+	tmp := chdirTemp(t)
+	for name, body := range map[string]string{
+		"content/site/metadata.json":   `{"categories":[],"exported_at":"","media":[]}`,
+		"content/site/pages/about.md":  "---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\nAbout us.\n",
+		"templates/minimal/page.html":  "<main>{{ .Title }}</main>",
+		"templates/minimal/index.html": "<h1>{{ .Domain }}</h1>",
+		".ssg.yaml":                    "source: site\ntemplate: minimal\ndomain: example.com\nquiet: true\n",
+	} {
+		path := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var code int
+	out, _ := captureStdout(func() error { code = runExplain([]string{"/about/"}); return nil })
+	if code != 0 || !strings.Contains(out, "template: page.html") || !strings.Contains(out, "source: content/site/pages/about.md") {
+		t.Errorf("exit %d, output:\n%s", code, out)
+	}
+	out, _ = captureStdout(func() error { code = runExplain([]string{"content/site/pages/about.md", "--json"}); return nil })
+	var report struct {
+		Kind      string `json:"kind"`
+		Permalink string `json:"permalink"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil || code != 0 || report.Kind != "page" || report.Permalink != "/about/" {
+		t.Errorf("exit %d, err %v, JSON report:\n%s", code, err, out)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "output")); !os.IsNotExist(err) {
+		t.Errorf("explain must not write the project's output directory: %v", err)
+	}
+
+	if code := runExplain([]string{"/nowhere/"}); code != 1 {
+		t.Errorf("an unknown URL exits 1, got %d", code)
+	}
+	if code := runExplain(nil); code != 2 {
+		t.Errorf("a missing query exits 2, got %d", code)
+	}
+	if code := runExplain([]string{"/about/", "--format=xml"}); code != 2 {
+		t.Errorf("an unknown format exits 2, got %d", code)
+	}
+}
@@ -73,6 +73,14 @@ func dispatchSingleVerb(args []string) (int, bool) {
 	if len(args) >= 1 && args[0] == "repair" {
 		return runRepair(args[1:]), true
 	}
+	// `lint` likewise: optional paths, and --source=lint still builds.
+	if len(args) >= 1 && args[0] == "lint" {
+		return runLint(args[1:]), true
+	}
+	// `explain` takes the URL or file it explains, then any build flag.
+	if len(args) >= 1 && args[0] == "explain" {
+		return runExplain(args[1:]), true
+	}
 	return 0, false
 }
 
@@ -0,0 +1,284 @@
+package main
+
+// `ssg lint` reviews the writing in the content: heading structure, sentence
+// and paragraph length, passive voice, banned words, bare URLs, images without
+// alt text and empty links (internal/lint). The rules and their thresholds
+// come from the `lint:` block of the config file, per content type; the build
+// runs the same rules with check_prose.
+//
+// Findings print as path:line: rule: message, the shape editors and CI
+// annotations read, and any finding exits 1.
+//
+// --spelling adds a spell check against each language's Hunspell dictionary
+// (internal/spell), the check check_spelling runs; --add-words accepts every
+// unknown word instead of reporting it, appending it to the language's word
+// list under data_dir/spelling/ for review in the diff.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spagu/ssg/internal/config"
+	ssgi18n "github.com/spagu/ssg/internal/i18n"
+	"github.com/spagu/ssg/internal/lint"
+	"github.com/spagu/ssg/internal/parser"
+	"github.com/spagu/ssg/internal/spell"
+)
+
+type lintFlags struct {
+	quiet    bool
+	spelling bool
+	addWords bool
+	paths    []string
+}
+
+func runLint(args []string) int {
+	flags, code := parseLintFlags(args)
+	if code >= 0 {
+		return code
+	}
+	cfg, err := loadConfigFile(config.FindConfigFile())
+	if err == nil {
+		err = lint.Validate(cfg.Lint)
+	}
+	if err != nil {
+		errf("❌ %v\n", err)
+		return 2
+	}
+	roots := flags.paths
+	if len(roots) == 0 {
+		roots = lintDefaultRoots(cfg)
+	}
+
+	var dicts *spell.Set
+	if flags.spelling {
+		dicts = spell.NewSet(cfg.Spelling, cfg.DataDir, ssgi18n.Normalize(cfg.Languages, cfg.LanguageConfigs, nil))
+	}
+	accepted := map[string][]string{} // language → words for --add-words
+	missing := map[string]bool{}
+
+	files, total := 0, 0
+	for _, root := range roots {
+		paths, err := lintFiles(root)
+		if err != nil {
+			errf("❌ %v\n", err)
+			return 1
+		}
+		for _, path := range paths {
+			findings, lang, err := lintFile(path, cfg)
+			if err == nil && dicts != nil {
+				var unknown []lint.Finding
+				unknown, err = spellFile(path, lang, dicts, missing)
+				if flags.addWords {
+					for _, f := range unknown {
+						accepted[lang] = append(accepted[lang], f.Word)
+					}
+					unknown = nil
+				}
+				findings = mergeFindings(findings, unknown)
+			}
+			if err != nil {
+				errf("❌ %v\n", err)
+				return 1
+			}
+			if len(findings) > 0 {
+				files++
+				total += len(findings)
+			}
+			if flags.quiet {
+				continue
+			}
+			for _, f := range findings {
+				fmt.Printf("%s:%s\n", filepath.ToSlash(path), f)
+			}
+		}
+	}
+	langs := make([]string, 0, len(missing))
+	for lang := range missing {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		errf("ℹ️  No Hunspell dictionary for %q; its files were not spell-checked (set spelling.dictionaries.%s)\n", lang, lang)
+	}
+	if flags.addWords && !addWords(cfg.DataDir, accepted, flags.quiet) {
+		return 1
+	}
+	if total == 0 {
+		if !flags.quiet {
+			fmt.Println("✅ No lint findings.")
+		}
+		return 0
+	}
+	fmt.Printf("\n⚠️  %d finding(s) in %d file(s).\n", total, files)
+	fmt.Println("   Silence one in place with <!-- lint-disable-next-line RULE -->, or tune")
+	fmt.Println("   the rules under lint: in the config file.")
+	return 1
+}
+
+// lintDefaultRoots is the content directory and every content_sources path, so
+// a bare `ssg lint` in a project root covers what the build reads.
+func lintDefaultRoots(cfg *config.Config) []string {
+	roots := []string{cfg.ContentDir}
+	if cfg.ContentDir == "" {
+		roots[0] = "content"
+	}
+	for _, src := range cfg.ContentSources {
+		if p := strings.TrimSpace(src.Path); p != "" {
+			roots = append(roots, p)
+		}
+	}
+	return roots
+}
+
+// lintFiles lists the Markdown files under root, or root itself.
+func lintFiles(root string) ([]string, error) {
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", root, err)
+	}
+	if !info.IsDir() {
+		return []string{root}, nil
+	}
+	var out []string
+	err = filepath.Walk(root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".md" || ext == ".markdown" {
+			out = append(out, path)
+		}
+		return nil
+	})
+	return out, err
+}
+
+// lintFile checks one file with the rules for its frontmatter type, in its
+// frontmatter language — the default language, or English, when it names none
+// — and returns that language.
+func lintFile(path string, cfg *config.Config) ([]lint.Finding, string, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- path comes from the operator's own content tree
+	if err != nil {
+		return nil, "", fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	typ, lang := "", ""
+	if page, err := parser.ParseMarkdownFile(path); err == nil {
+		typ, lang = page.Type, page.Lang
+	}
+	if lang == "" {
+		lang = cfg.DefaultLanguage
+	}
+	if lang == "" {
+		lang = "en"
+	}
+	return lint.Check(string(raw), cfg.Lint.ForType(typ), lang), lang, nil
+}
+
+// spellFile spell-checks one file in lang, noting a language with no
+// dictionary in missing instead.
+func spellFile(path, lang string, dicts *spell.Set, missing map[string]bool) ([]lint.Finding, error) {
+	dict, err := dicts.For(lang)
+	if err != nil {
+		return nil, err
+	}
+	if dict == nil {
+		missing[lang] = true
+		return nil, nil
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- as above
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %s: %w", path, err)
+	}
+	return lint.Spelling(string(raw), dict.Check), nil
+}
+
+// mergeFindings interleaves two line-ordered lists.
+func mergeFindings(a, b []lint.Finding) []lint.Finding {
+	out := append(a, b...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Line < out[j].Line })
+	return out
+}
+
+// addWords appends the accepted words to each language's word list.
+func addWords(dataDir string, accepted map[string][]string, quiet bool) bool {
+	langs := make([]string, 0, len(accepted))
+	for lang := range accepted {
+		langs = append(langs, lang)
+	}
+	sort.Strings(langs)
+	for _, lang := range langs {
+		path := spell.WordsFile(dataDir, lang)
+		n, err := spell.AppendWords(path, accepted[lang])
+		if err != nil {
+			errf("❌ cannot update %s: %v\n", path, err)
+			return false
+		}
+		if n > 0 && !quiet {
+			fmt.Printf("📖 Added %d word(s) to %s\n", n, filepath.ToSlash(path))
+		}
+	}
+	return true
+}
+
+func parseLintFlags(args []string) (lintFlags, int) {
+	var f lintFlags
+	for _, arg := range args {
+		switch {
+		case arg == "--quiet" || arg == "-q":
+			f.quiet = true
+		case arg == "--spelling":
+			f.spelling = true
+		case arg == "--add-words":
+			f.spelling, f.addWords = true, true
+		case arg == "--help" || arg == "-h":
+			printLintUsage()
+			return f, 0
+		case strings.HasPrefix(arg, "-"):
+			errf("❌ unknown flag %q\n\n", arg)
+			printLintUsage()
+			return f, 2
+		default:
+			f.paths = append(f.paths, arg)
+		}
+	}
+	return f, -1
+}
+
+func printLintUsage() {
+	fmt.Print(`usage: ssg lint [path...]
+
+   ssg lint                      lint every Markdown file in the content dir
+   ssg lint content/site/posts   lint one directory or file
+   ssg lint --spelling           spell-check as well
+   ssg lint --add-words          accept every unknown word into data/spelling/
+
+Reviews prose and structure and prints path:line: rule: message. Rules:
+
+`)
+	for _, r := range lint.Rules {
+		fmt.Printf("   %-18s %s\n", r.Name, r.Description)
+	}
+	fmt.Print(`
+Configure them under lint: in the config file (disable, max_sentence_words,
+max_paragraph_words, banned_words per language, types: per content type).
+Silence one in the file with <!-- lint-disable RULE --> … <!-- lint-enable RULE -->
+or <!-- lint-disable-next-line RULE -->. check_prose runs the same rules in a build.
+
+Spelling uses the Hunspell dictionary for each file's language: the one named
+under spelling.dictionaries: in the config file, or one installed where
+Hunspell looks (DICPATH, /usr/share/hunspell, ...). Words listed one per line
+in data/spelling/words.txt (every language) or data/spelling/<lang>.txt are
+accepted too. check_spelling runs the same check in a build.
+
+flags:
+   --quiet, -q  print the summary only
+   --spelling   report unknown words as well (rule: spelling)
+   --add-words  append unknown words to data/spelling/<lang>.txt instead of
+                reporting them; review the list in the diff
+
+exit codes: 0 no findings, 1 findings, 2 bad usage or configuration
+`)
+}
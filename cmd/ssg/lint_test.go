@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunLint(t *testing.T) {
+	tmp := chdirTemp(t)
+	posts := filepath.Join(tmp, "content", "site", "posts")
+	if err := os.MkdirAll(posts, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, body := range map[string]string{
+		"bad.md":   "---\ntitle: Bad\ntype: post\n---\n\nIt is simply done.\n",
+		"clean.md": "---\ntitle: Clean\n---\n\nJust prose.\n",
+	} {
+		if err := os.WriteFile(filepath.Join(posts, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(tmp, ".ssg.yaml"), []byte("content_dir: content\nlint:\n  types:\n    post:\n      banned_words:\n        en: [simply]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var code int
+	out, _ := captureStdout(func() error { code = runLint(nil); return nil })
+	if code != 1 || !strings.Contains(out, "content/site/posts/bad.md:6: banned-words: banned word \"simply\"") {
+		t.Errorf("exit %d, output:\n%s", code, out)
+	}
+	if strings.Contains(out, "clean.md") {
+		t.Errorf("a clean file is not reported:\n%s", out)
+	}
+	if code := runLint([]string{"-q", filepath.Join(posts, "clean.md")}); code != 0 {
+		t.Errorf("a clean file exits 0, got %d", code)
+	}
+	if code := runLint([]string{"--bogus"}); code != 2 {
+		t.Errorf("an unknown flag exits 2, got %d", code)
+	}
+}
+
+func TestRunLintSpelling(t *testing.T) {
+	tmp := chdirTemp(t)
+	for name, body := range map[string]string{
+		"dict/en.aff":          "SET UTF-8\n",
+		"dict/en.dic":          "3\nthe\nguide\nwords\n",
+		"content/site/page.md": "---\ntitle: Guide\n---\n\nThe guide wrods.\n\nThe ssg guide.\n",
+		".ssg.yaml":            "content_dir: content\nspelling:\n  dictionaries:\n    en: dict/en\n",
+	} {
+		path := filepath.Join(tmp, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var code int
+	out, _ := captureStdout(func() error { code = runLint([]string{"--spelling"}); return nil })
+	if code != 1 || !strings.Contains(out, `content/site/page.md:5: spelling: unknown word "wrods"`) ||
+		!strings.Contains(out, `page.md:7: spelling: unknown word "ssg"`) {
+		t.Errorf("exit %d, output:\n%s", code, out)
+	}
+	if code := runLint(nil); code != 0 {
+		t.Errorf("without --spelling no word is checked, got exit %d", code)
+	}
+
+	out, _ = captureStdout(func() error { code = runLint([]string{"--add-words"}); return nil })
+	if code != 0 || !strings.Contains(out, "Added 2 word(s) to data/spelling/en.txt") {
+		t.Errorf("exit %d, output:\n%s", code, out)
+	}
+	raw, _ := os.ReadFile(filepath.Join(tmp, "data", "spelling", "en.txt"))
+	if string(raw) != "ssg\nwrods\n" {
+		t.Errorf("word list = %q", raw)
+	}
+	if code := runLint([]string{"--spelling", "-q"}); code != 0 {
+		t.Errorf("accepted words are known afterwards, got exit %d", code)
+	}
+}
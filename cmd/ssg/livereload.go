@@ -2,40 +2,84 @@ package main
 
 // Live reload for `--http --watch` (GO-090): the built-in server exposes a tiny
 // long-lived endpoint the page holds open and waits on. After a successful
-// rebuild the server pushes a "reload" event and the browser refreshes; when a
-// rebuild FAILS it pushes the error, and the page shows a dismissable overlay
-// bar with the message instead of silently serving the stale page. No external
-// process — it is part of the dev server, active only with --auto-reload
-// (on by default in --http --watch).
+// rebuild the server pushes what changed and the browser reacts to just that;
+// when a rebuild FAILS it pushes the error, and the page shows a dismissable
+// overlay bar with the message instead of silently serving the stale page. No
+// external process — it is part of the dev server, active only with
+// --auto-reload (on by default in --http --watch).
+//
+// The events are typed, because "something changed, reload" threw away scroll
+// position and form state on every stylesheet edit, in every open tab:
+//
+//	css    {"paths":["/css/site.css"]}  swap matching <link> hrefs in place
+//	page   {"urls":["/about/"]}         reload only tabs showing one of these
+//	reload                              anything else: reload every tab
+//	error  {"message":…,"file":…,"line":…}  the overlay, with where it failed
+//
+// What changed is read off the output files, not guessed from the inputs: the
+// hub keeps a content hash of every output file and, after each rebuild,
+// re-hashes the ones the build says it wrote — its rendered pages plus the
+// sitemap, feeds, copied assets and the like — so a partial edit that
+// re-rendered forty pages names those forty, and a full render that rewrote
+// identical bytes names none. Only a build that cannot say (no dependency
+// graph, or a pass such as fingerprinting that rewrote the tree) costs a walk
+// of the whole output directory.
 
 import (
 	"bytes"
+	"encoding/json"
 	"net/http"
+	"path/filepath"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+
+	"github.com/spagu/ssg/internal/config"
+	"github.com/spagu/ssg/internal/generator"
 )
 
 const liveReloadPath = "/__livereload"
 
-// liveReloadScript subscribes the tab to rebuild events: "reload" refreshes the
-// page; "builderror" shows a fixed error bar (white on dark red, role=alert for
-// assistive tech). A successful reload clears the bar by loading a fresh page.
-const liveReloadScript = `<script>(function(){try{var s=new EventSource("` + liveReloadPath + `");` +
+// liveReloadScript subscribes the tab to rebuild events (see the table at the
+// top of this file). A stylesheet is swapped by inserting a fresh copy of its
+// <link> and removing the old one once the new one has loaded, so the page
+// never renders unstyled; a changed CSS file no <link> names (an @import) re-
+// fetches every same-origin stylesheet. Every success event clears the error
+// bar, so a fix that changes nothing this tab shows still removes it.
+//
+// "error" is also the name EventSource gives its own connection failures,
+// which carry no data — those are ignored.
+const liveReloadScript = `<script>(function(){try{var s=new EventSource("` + liveReloadPath + `"),` +
+	`B="__ssg_builderror",J=function(e){try{return JSON.parse(e.data)}catch(x){return null}},` +
+	`C=function(){var el=document.getElementById(B);if(el)el.remove()};` +
 	`s.addEventListener("reload",function(){location.reload()});` +
-	`s.addEventListener("builderror",function(e){var i="__ssg_builderror",el=document.getElementById(i);` +
-	`if(!el){el=document.createElement("div");el.id=i;el.setAttribute("role","alert");` +
+	`s.addEventListener("page",function(e){var d=J(e);C();if(d&&d.urls&&d.urls.indexOf(location.pathname)>=0)location.reload()});` +
+	`s.addEventListener("css",function(e){var d=J(e);C();if(!d||!d.paths)return;` +
+	`var ls=document.querySelectorAll('link[rel~="stylesheet"]'),hit=[],all=[];` +
+	`for(var i=0;i<ls.length;i++){var u=new URL(ls[i].href,location.href);if(u.origin!==location.origin)continue;` +
+	`all.push(ls[i]);if(d.paths.indexOf(u.pathname)>=0)hit.push(ls[i])}` +
+	`(hit.length?hit:all).forEach(function(l){var u=new URL(l.href,location.href),n=l.cloneNode();` +
+	`u.searchParams.set("__ssg",Date.now());n.href=u.href;n.onload=function(){l.remove()};` +
+	`l.parentNode.insertBefore(n,l.nextSibling)})});` +
+	`s.addEventListener("error",function(e){if(!e.data)return;var d=J(e)||{message:e.data},el=document.getElementById(B);` +
+	`if(!el){el=document.createElement("div");el.id=B;el.setAttribute("role","alert");` +
 	`el.style.cssText="position:fixed;left:0;right:0;bottom:0;z-index:2147483647;background:#7f1d1d;color:#fff;` +
 	`font:14px/1.5 ui-monospace,SFMono-Regular,Menlo,monospace;padding:12px 16px;white-space:pre-wrap;` +
 	`max-height:50vh;overflow:auto;box-shadow:0 -2px 12px rgba(0,0,0,.4)";document.body.appendChild(el)}` +
-	`el.textContent="⚠ Build failed\n"+e.data})}catch(e){}})();</script>`
+	`el.textContent="⚠ Build failed"+(d.file?" — "+d.file+(d.line?":"+d.line:""):"")+"\n"+d.message})}catch(e){}})();</script>`
 
 // liveReloadHub fans a rebuild payload out to every connected browser tab. The
 // payload is a ready-to-write SSE event.
 type liveReloadHub struct {
 	mu   sync.Mutex
 	subs map[chan string]struct{}
+	// outMu guards the output snapshot the rebuild events are computed from.
+	outMu     sync.Mutex
+	outputDir string
+	outputs   *fileSigCache
 }
 
 // reloadHub holds the live-reload hub while --auto-reload is active, and nil
@@ -99,18 +143,188 @@ func sseEvent(event, data string) string {
 	return b.String()
 }
 
-// notifyReload signals a successful rebuild (no-op unless the hub is running).
+// startReloadHub installs a hub that knows the output directory as it stands
+// now, after the initial build, so the first rebuild's events name only what
+// that rebuild changed.
+func startReloadHub(outputDir string) {
+	h := newLiveReloadHub()
+	h.trackOutput(outputDir)
+	setReloadHub(h)
+}
+
+// trackOutput (re)takes the output snapshot rebuilds are compared against.
+func (h *liveReloadHub) trackOutput(outputDir string) {
+	h.outMu.Lock()
+	defer h.outMu.Unlock()
+	h.outputDir = outputDir
+	h.outputs = newFileSigCache()
+	h.outputs.signature([]string{outputDir})
+}
+
+// outputChanges returns the output files a rebuild changed, relative to the
+// output directory, and false when there is no snapshot of this directory to
+// compare with — a config reload that moved it, or a hub nobody primed. Only
+// written (relative paths) is compared when known; otherwise the whole
+// directory is.
+func (h *liveReloadHub) outputChanges(outputDir string, written []string, known bool) ([]string, bool) {
+	h.outMu.Lock()
+	defer h.outMu.Unlock()
+	if h.outputs == nil || filepath.Clean(h.outputDir) != filepath.Clean(outputDir) {
+		return nil, false
+	}
+	paths := []string{outputDir}
+	if known {
+		paths = make([]string, 0, len(written))
+		for _, rel := range written {
+			paths = append(paths, filepath.Join(outputDir, filepath.FromSlash(rel)))
+		}
+	}
+	changed := h.outputs.changedOf(paths)
+	rel := make([]string, 0, len(changed))
+	for _, p := range changed {
+		if r, err := filepath.Rel(outputDir, p); err == nil {
+			rel = append(rel, filepath.ToSlash(r))
+		}
+	}
+	return rel, true
+}
+
+// maxPageURLs caps a page event. Past it — a full render that really changed
+// everything — a plain reload says the same in fewer bytes.
+const maxPageURLs = 500
+
+// reloadEvents turns the output files a rebuild changed into the events to
+// send. Outputs no open tab displays (sitemaps, feeds, the search index,
+// llms.txt, source maps) never reload anything: they are rewritten on every
+// build. Scripts, images and anything else a page pulls in reload every tab,
+// since which pages use them is not known here. A success always sends at
+// least an empty page event, which is what clears a stale error bar.
+func reloadEvents(changed []string) []string {
+	var css, urls []string
+	other := false
+	for _, rel := range changed {
+		switch strings.ToLower(filepath.Ext(rel)) {
+		case ".css":
+			css = append(css, "/"+rel)
+		case ".html", ".htm":
+			urls = append(urls, pageURLs(rel)...)
+		case ".xml", ".txt", ".json", ".map", ".xsl", ".gz", ".br":
+		default:
+			other = true
+		}
+	}
+	if other || len(urls) > maxPageURLs {
+		return []string{sseEvent("reload", "1")}
+	}
+	var events []string
+	if len(css) > 0 {
+		raw, _ := json.Marshal(map[string][]string{"paths": css})
+		events = append(events, sseEvent("css", string(raw)))
+	}
+	if urls == nil {
+		urls = []string{}
+	}
+	sort.Strings(urls)
+	raw, _ := json.Marshal(map[string][]string{"urls": urls})
+	return append(events, sseEvent("page", string(raw)))
+}
+
+// pageURLs lists every path a browser may show an output file under:
+// about/index.html is /about/ and /about/index.html; with pretty URLs,
+// about.html is also /about.
+func pageURLs(rel string) []string {
+	u := "/" + rel
+	if base := filepath.Base(rel); base == "index.html" {
+		return []string{strings.TrimSuffix(u, "index.html"), u}
+	}
+	return []string{u, strings.TrimSuffix(u, filepath.Ext(u))}
+}
+
+// rebuiltOutputs is what the last build wrote, relative to the output
+// directory, as the generator recorded it. known is false when it could not
+// say. notifyRebuilt takes it, so a build that never noted anything (a config
+// reload, a test's stand-in build) falls back to the directory walk.
+var rebuiltOutputs struct {
+	sync.Mutex
+	files []string
+	known bool
+}
+
+// noteRebuiltOutputs records what gen wrote for the next notifyRebuilt.
+func noteRebuiltOutputs(gen *generator.Generator, cfg *config.Config) {
+	files, known := gen.AggregateOutputs()
+	// The WebP/AVIF pass runs after the generator: it converts images and
+	// rewrites their references in pages this build did not render.
+	if cfg.WebP || wantsFormat(cfg, "webp") || wantsFormat(cfg, "avif") {
+		known = false
+	}
+	rebuiltOutputs.Lock()
+	defer rebuiltOutputs.Unlock()
+	rebuiltOutputs.files, rebuiltOutputs.known = append(files, gen.RenderedOutputs()...), known
+}
+
+func takeRebuiltOutputs() ([]string, bool) {
+	rebuiltOutputs.Lock()
+	defer rebuiltOutputs.Unlock()
+	files, known := rebuiltOutputs.files, rebuiltOutputs.known
+	rebuiltOutputs.files, rebuiltOutputs.known = nil, false
+	return files, known
+}
+
+// notifyRebuilt tells connected tabs what a successful rebuild changed in
+// outputDir (no-op unless the hub is running). Without a snapshot to compare
+// with it reloads everything and takes one for next time.
+func notifyRebuilt(outputDir string) {
+	written, known := takeRebuiltOutputs()
+	hub := currentReloadHub()
+	if hub == nil {
+		return
+	}
+	changed, ok := hub.outputChanges(outputDir, written, known)
+	if !ok {
+		hub.trackOutput(outputDir)
+		hub.broadcast(sseEvent("reload", "1"))
+		return
+	}
+	for _, ev := range reloadEvents(changed) {
+		hub.broadcast(ev)
+	}
+}
+
+// notifyReload reloads every connected tab (no-op unless the hub is running).
 func notifyReload() {
 	if hub := currentReloadHub(); hub != nil {
 		hub.broadcast(sseEvent("reload", "1"))
 	}
 }
 
+// buildErrorLocation finds the first file:line[:col] in a build error — the
+// shape of template errors ("template: post.html:12:5: …") and of the parse
+// errors that name their source.
+var buildErrorLocation = regexp.MustCompile(`([\w./\\-]+\.[A-Za-z0-9]+):(\d+)(?::(\d+))?`)
+
+// buildErrorPayload structures a failed build's message for the overlay.
+func buildErrorPayload(msg string) string {
+	payload := struct {
+		Message string `json:"message"`
+		File    string `json:"file,omitempty"`
+		Line    int    `json:"line,omitempty"`
+		Column  int    `json:"column,omitempty"`
+	}{Message: msg}
+	if m := buildErrorLocation.FindStringSubmatch(msg); m != nil {
+		payload.File = m[1]
+		payload.Line, _ = strconv.Atoi(m[2])
+		payload.Column, _ = strconv.Atoi(m[3])
+	}
+	raw, _ := json.Marshal(payload)
+	return string(raw)
+}
+
 // notifyBuildError pushes a failed build's message to the overlay (no-op unless
 // the hub is running).
 func notifyBuildError(msg string) {
 	if hub := currentReloadHub(); hub != nil {
-		hub.broadcast(sseEvent("builderror", msg))
+		hub.broadcast(sseEvent("error", buildErrorPayload(msg)))
 	}
 }
 
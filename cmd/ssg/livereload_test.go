@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -86,8 +88,111 @@ func TestNotifyBuildError_Broadcasts(t *testing.T) {
 	ch := currentReloadHub().subscribe()
 	notifyBuildError("template: index.html:13: unterminated quoted string")
 	msg := <-ch
-	if !strings.Contains(msg, "event: builderror") || !strings.Contains(msg, "unterminated quoted string") {
-		t.Fatalf("build error not delivered: %q", msg)
+	want := `{"message":"template: index.html:13: unterminated quoted string","file":"index.html","line":13}`
+	if !strings.Contains(msg, "event: error\ndata: "+want+"\n") {
+		t.Fatalf("build error not delivered structured: %q", msg)
+	}
+}
+
+func TestReloadEventsAreTyped(t *testing.T) {
+	cases := []struct {
+		name    string
+		changed []string
+		want    []string
+	}{
+		{"nothing changed still clears the overlay", nil,
+			[]string{"event: page\ndata: {\"urls\":[]}\n\n"}},
+		{"a stylesheet swaps in place", []string{"css/site.css", "sitemap.xml"},
+			[]string{"event: css\ndata: {\"paths\":[\"/css/site.css\"]}\n\n", "event: page\ndata: {\"urls\":[]}\n\n"}},
+		{"pages name every URL they answer to", []string{"about/index.html", "index.html", "feed.xml", "x.html"},
+			[]string{"event: page\ndata: {\"urls\":[\"/\",\"/about/\",\"/about/index.html\",\"/index.html\",\"/x\",\"/x.html\"]}\n\n"}},
+		{"a script reloads everything", []string{"js/app.js", "css/site.css"},
+			[]string{"event: reload\ndata: 1\n\n"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := reloadEvents(tc.changed); strings.Join(got, "|") != strings.Join(tc.want, "|") {
+				t.Errorf("reloadEvents(%v)\n got:  %q\n want: %q", tc.changed, got, tc.want)
+			}
+		})
+	}
+}
+
+// TestNotifyRebuiltNamesWhatTheBuildChanged: the events come from the output
+// directory, so a file rewritten with identical bytes is not a change and a
+// stylesheet edit is a css event, not a reload.
+func TestNotifyRebuiltNamesWhatTheBuildChanged(t *testing.T) {
+	out := t.TempDir()
+	write := func(rel, body string) {
+		t.Helper()
+		p := filepath.Join(out, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("index.html", "<p>home</p>")
+	write("css/site.css", "body{}")
+	old := currentReloadHub()
+	startReloadHub(out)
+	t.Cleanup(func() { setReloadHub(old) })
+	ch := currentReloadHub().subscribe()
+
+	write("index.html", "<p>home</p>") // rewritten, same bytes
+	write("css/site.css", "body{color:red}")
+	notifyRebuilt(out)
+	if got := <-ch; !strings.HasPrefix(got, "event: css") || !strings.Contains(got, "/css/site.css") {
+		t.Errorf("first event = %q, want the stylesheet", got)
+	}
+	if got := <-ch; got != "event: page\ndata: {\"urls\":[]}\n\n" {
+		t.Errorf("second event = %q, want no pages", got)
+	}
+
+	// A directory the hub has no snapshot of (a config reload moved it).
+	notifyRebuilt(t.TempDir())
+	if got := <-ch; !strings.HasPrefix(got, "event: reload") {
+		t.Errorf("unknown output dir = %q, want a full reload", got)
+	}
+}
+
+// TestNotifyRebuiltComparesOnlyWhatTheBuildWrote: with the build's list of
+// outputs noted, only those are re-hashed — a change elsewhere in the output
+// directory is not the build's and names nothing — and the note is used once.
+func TestNotifyRebuiltComparesOnlyWhatTheBuildWrote(t *testing.T) {
+	out := t.TempDir()
+	write := func(rel, body string) {
+		t.Helper()
+		p := filepath.Join(out, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("about/index.html", "<p>about</p>")
+	write("blog/index.html", "<p>blog</p>")
+	old := currentReloadHub()
+	startReloadHub(out)
+	t.Cleanup(func() { setReloadHub(old) })
+	ch := currentReloadHub().subscribe()
+
+	write("about/index.html", "<p>about us</p>")
+	write("blog/index.html", "<p>not this build's</p>")
+	rebuiltOutputs.Lock()
+	rebuiltOutputs.files, rebuiltOutputs.known = []string{"about/index.html", "sitemap.xml"}, true
+	rebuiltOutputs.Unlock()
+	notifyRebuilt(out)
+	if got := <-ch; !strings.HasPrefix(got, "event: page") || !strings.Contains(got, "/about/") || strings.Contains(got, "/blog/") {
+		t.Errorf("event = %q, want /about/ alone", got)
+	}
+
+	// No note left: the next rebuild walks the directory and finds the blog.
+	notifyRebuilt(out)
+	if got := <-ch; !strings.Contains(got, "/blog/") {
+		t.Errorf("fallback event = %q, want /blog/", got)
 	}
 }
 
@@ -66,7 +66,7 @@ func main() {
 
 	if cfg.HTTP {
 		if autoReloadEnabled(cfg) {
-			setReloadHub(newLiveReloadHub())
+			startReloadHub(cfg.OutputDir)
 		}
 		startServerAsync(cfg)
 	}
@@ -117,15 +117,18 @@ func runWatchOrServe(genCfg generator.Config, cfg *config.Config) {
 	}
 }
 
-// runWatchLoop continuously watches for file changes and rebuilds. Rebuilds are
-// gated on a content signature so that touch-only events (mtime bumped, bytes
-// unchanged) do not trigger redundant work — a conservative first increment of
-// incremental builds where any real change still triggers a full, correct rebuild
-// (PLAT-006).
+// runWatchLoop watches for file changes and rebuilds. The watcher names the
+// files that changed (inotify on Linux, polling elsewhere — internal/watch);
+// rebuilds are gated on their content so touch-only events do not trigger
+// redundant work (PLAT-006), and each rebuild is incremental: the generator
+// re-renders only the outputs whose recorded inputs changed (see
+// internal/generator/depgraph.go), re-hashing the files the watcher named.
 func runWatchLoop(genCfg generator.Config, cfg *config.Config, stop <-chan struct{}) {
 	configPath := configPathOf(os.Args[1:])
+	sw := newSiteWatch(cfg, configPath)
+	defer sw.close()
 	if !cfg.Quiet {
-		fmt.Printf("👀 Watching for changes in %s...\n", strings.Join(watchedInputs(cfg, configPath), ", "))
+		fmt.Printf("👀 Watching for changes in %s (%s)...\n", strings.Join(watchedInputs(cfg, configPath), ", "), sw.describe())
 	}
 	// A configured worker defaults the watch runner to `wrangler pages dev`, so
 	// the static preview and the Functions run together (GO-065). Only here, in
@@ -149,15 +152,9 @@ func runWatchLoop(genCfg generator.Config, cfg *config.Config, stop <-chan struc
 			}()
 		}
 	}
-	dirs := watchDirs(cfg)
-	// One cache for the whole loop: unchanged files keep their hash between
-	// polls, so a change event never re-reads the entire content tree (PERF-008).
-	sigCache := newFileSigCache()
-	lastBuild := time.Now()
-	lastSig := sigCache.signature(dirs)
-	configSig := fileSignature(configPath)
 
 	for {
+		var batch []string
 		select {
 		case <-stop:
 			// The owner is done with this watcher. A nil channel never fires,
@@ -166,26 +163,33 @@ func runWatchLoop(genCfg generator.Config, cfg *config.Config, stop <-chan struc
 			// outliving it kept rebuilding into whatever directory the process
 			// had wandered to by then (#191).
 			return
-		case <-time.After(1 * time.Second):
+		case <-stateChangeDue():
+			// Nothing was edited, but a page's publish_date or expiry_date
+			// arrived: the last build said when.
+			rebuildOnChange(genCfg, cfg, nil)
+			sw.followIncludes(cfg)
+			continue
+		case batch = <-sw.changes():
 		}
+		changed, configEdited := sw.sift(batch)
 		// The config file is watched as an input of its own: an edit reloads it
 		// and rebuilds with the new settings, so the watcher never keeps building
 		// from the configuration it started with (#70).
-		if sig := fileSignature(configPath); sig != configSig {
-			configSig = sig
+		if configEdited {
 			if newGen, newCfg, ok := reloadWatchConfig(os.Args[1:], configPath, cfg); ok {
 				genCfg, cfg = newGen, newCfg
-				dirs = watchDirs(cfg)
-				sigCache = newFileSigCache()
-				lastSig = sigCache.signature(dirs)
+				sw.retarget(cfg)
 			}
-			rebuildOnChange(genCfg, cfg)
-			lastBuild = time.Now()
+			rebuildOnChange(genCfg, cfg, nil)
+			sw.followIncludes(cfg)
 			continue
 		}
-		lastBuild, lastSig = watchIteration(dirs, sigCache, lastBuild, lastSig, func() {
-			rebuildOnChange(genCfg, cfg)
-		})
+		// Edits saved while the build runs queue up in the watcher and arrive
+		// as the next batch, so none is lost (GO-025).
+		if len(changed) > 0 {
+			rebuildOnChange(genCfg, cfg, changed)
+			sw.followIncludes(cfg)
+		}
 	}
 }
 
@@ -234,25 +238,6 @@ func reloadWatchConfig(args []string, configPath string, old *config.Config) (ge
 	return createGeneratorConfig(cfg), cfg, true
 }
 
-// watchIteration runs one poll of the watch loop: detect changes, skip
-// touch-only events, rebuild. It returns the updated lastBuild/lastSig pair.
-// The build timestamp is taken BEFORE the rebuild runs, so files edited while
-// the build is in progress are picked up on the next poll instead of being
-// lost (GO-025).
-func watchIteration(dirs []string, sigCache *fileSigCache, lastBuild time.Time, lastSig string, rebuild func()) (time.Time, string) {
-	if !hasChanges(dirs, lastBuild) {
-		return lastBuild, lastSig
-	}
-	sig := sigCache.signature(dirs)
-	if sig == lastSig {
-		// mtime changed but bytes did not — skip the rebuild (PLAT-006).
-		return time.Now(), lastSig
-	}
-	buildStart := time.Now()
-	rebuild()
-	return buildStart, sig
-}
-
 // watchDirs returns the directories watched for changes: content, templates,
 // data and every extra Markdown root, so editing a file in a content_sources
 // directory rebuilds like editing the primary source does (CONTENT-002).
@@ -324,18 +309,23 @@ func runMddbWatchLoop(genCfg generator.Config, cfg *config.Config) {
 					lastChecksum, checksumResp.Checksum, checksumResp.DocumentCount)
 			}
 			lastChecksum = checksumResp.Checksum
-			rebuildOnChange(genCfg, cfg)
+			rebuildOnChange(genCfg, cfg, nil)
 		}
 	}
 }
 
-// rebuildOnChange handles rebuilding when changes are detected. It no longer
-// returns a completion timestamp: the watch loop stamps lastBuild before the
-// build starts so mid-build edits are not lost (GO-025).
-func rebuildOnChange(genCfg generator.Config, cfg *config.Config) {
+// rebuildOnChange handles rebuilding when changes are detected. changed are
+// the files the watcher saw change, nil when the trigger was not a file (the
+// config, MDDB); the build re-hashes them rather than trusting their mtime.
+func rebuildOnChange(genCfg generator.Config, cfg *config.Config, changed []string) {
 	if !cfg.Quiet {
-		fmt.Println("\n🔄 Changes detected! Rebuilding...")
+		if len(changed) > 0 {
+			fmt.Printf("\n🔄 Changed: %s — rebuilding...\n", changedSummary(changed))
+		} else {
+			fmt.Println("\n🔄 Changes detected! Rebuilding...")
+		}
 	}
+	genCfg.ChangedPaths = changed
 	if err := build(genCfg, cfg); err != nil {
 		notifyBuildError(err.Error()) // show the error overlay in connected browsers
 		if !cfg.Quiet {
@@ -347,7 +337,7 @@ func rebuildOnChange(genCfg generator.Config, cfg *config.Config) {
 		// the running server re-reads them here rather than waiting for a
 		// config edit or a restart (#181).
 		republishOutputRules(cfg)
-		notifyReload() // refresh connected browsers (no-op unless --auto-reload)
+		notifyRebuilt(cfg.OutputDir) // css swap / page reload in connected browsers (no-op unless --auto-reload)
 		if !cfg.Quiet {
 			fmt.Printf("✅ Rebuilt successfully\n")
 		}
@@ -489,6 +479,10 @@ func setupTemplateEngine(cfg *config.Config) {
 			cfg.Deploy, strings.Join(deploy.SupportedProviders(), ", "))
 		os.Exit(1)
 	}
+	if err := refusePreviewDeploy(cfg); err != nil {
+		errf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
 
 	if cfg.Engine != "" && !cfg.Quiet {
 		fmt.Printf("🔧 Using template engine: %s\n", cfg.Engine)
@@ -496,9 +490,10 @@ func setupTemplateEngine(cfg *config.Config) {
 }
 
 // validateTemplateEngine checks that the requested template engine is supported.
-// All four back-ends now render for real (GO-007): the generator loads the theme's
+// Every back-end renders for real (GO-007): the generator loads the theme's
 // templates through the selected engine. Alt-engine themes must be authored in
-// that engine's syntax (pongo2/mustache/handlebars have no Go FuncMap/inheritance).
+// that engine's syntax (pongo2/mustache/handlebars/liquid have no Go
+// FuncMap/inheritance).
 func validateTemplateEngine(cfg *config.Config) error {
 	if cfg.Engine == "" {
 		return nil
@@ -507,10 +502,11 @@ func validateTemplateEngine(cfg *config.Config) error {
 	case engine.EngineGo,
 		engine.EnginePongo2, "jinja2", "django",
 		engine.EngineMustache,
-		engine.EngineHandlebars, "hbs":
+		engine.EngineHandlebars, "hbs",
+		engine.EngineLiquid, "jekyll":
 		return nil
 	default:
-		return fmt.Errorf("unknown template engine: %s (supported: go, pongo2, mustache, handlebars)", cfg.Engine)
+		return fmt.Errorf("unknown template engine: %s (supported: go, pongo2, mustache, handlebars, liquid)", cfg.Engine)
 	}
 }
 
@@ -520,7 +516,9 @@ func downloadOnlineTheme(cfg *config.Config) {
 		return
 	}
 
-	themeDir := filepath.Join(cfg.TemplatesDir, cfg.Template)
+	// A child theme (theme.yaml extends) keeps its own files; the download
+	// is its parent.
+	themeDir := generator.OnlineThemeDir(cfg.TemplatesDir, cfg.Template)
 	if !cfg.Quiet {
 		fmt.Printf("🌐 Downloading theme from: %s\n", cfg.OnlineTheme)
 	}
@@ -630,6 +628,8 @@ func createGeneratorConfig(cfg *config.Config) generator.Config {
 		DataDir:                cfg.DataDir,
 		RewriteMdLinks:         cfg.RewriteMdLinks == nil || *cfg.RewriteMdLinks, // default on (#1.8.15)
 		StripMdLinkText:        cfg.StripMdLinkText,
+		Wikilinks:              cfg.Wikilinks,
+		Callouts:               cfg.Callouts == nil || *cfg.Callouts, // default on
 		MarkdownPublish:        cfg.MarkdownPublish,
 		CleanSpecialChars:      cfg.CleanSpecialChars,
 		OutputEncoding:         cfg.OutputEncoding,
@@ -668,6 +668,8 @@ func createGeneratorConfig(cfg *config.Config) generator.Config {
 		SEO:                    cfg.SEO,
 		Analytics:              cfg.Analytics,
 		DateArchives:           cfg.DateArchives,
+		Authors:                cfg.Authors,
+		Menus:                  cfg.Menus,
 		TypeArchives:           cfg.TypeArchives,
 		SanitizeOutput:         cfg.SanitizeOutput,
 		ImageMetadata:          cfg.ImageMetadata,
@@ -686,15 +688,21 @@ func createGeneratorConfig(cfg *config.Config) generator.Config {
 		Colors:                 cfg.Colors,
 		CheckImages:            cfg.CheckImages,
 		CheckMarkup:            cfg.CheckMarkup,
+		CheckProse:             cfg.CheckProse,
+		CheckSpelling:          cfg.CheckSpelling,
+		Spelling:               cfg.Spelling,
+		Lint:                   cfg.Lint,
 		CheckMeta:              cfg.CheckMeta,
 		CheckSchema:            cfg.CheckSchema,
 		CheckOrphans:           cfg.CheckOrphans,
 		CheckRedirects:         cfg.CheckRedirects,
 		PrettyURLs:             cfg.PrettyURLs,
 		ContentExclude:         cfg.ContentExclude,
+		ContentDefaults:        cfg.ContentDefaults,
 		SitemapPruneCanonical:  cfg.SitemapPruneCanonical,
 		StaticSources:          cfg.StaticSources,
 		Feeds:                  cfg.Feeds,
+		PageGenerators:         cfg.PageGenerators,
 		FeedAutodiscovery:      cfg.FeedAutodiscovery,
 		MetaLimits:             cfg.MetaLimits,
 		Bundles:                cfg.Bundles,
@@ -703,6 +711,7 @@ func createGeneratorConfig(cfg *config.Config) generator.Config {
 		SanitizeHTML:           cfg.SanitizeHTML,
 		ShortcodeErrors:        cfg.ShortcodeErrors,
 		ContentSources:         contentSourcesOf(cfg),
+		Versions:               versionsOf(cfg),
 		LinkRewrites:           cfg.LinkRewrites,
 		AutoExcerpt:            cfg.AutoExcerpt,
 		Headers:                cfg.Headers,
@@ -712,6 +721,13 @@ func createGeneratorConfig(cfg *config.Config) generator.Config {
 		// keeps only the _redirects 301s (GO-063).
 		AliasStubsOff: cfg.AliasStubs != nil && !*cfg.AliasStubs,
 		Workers:       workersOf(cfg),
+		// A watch rebuild follows a save, which is the case incremental
+		// rendering exists for; a one-shot build opts in.
+		Incremental:  cfg.Incremental || cfg.Watch,
+		Profile:      cfg.Profile,
+		BuildFuture:  cfg.BuildFuture,
+		BuildExpired: cfg.BuildExpired,
+		Drafts:       cfg.Drafts,
 		Mddb: generator.MddbConfig{
 			Enabled:    cfg.Mddb.Enabled,
 			URL:        cfg.Mddb.URL,
@@ -772,14 +788,32 @@ func contentSourcesOf(cfg *config.Config) []generator.ContentSource {
 	out := make([]generator.ContentSource, 0, len(cfg.ContentSources))
 	for _, src := range cfg.ContentSources {
 		out = append(out, generator.ContentSource{
-			Path:     src.Path,
-			Type:     src.Type,
-			Category: src.Category,
+			Path:        src.Path,
+			Type:        src.Type,
+			Category:    src.Category,
+			URL:         src.URL,
+			Ref:         src.Ref,
+			Subdir:      src.Subdir,
+			Auth:        fetch.Auth(src.Auth),
+			ContentType: src.ContentType,
 		})
 	}
 	return out
 }
 
+// versionsOf converts the configured `versions:` entries into the generator's
+// own type (same pattern as contentSourcesOf).
+func versionsOf(cfg *config.Config) []generator.Version {
+	if len(cfg.Versions) == 0 {
+		return nil
+	}
+	out := make([]generator.Version, 0, len(cfg.Versions))
+	for _, v := range cfg.Versions {
+		out = append(out, generator.Version{Name: v.Name, Ref: v.Ref, Title: v.Title, Latest: v.Latest})
+	}
+	return out
+}
+
 // robotsRulesOf converts the config's robots rules into the generator's own
 // type, keeping the two packages decoupled (same pattern as contentSourcesOf).
 func robotsRulesOf(cfg *config.Config) []generator.RobotsRule {
@@ -849,6 +883,14 @@ func parseBoolFlags(arg string, cfg *config.Config) bool {
 		cfg.CheckMarkup = ""
 		return true
 	}
+	if arg == "--check-prose" { // same shape: bare form means warn
+		cfg.CheckProse = "warn"
+		return true
+	}
+	if arg == "--check-spelling" { // same shape: bare form means warn
+		cfg.CheckSpelling = "warn"
+		return true
+	}
 	if arg == "--check-schema" { // same shape: bare form means warn (#111)
 		cfg.CheckSchema = "warn"
 		return true
@@ -992,6 +1034,7 @@ func stringEqualFlags(cfg *config.Config) map[string]*string {
 		"--mddb-lang=":        &cfg.Mddb.Lang,
 		"--external-source=":  &cfg.ExternalSources.Only,
 		"--watch-runner=":     &cfg.WatchRunner,
+		"--profile=":          &cfg.Profile,
 		// Runner-agnostic spellings; --wrangler-config/--wrangler-dir (and the
 		// workerd pair) are the convenience forms that also select the runner.
 		"--watch-runner-config=": &cfg.WatchRunnerConfig,
@@ -1094,6 +1137,14 @@ func parseMiscEqualFlags(arg string, cfg *config.Config) {
 		if v := strings.TrimPrefix(arg, "--check-markup="); v == "warn" || v == "strict" || v == "off" || v == "" {
 			cfg.CheckMarkup = v
 		}
+	case strings.HasPrefix(arg, "--check-prose="):
+		if v := strings.TrimPrefix(arg, "--check-prose="); v == "warn" || v == "strict" {
+			cfg.CheckProse = v
+		}
+	case strings.HasPrefix(arg, "--check-spelling="):
+		if v := strings.TrimPrefix(arg, "--check-spelling="); v == "warn" || v == "strict" {
+			cfg.CheckSpelling = v
+		}
 	case strings.HasPrefix(arg, "--check-meta="):
 		if v := strings.TrimPrefix(arg, "--check-meta="); v == "warn" || v == "strict" {
 			cfg.CheckMeta = v
@@ -1220,6 +1271,9 @@ func build(genCfg generator.Config, cfg *config.Config) error {
 	if err := gen.Generate(); err != nil {
 		return fmt.Errorf("generating site: %w", err)
 	}
+	noteStateChange(gen.NextStateChange())
+	noteIncludes(gen.IncludedFiles())
+	noteRebuiltOutputs(gen, cfg)
 	if err := emitEndpoints(cfg); err != nil {
 		return err
 	}
@@ -1396,12 +1450,26 @@ func createZipArchive(cfg *config.Config) error {
 	return nil
 }
 
+// refusePreviewDeploy rejects --deploy with --drafts: a preview build carries
+// unpublished content, and shipping it is never what the flags meant. Checked
+// before the build so it fails fast, and again in runDeploy for any path that
+// did not come through flag validation.
+func refusePreviewDeploy(cfg *config.Config) error {
+	if cfg.Drafts && cfg.Deploy != "" {
+		return fmt.Errorf("--deploy is refused in --drafts preview mode: the output contains unpublished content")
+	}
+	return nil
+}
+
 // runDeploy publishes the output tree to the configured provider (v1.8.1). No-op when
 // --deploy is unset.
 func runDeploy(cfg *config.Config) error {
 	if cfg.Deploy == "" {
 		return nil
 	}
+	if err := refusePreviewDeploy(cfg); err != nil {
+		return err
+	}
 	url, err := deploy.Run(context.Background(), deploy.Options{
 		Provider: cfg.Deploy,
 		Dir:      cfg.OutputDir,
@@ -1434,28 +1502,6 @@ func makeArchive(cfg *config.Config, ext string, fn func(src, out string) error)
 	return nil
 }
 
-func hasChanges(dirs []string, lastBuild time.Time) bool {
-	changed := false
-	for _, dir := range dirs {
-		_ = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error { // #nosec G703 -- CLI tool scans user's content dirs
-			if err != nil {
-				return nil
-			}
-			if !info.IsDir() {
-				if info.ModTime().After(lastBuild) {
-					changed = true
-					return io.EOF
-				}
-			}
-			return nil
-		})
-		if changed {
-			break
-		}
-	}
-	return changed
-}
-
 // createZip builds a ZIP archive of sourceDir at zipFileName. Errors from the
 // writer Close (which emits the ZIP central directory) and from the file Close
 // are propagated so a corrupt archive is never reported as success (GO-024).
@@ -1556,6 +1602,8 @@ func printUsage() {
 	fmt.Println("  ssg import redirects   - Convert a Next.js redirects() rule set")
 	fmt.Println("  ssg migrate <src> <url> - Migrate a live site (see 'ssg migrate --help')")
 	fmt.Println("  ssg repair [--fix]     - Find (and fix) markup a migration left indented")
+	fmt.Println("  ssg lint [path...]     - Review prose and structure (see 'ssg lint --help')")
+	fmt.Println("  ssg explain <url|file> - Show which template rendered a page, and its data")
 	fmt.Println("  ssg mcp                - Development MCP server for AI-assisted editing")
 	fmt.Println("                           (designer + content manager; see 'ssg mcp --help')")
 	fmt.Println("")
@@ -1565,7 +1613,7 @@ func printUsage() {
 	fmt.Println("")
 	fmt.Println("Template Engine:")
 	fmt.Println("  --engine=ENGINE        - Template engine (default: go)")
-	fmt.Println("                           Supported: go, pongo2, mustache, handlebars")
+	fmt.Println("                           Supported: go, pongo2, mustache, handlebars, liquid")
 	fmt.Println("                           (alt engines load the theme's own templates verbatim)")
 	fmt.Println("  --online-theme=URL     - Download theme from URL (GitHub, GitLab, or direct ZIP)")
 	fmt.Println("                           Example: --online-theme=https://github.com/user/hugo-theme")
@@ -1588,7 +1636,12 @@ func printUsage() {
 	fmt.Println("  --host=ADDR            - Dev server bind address (default: 127.0.0.1; use 0.0.0.0 to expose)")
 	fmt.Println("  --port=PORT            - HTTP server port (default: 8888)")
 	fmt.Println("  --watch                - Watch for changes and rebuild automatically")
+	fmt.Println("  --watch-poll           - Poll for changes instead of using inotify (network mounts)")
 	fmt.Println("  --clean                - Clean output directory before build")
+	fmt.Println("  --incremental          - Re-render only outputs whose inputs changed since the last")
+	fmt.Println("                           build (dependency graph in .ssg-cache/deps/; --watch always does)")
+	fmt.Println("  --profile=FILE         - Write a Chrome trace of build phases, templates and image work")
+	fmt.Println("                           to FILE, with a slowest-first text summary beside it (.txt)")
 	fmt.Println("")
 	fmt.Println("Watch runners (spawned alongside --watch):")
 	fmt.Println("  --wrangler                  - Run `npx wrangler dev` in the background")
@@ -1649,6 +1702,10 @@ func printUsage() {
 	fmt.Println("                           (opt-in since v1.8.2; --seo-off / seo_off forces it off, deprecated)")
 	fmt.Println("  --timezone=ZONE        - IANA zone for content dates in permalinks/templates (e.g. Europe/Warsaw);")
 	fmt.Println("                           per-language overrides via language_timezones: in .ssg.yaml")
+	fmt.Println("  --build-future         - Build pages whose publish_date (or date) is still ahead")
+	fmt.Println("  --build-expired        - Build pages whose expiry_date has passed")
+	fmt.Println("  --drafts               - Preview mode: also build draft/pending/future content, bannered and")
+	fmt.Println("                           noindexed, kept out of sitemap/feeds/llms.txt/search (refuses --deploy)")
 	fmt.Println("")
 	fmt.Println("Feeds, Search & Listings:")
 	fmt.Println("  --feed                 - Generate an Atom feed (feed.xml)")
@@ -1666,6 +1723,10 @@ func printUsage() {
 	fmt.Println("  --check-markup=MODE    - warn (default) | strict | off — report source markup")
 	fmt.Println("                           indented into a code block ('ssg repair --fix')")
 	fmt.Println("  --no-check-markup      - Turn that default check off")
+	fmt.Println("  --check-prose          - Run the lint: rules over every page's source (warn mode)")
+	fmt.Println("  --check-prose=MODE     - warn | strict (strict fails the build; see 'ssg lint')")
+	fmt.Println("  --check-spelling       - Spell-check prose with each language's Hunspell dictionary (warn mode)")
+	fmt.Println("  --check-spelling=MODE  - warn | strict (accept words with 'ssg lint --spelling --add-words')")
 	fmt.Println("  --check-schema         - Validate emitted JSON-LD against required properties (warn)")
 	fmt.Println("  --check-schema=MODE    - warn | strict (strict fails the build)")
 	fmt.Println("  --check-orphans        - Report indexable pages with no inbound links (warn mode)")
@@ -1929,6 +1990,8 @@ var standaloneFlagNames = []string{
 	"--help", "-h", "--version", "-v", "--auto-reload", "--no-auto-reload",
 	"--check-links", "--check-images", "--check-meta", "--check-schema",
 	"--check-orphans", "--check-redirects", "--seo-off", "--no-check-markup",
+	"--check-prose",
+	"--check-spelling",
 }
 
 // nearestFlag returns the known option closest to what was typed, so a
@@ -1991,7 +2054,7 @@ func boolFlagTargets(cfg *config.Config) map[string]*bool {
 		"--webp-keep-original": &cfg.WebPKeepOriginal,
 		"--reconvert-images":   &cfg.ReconvertImages,
 		"--images-gc":          &cfg.ImagesGC, "--images-gc-dry": &cfg.ImagesGCDry,
-		"--watch": &cfg.Watch, "-watch": &cfg.Watch,
+		"--watch": &cfg.Watch, "-watch": &cfg.Watch, "--watch-poll": &cfg.WatchPoll,
 		"--http": &cfg.HTTP, "-http": &cfg.HTTP,
 		"--sitemap-off": &cfg.SitemapOff, "--robots-off": &cfg.RobotsOff,
 		"--not-found-off": &cfg.NotFoundOff,
@@ -2008,8 +2071,9 @@ func boolFlagTargets(cfg *config.Config) map[string]*bool {
 		"--strict": &cfg.Strict, "--route-manifest": &cfg.RouteManifest, // #62
 		"--notify":     &cfg.Notify,     // #1.8.16 announce new/changed posts
 		"--mddb-watch": &cfg.Mddb.Watch, // bool flag, not an =value flag (GO-018)
-		"--clean":      &cfg.Clean,
-		"--quiet":      &cfg.Quiet, "-q": &cfg.Quiet,
+		"--clean":      &cfg.Clean, "--incremental": &cfg.Incremental,
+		"--build-future": &cfg.BuildFuture, "--build-expired": &cfg.BuildExpired, "--drafts": &cfg.Drafts,
+		"--quiet": &cfg.Quiet, "-q": &cfg.Quiet,
 		// External sources (docs/EXTERNAL_SOURCES.md)
 		"--offline":                  &cfg.ExternalSources.Offline,
 		"--refresh-external-sources": &cfg.ExternalSources.Refresh,
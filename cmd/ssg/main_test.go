@@ -418,60 +418,6 @@ func TestApplyMinifyAllPreservesExisting(t *testing.T) {
 	}
 }
 
-func TestHasChangesDetectsModified(t *testing.T) {
-	tmpDir := t.TempDir()
-	pastTime := time.Now().Add(-2 * time.Second)
-
-	filePath := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(filePath, []byte("content"), 0644); err != nil {
-		t.Fatal(err)
-	}
-
-	if !hasChanges([]string{tmpDir}, pastTime) {
-		t.Error("expected hasChanges to return true for recently modified file")
-	}
-}
-
-func TestHasChangesNoChanges(t *testing.T) {
-	tmpDir := t.TempDir()
-	filePath := filepath.Join(tmpDir, "test.txt")
-	if err := os.WriteFile(filePath, []byte("content"), 0644); err != nil {
-		t.Fatal(err)
-	}
-
-	futureTime := time.Now().Add(10 * time.Second)
-	if hasChanges([]string{tmpDir}, futureTime) {
-		t.Error("expected hasChanges to return false when no files modified after lastBuild")
-	}
-}
-
-func TestHasChangesEmptyDir(t *testing.T) {
-	tmpDir := t.TempDir()
-	if hasChanges([]string{tmpDir}, time.Now().Add(-1*time.Second)) {
-		t.Error("expected hasChanges to return false for empty directory")
-	}
-}
-
-func TestHasChangesNonExistentDir(t *testing.T) {
-	if hasChanges([]string{"/nonexistent/dir"}, time.Now().Add(-1*time.Second)) {
-		t.Error("expected hasChanges to return false for nonexistent directory")
-	}
-}
-
-func TestHasChangesMultipleDirs(t *testing.T) {
-	dir1 := t.TempDir()
-	dir2 := t.TempDir()
-
-	pastTime := time.Now().Add(-2 * time.Second)
-	if err := os.WriteFile(filepath.Join(dir2, "changed.txt"), []byte("data"), 0644); err != nil {
-		t.Fatal(err)
-	}
-
-	if !hasChanges([]string{dir1, dir2}, pastTime) {
-		t.Error("expected hasChanges to detect change in second directory")
-	}
-}
-
 func TestCreateZip(t *testing.T) {
 	srcDir := t.TempDir()
 
@@ -1200,7 +1146,7 @@ func TestRebuildOnChange(t *testing.T) {
 	}
 	cfg := &config.Config{OutputDir: outputDir, Quiet: true}
 
-	rebuildOnChange(genCfg, cfg)
+	rebuildOnChange(genCfg, cfg, nil)
 	if _, err := os.Stat(filepath.Join(outputDir, "index.html")); err != nil {
 		t.Errorf("expected rebuilt output: %v", err)
 	}
@@ -1216,7 +1162,7 @@ func TestRebuildOnChangeFailure(t *testing.T) {
 	cfg := &config.Config{Quiet: false}
 
 	// Must not panic or exit on a build failure; it reports and carries on.
-	if out := captureStderr(t, func() { rebuildOnChange(genCfg, cfg) }); out == "" {
+	if out := captureStderr(t, func() { rebuildOnChange(genCfg, cfg, nil) }); out == "" {
 		t.Error("a failed rebuild must say so")
 	}
 }
@@ -1530,7 +1476,7 @@ func TestRebuildOnChangeVerbose(t *testing.T) {
 	}
 	cfg := &config.Config{OutputDir: outputDir, Quiet: false}
 
-	rebuildOnChange(genCfg, cfg)
+	rebuildOnChange(genCfg, cfg, nil)
 
 	_ = w.Close()
 	os.Stdout = oldStdout
@@ -1969,23 +1915,6 @@ func TestParseFlagsIgnoresPositional(t *testing.T) {
 	}
 }
 
-func TestHasChangesSubdirectory(t *testing.T) {
-	tmpDir := t.TempDir()
-	subDir := filepath.Join(tmpDir, "sub")
-	if err := os.MkdirAll(subDir, 0755); err != nil {
-		t.Fatal(err)
-	}
-
-	pastTime := time.Now().Add(-2 * time.Second)
-	if err := os.WriteFile(filepath.Join(subDir, "file.txt"), []byte("data"), 0644); err != nil {
-		t.Fatal(err)
-	}
-
-	if !hasChanges([]string{tmpDir}, pastTime) {
-		t.Error("expected hasChanges to detect file in subdirectory")
-	}
-}
-
 func TestDownloadOnlineThemeVerbose(t *testing.T) {
 	if os.Getenv("TEST_DOWNLOAD_VERBOSE") == "1" {
 		cfg := &config.Config{
@@ -2092,92 +2021,6 @@ func TestParseFlagsMddbWatch(t *testing.T) {
 	}
 }
 
-// TestWatchIterationDetectsMidBuildEdit verifies GO-025: lastBuild is stamped
-// BEFORE the rebuild runs, so a file saved while the build is in progress is
-// still newer than lastBuild and triggers the next iteration.
-func TestWatchIterationDetectsMidBuildEdit(t *testing.T) {
-	dir := t.TempDir()
-	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("one"), 0644); err != nil {
-		t.Fatal(err)
-	}
-	dirs := []string{dir}
-
-	newBuild, newSig := watchIteration(dirs, newFileSigCache(), time.Now().Add(-time.Hour), "stale", func() {
-		// Simulate an edit landing while the (slow) rebuild is running.
-		time.Sleep(10 * time.Millisecond)
-		if err := os.WriteFile(filepath.Join(dir, "b.md"), []byte("mid-build edit"), 0644); err != nil {
-			t.Error(err)
-		}
-	})
-
-	if newSig == "stale" {
-		t.Error("signature should be refreshed after a rebuild")
-	}
-	if !hasChanges(dirs, newBuild) {
-		t.Error("edit made during the rebuild must be detected on the next poll (GO-025)")
-	}
-}
-
-// TestWatchIterationNoDoubleRebuild verifies that the change which triggered a
-// rebuild does not re-trigger one, and that quiet polls rebuild nothing.
-func TestWatchIterationNoDoubleRebuild(t *testing.T) {
-	dir := t.TempDir()
-	if err := os.WriteFile(filepath.Join(dir, "a.md"), []byte("one"), 0644); err != nil {
-		t.Fatal(err)
-	}
-	dirs := []string{dir}
-	time.Sleep(5 * time.Millisecond) // ensure the file mtime precedes buildStart
-
-	builds := 0
-	lastBuild, lastSig := watchIteration(dirs, newFileSigCache(), time.Now().Add(-time.Hour), "stale", func() { builds++ })
-	if builds != 1 {
-		t.Fatalf("expected exactly one rebuild, got %d", builds)
-	}
-	if hasChanges(dirs, lastBuild) {
-		t.Error("the triggering change must not re-trigger a rebuild")
-	}
-
-	// A poll without changes must not rebuild and must keep lastBuild/lastSig.
-	b2, s2 := watchIteration(dirs, newFileSigCache(), lastBuild, lastSig, func() { builds++ })
-	if builds != 1 {
-		t.Errorf("no rebuild expected without changes, got %d", builds)
-	}
-	if !b2.Equal(lastBuild) || s2 != lastSig {
-		t.Error("lastBuild/lastSig must be unchanged on a quiet poll")
-	}
-}
-
-// TestWatchIterationTouchOnlySkipsRebuild verifies the PLAT-006 gate inside the
-// extracted iteration: an mtime bump with identical bytes advances lastBuild
-// without rebuilding.
-func TestWatchIterationTouchOnlySkipsRebuild(t *testing.T) {
-	dir := t.TempDir()
-	file := filepath.Join(dir, "a.md")
-	if err := os.WriteFile(file, []byte("one"), 0644); err != nil {
-		t.Fatal(err)
-	}
-	dirs := []string{dir}
-	sig := newFileSigCache().signature(dirs)
-
-	// Touch: same bytes, newer mtime than lastBuild.
-	now := time.Now()
-	if err := os.Chtimes(file, now, now); err != nil {
-		t.Fatal(err)
-	}
-
-	builds := 0
-	newBuild, newSig := watchIteration(dirs, newFileSigCache(), now.Add(-time.Hour), sig, func() { builds++ })
-	if builds != 0 {
-		t.Errorf("touch-only event must not rebuild, got %d builds", builds)
-	}
-	if newSig != sig {
-		t.Error("signature must be unchanged for a touch-only event")
-	}
-	if !newBuild.After(now.Add(-time.Hour)) {
-		t.Error("lastBuild must advance after a touch-only event")
-	}
-}
-
 // failWriter always fails, simulating a full disk during archive finalization.
 type failWriter struct{}
 
@@ -128,7 +128,7 @@ func runMCP(args []string) int {
 	serveMCPPreview(cfg, logf)
 	// --watch was parsed into cfg by parseFlags and read by nothing in this
 	// path, so a file changed outside MCP never rebuilt and never reloaded the
-	// preview. It now drives the same polling loop the serve path runs, through
+	// preview. It now drives the same watch the serve path runs, through
 	// the same serialised rebuilder (#184).
 	startMCPWatch(rebuilder, rest, configPathOf(rest), logf)
 
@@ -271,7 +271,7 @@ func serveMCPPreview(cfg *config.Config, logf func(string, ...any)) {
 	if !cfg.HTTP {
 		return
 	}
-	setReloadHub(newLiveReloadHub()) // each MCP rebuild refreshes the open tab
+	startReloadHub(cfg.OutputDir) // each MCP rebuild refreshes what it changed in the open tab
 	// The port is claimed before the address is logged, so a busy 8888 shifts
 	// the announcement too instead of pointing the agent at someone else's
 	// server (#135).
@@ -19,11 +19,10 @@ package main
 //     it. A rebuild racing a rebuild over one output directory is a preview
 //     that intermittently serves half a site.
 //
-//  2. With `--watch`, the mcp path runs the same polling loop the serve path
-//     runs — the same watchIteration, the same fileSigCache, the same
-//     content-signature gate that skips touch-only events — and calls the same
-//     serialised rebuild. Outside edits now reload the preview exactly as MCP
-//     edits do.
+//  2. With `--watch`, the mcp path runs the same watch the serve path runs —
+//     the same siteWatch over internal/watch, the same content gate that skips
+//     touch-only events — and calls the same serialised rebuild. Outside edits
+//     now reload the preview exactly as MCP edits do.
 //
 // The alternative was `--watch-runner="ssg mcp --listen=…"`: one command line,
 // still two processes, two independent builders over one output tree, and
@@ -32,7 +31,6 @@ package main
 import (
 	"strings"
 	"sync"
-	"time"
 
 	"github.com/spagu/ssg/internal/config"
 	"github.com/spagu/ssg/internal/generator"
@@ -60,9 +58,9 @@ func newMCPRebuilder(genCfg generator.Config, cfg *config.Config) *mcpRebuilder
 	return &mcpRebuilder{genCfg: genCfg, cfg: cfg, buildFn: build}
 }
 
-// rebuild runs one build and pushes the result to an open `--http` preview: a
-// reload on success, the error overlay otherwise (GO-090). A no-op push without
-// --http.
+// rebuild runs one build and pushes the result to an open `--http` preview:
+// what changed on success (a stylesheet swap, the pages to reload), the error
+// overlay otherwise (GO-090). A no-op push without --http.
 //
 // Output is captured rather than printed. stdout belongs to the JSON-RPC
 // channel, so build noise reaching it would corrupt the protocol; the captured
@@ -70,9 +68,16 @@ func newMCPRebuilder(genCfg generator.Config, cfg *config.Config) *mcpRebuilder
 // os.Stdout process-wide, which is only safe because this whole method is
 // serialised.
 func (r *mcpRebuilder) rebuild() (string, error) {
+	return r.rebuildChanged(nil)
+}
+
+// rebuildChanged is rebuild for a watcher that knows which files changed; the
+// build re-hashes them (see rebuildOnChange).
+func (r *mcpRebuilder) rebuildChanged(changed []string) (string, error) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 	genCfg, cfg := r.genCfg, r.cfg
+	genCfg.ChangedPaths = changed
 	quiet := *cfg
 	quiet.Quiet = true
 	out, err := captureStdout(func() error { return r.buildFn(genCfg, &quiet) })
@@ -82,7 +87,7 @@ func (r *mcpRebuilder) rebuild() (string, error) {
 		// The generated _redirects/_headers moved with the build; the preview
 		// re-reads them so it keeps serving what the platform would (#181).
 		republishOutputRules(cfg)
-		notifyReload()
+		notifyRebuilt(cfg.OutputDir)
 	}
 	return out, err
 }
@@ -103,74 +108,54 @@ func (r *mcpRebuilder) config() *config.Config {
 	return r.cfg
 }
 
-// mcpWatchInterval is the poll period, matching the serve path's loop.
-const mcpWatchInterval = 1 * time.Second
-
-// mcpWatcher polls the site's inputs on behalf of `ssg mcp --watch`. It holds
-// the same state runWatchLoop holds — one signature cache reused across polls,
-// the last build time, the last content signature, the config file's own hash —
-// because it is the same watch, driven from a different command.
+// mcpWatcher watches the site's inputs on behalf of `ssg mcp --watch`. It
+// holds the same siteWatch runWatchLoop holds, because it is the same watch,
+// driven from a different command.
 type mcpWatcher struct {
 	rebuilder  *mcpRebuilder
 	args       []string // the arguments left after `ssg mcp`'s own flags
 	configPath string
 	logf       func(string, ...any)
-	dirs       []string
-	sigCache   *fileSigCache
-	lastBuild  time.Time
-	lastSig    string
-	configSig  string
+	sw         *siteWatch
 	stop       chan struct{}
 	done       chan struct{}
-	// interval is the poll period. A field rather than a read of the constant
-	// so a test can drive the loop at its own pace without writing to state
-	// another test's watcher is still reading.
-	interval time.Duration
 }
 
 // newMCPWatcher primes a watcher from the current state of the tree, so the
-// first poll reports changes made after the process started rather than
+// first batch reports changes made after the process started rather than
 // rebuilding immediately on everything that already existed.
 func newMCPWatcher(r *mcpRebuilder, args []string, configPath string, logf func(string, ...any)) *mcpWatcher {
-	cfg := r.config()
-	dirs := watchDirs(cfg)
-	cache := newFileSigCache()
 	return &mcpWatcher{
 		rebuilder:  r,
 		args:       args,
 		configPath: configPath,
 		logf:       logf,
-		dirs:       dirs,
-		sigCache:   cache,
-		lastBuild:  time.Now(),
-		lastSig:    cache.signature(dirs),
-		configSig:  fileSignature(configPath),
+		sw:         newSiteWatch(r.config(), configPath),
 		stop:       make(chan struct{}),
 		done:       make(chan struct{}),
-		interval:   mcpWatchInterval,
 	}
 }
 
-// poll runs one iteration and reports whether it rebuilt.
+// handle acts on one batch of changed paths and reports whether it rebuilt.
 //
 // The config file is an input of its own, exactly as it is in the serve path
 // (#70): an edit reloads it, republishes the endpoints onto the running preview
 // (#180) and rebuilds, so a watcher never keeps building from the configuration
 // it started with.
-func (w *mcpWatcher) poll() bool {
-	if sig := fileSignature(w.configPath); sig != w.configSig {
-		w.configSig = sig
+func (w *mcpWatcher) handle(batch []string) bool {
+	changed, configEdited := w.sw.sift(batch)
+	if configEdited {
 		w.reload()
 		_, _ = w.rebuilder.rebuild()
-		w.lastBuild = time.Now()
+		w.sw.followIncludes(w.rebuilder.config())
 		return true
 	}
-	rebuilt := false
-	w.lastBuild, w.lastSig = watchIteration(w.dirs, w.sigCache, w.lastBuild, w.lastSig, func() {
-		rebuilt = true
-		_, _ = w.rebuilder.rebuild()
-	})
-	return rebuilt
+	if len(changed) == 0 {
+		return false
+	}
+	_, _ = w.rebuilder.rebuildChanged(changed)
+	w.sw.followIncludes(w.rebuilder.config())
+	return true
 }
 
 // reload adopts an edited config file, or keeps the last good one when the edit
@@ -189,37 +174,36 @@ func (w *mcpWatcher) reload() {
 		return
 	}
 	w.rebuilder.reconfigure(newGen, newCfg)
-	w.dirs = watchDirs(newCfg)
-	w.sigCache = newFileSigCache()
-	w.lastSig = w.sigCache.signature(w.dirs)
+	w.sw.retarget(newCfg)
 }
 
-// run polls until the watcher is stopped. Called on its own goroutine: `ssg
-// mcp` is already blocked on stdio or parked on the HTTP transport.
+// run handles batches until the watcher is stopped. Called on its own
+// goroutine: `ssg mcp` is already blocked on stdio or parked on the HTTP
+// transport.
 func (w *mcpWatcher) run() {
 	defer close(w.done)
-	ticker := time.NewTicker(w.interval)
-	defer ticker.Stop()
 	for {
 		select {
 		case <-w.stop:
 			return
-		case <-ticker.C:
-			w.poll()
+		case batch := <-w.sw.changes():
+			w.handle(batch)
 		}
 	}
 }
 
-// stopWatching ends the loop and waits for the poll in flight to finish. The
-// CLI never calls it — the watcher lives as long as the process it belongs to —
-// but a loop with no way out is a loop no test can start without leaking it
-// into every test that follows, and a rebuild still running after its owner
-// went away is the same shared-output hazard this file exists to remove.
+// stopWatching ends the loop, waits for the rebuild in flight to finish and
+// releases the watcher. The CLI never calls it — the watcher lives as long as
+// the process it belongs to — but a loop with no way out is a loop no test can
+// start without leaking it into every test that follows, and a rebuild still
+// running after its owner went away is the same shared-output hazard this file
+// exists to remove.
 //
 // Only valid on a watcher whose run() was started; it waits for that goroutine.
 func (w *mcpWatcher) stopWatching() {
 	close(w.stop)
 	<-w.done
+	w.sw.close()
 }
 
 // startMCPWatch begins watching the filesystem when `--watch` was given, and
@@ -233,7 +217,8 @@ func startMCPWatch(r *mcpRebuilder, args []string, configPath string, logf func(
 		return nil
 	}
 	w := newMCPWatcher(r, args, configPath, logf)
-	logf("   👀 watching %s — edits from outside MCP rebuild and reload too", strings.Join(watchedInputs(r.config(), configPath), ", "))
+	logf("   👀 watching %s (%s) — edits from outside MCP rebuild and reload too",
+		strings.Join(watchedInputs(r.config(), configPath), ", "), w.sw.describe())
 	go w.run()
 	return w
 }
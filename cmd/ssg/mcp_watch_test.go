@@ -127,13 +127,8 @@ func watchedSite(t *testing.T) (*config.Config, string) {
 	return cfg, configPath
 }
 
-// writeEdit writes content and stamps the file plainly in the future.
-//
-// Not a convenience: Linux stamps file times from the coarse kernel clock while
-// time.Now() reads the fine one, so a file written microseconds after the
-// watcher recorded its start can carry an mtime a millisecond *before* it. The
-// real loop sleeps a second between polls and never notices; a test that writes
-// and polls immediately notices about one run in three.
+// writeEdit writes content and stamps the file plainly in the future, so the
+// polling fallback sees the edit even within the coarse kernel clock's tick.
 func writeEdit(t *testing.T, path, content string) {
 	t.Helper()
 	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
@@ -145,25 +140,35 @@ func writeEdit(t *testing.T, path, content string) {
 	}
 }
 
+// newTestWatcher is newMCPWatcher for a test that feeds batches by hand; the
+// real watcher underneath is released with the test.
+func newTestWatcher(t *testing.T, cfg *config.Config, configPath string, builds *atomic.Int32) *mcpWatcher {
+	t.Helper()
+	w := newMCPWatcher(countingRebuilder(cfg, builds), nil, configPath, func(string, ...any) {})
+	t.Cleanup(func() { w.sw.close() })
+	return w
+}
+
 // TestAnEditMadeOutsideMCPRebuildsTheSite is the reported gap: a human editor
 // beside the agent, an rsync, a CMS export. None of them went through MCP, and
 // none of them used to rebuild anything.
 func TestAnEditMadeOutsideMCPRebuildsTheSite(t *testing.T) {
 	cfg, configPath := watchedSite(t)
 	var builds atomic.Int32
-	w := newMCPWatcher(countingRebuilder(cfg, &builds), nil, configPath, func(string, ...any) {})
+	w := newTestWatcher(t, cfg, configPath, &builds)
+	page := filepath.Join("content", "page.md")
 
-	if w.poll() {
+	if w.handle(nil) {
 		t.Fatal("a quiet tree must not rebuild")
 	}
-	writeEdit(t, filepath.Join("content", "page.md"), "# Two\n")
-	if !w.poll() {
+	writeEdit(t, page, "# Two\n")
+	if !w.handle([]string{page}) {
 		t.Fatal("an edit from outside MCP must rebuild")
 	}
 	if builds.Load() != 1 {
 		t.Fatalf("builds = %d, want exactly one", builds.Load())
 	}
-	if w.poll() {
+	if w.handle([]string{page}) {
 		t.Error("the same edit must not rebuild twice")
 	}
 }
@@ -173,13 +178,14 @@ func TestAnEditMadeOutsideMCPRebuildsTheSite(t *testing.T) {
 func TestATouchDoesNotRebuild(t *testing.T) {
 	cfg, configPath := watchedSite(t)
 	var builds atomic.Int32
-	w := newMCPWatcher(countingRebuilder(cfg, &builds), nil, configPath, func(string, ...any) {})
+	w := newTestWatcher(t, cfg, configPath, &builds)
 
+	page := filepath.Join("content", "page.md")
 	later := time.Now().Add(2 * time.Second)
-	if err := os.Chtimes(filepath.Join("content", "page.md"), later, later); err != nil {
+	if err := os.Chtimes(page, later, later); err != nil {
 		t.Fatal(err)
 	}
-	if w.poll() || builds.Load() != 0 {
+	if w.handle([]string{page}) || builds.Load() != 0 {
 		t.Fatalf("mtime moved but bytes did not: builds = %d", builds.Load())
 	}
 }
@@ -195,7 +201,7 @@ func TestAConfigEditReachesTheRunningPreview(t *testing.T) {
 	h := liveEndpointHandler(static)
 
 	var builds atomic.Int32
-	w := newMCPWatcher(countingRebuilder(cfg, &builds), nil, configPath, func(string, ...any) {})
+	w := newTestWatcher(t, cfg, configPath, &builds)
 
 	if rec := getPath(h, "/go/latest"); rec.Body.String() != "static" {
 		t.Fatalf("before: %q", rec.Body)
@@ -206,7 +212,7 @@ func TestAConfigEditReachesTheRunningPreview(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	if !w.poll() {
+	if !w.handle([]string{configPath}) {
 		t.Fatal("a config edit must rebuild")
 	}
 	if got := getPath(h, "/go/latest").Header().Get("Location"); got != "/blog/" {
@@ -215,8 +221,8 @@ func TestAConfigEditReachesTheRunningPreview(t *testing.T) {
 	if w.rebuilder.config().ContentDir != "docs" {
 		t.Errorf("content_dir = %q, want the reloaded value", w.rebuilder.config().ContentDir)
 	}
-	if len(w.dirs) == 0 || w.dirs[0] != "docs" {
-		t.Errorf("the watcher must follow the new content dir, watching %v", w.dirs)
+	if len(w.sw.dirs) == 0 || w.sw.dirs[0] != "docs" {
+		t.Errorf("the watcher must follow the new content dir, watching %v", w.sw.dirs)
 	}
 }
 
@@ -225,12 +231,12 @@ func TestAConfigEditReachesTheRunningPreview(t *testing.T) {
 func TestAHalfSavedConfigKeepsTheLastGoodOne(t *testing.T) {
 	cfg, configPath := watchedSite(t)
 	var builds atomic.Int32
-	w := newMCPWatcher(countingRebuilder(cfg, &builds), nil, configPath, func(string, ...any) {})
+	w := newTestWatcher(t, cfg, configPath, &builds)
 
 	if err := os.WriteFile(configPath, []byte("content_dir: [unclosed\n"), 0o600); err != nil {
 		t.Fatal(err)
 	}
-	if !w.poll() {
+	if !w.handle([]string{configPath}) {
 		t.Fatal("a config change must still be acted on")
 	}
 	if got := w.rebuilder.config().ContentDir; got != "content" {
@@ -265,14 +271,13 @@ func TestWatchIsOnlyStartedWhenAsked(t *testing.T) {
 	}
 }
 
-// TestTheLoopKeepsPolling: run() is a goroutine in production, so the only
-// thing worth asserting is that it does poll — driven here on a short interval
-// rather than a sleeping test.
-func TestTheLoopKeepsPolling(t *testing.T) {
+// TestTheLoopPicksEditsUp: run() is a goroutine in production, so the only
+// thing worth asserting is that a real edit reaches it — through inotify here,
+// through the poller where there is none.
+func TestTheLoopPicksEditsUp(t *testing.T) {
 	cfg, configPath := watchedSite(t)
 	var builds atomic.Int32
 	w := newMCPWatcher(countingRebuilder(cfg, &builds), nil, configPath, func(string, ...any) {})
-	w.interval = time.Millisecond // set before the goroutine reads it
 	t.Cleanup(w.stopWatching)
 	go w.run()
 
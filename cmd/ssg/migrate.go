@@ -228,7 +228,7 @@ func migrateLive(provider migrate.Provider, rawURL string, opts migrate.Options,
 	runInitialBuild(genCfg, cfg)
 	if cfg.HTTP {
 		if autoReloadEnabled(cfg) {
-			setReloadHub(newLiveReloadHub())
+			startReloadHub(cfg.OutputDir)
 		}
 		// Claimed in the foreground: the address printed below must be the one
 		// the server took, port walk included (#135).
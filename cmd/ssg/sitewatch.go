@@ -0,0 +1,186 @@
+package main
+
+// The site watch shared by `ssg --watch` and `ssg mcp --watch`.
+//
+// Both used to poll: wake every second, walk content, templates and data, and
+// compare one content signature with the last. internal/watch now names the
+// changed files — from inotify where it can, from the same poll where it
+// cannot — and this type turns a batch of names into the two decisions a watch
+// loop makes: did the config file change, and which site files changed bytes.
+// The changed set goes on to the build, which re-hashes exactly those inputs.
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spagu/ssg/internal/config"
+	"github.com/spagu/ssg/internal/generator"
+	"github.com/spagu/ssg/internal/watch"
+)
+
+// siteWatch watches one site's inputs and its config file. Single-goroutine
+// use: the loop that owns it.
+type siteWatch struct {
+	configPath string
+	dirs       []string
+	poll       bool
+	w          *watch.Watcher
+	// sigCache holds every watched file's content hash, so a reported path is
+	// a change only if its bytes moved (PLAT-006).
+	sigCache  *fileSigCache
+	configSig string
+	// includes are the files the last build included from outside dirs,
+	// watched as roots of their own (see followIncludes).
+	includes []string
+}
+
+// newSiteWatch starts watching from the current state of the tree: the first
+// batch reports changes made after this call, not everything that existed.
+func newSiteWatch(cfg *config.Config, configPath string) *siteWatch {
+	s := &siteWatch{configPath: configPath, configSig: fileSignature(configPath)}
+	s.retarget(cfg)
+	return s
+}
+
+// retarget follows a reloaded configuration to its (possibly moved) inputs.
+func (s *siteWatch) retarget(cfg *config.Config) {
+	if s.w != nil {
+		s.w.Close()
+	}
+	s.dirs = watchDirs(cfg)
+	s.includes = outsideDirs(includedFiles(), s.dirs)
+	s.dirs = append(s.dirs, s.includes...)
+	s.poll = cfg.WatchPoll
+	s.sigCache = newFileSigCache()
+	s.sigCache.signature(s.dirs) // prime: a later batch compares against this
+	roots := append([]string{}, s.dirs...)
+	if s.configPath != "" {
+		roots = append(roots, s.configPath)
+	}
+	s.w = watch.New(roots, watch.Options{Poll: s.poll})
+}
+
+// followIncludes retargets when the last build included a different set of
+// files from outside the watched directories: a snippet under docs/ or a
+// source file shown on a page is an input like any content file, and an edit
+// to it must rebuild. Called after every rebuild.
+func (s *siteWatch) followIncludes(cfg *config.Config) {
+	if !slices.Equal(outsideDirs(includedFiles(), watchDirs(cfg)), s.includes) {
+		s.retarget(cfg)
+	}
+}
+
+// outsideDirs keeps the files not already under one of dirs.
+func outsideDirs(files, dirs []string) []string {
+	var out []string
+	for _, f := range files {
+		inside := false
+		for _, d := range dirs {
+			if rel, err := filepath.Rel(d, f); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				inside = true
+				break
+			}
+		}
+		if !inside {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// changes delivers the watcher's batches. Re-read after retarget.
+func (s *siteWatch) changes() <-chan []string { return s.w.Changes() }
+
+// sift splits a batch into changed site files and whether the config file's
+// bytes changed. Either may be empty: a touch, an editor's swap file, a save
+// that rewrote identical content.
+func (s *siteWatch) sift(batch []string) (changed []string, configEdited bool) {
+	var site []string
+	for _, p := range batch {
+		if s.configPath != "" && filepath.Clean(p) == filepath.Clean(s.configPath) {
+			if sig := fileSignature(s.configPath); sig != s.configSig {
+				s.configSig = sig
+				configEdited = true
+			}
+			continue
+		}
+		site = append(site, p)
+	}
+	return s.sigCache.changedOf(site), configEdited
+}
+
+// describe says how changes are detected, for the startup line, and why not
+// with events when inotify was wanted but is unavailable.
+func (s *siteWatch) describe() string {
+	if err := s.w.FallbackReason(); err != nil && !s.poll {
+		return fmt.Sprintf("%s — %v", s.w.Backend(), err)
+	}
+	return s.w.Backend()
+}
+
+func (s *siteWatch) close() { s.w.Close() }
+
+// changedSummary names a rebuild's trigger in one line: the first few paths
+// and a count of the rest.
+func changedSummary(changed []string) string {
+	const shown = 3
+	if len(changed) <= shown {
+		return strings.Join(changed, ", ")
+	}
+	return fmt.Sprintf("%s (+%d more)", strings.Join(changed[:shown], ", "), len(changed)-shown)
+}
+
+// nextStateChange is when the last build said a scheduled page publishes or
+// expires. A watch loop rebuilds then, edits or not — which is how `ssg daemon`
+// projects pick up an embargo lifting.
+var nextStateChange struct {
+	sync.Mutex
+	at time.Time
+}
+
+func noteStateChange(c generator.StateChange, ok bool) {
+	nextStateChange.Lock()
+	defer nextStateChange.Unlock()
+	nextStateChange.at = time.Time{}
+	// Well in the past only when SOURCE_DATE_EPOCH pinned the build's clock:
+	// that build would report the same change again, so it is not scheduled.
+	if ok && time.Until(c.At) > -time.Minute {
+		nextStateChange.at = c.At
+	}
+}
+
+// stateChangeDue fires when the recorded state change arrives; nil (never)
+// when none is scheduled. Re-read on every loop turn: each build replaces it.
+func stateChangeDue() <-chan time.Time {
+	nextStateChange.Lock()
+	at := nextStateChange.at
+	nextStateChange.Unlock()
+	if at.IsZero() {
+		return nil
+	}
+	// A second's slack: the rebuild must see the moment as passed.
+	return time.After(time.Until(at) + time.Second)
+}
+
+// watchedIncludes is what the last build included, relative to the project
+// root. Replaced by every build, read by followIncludes.
+var watchedIncludes struct {
+	sync.Mutex
+	files []string
+}
+
+func noteIncludes(files []string) {
+	watchedIncludes.Lock()
+	defer watchedIncludes.Unlock()
+	watchedIncludes.files = files
+}
+
+func includedFiles() []string {
+	watchedIncludes.Lock()
+	defer watchedIncludes.Unlock()
+	return append([]string(nil), watchedIncludes.files...)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spagu/ssg/internal/generator"
+)
+
+// TestSiteWatchSiftsConfigFromContent: a batch naming the config file reports
+// an edit only when its bytes changed, and never as a site file.
+func TestSiteWatchSiftsConfigFromContent(t *testing.T) {
+	cfg, configPath := watchedSite(t)
+	sw := newSiteWatch(cfg, configPath)
+	t.Cleanup(sw.close)
+
+	page := filepath.Join("content", "page.md")
+	if err := os.WriteFile(page, []byte("# Two\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	changed, configEdited := sw.sift([]string{configPath, page})
+	if configEdited || len(changed) != 1 || changed[0] != page {
+		t.Errorf("sift = %v, %v; want the page and an untouched config", changed, configEdited)
+	}
+	if err := os.WriteFile(configPath, []byte("domain: example.org\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if changed, configEdited = sw.sift([]string{configPath}); !configEdited || len(changed) != 0 {
+		t.Errorf("config edit: %v, %v", changed, configEdited)
+	}
+}
+
+// TestSiteWatchKeepsEditsMadeDuringABuild is GO-025 for the event watcher: a
+// file saved while a rebuild runs arrives as the next batch, not never.
+func TestSiteWatchKeepsEditsMadeDuringABuild(t *testing.T) {
+	cfg, configPath := watchedSite(t)
+	sw := newSiteWatch(cfg, configPath)
+	t.Cleanup(sw.close)
+
+	next := func() []string {
+		t.Helper()
+		select {
+		case batch := <-sw.changes():
+			changed, _ := sw.sift(batch)
+			return changed
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no batch (%s)", sw.describe())
+			return nil
+		}
+	}
+	first := filepath.Join("content", "page.md")
+	writeEdit(t, first, "# Two\n")
+	if got := next(); len(got) != 1 || got[0] != first {
+		t.Fatalf("first batch = %v", got)
+	}
+	// "Building": nobody reads the channel while this edit lands.
+	second := filepath.Join("content", "other.md")
+	writeEdit(t, second, "# Other\n")
+	time.Sleep(1500 * time.Millisecond)
+	if got := next(); len(got) != 1 || got[0] != second {
+		t.Errorf("edit made mid-build = %v, want [%s]", got, second)
+	}
+}
+
+// TestStateChangeDueFollowsTheLastBuild: a scheduled publish fires the watch
+// loop once it arrives; a build with nothing scheduled, or one whose pinned
+// clock reports a change long past, leaves the loop to file events.
+func TestStateChangeDueFollowsTheLastBuild(t *testing.T) {
+	t.Cleanup(func() { noteStateChange(generator.StateChange{}, false) })
+
+	noteStateChange(generator.StateChange{At: time.Now().Add(-time.Second)}, true)
+	select {
+	case <-stateChangeDue():
+	case <-time.After(5 * time.Second):
+		t.Fatal("a due state change did not fire")
+	}
+
+	noteStateChange(generator.StateChange{At: time.Now().Add(-24 * time.Hour)}, true)
+	if stateChangeDue() != nil {
+		t.Error("a change from a pinned build clock must not be scheduled")
+	}
+	noteStateChange(generator.StateChange{}, false)
+	if stateChangeDue() != nil {
+		t.Error("nothing scheduled must mean no timer")
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
@@ -93,3 +95,66 @@ func fileSignature(path string) string {
 	}
 	return hex.EncodeToString(h.Sum(nil))
 }
+
+// changedOf narrows a watcher's report to the files whose bytes really
+// changed, keeping the touch-only gate (PLAT-006) now that the watcher names
+// paths instead of answering yes/no. A reported file is re-hashed even when its
+// size and mtime match the cache — the watcher saw a write. A reported
+// directory (a new tree, or "something under here" after an event overflow) is
+// compared file by file, including files that vanished from under it.
+func (c *fileSigCache) changedOf(paths []string) []string {
+	var changed []string
+	seen := map[string]bool{}
+	report := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			changed = append(changed, p)
+		}
+	}
+	check := func(path string, info os.FileInfo, force bool) {
+		old, had := c.entries[path]
+		if force {
+			delete(c.entries, path)
+		}
+		sum, err := c.hashFor(path, info)
+		if err != nil {
+			return
+		}
+		if !had || sum != old.hash {
+			report(path)
+		}
+	}
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		switch {
+		case err != nil:
+			// Gone: a change if we knew it, or anything under it.
+			for known := range c.entries {
+				if known == p || strings.HasPrefix(known, p+string(filepath.Separator)) {
+					delete(c.entries, known)
+					report(known)
+				}
+			}
+		case info.IsDir():
+			present := map[string]bool{}
+			_ = filepath.Walk(p, func(path string, fi os.FileInfo, werr error) error { // #nosec G703 -- best-effort watch signature
+				if werr != nil || fi.IsDir() {
+					return nil
+				}
+				present[path] = true
+				check(path, fi, false)
+				return nil
+			})
+			for known := range c.entries {
+				if strings.HasPrefix(known, p+string(filepath.Separator)) && !present[known] {
+					delete(c.entries, known)
+					report(known)
+				}
+			}
+		default:
+			check(p, info, true)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
@@ -134,3 +134,53 @@ func TestContentSignatureWrapper(t *testing.T) {
 		t.Error("wrapper must return a signature")
 	}
 }
+
+// TestChangedOfNarrowsAWatcherReport: the watcher names paths; only the ones
+// whose bytes moved are changes. A same-size, same-mtime rewrite the watcher
+// reported is still caught, and a reported directory is compared file by file,
+// deletions included.
+func TestChangedOfNarrowsAWatcherReport(t *testing.T) {
+	dir := t.TempDir()
+	a, b := filepath.Join(dir, "a.md"), filepath.Join(dir, "sub", "b.md")
+	for _, p := range []string{a, b} {
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte("one"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	c := newFileSigCache()
+	c.signature([]string{dir})
+
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(a, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.changedOf([]string{a}); len(got) != 0 {
+		t.Errorf("touch reported as a change: %v", got)
+	}
+
+	info, _ := os.Stat(a)
+	if err := os.WriteFile(a, []byte("two"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(a, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.changedOf([]string{a}); len(got) != 1 || got[0] != a {
+		t.Errorf("same-size same-mtime edit = %v, want [%s]", got, a)
+	}
+
+	c3 := filepath.Join(dir, "sub", "c.md")
+	if err := os.WriteFile(c3, []byte("new"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(b); err != nil {
+		t.Fatal(err)
+	}
+	got := c.changedOf([]string{filepath.Join(dir, "sub")})
+	if len(got) != 2 || got[0] != b || got[1] != c3 {
+		t.Errorf("directory report = %v, want the deleted and the new file", got)
+	}
+}
@@ -53,11 +53,32 @@ type MddbConfig struct {
 // metadata does not already define it. Per-file frontmatter always wins.
 // `name` is optional and used only so include files can contribute a source by
 // name without clobbering the others (GO-076).
+//
+// `type: git` reads the source from a repository instead of `path`: `url`,
+// `ref` (branch, tag or commit), `subdir`, and `auth` for a private https
+// remote, whose secrets must reference environment variables like a worker
+// source's. `content_type` says whether its entries are pages or posts.
 type ContentSource struct {
 	Name     string `yaml:"name" toml:"name" json:"name"`
 	Path     string `yaml:"path" toml:"path" json:"path"`
 	Type     string `yaml:"type" toml:"type" json:"type"`
 	Category string `yaml:"category" toml:"category" json:"category"`
+
+	URL         string     `yaml:"url" toml:"url" json:"url"`
+	Ref         string     `yaml:"ref" toml:"ref" json:"ref"`
+	Subdir      string     `yaml:"subdir" toml:"subdir" json:"subdir"`
+	Auth        WorkerAuth `yaml:"auth" toml:"auth" json:"auth"`
+	ContentType string     `yaml:"content_type" toml:"content_type" json:"content_type"`
+}
+
+// Version is one `versions:` entry: a git ref whose content is built as a
+// copy of the site under /<name>/. An entry without a ref stands for the
+// working tree, which is built at the site root as usual.
+type Version struct {
+	Name   string `yaml:"name" toml:"name" json:"name"`
+	Ref    string `yaml:"ref" toml:"ref" json:"ref"`
+	Title  string `yaml:"title" toml:"title" json:"title"`
+	Latest bool   `yaml:"latest" toml:"latest" json:"latest"`
 }
 
 // RobotsRule is one User-agent block in a custom robots.txt (GO-089). Allow and
@@ -106,7 +127,7 @@ type Config struct {
 	Mddb MddbConfig `yaml:"mddb" toml:"mddb" json:"mddb"`
 
 	// Template Engine
-	Engine      string `yaml:"engine" toml:"engine" json:"engine"`                   // go, pongo2, mustache, handlebars
+	Engine      string `yaml:"engine" toml:"engine" json:"engine"`                   // go, pongo2, mustache, handlebars, liquid
 	OnlineTheme string `yaml:"online_theme" toml:"online_theme" json:"online_theme"` // URL to download theme
 
 	// Server & Development
@@ -114,6 +135,10 @@ type Config struct {
 	Host  string `yaml:"host" toml:"host" json:"host"` // Dev-server bind address (default: 127.0.0.1; use 0.0.0.0 to expose)
 	Port  int    `yaml:"port" toml:"port" json:"port"`
 	Watch bool   `yaml:"watch" toml:"watch" json:"watch"`
+	// WatchPoll makes --watch poll the tree every second instead of listening
+	// for inotify events — for mounts that do not deliver them (NFS, some
+	// container bind mounts). Platforms without inotify always poll.
+	WatchPoll bool `yaml:"watch_poll" toml:"watch_poll" json:"watch_poll"`
 	// AutoReload injects a live-reload client into served HTML so the browser
 	// refreshes itself after each successful rebuild. On by default in
 	// --http --watch; --no-auto-reload (or auto_reload: false) opts out. Pointer
@@ -174,6 +199,13 @@ type Config struct {
 	// WordPress publishes and links to from every byline (#146). Opt-in: a
 	// site that never had these URLs should not grow them because it upgraded.
 	DateArchives bool `yaml:"date_archives" toml:"date_archives" json:"date_archives"`
+	// Authors paginates the author archives and gives each author its own
+	// feeds; profiles come from metadata.json users and data/authors/*.yaml.
+	Authors models.AuthorsConfig `yaml:"authors" toml:"authors" json:"authors"`
+	// Menus adds entries no page can declare — external links, section
+	// headings — to the navigation, keyed by menu name; they merge with the
+	// imported menus and the pages' frontmatter `menu:` (menus.go).
+	Menus map[string][]models.MenuEntry `yaml:"menus" toml:"menus" json:"menus"`
 	// TypeArchives declares which content types get a listing at /<type>/ — the
 	// archive WordPress renders from has_archive and links to from its own menu,
 	// which is not a document anywhere and so cannot be exported (#165).
@@ -272,6 +304,15 @@ type Config struct {
 	// prose, inline code and code blocks are left alone, and source .md files
 	// are not modified. Opt-in, at publish time (GO-075).
 	StripMdLinkText bool `yaml:"strip_md_link_text" toml:"strip_md_link_text" json:"strip_md_link_text"`
+	// Wikilinks resolves Obsidian-style [[Page]], [[Page#Heading|label]] and
+	// ![[embed]] syntax against page titles, slugs and file names, fills each
+	// page's .Backlinks and writes graph.json. Opt-in: `[[…]]` is literal text
+	// in plain Markdown.
+	Wikilinks bool `yaml:"wikilinks" toml:"wikilinks" json:"wikilinks"`
+	// Callouts renders GitHub/Obsidian `> [!NOTE]` blockquotes and MkDocs
+	// `!!! tip` admonitions as <aside class="callout …">. Default ON — without it
+	// the marker shows as text — so a pointer: set callouts: false to opt out.
+	Callouts *bool `yaml:"callouts" toml:"callouts" json:"callouts"`
 	// MarkdownPublish emits a clean Markdown copy of every page next to its
 	// index.html (index.md), links it from the page <head> as a
 	// text/markdown alternate, and writes an llms.txt index at the site root.
@@ -417,6 +458,11 @@ type Config struct {
 	// (#86).
 	Feeds []models.FeedSpec `yaml:"feeds" toml:"feeds" json:"feeds"`
 
+	// PageGenerators turn every row of a data file or external source into a
+	// page of its own — a product per CSV line — with a URL, sitemap entry and
+	// search record. Empty by default.
+	PageGenerators []models.PageGenerator `yaml:"page_generators" toml:"page_generators" json:"page_generators"`
+
 	// FeedAutodiscovery injects a <link rel="alternate"> for every published feed
 	// into every page. Default true — set false to place them yourself, when the
 	// theme wants control over their order, titles, or which feeds are advertised
@@ -487,6 +533,27 @@ type Config struct {
 	// is the fix.
 	CheckMarkup string `yaml:"check_markup" toml:"check_markup" json:"check_markup"`
 
+	// CheckProse runs the `lint:` rules over every page's source during the
+	// build: "" (off), "warn" or "strict". Off by default — unlike check_markup
+	// its findings are judgement calls — and `ssg lint` runs the same rules on
+	// demand.
+	CheckProse string `yaml:"check_prose" toml:"check_prose" json:"check_prose"`
+
+	// Lint configures the prose and structure rules of check_prose and
+	// `ssg lint`: which are off, their thresholds, banned words per language,
+	// and overrides per content type.
+	Lint models.LintConfig `yaml:"lint" toml:"lint" json:"lint"`
+
+	// CheckSpelling spell-checks every page's prose against the Hunspell
+	// dictionary for its language and the project word lists under
+	// data_dir/spelling/: "" (off), "warn" or "strict". A language with no
+	// dictionary is skipped with a note, not failed.
+	CheckSpelling string `yaml:"check_spelling" toml:"check_spelling" json:"check_spelling"`
+
+	// Spelling says where each language's dictionary is, when it is not
+	// installed where Hunspell looks.
+	Spelling models.SpellingConfig `yaml:"spelling" toml:"spelling" json:"spelling"`
+
 	// CheckMeta validates rendered page metadata after build: "" (off), "warn" or
 	// "strict". An indexable page must have a non-empty <title> and meta
 	// description; noindex pages are skipped. Catches the silent whole-site
@@ -521,6 +588,12 @@ type Config struct {
 	// cleanly instead of warning and being silently dropped (#74).
 	ContentExclude []string `yaml:"content_exclude" toml:"content_exclude" json:"content_exclude"`
 
+	// ContentDefaults give documents frontmatter they do not set, targeted by
+	// path glob and type. They are the farthest layer of the cascade:
+	// _defaults.yaml files and section `cascade:` keys nearer the document win
+	// over them, and the document's own frontmatter wins over everything.
+	ContentDefaults []models.ContentDefault `yaml:"content_defaults" toml:"content_defaults" json:"content_defaults"`
+
 	// CheckOrphans reports published, indexable pages that nothing links to:
 	// "" (off), "warn" or "strict". Only <a href> counts as a link — every page
 	// links to itself via <link rel="canonical">, so counting all refs would make
@@ -584,6 +657,29 @@ type Config struct {
 	// route and its metadata — for external tooling and typed clients (#62).
 	RouteManifest bool `yaml:"route_manifest" toml:"route_manifest" json:"route_manifest"`
 
+	// Incremental re-renders only the outputs whose recorded inputs changed
+	// since the last build, from a dependency graph kept under .ssg-cache/deps/.
+	// Every --watch rebuild is incremental regardless; this makes a one-shot
+	// build incremental too.
+	Incremental bool `yaml:"incremental" toml:"incremental" json:"incremental"`
+
+	// BuildFuture builds pages whose publish_date (or date) has not arrived;
+	// BuildExpired builds pages whose expiry_date has passed. Both are off by
+	// default: scheduled-out content is left out of pages, listings, feeds,
+	// the sitemap and the search index.
+	BuildFuture  bool `yaml:"build_future" toml:"build_future" json:"build_future"`
+	BuildExpired bool `yaml:"build_expired" toml:"build_expired" json:"build_expired"`
+
+	// Drafts is preview mode: draft, pending and future content is rendered
+	// with a banner and a robots noindex, and left out of everything that
+	// announces pages. A preview build refuses --deploy.
+	Drafts bool `yaml:"drafts" toml:"drafts" json:"drafts"`
+
+	// Profile writes a Chrome trace-event file of the build's phases, template
+	// executions and image-processor work, plus a text summary of the slowest
+	// templates and pages beside it (same name, .txt).
+	Profile string `yaml:"profile" toml:"profile" json:"profile"`
+
 	// DataDir is the directory of data files (*.yaml|*.yml|*.json) loaded into
 	// the .Data.* template namespace (default "data", PLAT-002).
 	DataDir string `yaml:"data_dir" toml:"data_dir" json:"data_dir"`
@@ -614,6 +710,11 @@ type Config struct {
 	// copy. Empty by default, which keeps single-source builds unchanged.
 	ContentSources []ContentSource `yaml:"content_sources" toml:"content_sources" json:"content_sources"`
 
+	// Versions builds the content of other git refs (release tags, branches)
+	// beside the site, each under /<name>/ with the same theme, for versioned
+	// documentation. Empty by default.
+	Versions []Version `yaml:"versions" toml:"versions" json:"versions"`
+
 	// LinkRewrites maps an href prefix in content to its replacement, so links
 	// to repository files that the site never publishes (../examples/, a sample
 	// config) can point at the repository instead of 404ing. Longest matching
@@ -885,9 +986,10 @@ type WorkerConfig struct {
 	Config map[string]interface{} `yaml:"config" toml:"config" json:"config"`
 }
 
-// WorkerAuth authenticates a remote worker Source. Secret fields (token,
-// password, value) must reference an environment variable; a literal is
-// rejected so a credential never lives in the config file.
+// WorkerAuth authenticates a remote worker Source or a git content source.
+// Secret fields (token, password, value) must reference an environment
+// variable; a literal is rejected so a credential never lives in the config
+// file.
 type WorkerAuth struct {
 	Type     string `yaml:"type" toml:"type" json:"type"` // bearer | basic | header
 	Token    string `yaml:"token" toml:"token" json:"token"`
@@ -30,11 +30,12 @@ const (
 	EnginePongo2     = "pongo2"
 	EngineMustache   = "mustache"
 	EngineHandlebars = "handlebars"
+	EngineLiquid     = "liquid"
 )
 
 // AvailableEngines returns list of available engine names
 func AvailableEngines() []string {
-	return []string{EngineGo, EnginePongo2, EngineMustache, EngineHandlebars}
+	return []string{EngineGo, EnginePongo2, EngineMustache, EngineHandlebars, EngineLiquid}
 }
 
 // New creates a new template engine by name
@@ -48,6 +49,8 @@ func New(name string) (Engine, error) {
 		return NewMustacheEngine(), nil
 	case EngineHandlebars, "hbs":
 		return NewHandlebarsEngine(), nil
+	case EngineLiquid, "jekyll":
+		return NewLiquidEngine(), nil
 	default:
 		return nil, fmt.Errorf("unknown template engine: %s (available: %v)", name, AvailableEngines())
 	}
@@ -23,6 +23,8 @@ func TestNew(t *testing.T) {
 		{"mustache engine", "mustache", false},
 		{"handlebars engine", "handlebars", false},
 		{"hbs alias", "hbs", false},
+		{"liquid engine", "liquid", false},
+		{"jekyll alias", "jekyll", false},
 		{"unknown engine", "unknown", true},
 		{"invalid engine", "invalid123", true},
 	}
@@ -49,11 +51,11 @@ func TestNew(t *testing.T) {
 
 func TestAvailableEngines(t *testing.T) {
 	engines := AvailableEngines()
-	if len(engines) != 4 {
-		t.Errorf("expected 4 engines, got %d", len(engines))
+	if len(engines) != 5 {
+		t.Errorf("expected 5 engines, got %d", len(engines))
 	}
 
-	expected := []string{EngineGo, EnginePongo2, EngineMustache, EngineHandlebars}
+	expected := []string{EngineGo, EnginePongo2, EngineMustache, EngineHandlebars, EngineLiquid}
 	for i, e := range expected {
 		if engines[i] != e {
 			t.Errorf("expected %s at index %d, got %s", e, i, engines[i])
@@ -349,6 +351,22 @@ func TestPongo2ContextConversion(t *testing.T) {
 	}
 }
 
+// TestPongo2ContextLiftsStructFields: a struct context (the front page's) is
+// readable field by field, as a map context is, and still as Data.
+func TestPongo2ContextLiftsStructFields(t *testing.T) {
+	tmpl, err := NewPongo2Engine().Parse("test", "{{ Title }}/{{ Data.Title }}", nil)
+	if err != nil {
+		t.Fatalf("failed to parse: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Title string }{"Home"}); err != nil {
+		t.Fatalf("failed to execute: %v", err)
+	}
+	if buf.String() != "Home/Home" {
+		t.Errorf("expected 'Home/Home', got '%s'", buf.String())
+	}
+}
+
 func TestGoEngineParseError(t *testing.T) {
 	engine := NewGoEngine()
 	_, err := engine.Parse("test", "{{invalid syntax", nil)
@@ -138,3 +138,27 @@ func warnUnsupported(engineName string, names []string) {
 	warnHelpersOnce(engineName, fmt.Sprintf("%s engine: %d template helper(s) unavailable: %s",
 		engineName, len(names), strings.Join(names, ", ")))
 }
+
+// ContextMap flattens a render context into top-level names: a map is
+// copied, anything else is wrapped in "Data", and a struct's exported fields
+// are lifted to the top as well, so the front page reads {{ Menus.main }} like
+// every other page does; "Data" keeps meaning the wrapper for templates
+// written to it.
+func ContextMap(data interface{}) map[string]interface{} {
+	ctx := map[string]interface{}{}
+	if m, ok := data.(map[string]interface{}); ok {
+		for k, v := range m {
+			ctx[k] = v
+		}
+		return ctx
+	}
+	if rv := reflect.Indirect(reflect.ValueOf(data)); rv.Kind() == reflect.Struct {
+		for i := 0; i < rv.NumField(); i++ {
+			if f := rv.Type().Field(i); f.IsExported() {
+				ctx[f.Name] = rv.Field(i).Interface()
+			}
+		}
+	}
+	ctx["Data"] = data
+	return ctx
+}
@@ -0,0 +1,453 @@
+package engine
+
+// Liquid, for themes written for Jekyll (or Shopify): a team moving a site
+// over keeps its layouts instead of rewriting each one in another syntax. The
+// Jekyll conventions come with it — a template's `layout:` frontmatter wraps
+// it in _layouts/<name>.html, `{% include file.html key=value %}` reads
+// _includes/, and the Jekyll filters a theme cannot do without are defined
+// here. The Jekyll names for the site's data (site.posts, page.title, …) are
+// the generator's to fill in, since only it knows what they hold.
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/osteele/liquid"
+	"github.com/osteele/liquid/filters"
+	"github.com/osteele/liquid/render"
+	"github.com/osteele/liquid/values"
+	"gopkg.in/yaml.v3"
+)
+
+// LiquidEngine implements Engine using Liquid (osteele/liquid)
+type LiquidEngine struct {
+	eng *liquid.Engine
+
+	mu sync.Mutex
+	// roots are the theme directories _layouts/ and _includes/ are read
+	// from, nearest first: a child theme, then the themes it extends. Without
+	// SetThemeRoots it is the directory of the first file parsed.
+	roots   []string
+	layouts map[string]*LiquidTemplate
+	helpers map[string]bool // bridged helper name → supported (GO-054)
+}
+
+// LiquidTemplate wraps liquid.Template, with the layout it renders into.
+type LiquidTemplate struct {
+	tmpl   *liquid.Template
+	front  map[string]interface{}
+	parent *LiquidTemplate
+}
+
+// liquidBuiltins are the filters Liquid and this engine define themselves; a
+// template helper of the same name does not replace them, as a theme written
+// for Liquid means Liquid's `where` and `sort`, not ssg's.
+var liquidBuiltins = func() map[string]bool {
+	names := filterNames{}
+	filters.AddStandardFilters(names)
+	for name := range jekyllFilters {
+		names[name] = true
+	}
+	return names
+}()
+
+// filterNames records the names a filter set registers.
+type filterNames map[string]bool
+
+// AddFilter implements filters.FilterDictionary.
+func (n filterNames) AddFilter(name string, _ any) { n[name] = true }
+
+// jekyllFilters are Jekyll's own filters that need nothing from the site.
+// relative_url, absolute_url, markdownify and slugify do, and arrive with the
+// generator's template helpers.
+var jekyllFilters = map[string]interface{}{
+	"where":               jekyllWhere,
+	"date_to_xmlschema":   func(t time.Time) string { return t.Format(time.RFC3339) },
+	"date_to_rfc822":      func(t time.Time) string { return t.Format(time.RFC1123Z) },
+	"date_to_string":      func(t time.Time) string { return t.Format("02 Jan 2006") },
+	"date_to_long_string": func(t time.Time) string { return t.Format("02 January 2006") },
+	"xml_escape": func(s string) string {
+		var b strings.Builder
+		_ = xml.EscapeText(&b, []byte(s))
+		return b.String()
+	},
+	"jsonify": func(v any) (string, error) {
+		out, err := json.Marshal(v)
+		return string(out), err
+	},
+	"number_of_words": func(s string) int { return len(strings.Fields(s)) },
+}
+
+// jekyllWhere is Jekyll's where, which Liquid's own does not match: a list
+// property matches when it holds the value, so `site.posts | where:
+// "categories", "Guides"` finds every post filed under Guides, and values
+// compare as text, so 2024 finds "2024".
+func jekyllWhere(items []any, key string, target any) []any {
+	want := fmt.Sprint(target)
+	prop := values.ValueOf(key)
+	var out []any
+	for _, item := range items {
+		v := values.ValueOf(item).PropertyValue(prop).Interface()
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array {
+			for i := 0; i < rv.Len(); i++ {
+				if fmt.Sprint(rv.Index(i).Interface()) == want {
+					out = append(out, item)
+					break
+				}
+			}
+			continue
+		}
+		if v != nil && fmt.Sprint(v) == want || values.Equal(v, target) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// jekyllPluginTags are tags from Jekyll plugins whose output ssg produces on
+// its own (SEO metadata, feed autodiscovery); they render nothing rather
+// than failing a theme that uses them.
+var jekyllPluginTags = []string{"seo", "feed_meta"}
+
+// NewLiquidEngine creates a new Liquid template engine
+func NewLiquidEngine() *LiquidEngine {
+	e := &LiquidEngine{
+		eng:     liquid.NewEngine(),
+		layouts: map[string]*LiquidTemplate{},
+		helpers: map[string]bool{},
+	}
+	e.eng.EnableJekyllExtensions()
+	e.eng.RegisterTemplateStore(liquidStore{e})
+	for name, fn := range jekyllFilters {
+		e.eng.RegisterFilter(name, fn)
+	}
+	for _, name := range jekyllPluginTags {
+		e.eng.RegisterTag(name, func(render.Context) (string, error) { return "", nil })
+	}
+	e.eng.RegisterTag("include", e.includeTag)
+	return e
+}
+
+// Name returns the engine name
+func (e *LiquidEngine) Name() string {
+	return EngineLiquid
+}
+
+// Parse parses template content
+func (e *LiquidEngine) Parse(name, content string, funcs template.FuncMap) (Template, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	// Register FuncMap helpers as Liquid filters (GO-054); anything a filter
+	// cannot express is reported once instead of passing through.
+	var unsupported []string
+	for fname, fn := range funcs {
+		if !e.registerHelper(fname, fn) {
+			unsupported = append(unsupported, fname)
+		}
+	}
+	warnUnsupported(EngineLiquid, unsupported)
+
+	self := strings.TrimSuffix(filepath.Base(name), filepath.Ext(name))
+	return e.parse(name, content, []string{self})
+}
+
+// ParseFile parses a template file
+func (e *LiquidEngine) ParseFile(path string, funcs template.FuncMap) (Template, error) {
+	content, err := os.ReadFile(path) // #nosec G304 -- CLI tool reads user's template files
+	if err != nil {
+		return nil, err
+	}
+	e.mu.Lock()
+	if len(e.roots) == 0 {
+		e.roots = []string{liquidThemeRoot(path)}
+	}
+	e.mu.Unlock()
+	return e.Parse(path, string(content), funcs)
+}
+
+// Execute renders the template, then each layout around it: every layout
+// sees the output so far as `content` and its own frontmatter as `layout`.
+func (t *LiquidTemplate) Execute(w io.Writer, data interface{}) error {
+	bindings := liquid.Bindings(ContextMap(data))
+	out, rerr := t.tmpl.Render(bindings)
+	if rerr != nil {
+		return rerr
+	}
+	for l := t.parent; l != nil; l = l.parent {
+		outer := make(liquid.Bindings, len(bindings)+2)
+		for k, v := range bindings {
+			outer[k] = v
+		}
+		outer["content"] = string(out)
+		outer["layout"] = l.front
+		if out, rerr = l.tmpl.Render(outer); rerr != nil {
+			return rerr
+		}
+	}
+	_, err := w.Write(out)
+	return err
+}
+
+// parse compiles one template and the layouts it names. stack holds the
+// layouts being resolved, so a layout that wraps itself is an error rather
+// than a hang.
+func (e *LiquidEngine) parse(name, content string, stack []string) (*LiquidTemplate, error) {
+	front, body, line, err := splitLiquidFrontMatter(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: frontmatter: %w", name, err)
+	}
+	tmpl, perr := e.eng.ParseTemplateLocation([]byte(body), name, line)
+	if perr != nil {
+		return nil, perr
+	}
+	t := &LiquidTemplate{tmpl: tmpl, front: front}
+	if layout, _ := front["layout"].(string); layout != "" && layout != "none" {
+		if t.parent, err = e.layout(layout, name, stack); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// layout returns the parsed _layouts/<name>.html. A layout that does not
+// exist is reported and the template renders on its own, as Jekyll does.
+func (e *LiquidEngine) layout(name, from string, stack []string) (*LiquidTemplate, error) {
+	for _, s := range stack {
+		if s == name {
+			return nil, fmt.Errorf("%s: layout %q wraps itself (%s → %s)", from, name, strings.Join(stack, " → "), name)
+		}
+	}
+	if t, ok := e.layouts[name]; ok {
+		return t, nil
+	}
+	for _, root := range e.roots {
+		for _, dir := range []string{"_layouts", "layouts"} {
+			path := filepath.Join(root, dir, name)
+			if filepath.Ext(name) == "" {
+				path += ".html"
+			}
+			content, err := os.ReadFile(path) // #nosec G304 -- the theme's own layouts
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			t, err := e.parse(path, string(content), append(stack, name))
+			if err != nil {
+				return nil, err
+			}
+			e.layouts[name] = t
+			return t, nil
+		}
+	}
+	warnHelpersOnce(EngineLiquid+":layout:"+name,
+		fmt.Sprintf("liquid engine: layout %q requested in %s does not exist; rendering without it", name, from))
+	e.layouts[name] = nil
+	return nil, nil
+}
+
+// includeParam matches one `key=value` argument of a Jekyll include.
+var includeParam = regexp.MustCompile(`([\w-]+)\s*=\s*("[^"]*"|'[^']*'|\S+)`)
+
+// includeTag is Jekyll's include: `{% include footer.html title=page.title %}`
+// renders _includes/footer.html with the parameters as include.title. The
+// file name may be bare, quoted or built with {{ }}.
+func (e *LiquidEngine) includeTag(ctx render.Context) (string, error) {
+	args, err := ctx.ExpandTagArg()
+	if err != nil {
+		return "", err
+	}
+	args = strings.TrimSpace(args)
+	name, rest, _ := strings.Cut(args, " ")
+	name = strings.Trim(name, `"'`)
+	if name == "" || !filepath.IsLocal(filepath.Clean(name)) {
+		return "", ctx.Errorf("include needs a file name inside _includes/, got %q", args)
+	}
+	params := map[string]any{}
+	for _, m := range includeParam.FindAllStringSubmatch(rest, -1) {
+		value := m[2]
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			params[m[1]] = value[1 : len(value)-1]
+			continue
+		}
+		v, err := ctx.EvaluateString(value)
+		if err != nil {
+			return "", ctx.WrapError(err)
+		}
+		params[m[1]] = v
+	}
+	return ctx.RenderFile(filepath.Join(e.themeRoot(), "_includes", name), map[string]any{"include": params})
+}
+
+// registerHelper registers a Go FuncMap helper as a Liquid filter through the
+// reflection adapter (GO-054): `{{ value | helper }}` calls helper(value) and
+// `{{ value | helper: a, b }}` calls helper(value, a, b), up to three
+// arguments in all. A Liquid or Jekyll filter of the same name stays in place.
+// Reports whether the helper is supported.
+func (e *LiquidEngine) registerHelper(name string, fn interface{}) bool {
+	if supported, seen := e.helpers[name]; seen {
+		return supported
+	}
+	if liquidBuiltins[name] {
+		e.helpers[name] = true
+		return true
+	}
+	adapter, err := adaptHelper(fn)
+	numIn, variadic := 0, false
+	if err == nil {
+		t := adapter.fn.Type()
+		numIn, variadic = t.NumIn(), t.IsVariadic()
+		if variadic {
+			numIn-- // the fixed parameters; the rest are whatever the template passes
+		}
+		if numIn > 3 || !variadic && numIn < 1 {
+			err = fmt.Errorf("arity not expressible as a liquid filter")
+		} else if variadic {
+			numIn = 3
+		}
+	}
+	if err != nil {
+		e.helpers[name] = false
+		return false
+	}
+	e.helpers[name] = true
+
+	call := func(args ...interface{}) (interface{}, error) {
+		// Liquid hands a filter nil for each argument the template left out;
+		// a variadic helper gets only the ones it was given.
+		for variadic && len(args) > 0 && args[len(args)-1] == nil && adapter.accepts(len(args)-1) {
+			args = args[:len(args)-1]
+		}
+		res, cerr := adapter.call(args...)
+		if cerr != nil {
+			return nil, fmt.Errorf("helper %q: %w", name, cerr)
+		}
+		if s, safe := helperResultString(res); safe {
+			return s, nil
+		}
+		return res, nil
+	}
+	switch numIn {
+	case 1:
+		e.eng.RegisterFilter(name, func(a interface{}) (interface{}, error) { return call(a) })
+	case 2:
+		e.eng.RegisterFilter(name, func(a, b interface{}) (interface{}, error) { return call(a, b) })
+	default:
+		e.eng.RegisterFilter(name, func(a, b, c interface{}) (interface{}, error) { return call(a, b, c) })
+	}
+	return true
+}
+
+// liquidStore reads the files `include` and `render` name, confined to the
+// theme. A name a layout gives relative to itself is also looked up in
+// _includes/, where a Jekyll theme keeps its partials, with .liquid or
+// .html added when it has no extension.
+type liquidStore struct{ e *LiquidEngine }
+
+// ReadTemplate implements render.TemplateStore.
+func (s liquidStore) ReadTemplate(filename string) ([]byte, error) {
+	roots := s.e.roots
+	if len(roots) == 0 {
+		return os.ReadFile(filename) // #nosec G304 -- template source named by the theme
+	}
+	// A name is relative to whichever theme of the chain it was built from,
+	// and then looked up nearest theme first.
+	rel := ""
+	for _, root := range roots {
+		if r, err := filepath.Rel(root, filename); err == nil && filepath.IsLocal(r) {
+			rel = r
+			break
+		}
+	}
+	if rel == "" {
+		return nil, fmt.Errorf("template path %q is outside the theme %s", filename, roots[0])
+	}
+	candidates := []string{rel}
+	rest := rel
+	if first, after, ok := strings.Cut(filepath.ToSlash(rel), "/"); ok && (first == "_layouts" || first == "layouts") {
+		rest = filepath.FromSlash(after)
+	}
+	candidates = append(candidates, filepath.Join("_includes", rest))
+	if filepath.Ext(rest) == "" {
+		candidates = append(candidates, filepath.Join("_includes", rest+".liquid"), filepath.Join("_includes", rest+".html"))
+	}
+	for _, c := range candidates {
+		for _, root := range roots {
+			content, err := os.ReadFile(filepath.Join(root, c)) // #nosec G304 -- confined to the theme above
+			if !errors.Is(err, fs.ErrNotExist) {
+				return content, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("%s: %w", filename, fs.ErrNotExist)
+}
+
+// SetThemeRoots sets the theme directories layouts and includes are read
+// from, nearest first. Call it before parsing.
+func (e *LiquidEngine) SetThemeRoots(roots []string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.roots = append([]string(nil), roots...)
+	e.layouts = map[string]*LiquidTemplate{}
+}
+
+// themeRoot is the nearest theme directory, "" before any is known.
+func (e *LiquidEngine) themeRoot() string {
+	if len(e.roots) == 0 {
+		return ""
+	}
+	return e.roots[0]
+}
+
+// liquidThemeRoot is the theme directory a template file belongs to: its
+// own directory, or the one above for a file in _layouts/ or layouts/.
+func liquidThemeRoot(path string) string {
+	dir := filepath.Dir(path)
+	if base := filepath.Base(dir); base == "_layouts" || base == "layouts" {
+		return filepath.Dir(dir)
+	}
+	return dir
+}
+
+// splitLiquidFrontMatter separates a Jekyll template's YAML frontmatter from
+// its body, and returns the line the body starts on for error messages.
+func splitLiquidFrontMatter(content string) (map[string]interface{}, string, int, error) {
+	front := map[string]interface{}{}
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	if !strings.HasPrefix(normalized, "---\n") {
+		return front, content, 1, nil
+	}
+	end := strings.Index(normalized[4:], "\n---")
+	if end < 0 {
+		return front, content, 1, nil
+	}
+	raw := normalized[4 : 4+end]
+	body := normalized[4+end+len("\n---"):]
+	if nl := strings.IndexByte(body, '\n'); nl >= 0 {
+		body = body[nl+1:]
+	} else {
+		body = ""
+	}
+	if err := yaml.Unmarshal([]byte(raw), &front); err != nil {
+		return nil, "", 0, err
+	}
+	if front == nil {
+		front = map[string]interface{}{}
+	}
+	return front, body, strings.Count(normalized[:len(normalized)-len(body)], "\n") + 1, nil
+}
@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeLiquidTheme lays out a Jekyll-style theme and returns its directory.
+func writeLiquidTheme(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func renderLiquid(t *testing.T, e *LiquidEngine, path string, data interface{}) string {
+	t.Helper()
+	tmpl, err := e.ParseFile(path, go054Funcs())
+	if err != nil {
+		t.Fatalf("parse %s: %v", path, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		t.Fatalf("execute %s: %v", path, err)
+	}
+	return buf.String()
+}
+
+// TestLiquidLayoutsAndIncludes: post.html wraps into default.html, which
+// reads the layout's frontmatter and includes a partial with parameters; the
+// render tag finds snippets in _includes/ as well.
+func TestLiquidLayoutsAndIncludes(t *testing.T) {
+	dir := writeLiquidTheme(t, map[string]string{
+		"_layouts/default.html": "---\nlang: en\n---\n<html lang=\"{{ layout.lang }}\">" +
+			"{% include nav.html active=page.title label=\"Menu\" %}{{ content }}</html>",
+		"_layouts/post.html": "---\nlayout: default\n---\n<article>{{ page.title }}: {{ content }}</article>" +
+			"{% render 'card', title: page.title %}",
+		"_includes/nav.html":    "<nav>{{ include.label }}:{{ include.active }}</nav>",
+		"_includes/card.liquid": "<div>{{ title }}{{ content }}</div>",
+	})
+	out := renderLiquid(t, NewLiquidEngine(), filepath.Join(dir, "_layouts", "post.html"), map[string]interface{}{
+		"page":    map[string]interface{}{"title": "Hi"},
+		"content": "<p>body</p>",
+	})
+	want := `<html lang="en"><nav>Menu:Hi</nav><article>Hi: <p>body</p></article><div>Hi</div></html>`
+	if out != want {
+		t.Errorf("got  %q\nwant %q", out, want)
+	}
+}
+
+// TestLiquidThemeRoots: with a child theme in front of its parent, a layout
+// and an include the child lacks come from the parent, and the child's own
+// include wins over the parent's.
+func TestLiquidThemeRoots(t *testing.T) {
+	parent := writeLiquidTheme(t, map[string]string{
+		"_layouts/default.html": "<html>{% include nav.html %}{% include foot.html %}{{ content }}</html>",
+		"_includes/nav.html":    "<nav>parent</nav>",
+		"_includes/foot.html":   "<footer>parent</footer>",
+	})
+	child := writeLiquidTheme(t, map[string]string{
+		"_layouts/post.html": "---\nlayout: default\n---\n<article>{{ page.title }}</article>",
+		"_includes/nav.html": "<nav>child</nav>",
+	})
+	e := NewLiquidEngine()
+	e.SetThemeRoots([]string{child, parent})
+	out := renderLiquid(t, e, filepath.Join(child, "_layouts", "post.html"), map[string]interface{}{
+		"page": map[string]interface{}{"title": "Hi"},
+	})
+	want := `<html><nav>child</nav><footer>parent</footer><article>Hi</article></html>`
+	if out != want {
+		t.Errorf("got  %q\nwant %q", out, want)
+	}
+}
+
+// TestLiquidFilters: Jekyll's where matches inside a list, the date filters
+// read text dates, and template helpers are bridged as filters without
+// replacing Liquid's own of the same name.
+func TestLiquidFilters(t *testing.T) {
+	e := NewLiquidEngine()
+	tmpl, err := e.Parse("t", `{{ posts | where: "tags", "go" | map: "title" | join: "," }}|`+
+		`{{ "2024-03-05" | date: "%b %-d, %Y" }}|{{ "2024-03-05" | date_to_string }}|`+
+		`{{ name | shout54 }}|{{ "ab" | repeat54: 2 }}|{{ name | safe54 }}|`+
+		`{{ "k" | dictish: "v" | size }}|{{ "a<b" | xml_escape }}|{{ "one two" | number_of_words }}|{% assign p = posts | first %}{{ p.title }}`, go054Funcs())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]interface{}{
+		"name": "krowa",
+		"posts": []interface{}{
+			map[string]interface{}{"title": "A", "tags": []interface{}{"go", "web"}},
+			map[string]interface{}{"title": "B", "tags": []interface{}{"rust"}},
+			map[string]interface{}{"title": "C", "tags": []interface{}{"go"}},
+		},
+	})
+	if err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	want := "A,C|Mar 5, 2024|05 Mar 2024|KROWA|abab|<b>krowa</b>|2|a&lt;b|2|A"
+	if got := buf.String(); got != want {
+		t.Errorf("got  %q\nwant %q", got, want)
+	}
+}
+
+// TestLiquidHelperErrorPropagates: a failing helper fails the render, as it
+// does in pongo2, rather than printing nothing.
+func TestLiquidHelperErrorPropagates(t *testing.T) {
+	tmpl, err := NewLiquidEngine().Parse("t", `{{ name | fail54 }}`, go054Funcs())
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var buf bytes.Buffer
+	if execErr := tmpl.Execute(&buf, map[string]interface{}{"name": "x"}); execErr == nil ||
+		!strings.Contains(execErr.Error(), "boom") {
+		t.Errorf("helper error must propagate, got %v", execErr)
+	}
+}
+
+func TestLiquidUnsupportedReported(t *testing.T) {
+	e := NewLiquidEngine()
+	if e.registerHelper("wide54", go054Funcs()["wide54"]) {
+		t.Error("arity-4 helper must be unsupported in liquid")
+	}
+	if !e.registerHelper("dictish", go054Funcs()["dictish"]) {
+		t.Error("variadic helper must be bridged in liquid")
+	}
+}
+
+// TestLiquidLayoutErrors: a layout chain that loops back is an error at
+// parse time; a layout that does not exist renders the template on its own.
+func TestLiquidLayoutErrors(t *testing.T) {
+	dir := writeLiquidTheme(t, map[string]string{
+		"_layouts/a.html":    "---\nlayout: b\n---\nA{{ content }}",
+		"_layouts/b.html":    "---\nlayout: a\n---\nB{{ content }}",
+		"_layouts/lost.html": "---\nlayout: nowhere\n---\nlost",
+	})
+	e := NewLiquidEngine()
+	if _, err := e.ParseFile(filepath.Join(dir, "_layouts", "a.html"), nil); err == nil ||
+		!strings.Contains(err.Error(), "wraps itself") {
+		t.Errorf("layout loop: %v", err)
+	}
+	if out := renderLiquid(t, e, filepath.Join(dir, "_layouts", "lost.html"), nil); out != "lost" {
+		t.Errorf("missing layout rendered %q, want %q", out, "lost")
+	}
+}
+
+func TestLiquidContextLiftsStructFields(t *testing.T) {
+	tmpl, err := NewLiquidEngine().Parse("t", `{{ Title }}`, nil)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, struct{ Title string }{"Home"}); err != nil {
+		t.Fatalf("execute: %v", err)
+	}
+	if buf.String() != "Home" {
+		t.Errorf("got %q, want Home", buf.String())
+	}
+}
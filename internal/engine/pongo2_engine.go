@@ -65,21 +65,10 @@ func (t *Pongo2Template) Execute(w io.Writer, data interface{}) error {
 
 // dataToPongo2Context converts Go data to pongo2.Context
 func dataToPongo2Context(data interface{}) pongo2.Context {
-	ctx := pongo2.Context{}
-
-	switch v := data.(type) {
-	case map[string]interface{}:
-		for k, val := range v {
-			ctx[k] = val
-		}
-	case pongo2.Context:
+	if v, ok := data.(pongo2.Context); ok {
 		return v
-	default:
-		// Wrap in "Data" key
-		ctx["Data"] = data
 	}
-
-	return ctx
+	return pongo2.Context(ContextMap(data))
 }
 
 // pongo2Registered remembers which filter names this process registered and
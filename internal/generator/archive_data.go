@@ -48,7 +48,9 @@ func (g *Generator) archiveData(kind, name string, cat models.Category,
 		"ExternalData": g.externalData,
 		// The same build timestamp a page gets: a footer rendered in a base
 		// template must not say one year on a post and another on an archive.
-		"BuildTime": g.buildTime,
+		"BuildTime":      g.buildTime,
+		"Versions":       g.versions,
+		"CurrentVersion": currentVersion(g.versions),
 	}
 }
 
@@ -0,0 +1,370 @@
+package generator
+
+// Authors beyond one id per post. A co-written post or a guest contribution
+// names everyone in `authors:`, each resolved like `author:` (id, name or
+// slug); profiles — bio, avatar, website, social links — come from
+// metadata.json users and data/authors/*.yaml; and every author's archive is
+// paginated and publishes its own feeds, so a reader can follow one writer
+// rather than the whole site.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spagu/ssg/internal/externalsource"
+	"github.com/spagu/ssg/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// authorFeedFiles names the file each feed format is written to beside an
+// author archive.
+var authorFeedFiles = map[string]string{
+	"atom": feedFileName,
+	"rss":  "rss.xml",
+	"json": "feed.json",
+}
+
+// loadAuthorProfiles merges <data_dir>/authors/*.yaml into the site's
+// authors. A profile joins the author with the same id, else slug (the file
+// name when it sets none), else name, and its non-empty fields win: the file
+// is hand-edited, the export is not. A profile matching nobody is a new
+// author with the next free id, so a guest who never had a CMS account can
+// still have an archive. Names in frontmatter are resolved again afterwards.
+func (g *Generator) loadAuthorProfiles() error {
+	dir := g.config.DataDir
+	if dir == "" {
+		dir = "data"
+	}
+	entries, err := os.ReadDir(filepath.Join(dir, "authors"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	loaded := 0
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		path := filepath.Join(dir, "authors", e.Name())
+		raw, err := os.ReadFile(path) // #nosec G304 -- CLI reads its own data dir
+		if err != nil {
+			return err
+		}
+		var prof models.Author
+		if err := yaml.Unmarshal(raw, &prof); err != nil {
+			// The directory is also plain .Data: a file that is not a profile
+			// stays data rather than failing the build.
+			fmt.Printf("   ⚠️  %s is not an author profile, skipping: %v\n", path, err)
+			continue
+		}
+		if prof.Slug == "" {
+			prof.Slug = strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		}
+		g.mergeAuthorProfile(prof)
+		loaded++
+	}
+	if loaded > 0 {
+		g.siteData.ResolveFlexibleFields()
+	}
+	return nil
+}
+
+// mergeAuthorProfile adds one profile to the site's authors (see
+// loadAuthorProfiles).
+func (g *Generator) mergeAuthorProfile(prof models.Author) {
+	if g.siteData.Authors == nil {
+		g.siteData.Authors = map[int]models.Author{}
+	}
+	existing, ok := g.siteData.Authors[prof.ID]
+	ok = ok && prof.ID != 0
+	for _, ref := range []string{prof.Slug, prof.Name} {
+		if !ok && ref != "" {
+			existing, ok = g.siteData.AuthorByRef(ref)
+		}
+	}
+	if !ok {
+		if prof.ID == 0 {
+			for known := range g.siteData.Authors {
+				prof.ID = max(prof.ID, known)
+			}
+			prof.ID++
+		}
+		if prof.Name == "" {
+			prof.Name = prof.Slug
+		}
+		g.siteData.Authors[prof.ID] = prof
+		return
+	}
+	overlay := func(dst *string, src string) {
+		if src != "" {
+			*dst = src
+		}
+	}
+	overlay(&existing.Name, prof.Name)
+	overlay(&existing.Bio, prof.Bio)
+	overlay(&existing.Avatar, prof.Avatar)
+	overlay(&existing.URL, prof.URL)
+	if len(prof.Social) > 0 {
+		existing.Social = prof.Social
+	}
+	if len(prof.SameAs) > 0 {
+		existing.SameAs = prof.SameAs
+	}
+	g.siteData.Authors[existing.ID] = existing
+}
+
+// authorIDs are the registered authors a post is credited to: its `authors:`
+// list, else its single `author:`.
+func authorIDs(p models.Page) []int {
+	if len(p.Authors) > 0 {
+		return p.Authors
+	}
+	if p.Author != 0 {
+		return []int{p.Author}
+	}
+	return nil
+}
+
+// pageAuthors is a page's byline, in order. A name with no profile is
+// credited as a name-only Author (no slug, so no archive link).
+func (g *Generator) pageAuthors(p models.Page) []models.Author {
+	if len(p.AuthorsRaw) > 0 {
+		out := make([]models.Author, 0, len(p.AuthorsRaw))
+		for _, ref := range p.AuthorsRaw {
+			if a, ok := g.siteData.AuthorByRef(ref); ok {
+				out = append(out, a)
+			} else if s, ok := ref.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, models.Author{Name: strings.TrimSpace(s)})
+			}
+		}
+		return out
+	}
+	if a, ok := g.siteData.Authors[p.Author]; ok && p.Author != 0 {
+		return []models.Author{a}
+	}
+	if name := g.authorDisplayName(p); name != "" {
+		return []models.Author{{Name: name}}
+	}
+	return nil
+}
+
+// bylineNames joins a page's authors for formats that take one string.
+func (g *Generator) bylineNames(p models.Page) string {
+	authors := g.pageAuthors(p)
+	names := make([]string, 0, len(authors))
+	for _, a := range authors {
+		names = append(names, a.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// authorPosts groups posts by every author they credit.
+func (g *Generator) authorPosts() map[int][]models.Page {
+	groups := make(map[int][]models.Page)
+	for _, post := range g.siteData.Posts {
+		for _, id := range authorIDs(post) {
+			groups[id] = append(groups[id], post)
+		}
+	}
+	return groups
+}
+
+// renderAuthorArchive writes /author/{slug}/ and, with authors.paginate set,
+// /author/{slug}/page/N/. Beside the archive keys every view has, the context
+// carries the author's profile as .Author and the author's feeds as .Feeds.
+func (g *Generator) renderAuthorArchive(id int, name, slug string, posts []models.Page) error {
+	slug = models.SanitizeRelPath(slug)
+	if slug == "" {
+		return nil
+	}
+	root := filepath.Join(g.config.OutputDir, "author", slug)
+	tmpl := authorHTMLName
+	// A define-shell template (GO-051) counts as absent, as in renderArchive.
+	if !g.hasTemplate(tmpl) {
+		tmpl = categoryHTMLName
+	}
+	profile := g.siteData.Authors[id]
+	if profile.Name == "" {
+		profile = models.Author{ID: id, Name: name, Slug: slug}
+	}
+	feeds := g.authorFeedLinks(slug)
+	for _, chunk := range paginateTerm(sortPostsByDate(posts), g.config.Authors.Paginate, "/author/"+slug+"/") {
+		outPath := filepath.Join(root, indexHTMLName)
+		if chunk.Pager.Current > 1 {
+			outPath = filepath.Join(root, "page", fmt.Sprintf("%d", chunk.Pager.Current), indexHTMLName)
+		}
+		if err := g.ensureWithinOutput(outPath); err != nil {
+			fmt.Printf("   ⚠️  Skipping author %q with unsafe slug: %v\n", name, err)
+			return nil
+		}
+		if err := g.ensureParent(outPath); err != nil {
+			return err
+		}
+		data := g.archiveData("author", name, models.Category{Name: name, Slug: slug},
+			chunk.Posts, chunk.Pager, g.currentLang)
+		data["Author"] = profile
+		data["Feeds"] = feeds
+		if err := g.renderTemplate(tmpl, outPath, data); err != nil {
+			if err := g.renderTemplate(categoryHTMLName, outPath, data); err != nil {
+				fmt.Printf("   ⚠️  Failed to generate author %s: %v\n", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// authorFeed is one feed an author archive links to.
+type authorFeed struct {
+	Format string // atom, rss or json
+	Type   string // its MIME type, for <link rel="alternate" type=…>
+	URL    string // site-relative: /author/jane/feed.xml
+}
+
+// authorFeedFormats are the per-author feed formats authors.feeds asks for.
+// Opt-in: a site that never had these URLs should not grow them because it
+// upgraded.
+func (g *Generator) authorFeedFormats() ([]string, error) {
+	out := make([]string, 0, len(g.config.Authors.Feeds))
+	for _, f := range g.config.Authors.Feeds {
+		name := strings.ToLower(strings.TrimSpace(f))
+		if _, ok := feedFormats[name]; !ok {
+			return nil, fmt.Errorf("authors.feeds: unsupported format %q (supported: atom, rss, json)", f)
+		}
+		out = append(out, name)
+	}
+	return out, nil
+}
+
+func (g *Generator) authorFeedLinks(slug string) []authorFeed {
+	formats, err := g.authorFeedFormats()
+	if err != nil {
+		return nil // reported by generateAuthorFeeds
+	}
+	out := make([]authorFeed, 0, len(formats))
+	for _, f := range formats {
+		out = append(out, authorFeed{Format: f, Type: feedFormats[f].mime,
+			URL: "/author/" + slug + "/" + authorFeedFiles[f]})
+	}
+	return out
+}
+
+// generateAuthorFeeds writes each author's feeds beside their archive, for
+// the authors whose archive was generated.
+func (g *Generator) generateAuthorFeeds() error {
+	formats, err := g.authorFeedFormats()
+	if err != nil || len(formats) == 0 || len(g.authorSlugs) == 0 {
+		return err
+	}
+	limit := g.config.FeedItems
+	if limit <= 0 {
+		limit = 20
+	}
+	groups := g.authorPosts()
+	ids := make([]int, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	base := httpsScheme + g.config.Domain
+	for _, id := range ids {
+		name, slug := g.authorNameSlug(id)
+		if _, ok := g.authorSlugs[slug]; !ok {
+			continue
+		}
+		// A feed is chronological, pinning left to the listings (#155).
+		posts := sortPostsChronologically(published(groups[id]))
+		if len(posts) > limit {
+			posts = posts[:limit]
+		}
+		items := make([]externalsource.FeedItem, 0, len(posts))
+		for _, p := range posts {
+			items = append(items, g.postFeedItem(p, g.config.FeedFullContent))
+		}
+		for _, f := range formats {
+			rel := "author/" + slug + "/" + authorFeedFiles[f]
+			page := feedPage{title: name + " — " + g.config.Domain, selfURL: base + "/" + rel,
+				altURL: base + "/author/" + slug + "/", items: items, full: g.config.FeedFullContent}
+			if err := g.writeFeedFile(rel, feedFormats[f].render(g, page), len(items)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// authorPersons are a page's authors as schema.org Persons: a name, the
+// author's website as url, and every profile link as sameAs.
+func (g *Generator) authorPersons(p models.Page) []map[string]interface{} {
+	authors := g.pageAuthors(p)
+	out := make([]map[string]interface{}, 0, len(authors))
+	for _, a := range authors {
+		person := map[string]interface{}{"@type": "Person", "name": a.Name}
+		if a.URL != "" {
+			person["url"] = a.URL
+		}
+		var same []string
+		same = append(same, a.SameAs...)
+		keys := make([]string, 0, len(a.Social))
+		for k := range a.Social {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if u := a.Social[k]; strings.HasPrefix(u, "http") {
+				same = append(same, u)
+			}
+		}
+		if len(same) > 0 {
+			person["sameAs"] = same
+		}
+		out = append(out, person)
+	}
+	return out
+}
+
+// tmplGetAuthor returns an author's profile by id, name or slug:
+//
+//	{{ with getAuthor .Author }}{{ .Bio | safeHTML }}{{ end }}
+func (g *Generator) tmplGetAuthor(ref any) models.Author {
+	a, _ := g.siteData.AuthorByRef(ref)
+	return a
+}
+
+// tmplAuthorsOf returns a page's byline, in order (see pageAuthors).
+func (g *Generator) tmplAuthorsOf(p models.Page) []models.Author {
+	return g.pageAuthors(p)
+}
+
+// tmplAuthorAvatar returns the URL of an author's avatar cropped to a square
+// of size pixels through the image pipeline. A remote avatar (a WordPress
+// Gravatar) is returned as it is; an author without one yields "".
+//
+//	<img src="{{ authorAvatar . 96 }}" width="96" height="96" alt="">
+func (g *Generator) tmplAuthorAvatar(author any, size int) (string, error) {
+	var a models.Author
+	switch v := author.(type) {
+	case models.Author:
+		a = v
+	case *models.Author:
+		if v != nil {
+			a = *v
+		}
+	default:
+		a, _ = g.siteData.AuthorByRef(author)
+	}
+	if a.Avatar == "" || size <= 0 || strings.Contains(a.Avatar, "://") || strings.HasPrefix(a.Avatar, "//") {
+		return a.Avatar, nil
+	}
+	res, err := g.imageProcessor().CropDict(strings.TrimPrefix(a.Avatar, "/"),
+		map[string]any{"width": size, "height": size})
+	if err != nil {
+		return "", fmt.Errorf("authorAvatar %s: %w", a.Name, err)
+	}
+	return res.URL, nil
+}
@@ -1,10 +1,13 @@
 package generator
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/spagu/ssg/internal/models"
 )
 
 // writeAuthorsFixture builds a site with an author (metadata.json users block),
@@ -149,3 +152,84 @@ func TestHasPrefixSuffixAliases(t *testing.T) {
 	wantContains(t, "index", mustRead(t, filepath.Join(out, "index.html")),
 		"p:true", "s:true", "legacy:truetrue")
 }
+
+// TestCoAuthorsProfilesArchivesAndFeeds: an `authors:` list credits every
+// author, a data/authors profile completes a metadata.json user and adds a
+// guest, archives paginate, and each author gets the feeds authors.feeds
+// asks for.
+func TestCoAuthorsProfilesArchivesAndFeeds(t *testing.T) {
+	tmp := t.TempDir()
+	site := filepath.Join(tmp, "content", "site")
+	mustWrite(t, filepath.Join(site, "metadata.json"),
+		`{"categories":[],"exported_at":"","media":[],"users":[{"id":1,"name":"Ian Zane","slug":"ian-zane"}]}`)
+	mustWrite(t, filepath.Join(tmp, "data", "authors", "ian-zane.yaml"),
+		"bio: Writes about tools.\nsocial:\n  mastodon: https://mastodon.example/@ian\n")
+	mustWrite(t, filepath.Join(tmp, "data", "authors", "gina.yaml"),
+		"name: Gina Guest\nslug: gina-guest\nurl: https://gina.example\n")
+	posts := filepath.Join(site, "posts", "news")
+	mustWrite(t, filepath.Join(posts, "joint.md"),
+		"---\ntitle: Joint\nslug: joint\nstatus: publish\ntype: post\ndate: 2026-07-03\n"+
+			"authors: [Ian Zane, gina-guest, Pat Nobody]\n---\n\nBody.\n")
+	for i, day := range []string{"01", "02"} {
+		mustWrite(t, filepath.Join(posts, "solo"+day+".md"), fmt.Sprintf(
+			"---\ntitle: Solo %d\nslug: solo-%d\nstatus: publish\ntype: post\ndate: 2026-07-%s\nauthor: 1\n---\n\nBody.\n", i+1, i+1, day))
+	}
+	tmplDir := filepath.Join(tmp, "templates", "simple")
+	writeSimpleTemplates(t, tmplDir)
+	mustWrite(t, filepath.Join(tmplDir, "post.html"),
+		`{{define "post.html"}}<html><head></head><body>BY{{range .Authors}}[{{.Name}}]{{end}}</body></html>{{end}}`)
+	mustWrite(t, filepath.Join(tmplDir, "author.html"),
+		`{{define "author.html"}}<html><body>{{.Author.Name}}: {{.Author.Bio}} p{{.Pager.Current}}/{{.Pager.Total}}`+
+			`{{range .Posts}}[{{.Title}}]{{end}}{{range .Feeds}}({{.URL}}){{end}}</body></html>{{end}}`)
+	gen, err := New(Config{Source: "site", Template: "simple", Domain: "example.com",
+		ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+		DataDir: filepath.Join(tmp, "data"), OutputDir: filepath.Join(tmp, "output"), Quiet: true,
+		Authors: models.AuthorsConfig{Paginate: 2, Feeds: []string{"atom", "json"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	out := filepath.Join(tmp, "output")
+
+	wantContains(t, "ian page 1", mustRead(t, filepath.Join(out, "author", "ian-zane", "index.html")),
+		"Ian Zane: Writes about tools. p1/2", "[Joint][Solo 2]", "(/author/ian-zane/feed.xml)", "(/author/ian-zane/feed.json)")
+	wantContains(t, "ian page 2", mustRead(t, filepath.Join(out, "author", "ian-zane", "page", "2", "index.html")),
+		"p2/2", "[Solo 1]")
+	wantContains(t, "guest archive", mustRead(t, filepath.Join(out, "author", "gina-guest", "index.html")),
+		"Gina Guest", "[Joint]")
+	wantContains(t, "byline", mustRead(t, filepath.Join(out, "2026", "07", "03", "joint", "index.html")),
+		"BY[Ian Zane][Gina Guest][Pat Nobody]")
+	wantContains(t, "guest feed", mustRead(t, filepath.Join(out, "author", "gina-guest", "feed.json")),
+		`"name": "Ian Zane, Gina Guest, Pat Nobody"`)
+	wantContains(t, "atom feed", mustRead(t, filepath.Join(out, "author", "ian-zane", "feed.xml")),
+		"<title>Joint</title>", "<title>Solo 1</title>")
+	if _, err := os.Stat(filepath.Join(out, "author", "ian-zane", "rss.xml")); err == nil {
+		t.Error("rss.xml was not asked for")
+	}
+	// A name with no profile is credited but has no archive.
+	if _, err := os.Stat(filepath.Join(out, "author", "pat-nobody")); err == nil {
+		t.Error("an author without a profile has no archive")
+	}
+}
+
+// TestAuthorPersons: JSON-LD names every author, with the website as url and
+// the profile links as sameAs.
+func TestAuthorPersons(t *testing.T) {
+	g := newTestGen(t, "")
+	g.siteData.Authors = map[int]models.Author{
+		1: {ID: 1, Name: "Ian Zane", Slug: "ian-zane", URL: "https://ian.example",
+			SameAs: []string{"https://wiki.example/Ian"}, Social: map[string]string{"github": "https://github.com/ian"}},
+	}
+	people := g.authorPersons(models.Page{AuthorsRaw: []interface{}{"ian-zane", "Jo Guest"}})
+	if len(people) != 2 || people[0]["url"] != "https://ian.example" || people[1]["name"] != "Jo Guest" {
+		t.Fatalf("authorPersons = %v", people)
+	}
+	if same, _ := people[0]["sameAs"].([]string); len(same) != 2 || same[1] != "https://github.com/ian" {
+		t.Errorf("sameAs = %v", people[0]["sameAs"])
+	}
+	if _, ok := people[1]["sameAs"]; ok {
+		t.Error("a name-only author has no sameAs")
+	}
+}
@@ -0,0 +1,264 @@
+package generator
+
+// Callouts (admonitions): GitHub's and Obsidian's `> [!NOTE]` blockquotes and
+// MkDocs' `!!! tip "Title"` blocks, rendered as one semantic element:
+//
+//	<aside class="callout callout-note">
+//	<p class="callout-title">Note</p>
+//	…
+//	</aside>
+//
+// A foldable callout (`> [!TIP]-` / `[!TIP]+`, MkDocs `???` / `???+`) wraps its
+// body in <details>, closed or open. Docs migrated from GitHub or MkDocs used
+// to render the marker as visible text in a plain blockquote, or — the MkDocs
+// body being indented four columns — as a code block.
+//
+// Both syntaxes end up as the same AST node, so one renderer and one theme
+// hook (_markup/render-callout.html, see markup_hooks.go) cover them.
+
+import (
+	"bytes"
+	"html/template"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	gmparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+var kindCallout = ast.NewNodeKind("Callout")
+
+// calloutNode is one callout; its children are the body.
+type calloutNode struct {
+	ast.BaseBlock
+	kind     string // "note", "warning" — lower case, as it appears in the class
+	title    string // explicit title, or "" for the capitalized kind
+	foldable bool
+	open     bool // foldable and expanded by default
+	hooked   bool // rendered whole by the theme's hook
+}
+
+// Kind implements ast.Node.
+func (n *calloutNode) Kind() ast.NodeKind { return kindCallout }
+
+// Dump implements ast.Node.
+func (n *calloutNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{"Type": n.kind, "Title": n.title}, nil)
+}
+
+// heading is the title shown: the author's, else the type ("Note").
+func (n *calloutNode) heading() string {
+	if n.title != "" {
+		return n.title
+	}
+	return strings.ToUpper(n.kind[:1]) + n.kind[1:]
+}
+
+// ─── GitHub / Obsidian: > [!TYPE]± title ────────────────────────────────────
+
+// calloutMarkerRe is the first line of a callout blockquote.
+var calloutMarkerRe = regexp.MustCompile(`^\[!([A-Za-z][\w-]*)\]([+-]?)[ \t]*(.*?)[ \t]*$`)
+
+type calloutTransformer struct{}
+
+// Transform implements gmparser.ASTTransformer: every blockquote whose first
+// line is a [!TYPE] marker becomes a callout holding the blockquote's
+// children, minus the marker line.
+func (calloutTransformer) Transform(doc *ast.Document, reader text.Reader, _ gmparser.Context) {
+	var quotes []*ast.Blockquote
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if q, ok := n.(*ast.Blockquote); ok && entering {
+			quotes = append(quotes, q)
+		}
+		return ast.WalkContinue, nil
+	})
+	source := reader.Source()
+	for _, q := range quotes {
+		para, ok := q.FirstChild().(*ast.Paragraph)
+		if !ok || para.Lines().Len() == 0 {
+			continue
+		}
+		first := para.Lines().At(0)
+		m := calloutMarkerRe.FindSubmatch(bytes.TrimRight(first.Value(source), "\r\n"))
+		if m == nil {
+			continue
+		}
+		c := &calloutNode{
+			kind:     strings.ToLower(string(m[1])),
+			title:    string(m[3]),
+			foldable: len(m[2]) > 0,
+			open:     string(m[2]) == "+",
+		}
+		dropFirstLine(para, first.Stop)
+		if para.ChildCount() == 0 {
+			q.RemoveChild(q, para)
+		}
+		for child := q.FirstChild(); child != nil; child = q.FirstChild() {
+			q.RemoveChild(q, child)
+			c.AppendChild(c, child)
+		}
+		q.Parent().ReplaceChild(q.Parent(), q, c)
+	}
+}
+
+// dropFirstLine removes a paragraph's inline nodes up to and including the
+// break that ends its first line (which ends at stop in the source).
+func dropFirstLine(para *ast.Paragraph, stop int) {
+	for child := para.FirstChild(); child != nil; child = para.FirstChild() {
+		if t, ok := child.(*ast.Text); ok {
+			if t.Segment.Start >= stop {
+				break
+			}
+			para.RemoveChild(para, child)
+			if t.SoftLineBreak() || t.HardLineBreak() {
+				break
+			}
+			continue
+		}
+		para.RemoveChild(para, child)
+	}
+	lines := para.Lines()
+	if lines.Len() > 0 {
+		rest := text.NewSegments()
+		for i := 1; i < lines.Len(); i++ {
+			rest.Append(lines.At(i))
+		}
+		para.SetLines(rest)
+	}
+}
+
+// ─── MkDocs: !!! type "Title" + an indented body ────────────────────────────
+
+// admonitionRe is an MkDocs admonition header: `!!!` plain, `???` folded and
+// `???+` folded but open.
+var admonitionRe = regexp.MustCompile(`^[ ]{0,3}(!!!|\?\?\?\+?)[ \t]+([A-Za-z][\w-]*)(?:[ \t]+"([^"]*)")?[ \t]*\r?\n?$`)
+
+type admonitionParser struct{}
+
+// Trigger implements gmparser.BlockParser.
+func (admonitionParser) Trigger() []byte { return []byte{'!', '?'} }
+
+// Open implements gmparser.BlockParser.
+func (admonitionParser) Open(_ ast.Node, reader text.Reader, _ gmparser.Context) (ast.Node, gmparser.State) {
+	line, _ := reader.PeekLine()
+	m := admonitionRe.FindSubmatch(line)
+	if m == nil {
+		return nil, gmparser.NoChildren
+	}
+	reader.AdvanceToEOL()
+	marker := string(m[1])
+	return &calloutNode{
+		kind:     strings.ToLower(string(m[2])),
+		title:    string(m[3]),
+		foldable: marker != "!!!",
+		open:     marker == "???+",
+	}, gmparser.HasChildren
+}
+
+// Continue implements gmparser.BlockParser: the body is every following line
+// indented four columns, blank lines included, with the indentation removed.
+func (admonitionParser) Continue(_ ast.Node, reader text.Reader, _ gmparser.Context) gmparser.State {
+	line, _ := reader.PeekLine()
+	if util.IsBlank(line) {
+		reader.AdvanceToEOL()
+		return gmparser.Continue | gmparser.HasChildren
+	}
+	if indent, _ := util.IndentWidth(line, reader.LineOffset()); indent < 4 {
+		return gmparser.Close
+	}
+	pos, padding := util.IndentPosition(line, reader.LineOffset(), 4)
+	reader.AdvanceAndSetPadding(pos, padding)
+	return gmparser.Continue | gmparser.HasChildren
+}
+
+// Close implements gmparser.BlockParser.
+func (admonitionParser) Close(ast.Node, text.Reader, gmparser.Context) {}
+
+// CanInterruptParagraph implements gmparser.BlockParser.
+func (admonitionParser) CanInterruptParagraph() bool { return true }
+
+// CanAcceptIndentedLine implements gmparser.BlockParser.
+func (admonitionParser) CanAcceptIndentedLine() bool { return false }
+
+// ─── rendering ──────────────────────────────────────────────────────────────
+
+// calloutContext is what _markup/render-callout.html receives.
+type calloutContext struct {
+	Type     string // "note"
+	Title    string // "Note", or the author's title
+	Content  template.HTML
+	Foldable bool
+	Open     bool
+}
+
+type calloutRenderer struct {
+	md    goldmark.Markdown // renders the body for a hook
+	hooks *markupHooks
+}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r calloutRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindCallout, r.render)
+}
+
+func (r calloutRenderer) render(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	n := node.(*calloutNode)
+	if entering && r.hooks.lookup("callout") != nil {
+		var body bytes.Buffer
+		for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+			if err := r.md.Renderer().Render(&body, source, c); err != nil {
+				return ast.WalkStop, err
+			}
+		}
+		if out, ok := r.hooks.render("callout", calloutContext{
+			Type: n.kind, Title: n.heading(), Content: template.HTML(body.String()), // #nosec G203 -- rendered Markdown
+			Foldable: n.foldable, Open: n.open,
+		}); ok {
+			_, _ = w.WriteString(out)
+			n.hooked = true
+			return ast.WalkSkipChildren, nil
+		}
+	}
+	if n.hooked {
+		return ast.WalkContinue, nil
+	}
+	title := template.HTMLEscapeString(n.heading())
+	switch {
+	case entering && n.foldable:
+		open := ""
+		if n.open {
+			open = " open"
+		}
+		_, _ = w.WriteString(`<aside class="callout callout-` + n.kind + `">` + "\n<details" + open + ">\n" +
+			`<summary class="callout-title">` + title + "</summary>\n")
+	case entering:
+		_, _ = w.WriteString(`<aside class="callout callout-` + n.kind + `">` + "\n" +
+			`<p class="callout-title">` + title + "</p>\n")
+	case n.foldable:
+		_, _ = w.WriteString("</details>\n</aside>\n")
+	default:
+		_, _ = w.WriteString("</aside>\n")
+	}
+	return ast.WalkContinue, nil
+}
+
+type calloutExtension struct {
+	hooks *markupHooks
+}
+
+// Extend implements goldmark.Extender.
+func (e calloutExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(
+		gmparser.WithBlockParsers(util.Prioritized(admonitionParser{}, 750)),
+		gmparser.WithASTTransformers(util.Prioritized(calloutTransformer{}, 800)),
+	)
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(calloutRenderer{md: m, hooks: e.hooks}, 500)))
+}
+
+// calloutClassRe is the class attribute the callout markup uses, which the
+// HTML sanitizer (sanitize_html) lets through on the callout's elements.
+var calloutClassRe = regexp.MustCompile(`^callout(-[\w-]+)?( callout-[\w-]+)*$`)
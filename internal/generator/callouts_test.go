@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func renderCallouts(t *testing.T, cfg Config, src string) string {
+	t.Helper()
+	cfg.Callouts = true
+	var buf bytes.Buffer
+	if err := buildMarkdown(cfg).Convert([]byte(src), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestCallouts(t *testing.T) {
+	for _, tc := range []struct{ src, want string }{
+		{"> [!NOTE]\n> Body text.",
+			"<aside class=\"callout callout-note\">\n<p class=\"callout-title\">Note</p>\n<p>Body text.</p>\n</aside>\n"},
+		{"> [!warning] Mind the gap\n>\n> Second paragraph.",
+			"<aside class=\"callout callout-warning\">\n<p class=\"callout-title\">Mind the gap</p>\n<p>Second paragraph.</p>\n</aside>\n"},
+		{"> [!TIP]- More\n> Hidden *until* opened.",
+			"<aside class=\"callout callout-tip\">\n<details>\n<summary class=\"callout-title\">More</summary>\n<p>Hidden <em>until</em> opened.</p>\n</details>\n</aside>\n"},
+		{"> [!faq]+\n> Open.",
+			"<details open>\n<summary class=\"callout-title\">Faq</summary>"},
+		{"!!! tip \"Pro tip\"\n\n    Indented body.\n\n    - one\n\nAfter.",
+			"<aside class=\"callout callout-tip\">\n<p class=\"callout-title\">Pro tip</p>\n<p>Indented body.</p>\n<ul>\n<li>one</li>\n</ul>\n</aside>\n<p>After.</p>"},
+		{"??? danger\n    Folded <b>x</b> & y",
+			"<details>\n<summary class=\"callout-title\">Danger</summary>\n<p>Folded <b>x</b> &amp; y</p>\n</details>"},
+		{"> [!NOTE] <script>x</script>\n> Body.",
+			`<p class="callout-title">&lt;script&gt;x&lt;/script&gt;</p>`},
+		{"> Just a quote, [!NOTE] later.", "<blockquote>\n<p>Just a quote, [!NOTE] later.</p>\n</blockquote>"},
+		{"!!!note without space stays text", "<p>!!!note without space stays text</p>"},
+	} {
+		if got := renderCallouts(t, Config{}, tc.src); !strings.Contains(got, tc.want) {
+			t.Errorf("%q\n got: %s\nwant: %s", tc.src, got, tc.want)
+		}
+	}
+
+	var plain bytes.Buffer
+	_ = buildMarkdown(Config{}).Convert([]byte("> [!NOTE]\n> x"), &plain)
+	if strings.Contains(plain.String(), "callout") {
+		t.Errorf("callouts: false keeps the blockquote: %s", plain.String())
+	}
+}
+
+func TestCalloutHookAndSanitizer(t *testing.T) {
+	root := t.TempDir()
+	mustWrite(t, filepath.Join(root, "theme", "_markup", "render-callout.html"),
+		`<div class="admonition {{.Type}}"{{if .Foldable}} data-open="{{.Open}}"{{end}}><b>{{.Title}}</b>{{.Content}}</div>`)
+	got := renderCallouts(t, Config{TemplatesDir: root, Template: "theme"}, "> [!TIP]+ Hi & bye\n> **Body** with > [!NOTE]\n> > inner")
+	want := `<div class="admonition tip" data-open="true"><b>Hi &amp; bye</b><p><strong>Body</strong> with &gt; [!NOTE]</p>
+<blockquote>
+<p>inner</p>
+</blockquote>
+</div>`
+	if got != want {
+		t.Errorf("hook output:\n got: %s\nwant: %s", got, want)
+	}
+
+	html := renderCallouts(t, Config{}, "> [!CAUTION]- Careful\n> Text.")
+	clean := newSanitizer(true).Sanitize(html)
+	for _, keep := range []string{`<aside class="callout callout-caution">`, `<summary class="callout-title">`, "<details>"} {
+		if !strings.Contains(clean, keep) {
+			t.Errorf("sanitizer dropped %s:\n%s", keep, clean)
+		}
+	}
+	if s := newSanitizer(true).Sanitize(`<aside class="evil" onclick="x()">a</aside>`); strings.Contains(s, "evil") || strings.Contains(s, "onclick") {
+		t.Errorf("only callout classes pass: %s", s)
+	}
+}
@@ -0,0 +1,183 @@
+package generator
+
+// Frontmatter cascade: every post under posts/recipes/ repeating the same
+// layout, type, categories, schema and sitemap keys is one forgotten key away
+// from a broken page. A directory states them once instead, in a
+// _defaults.yaml beside its documents or a `cascade:` map in its section
+// _index.md, and every document below inherits them. content_defaults in the
+// config does the same site-wide, targeted by path glob and type.
+//
+// Precedence, nearest first: the document's own frontmatter, then the
+// directories from its own up to the content root, then content_defaults
+// (later entries over earlier). Each layer fills top-level keys only: a page
+// setting `schema:` replaces the inherited schema rather than merging into it.
+// The defaults are merged before the frontmatter is decoded, so an inherited
+// `status:` or `type:` counts as if the file had written it — and the content
+// schemas, validated after loading, see the result.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spagu/ssg/internal/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultsFileName is the per-directory defaults file. It is data, not a
+// page: the loader never parses it as content and a bundle does not publish it.
+const defaultsFileName = "_defaults.yaml"
+
+// frontmatterDefaults returns the cascade for the document at path, or nil
+// when nothing above it declares any.
+func (g *Generator) frontmatterDefaults(path string) parser.Defaults {
+	dirs := g.cascadeDirs(filepath.Dir(path))
+	var inherited map[string]interface{}
+	for _, dir := range dirs { // farthest first, so nearer directories win
+		for k, v := range g.dirDefaults(dir) {
+			if inherited == nil {
+				inherited = map[string]interface{}{}
+			}
+			inherited[k] = v
+		}
+	}
+	if inherited == nil && len(g.config.ContentDefaults) == 0 {
+		return nil
+	}
+	candidates := g.contentPathCandidates(path)
+	return func(own map[string]interface{}) map[string]interface{} {
+		typ, _ := own["type"].(string)
+		if typ == "" {
+			typ, _ = inherited["type"].(string)
+		}
+		out := map[string]interface{}{}
+		for _, d := range g.config.ContentDefaults {
+			if d.Type != "" && d.Type != typ {
+				continue
+			}
+			if d.Path != "" && !matchesAny(d.Path, candidates) {
+				continue
+			}
+			for k, v := range d.Frontmatter {
+				out[k] = v
+			}
+		}
+		for k, v := range inherited {
+			out[k] = v
+		}
+		return out
+	}
+}
+
+// cascadeDirs lists the directories whose defaults reach dir, from its
+// content root down to dir itself. A directory outside every root cascades
+// only its own defaults.
+func (g *Generator) cascadeDirs(dir string) []string {
+	dir = filepath.Clean(dir)
+	root := g.contentRootOf(dir)
+	if root == "" {
+		return []string{dir}
+	}
+	root = filepath.Clean(root)
+	var dirs []string
+	for {
+		dirs = append([]string{dir}, dirs...)
+		if dir == root {
+			return dirs
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dirs
+		}
+		dir = parent
+	}
+}
+
+// dirDefaults reads the defaults one directory declares: its _defaults.yaml,
+// with the `cascade:` of its section _index over it. Read once per build. A
+// file that cannot be read is reported and ignored, as an unparsable page is.
+func (g *Generator) dirDefaults(dir string) map[string]interface{} {
+	if d, ok := g.cascade[dir]; ok {
+		return d
+	}
+	if g.cascade == nil {
+		g.cascade = map[string]map[string]interface{}{}
+	}
+	var out map[string]interface{}
+	file := filepath.Join(dir, defaultsFileName)
+	if raw, err := os.ReadFile(file); err == nil { // #nosec G304 -- the project's own content directory
+		if err := yaml.Unmarshal(raw, &out); err != nil {
+			fmt.Printf("   ⚠️  Warning: ignoring %s: %v\n", file, err)
+			out = nil
+		}
+	}
+	// An _index directly in pages/, posts/ or a source root is not a section
+	// (sections.go), so it has no cascade either; a _defaults.yaml there does.
+	if entries, err := os.ReadDir(dir); err == nil && !g.isSectionRoot(dir) {
+		for _, e := range entries {
+			if e.IsDir() || !isSectionIndex(e.Name()) {
+				continue
+			}
+			index := filepath.Join(dir, e.Name())
+			page, err := parser.ParseFile(index)
+			if err != nil {
+				break // reported when the section tree is built
+			}
+			cascade, ok := page.Extra["cascade"].(map[string]interface{})
+			if !ok {
+				if _, set := page.Extra["cascade"]; set {
+					fmt.Printf("   ⚠️  Warning: ignoring cascade in %s: it must be a map of frontmatter fields\n", index)
+				}
+				break
+			}
+			if out == nil {
+				out = map[string]interface{}{}
+			}
+			for k, v := range cascade {
+				out[k] = v
+			}
+			break
+		}
+	}
+	g.cascade[dir] = out
+	return out
+}
+
+// contentPathCandidates are the names a content glob can match a file by: its
+// path, its base name and its path relative to the content source.
+func (g *Generator) contentPathCandidates(entryPath string) []string {
+	slashed := filepath.ToSlash(entryPath)
+	candidates := []string{slashed, filepath.Base(entryPath)}
+	if root := filepath.ToSlash(filepath.Join(g.config.ContentDir, g.config.Source)); root != "" {
+		if rel, err := filepath.Rel(root, entryPath); err == nil {
+			candidates = append(candidates, filepath.ToSlash(rel))
+		}
+	}
+	return candidates
+}
+
+// matchesAny reports whether pattern matches one of the candidate names.
+func matchesAny(pattern string, candidates []string) bool {
+	for _, c := range candidates {
+		if matchGlob(pattern, c) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDefaultsFile reports whether a content-directory file is a cascade's
+// _defaults.yaml rather than an asset.
+func isDefaultsFile(name string) bool {
+	return name == defaultsFileName
+}
+
+// isSectionRoot reports whether dir is one of sectionRoots.
+func (g *Generator) isSectionRoot(dir string) bool {
+	for _, root := range g.sectionRoots() {
+		if filepath.Clean(root) == dir {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// TestFrontmatterCascade: _defaults.yaml and a section's `cascade:` reach every
+// document below them, the nearer directory wins, the document's own field
+// wins over all, content_defaults fills what is left by path and by type —
+// and content_schemas validates the result, not the bare file.
+func TestFrontmatterCascade(t *testing.T) {
+	tmp := t.TempDir()
+	recipes := filepath.Join(tmp, "content", "site", "posts", "recipes")
+	mustWrite(t, filepath.Join(tmp, "content", "site", "metadata.json"), `{"categories":[],"exported_at":"","media":[]}`)
+	mustWrite(t, filepath.Join(recipes, "_defaults.yaml"), "type: recipe\ncuisine: italian\nsitemap: \"no\"\n")
+	mustWrite(t, filepath.Join(recipes, "quick", "_index.md"),
+		"---\ntitle: Quick\ncascade:\n  cuisine: any\n  difficulty: easy\n---\n")
+	post := "---\ntitle: %s\nstatus: publish\ndate: 2026-07-01\n%s---\n\nBody.\n"
+	mustWrite(t, filepath.Join(recipes, "carbonara.md"), fmt.Sprintf(post, "Carbonara", ""))
+	mustWrite(t, filepath.Join(recipes, "quick", "toast.md"), fmt.Sprintf(post, "Toast", ""))
+	mustWrite(t, filepath.Join(recipes, "crepes.md"), fmt.Sprintf(post, "Crepes", "cuisine: french\n"))
+	mustWrite(t, filepath.Join(tmp, "content", "site", "pages", "about.md"), fmt.Sprintf(post, "About", ""))
+	writeSimpleTemplates(t, filepath.Join(tmp, "templates", "simple"))
+
+	gen, err := New(Config{Source: "site", Template: "simple", Domain: "example.com",
+		ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+		OutputDir: filepath.Join(tmp, "output"), Quiet: true,
+		ContentDefaults: []models.ContentDefault{
+			{Path: "posts/recipes/**", Frontmatter: map[string]interface{}{"difficulty": "medium", "cuisine": "none"}},
+			{Type: "recipe", Frontmatter: map[string]interface{}{"course": "main"}},
+			{Path: "pages/*", Frontmatter: map[string]interface{}{"course": "none"}},
+		},
+		ContentSchemas: map[string]models.ContentSchema{"recipe": {Required: []string{"cuisine", "difficulty", "course"}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	gen.config.Strict = true
+	if err := gen.validateContentSchemas(); err != nil {
+		t.Errorf("the schema sees the cascaded fields: %v", err)
+	}
+	got := map[string]models.Page{}
+	for _, p := range gen.allContent() {
+		got[p.Title] = p
+	}
+	for title, want := range map[string]map[string]string{
+		"Carbonara": {"cuisine": "italian", "difficulty": "medium", "course": "main"},
+		"Toast":     {"cuisine": "any", "difficulty": "easy", "course": "main"},
+		"Crepes":    {"cuisine": "french", "difficulty": "medium", "course": "main"},
+		"About":     {"course": "none"},
+	} {
+		p, ok := got[title]
+		if !ok {
+			t.Fatalf("%s not loaded", title)
+		}
+		for field, value := range want {
+			if p.Extra[field] != value {
+				t.Errorf("%s: %s = %v, want %q", title, field, p.Extra[field], value)
+			}
+		}
+		if title != "About" && (p.Type != "recipe" || p.Sitemap != "no") {
+			t.Errorf("%s: type %q, sitemap %q — the modelled fields cascade too", title, p.Type, p.Sitemap)
+		}
+	}
+	if got["About"].Type == "recipe" {
+		t.Error("a _defaults.yaml reaches only the documents below it")
+	}
+}
@@ -569,18 +569,10 @@ func (g *Generator) excludedFromContent(entryPath string) bool {
 	if len(g.config.ContentExclude) == 0 {
 		return false
 	}
-	slashed := filepath.ToSlash(entryPath)
-	candidates := []string{slashed, path.Base(slashed)}
-	if root := filepath.ToSlash(filepath.Join(g.config.ContentDir, g.config.Source)); root != "" {
-		if rel, err := filepath.Rel(root, entryPath); err == nil {
-			candidates = append(candidates, filepath.ToSlash(rel))
-		}
-	}
+	candidates := g.contentPathCandidates(entryPath)
 	for _, pattern := range g.config.ContentExclude {
-		for _, c := range candidates {
-			if matchGlob(pattern, c) {
-				return true
-			}
+		if matchesAny(pattern, candidates) {
+			return true
 		}
 	}
 	return false
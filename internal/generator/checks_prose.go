@@ -0,0 +1,75 @@
+package generator
+
+// Build-time prose and structure review: check_prose runs the `lint:` rules
+// (internal/lint) over every page's source, the same rules `ssg lint` runs on
+// demand. Like check_markup it reads the SOURCE — the fix is an edit to the
+// Markdown file, so a finding names the file and the line in it — but it is
+// off by default: a long sentence is a judgement call, not broken output.
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spagu/ssg/internal/lint"
+	"github.com/spagu/ssg/internal/models"
+)
+
+// checkProseIfRequested reports lint findings for every loaded page and post.
+func (g *Generator) checkProseIfRequested() error {
+	if g.config.CheckProse == "" || g.config.CheckProse == "off" {
+		return nil
+	}
+	if err := lint.Validate(g.config.Lint); err != nil {
+		return err
+	}
+	mode := g.resolveMode(g.config.CheckProse)
+	var findings []finding
+	for _, p := range g.allContent() {
+		findings = append(findings, g.proseFindings(p)...)
+	}
+	// Not sortFindings: it orders "page.md:10" before "page.md:9". Pages come
+	// in load order, and each one's findings in line order.
+	return g.report(findings, mode, "prose", "Prose checks passed",
+		"%d prose finding(s); see ssg lint --help")
+}
+
+// proseFindings lints one document, as lintSource reads it.
+func (g *Generator) proseFindings(p models.Page) []finding {
+	label, source := g.lintSource(p)
+	var out []finding
+	for _, f := range lint.Check(source, g.config.Lint.ForType(p.Type), g.pageLang(p)) {
+		out = append(out, finding{
+			file:   fmt.Sprintf("%s:%d", label, f.Line),
+			detail: f.Rule + ": " + f.Message,
+		})
+	}
+	return out
+}
+
+// lintSource is what the source-level checks read for a page: its Markdown
+// file as on disk, so line numbers are the file's and lint-disable comments
+// are seen as written, or, for content without one (MDDB, page_generators
+// rows, Org and notebooks), its loaded body.
+func (g *Generator) lintSource(p models.Page) (label, source string) {
+	label, source = markupSourceLabel(p), p.Content
+	if path := filepath.Join(p.SourceDir, p.SourceFile); p.SourceFile != "" && isMarkdownFile(path) {
+		raw, err := os.ReadFile(path) // #nosec G304 -- the page's own source file
+		if err == nil {
+			return filepath.ToSlash(path), string(raw)
+		}
+	}
+	return label, source
+}
+
+// pageLang is the language a page is written in: its own, the site default,
+// or English for a site that never names one.
+func (g *Generator) pageLang(p models.Page) string {
+	switch {
+	case p.Lang != "":
+		return p.Lang
+	case g.config.DefaultLanguage != "":
+		return g.config.DefaultLanguage
+	}
+	return "en"
+}
@@ -0,0 +1,42 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+func TestCheckProseReportsFileLines(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "guide.md"),
+		[]byte("---\ntitle: Guide\n---\n\nSee https://example.com for more.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	g := newTestGen(t, "")
+	g.siteData.Pages = []models.Page{{SourceDir: dir, SourceFile: "guide.md"}}
+	g.siteData.Posts = []models.Page{{Slug: "from-mddb", Type: "post", Content: "## One\n\n#### Three\n\nhttps://example.com/post\n"}}
+	g.config.CheckProse = "warn"
+	g.config.Lint = models.LintConfig{Types: map[string]models.LintConfig{"post": {Disable: []string{"bare-url"}}}}
+
+	out, err := capture(t, g.checkProseIfRequested)
+	if err != nil {
+		t.Fatalf("warn must not fail the build: %v", err)
+	}
+	for _, want := range []string{"guide.md:5 → bare-url", "from-mddb:3 → heading-increment"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the report:\n%s", want, out)
+		}
+	}
+
+	g.config.CheckProse = "strict"
+	if _, err := capture(t, g.checkProseIfRequested); err == nil || !strings.Contains(err.Error(), "2 prose finding(s)") {
+		t.Errorf("strict: err = %v", err)
+	}
+	g.config.Lint.Disable = []string{"no-such-rule"}
+	if _, err := capture(t, g.checkProseIfRequested); err == nil || !strings.Contains(err.Error(), "unknown lint rule") {
+		t.Errorf("an unknown rule name fails the build: %v", err)
+	}
+}
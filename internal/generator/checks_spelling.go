@@ -0,0 +1,59 @@
+package generator
+
+// Build-time spell checking: check_spelling runs every page's prose (the text
+// a reader sees, not its code, URLs or shortcodes) through the Hunspell
+// dictionary for the page's language, plus the project word lists under
+// data_dir/spelling/. Like check_prose it reads the source, so a finding
+// names the line to edit, and it is off by default: it needs dictionaries the
+// machine may not have.
+
+import (
+	"fmt"
+	"sort"
+
+	ssgi18n "github.com/spagu/ssg/internal/i18n"
+	"github.com/spagu/ssg/internal/lint"
+	"github.com/spagu/ssg/internal/spell"
+)
+
+// checkSpellingIfRequested reports unknown words in every loaded page and
+// post. A language without a dictionary is named once and skipped; a
+// configured dictionary that cannot be read fails the build.
+func (g *Generator) checkSpellingIfRequested() error {
+	if g.config.CheckSpelling == "" || g.config.CheckSpelling == "off" {
+		return nil
+	}
+	mode := g.resolveMode(g.config.CheckSpelling)
+	languages := ssgi18n.Normalize(g.config.Languages, g.config.LanguageConfigs, g.config.LanguageTimezones)
+	dicts := spell.NewSet(g.config.Spelling, g.config.DataDir, languages)
+	missing := map[string]bool{}
+	var findings []finding
+	for _, p := range g.allContent() {
+		lang := g.pageLang(p)
+		dict, err := dicts.For(lang)
+		if err != nil {
+			return err
+		}
+		if dict == nil {
+			missing[lang] = true
+			continue
+		}
+		label, source := g.lintSource(p)
+		for _, f := range lint.Spelling(source, dict.Check) {
+			findings = append(findings, finding{file: fmt.Sprintf("%s:%d", label, f.Line), detail: f.Message})
+		}
+	}
+	if len(missing) > 0 {
+		var langs []string
+		for l := range missing {
+			langs = append(langs, l)
+		}
+		sort.Strings(langs)
+		for _, l := range langs {
+			fmt.Printf("   ℹ️  No Hunspell dictionary for %q; its pages are not spell-checked (set spelling.dictionaries.%s)\n", l, l)
+		}
+	}
+	// In page order and line order, as check_prose reports.
+	return g.report(findings, mode, "spelling", "Spelling check passed",
+		"%d unknown word(s); fix them or accept them with ssg lint --spelling --add-words")
+}
@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+func TestCheckSpellingReportsFileLines(t *testing.T) {
+	dir := t.TempDir()
+	for name, body := range map[string]string{
+		"en.aff":                "SET UTF-8\nSFX S Y 1\nSFX S 0 s .\n",
+		"en.dic":                "6\nthe\nguide/S\ncover/S\nall\nin\ncode\n",
+		"data/spelling/en.txt":  "ssg\n",
+		"content/guide.md":      "---\ntitle: Guide\n---\n\nThe guide covers all ssg featurs.\n\n`featurs` in code\n",
+		"content/przewodnik.md": "---\ntitle: Przewodnik\nlang: pl\n---\n\nPrzewodnik.\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	g := newTestGen(t, "")
+	g.siteData.Pages = []models.Page{
+		{SourceDir: filepath.Join(dir, "content"), SourceFile: "guide.md"},
+		{SourceDir: filepath.Join(dir, "content"), SourceFile: "przewodnik.md", Lang: "pl"},
+	}
+	g.config.DataDir = filepath.Join(dir, "data")
+	g.config.CheckSpelling = "warn"
+	g.config.Spelling = models.SpellingConfig{Dictionaries: map[string]string{"en": filepath.Join(dir, "en.dic")}}
+
+	out, err := capture(t, g.checkSpellingIfRequested)
+	if err != nil {
+		t.Fatalf("warn must not fail the build: %v", err)
+	}
+	for _, want := range []string{`guide.md:5 → unknown word "featurs"`, `No Hunspell dictionary for "pl"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in the report:\n%s", want, out)
+		}
+	}
+	if strings.Count(out, "unknown word") != 1 {
+		t.Errorf("only the prose typo is reported:\n%s", out)
+	}
+
+	g.config.CheckSpelling = "strict"
+	if _, err := capture(t, g.checkSpellingIfRequested); err == nil || !strings.Contains(err.Error(), "1 unknown word(s)") {
+		t.Errorf("strict: err = %v", err)
+	}
+	g.config.Spelling.Dictionaries["en"] = filepath.Join(dir, "missing")
+	if _, err := capture(t, g.checkSpellingIfRequested); err == nil || !strings.Contains(err.Error(), "spelling.dictionaries.en") {
+		t.Errorf("a configured dictionary that is missing fails the build: %v", err)
+	}
+}
@@ -24,6 +24,8 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+
+	"github.com/spagu/ssg/internal/parser"
 )
 
 // unreadDir is one directory holding Markdown the build did not read.
@@ -109,14 +111,15 @@ func (g *Generator) loadedContentRoots(sourcePath string) map[string]bool {
 	return loaded
 }
 
-// countMarkdown counts .md files anywhere under dir.
+// countMarkdown counts content files — .md and the other registered formats —
+// anywhere under dir.
 func countMarkdown(dir string) int {
 	n := 0
 	_ = filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
 		if err != nil || info.IsDir() {
 			return nil //nolint:nilerr // an unreadable subtree is not worth failing a report over
 		}
-		if strings.EqualFold(filepath.Ext(info.Name()), ".md") {
+		if parser.IsContentFile(info.Name()) {
 			n++
 		}
 		return nil
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A notebook and an Org file sit beside Markdown in the content tree and come
+// out as pages like any other, the notebook's plot next to it.
+func TestNotebookAndOrgContentBuild(t *testing.T) {
+	root := t.TempDir()
+	writeIncrementalSite(t, root)
+	content := filepath.Join(root, "content", "site")
+	const png = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+	mustWrite(t, filepath.Join(content, "posts", "news", "plot.ipynb"), `{"cells":[
+  {"cell_type":"raw","source":"---\ntitle: Plotting\nslug: plot\nstatus: publish\ntype: post\ndate: 2024-02-01\ncategories: [News]\n---"},
+  {"cell_type":"code","source":"plot(x)","outputs":[{"output_type":"display_data","data":{"image/png":"`+png+`"}}]}
+ ],"metadata":{"kernelspec":{"language":"python"}},"nbformat":4}`)
+	mustWrite(t, filepath.Join(content, "pages", "notes.org"),
+		"#+TITLE: Notes\n#+SLUG: notes\n#+TYPE: page\n\n* First\n\nSee [[https://orgmode.org][Org]].\n")
+
+	gen := buildScheduled(t, root, nil)
+	out := filepath.Join(root, "output")
+
+	post, err := os.ReadFile(filepath.Join(out, "2024/02/01/plot/index.html"))
+	if err != nil {
+		t.Fatalf("notebook not built: %v", err)
+	}
+	if !strings.Contains(string(post), `<code class="language-python">plot(x)`) ||
+		!strings.Contains(string(post), `<img src="plot-cell1-1.png"`) {
+		t.Errorf("notebook page:\n%s", post)
+	}
+	img, err := os.ReadFile(filepath.Join(out, "2024/02/01/plot/plot-cell1-1.png"))
+	if err != nil || !bytes.HasPrefix(img, []byte("\x89PNG")) {
+		t.Errorf("notebook plot not published next to the page: %v", err)
+	}
+
+	notes, err := os.ReadFile(filepath.Join(out, "notes/index.html"))
+	if err != nil {
+		t.Fatalf("org page not built: %v", err)
+	}
+	if !strings.Contains(string(notes), "<h2") || !strings.Contains(string(notes), `<a href="https://orgmode.org">Org</a>`) {
+		t.Errorf("org page:\n%s", notes)
+	}
+	if gen.siteData == nil || len(gen.siteData.Pages) != 2 {
+		t.Errorf("pages loaded = %d, want about and notes", len(gen.siteData.Pages))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/spagu/ssg/internal/fetch"
 	"github.com/spagu/ssg/internal/models"
 )
 
@@ -34,6 +35,16 @@ type ContentSource struct {
 	// which is created when the loaded metadata does not already define it.
 	// Frontmatter categories on an individual file win.
 	Category string
+
+	// Type "git" reads the source from a repository instead of Path (see
+	// git_sources.go): URL to fetch, Ref (branch, tag or commit; default the
+	// remote's HEAD), Subdir to read, and Auth for a private https remote.
+	// ContentType then says what its entries are: "page" (default) or "post".
+	URL         string
+	Ref         string
+	Subdir      string
+	Auth        fetch.Auth
+	ContentType string
 }
 
 // contentSourceTypes are the accepted Type values.
@@ -46,7 +57,19 @@ func (g *Generator) loadExtraContentSources() error {
 	if len(g.config.ContentSources) == 0 {
 		return nil
 	}
-	for _, src := range g.config.ContentSources {
+	g.gitPins = nil
+	for i, src := range g.config.ContentSources {
+		if src.Type == "git" {
+			// The checkout becomes the source's Path, which is what every
+			// later reader of content_sources (resources, sections, the
+			// dependency graph) looks at.
+			dir, err := g.checkoutGitSource(src)
+			if err != nil {
+				return err
+			}
+			g.config.ContentSources[i].Path = dir
+			src.Path, src.Type = dir, src.ContentType
+		}
 		if err := g.loadOneContentSource(src); err != nil {
 			return err
 		}
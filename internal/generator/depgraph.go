@@ -0,0 +1,857 @@
+package generator
+
+// Incremental rebuilds from a persisted dependency graph.
+//
+// A full build renders every page even when one typo changed. On a 9,000-post
+// site that is forty seconds per save in --watch, and nearly all of it is spent
+// re-rendering bytes that come out identical. The generator therefore records,
+// for every output file it writes, what that file was made from: its source
+// document, the template files (partials included) that executed, the .Data
+// keys those templates read, the taxonomy terms involved and — for listings —
+// the documents listed. The graph is stored under .ssg-cache/deps/ with the
+// content hash of every input, and the next incremental build compares the two.
+//
+// Loading is never skipped. Parsing the content is a small part of a build,
+// and every listing, link map and aggregate needs the whole site in memory to
+// be correct. What is skipped is the expensive part: executing templates and
+// running the per-page HTML transforms for outputs none of whose inputs moved.
+// The aggregates — sitemap, feeds, search index, llms.txt, _redirects — are
+// rebuilt from the loaded content every time; each is one file, and they are
+// exactly the outputs that change whenever anything does.
+//
+// The boundary is drawn conservatively. A change to a document's body
+// re-renders that document and the listings that show it. A change to anything
+// OTHER pages can see — title, URL, date, terms, excerpt, status, a document
+// appearing or disappearing — re-renders everything, because menus, related
+// posts, series navigation and link rewriting all read it and none of them
+// record which pages they touched. A changed input the graph cannot attribute
+// (a shortcode template, metadata.json, a data file no template names) is a
+// full render too. A wrong skip ships a stale page; a needless render costs a
+// second.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spagu/ssg/internal/cache"
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+)
+
+// depGraphVersion invalidates every stored graph when the recording changes
+// shape or meaning; a graph from another version forces a full render.
+const depGraphVersion = 1
+
+// depGraph is the persisted record of one build.
+type depGraph struct {
+	Version int `json:"version"`
+	// Config fingerprints the generator settings: a graph recorded under other
+	// settings describes other outputs.
+	Config string `json:"config"`
+	// External fingerprints the external-source data, which is not a file the
+	// input scan can see.
+	External string `json:"external,omitempty"`
+	// Inputs holds the content hash of every file under the watched roots.
+	Inputs map[string]inputSig `json:"inputs"`
+	// Includes holds the hash of every file an include read, keyed relative to
+	// the project root: snippets usually live outside the watched roots.
+	Includes map[string]inputSig `json:"includes,omitempty"`
+	// Sources holds, per document, the signature of everything about it that
+	// other pages can see (see sourceSignature).
+	Sources map[string]string `json:"sources"`
+	// Outputs is keyed by the output path relative to output_dir.
+	Outputs map[string]outputDeps `json:"outputs"`
+}
+
+// inputSig is one input file's hash with its freshness key: size and mtime
+// unchanged ⇒ the stored hash is reused instead of re-reading the file.
+type inputSig struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	Hash    string `json:"hash"`
+}
+
+// outputDeps is what one output file was made from.
+type outputDeps struct {
+	Source    string   `json:"source,omitempty"`    // the document rendered
+	Listed    []string `json:"listed,omitempty"`    // documents a listing shows
+	Templates []string `json:"templates,omitempty"` // template files executed, relative to the theme
+	Data      []string `json:"data,omitempty"`      // top-level .Data keys read; "*" = all of .Data
+	Terms     []string `json:"terms,omitempty"`     // taxonomy terms, as kind:slug
+}
+
+func newDepGraph() *depGraph {
+	return &depGraph{
+		Version: depGraphVersion,
+		Inputs:  map[string]inputSig{},
+		Sources: map[string]string{},
+		Outputs: map[string]outputDeps{},
+	}
+}
+
+// depGraphPath is where the graph of a build into outputDir is kept. One file
+// per output directory, so two sites built from one project (or a preview and
+// a production build) never read each other's record.
+func depGraphPath(outputDir string) string {
+	abs, err := filepath.Abs(outputDir)
+	if err != nil {
+		abs = outputDir
+	}
+	k := cache.NewKeyer("", 12)
+	k.WriteString(filepath.ToSlash(abs))
+	return filepath.Join(cache.Dir("", "deps"), "graph-"+k.Sum()+".json")
+}
+
+// loadDepGraph reads a stored graph; a missing or unreadable one is nil, which
+// the planner treats as "nothing to compare against".
+func loadDepGraph(path string) *depGraph {
+	raw, err := os.ReadFile(path) // #nosec G304 -- the graph lives in the project's own cache dir
+	if err != nil {
+		return nil
+	}
+	var dg depGraph
+	if err := json.Unmarshal(raw, &dg); err != nil || dg.Outputs == nil {
+		return nil
+	}
+	return &dg
+}
+
+// save writes the graph atomically, so an interrupted build never leaves a
+// half-written record the next one would trust.
+func (dg *depGraph) save(path string) error {
+	raw, err := json.Marshal(dg)
+	if err != nil {
+		return err
+	}
+	return cache.WriteAtomicBytes(filepath.Dir(path), filepath.Base(path), 0o644, raw)
+}
+
+// depTracker holds one incremental build's state: the previous graph it
+// decides against, the graph being recorded, and what changed in between.
+// Rendering is parallel (BUILD-PARALLEL), so everything mutable is under mu.
+type depTracker struct {
+	mu   sync.Mutex
+	path string
+	out  string // output_dir, to relativise output paths
+	prev *depGraph
+	next *depGraph
+	// full renders everything (still recording); reason says why, for the log.
+	full   bool
+	reason string
+	// What moved since prev: documents whose body changed, theme files, and
+	// top-level data keys.
+	changedSources   map[string]bool
+	changedTemplates map[string]bool
+	changedData      map[string]bool
+	// prevBySource indexes prev.Outputs by the document they render.
+	prevBySource map[string][]string
+	// includers maps an included file to the documents including it.
+	includers map[string][]string
+	// templates resolves a template name to the files and data keys it reaches.
+	templates *templateDeps
+	// rendered lists the outputs this build wrote; skipped counts the rest.
+	rendered []string
+	skipped  int
+	// written lists the outputs written outside the page render: aggregates,
+	// copied assets, rewritten stylesheets. untracked names a pass that
+	// rewrote outputs without listing them, which voids the list.
+	written   map[string]bool
+	untracked string
+}
+
+// sourceKey identifies a document in the graph: the file it was read from, or
+// — for content that has no file (MDDB, a CMS merge) — its output path.
+func sourceKey(p models.Page) string {
+	if p.SourceFile == "" {
+		return "page:" + p.GetOutputPath()
+	}
+	return filepath.ToSlash(filepath.Join(p.SourceDir, p.SourceFile))
+}
+
+// sourceSignature hashes what other pages can see of a document: everything
+// but the body. A body-only edit leaves it unchanged, which is what lets a
+// typo fix re-render one page. Content without a file has no input hash to
+// compare, so its body is folded in and any edit to it is a full render.
+// Modified goes too: without front matter it is the file's mtime, so every
+// save moves it, and the listings and feeds that show it re-render anyway.
+func sourceSignature(p models.Page) string {
+	if p.SourceFile != "" {
+		p.Content = ""
+		p.WordCount = 0
+		p.Modified = time.Time{}
+	}
+	raw, err := json.Marshal(p)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// configFingerprint hashes the settings that shape output. Runtime-only knobs
+// (quiet, clean, the worker count, the incremental switch itself, a watcher's
+// changed paths, the profile file) and the service clients are left out: they
+// change how a build runs, not what it writes.
+func configFingerprint(cfg Config) string {
+	cfg.Quiet, cfg.Clean, cfg.Incremental = false, false, false
+	cfg.BuildWorkers, cfg.ChangedPaths, cfg.Profile = 0, nil, ""
+	cfg.AI, cfg.Notify = nil, nil
+	raw, err := json.Marshal(cfg)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// inputRoots are the trees whose files feed a build: the primary source, every
+// content_sources root, the theme, and the data directory.
+func (g *Generator) inputRoots() (content []string, theme, data string) {
+	if g.config.Source != "" {
+		content = append(content, filepath.Join(g.config.ContentDir, g.config.Source))
+	}
+	for _, src := range g.config.ContentSources {
+		if p := strings.TrimSpace(src.Path); p != "" {
+			content = append(content, filepath.Clean(p))
+		}
+	}
+	data = g.config.DataDir
+	if data == "" {
+		data = "data"
+	}
+	return content, filepath.Join(g.config.TemplatesDir, g.config.Template), filepath.Clean(data)
+}
+
+// hashInputs hashes every file under the input roots, reusing prev's hash for
+// a file whose size and mtime are unchanged (the same trade the watch loop
+// makes, PERF-008), so an incremental build reads only what moved. Paths in
+// forced (absolute) are re-hashed regardless: a watcher said they changed.
+func hashInputs(roots []string, prev *depGraph, forced map[string]bool) map[string]inputSig {
+	out := map[string]inputSig{}
+	for _, root := range roots {
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error { // #nosec G703 -- CLI scans its own input trees
+			if err != nil || info.IsDir() {
+				return nil //nolint:nilerr // an unreadable entry is skipped, as the watcher does
+			}
+			key := filepath.ToSlash(path)
+			if prev != nil && !isForced(forced, path) {
+				if old, ok := prev.Inputs[key]; ok && old.Size == info.Size() && old.ModTime == info.ModTime().UnixNano() {
+					out[key] = old
+					return nil
+				}
+			}
+			if sum, herr := hashFile(path); herr == nil {
+				out[key] = inputSig{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Hash: sum}
+			}
+			return nil
+		})
+	}
+	return out
+}
+
+func isForced(forced map[string]bool, path string) bool {
+	if len(forced) == 0 {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	return err == nil && forced[abs]
+}
+
+// hashFile streams one file through sha256.
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path) // #nosec G304 -- CLI hashes its own input trees
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// planIncremental decides, after the content is loaded and before anything is
+// rendered, which outputs this build must write. A no-op unless incremental
+// builds are on.
+func (g *Generator) planIncremental() {
+	if !g.config.Incremental {
+		return
+	}
+	content, theme, data := g.inputRoots()
+	t := &depTracker{
+		path:             depGraphPath(g.config.OutputDir),
+		out:              g.config.OutputDir,
+		next:             newDepGraph(),
+		changedSources:   map[string]bool{},
+		changedTemplates: map[string]bool{},
+		changedData:      map[string]bool{},
+		written:          map[string]bool{},
+	}
+	g.deps = t
+	if g.engine == nil {
+		t.templates = loadTemplateDeps(g.themeChain())
+	}
+	prev := loadDepGraph(t.path)
+	t.next.Config = configFingerprint(g.config)
+	forced := map[string]bool{}
+	for _, p := range g.config.ChangedPaths {
+		if abs, err := filepath.Abs(p); err == nil {
+			forced[abs] = true
+		}
+	}
+	// A parent theme's files are inputs too; an edit to one is outside the
+	// theme root, so diff treats it as a change to everything.
+	roots := append(append(append([]string{}, content...), theme, data), g.themeDirs()[1:]...)
+	t.next.Inputs = hashInputs(roots, prev, forced)
+	if raw, err := json.Marshal(g.externalData); err == nil && len(g.externalData) > 0 {
+		sum := sha256.Sum256(raw)
+		t.next.External = hex.EncodeToString(sum[:])
+	}
+	t.includers = map[string][]string{}
+	for _, p := range g.allContent() {
+		t.next.Sources[sourceKey(p)] = sourceSignature(p)
+		for _, f := range p.Includes {
+			t.includers[f] = append(t.includers[f], sourceKey(p))
+		}
+	}
+	// Files included from templates are known only once rendered; the last
+	// build's list stands in for them until finishIncremental.
+	included := sortedKeys(t.includers)
+	for f := range prevIncludes(prev) {
+		if _, ok := t.includers[f]; !ok {
+			included = append(included, f)
+		}
+	}
+	t.next.Includes = hashInputs(included, &depGraph{Inputs: prevIncludes(prev)}, forced)
+
+	switch {
+	case g.config.Mddb.Enabled:
+		t.full, t.reason = true, "content comes from MDDB, not from files"
+	case prev == nil:
+		t.full, t.reason = true, "no dependency graph recorded yet"
+	case prev.Version != depGraphVersion || prev.Config != t.next.Config || t.next.Config == "":
+		t.full, t.reason = true, "configuration changed"
+	case prev.External != t.next.External:
+		t.full, t.reason = true, "external source data changed"
+	default:
+		t.prev = prev
+		if reason := t.diff(content, theme, data, g.engine != nil); reason != "" {
+			t.full, t.reason, t.prev = true, reason, nil
+		}
+	}
+	if t.prev != nil {
+		t.prevBySource = map[string][]string{}
+		for rel, d := range t.prev.Outputs {
+			if d.Source != "" {
+				t.prevBySource[d.Source] = append(t.prevBySource[d.Source], rel)
+			}
+		}
+	}
+}
+
+// allContent is every document the build renders.
+func (g *Generator) allContent() []models.Page {
+	out := make([]models.Page, 0, len(g.siteData.Pages)+len(g.siteData.Posts))
+	out = append(out, g.siteData.Pages...)
+	return append(out, g.siteData.Posts...)
+}
+
+// diff compares the recorded build with this one and fills the changed sets.
+// It returns a non-empty reason when the change cannot be confined to the
+// outputs that recorded it, and the build must render everything.
+func (t *depTracker) diff(content []string, theme, data string, altEngine bool) string {
+	prev, next := t.prev, t.next
+	// A document appearing, disappearing or changing what others see of it.
+	for _, k := range unionKeys(prev.Sources, next.Sources) {
+		was, had := prev.Sources[k]
+		now, has := next.Sources[k]
+		switch {
+		case !had:
+			return k + " was added"
+		case !has:
+			return k + " was removed"
+		case was != now:
+			return "front matter of " + k + " changed"
+		}
+	}
+	// Directories holding a document, for co-located assets.
+	docDirs := map[string][]string{}
+	for k := range next.Sources {
+		if !strings.HasPrefix(k, "page:") {
+			docDirs[filepath.ToSlash(filepath.Dir(k))] = append(docDirs[filepath.ToSlash(filepath.Dir(k))], k)
+		}
+	}
+	themeRoot := filepath.ToSlash(theme) + "/"
+	dataRoot := filepath.ToSlash(data) + "/"
+	for _, path := range unionKeys(prev.Inputs, next.Inputs) {
+		was, had := prev.Inputs[path]
+		now, has := next.Inputs[path]
+		if had && has && was.Hash == now.Hash {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(path, themeRoot):
+			if altEngine {
+				return "template " + path + " changed"
+			}
+			if !had || !has {
+				return "template " + path + " was added or removed"
+			}
+			t.changedTemplates[strings.TrimPrefix(path, themeRoot)] = true
+		case strings.HasPrefix(path, dataRoot):
+			if !had || !has {
+				return "data file " + path + " was added or removed"
+			}
+			t.changedData[dataKey(strings.TrimPrefix(path, dataRoot))] = true
+		case isSectionIndex(filepath.Base(path)):
+			// An _index.md feeds every page below it: titles, order, trails.
+			return "section " + path + " changed"
+		case isDefaultsFile(filepath.Base(path)):
+			// So does a _defaults.yaml: its fields are in their frontmatter.
+			return "frontmatter defaults " + path + " changed"
+		case isContentFile(path):
+			// Whether a document was added or removed is the Sources diff's
+			// call above: a new draft adds an input, not a page.
+			if _, isDoc := next.Sources[path]; isDoc && had && has {
+				t.changedSources[path] = true
+			}
+		default:
+			docs := docDirs[filepath.ToSlash(filepath.Dir(path))]
+			if len(docs) == 0 {
+				return path + " changed"
+			}
+			// A co-located asset: the documents beside it copy it on render.
+			for _, k := range docs {
+				t.changedSources[k] = true
+			}
+		}
+	}
+	// An included file is part of the body of every document including it. One
+	// no document includes is read by a template, which records no page.
+	for _, path := range unionKeys(prev.Includes, next.Includes) {
+		was, had := prev.Includes[path]
+		now, has := next.Includes[path]
+		if had && has && was.Hash == now.Hash {
+			continue
+		}
+		docs := t.includers[path]
+		if len(docs) == 0 {
+			return "included file " + path + " changed"
+		}
+		for _, k := range docs {
+			t.changedSources[k] = true
+		}
+	}
+	return t.unattributed()
+}
+
+// prevIncludes is a graph's include hashes, nil-safe.
+func prevIncludes(prev *depGraph) map[string]inputSig {
+	if prev == nil {
+		return nil
+	}
+	return prev.Includes
+}
+
+// dataKey is the top-level .Data key a file under the data dir loads into:
+// data/site.yaml is .Data.site, data/nav/main.yaml is .Data.nav.
+func dataKey(rel string) string {
+	first := strings.SplitN(rel, "/", 2)[0]
+	if !strings.Contains(rel, "/") {
+		first = strings.TrimSuffix(first, filepath.Ext(first))
+	}
+	return first
+}
+
+// unattributed reports a changed theme file or data key no recorded output
+// depends on. Something still reads it — a shortcode template, a helper — and
+// the graph does not know what, so the whole site renders.
+func (t *depTracker) unattributed() string {
+	usedTmpl := map[string]bool{}
+	usedData := map[string]bool{}
+	for _, d := range t.prev.Outputs {
+		for _, f := range d.Templates {
+			usedTmpl[f] = true
+		}
+		for _, k := range d.Data {
+			usedData[k] = true
+		}
+	}
+	for f := range t.changedTemplates {
+		if !usedTmpl[f] {
+			return "template " + f + " changed and no recorded page uses it"
+		}
+	}
+	for k := range t.changedData {
+		if !usedData[k] && !usedData["*"] {
+			return "data " + k + " changed and no recorded template reads it"
+		}
+	}
+	return ""
+}
+
+// isContentFile reports whether a path is a document the content loader reads.
+func isContentFile(path string) bool {
+	return parser.IsContentFile(path)
+}
+
+// depsDirty reports whether an output's template or data inputs moved.
+func (t *depTracker) depsDirty(d outputDeps) bool {
+	for _, f := range d.Templates {
+		if t.changedTemplates[f] {
+			return true
+		}
+	}
+	for _, k := range d.Data {
+		if k == "*" && len(t.changedData) > 0 || t.changedData[k] {
+			return true
+		}
+	}
+	return false
+}
+
+// outputExists guards a skip: an output deleted by hand (or by --clean) is
+// rendered again whatever the graph says.
+func (t *depTracker) outputExists(rel string) bool {
+	_, err := os.Stat(filepath.Join(t.out, filepath.FromSlash(rel)))
+	return err == nil
+}
+
+// skipPage reports whether a document's outputs are all current, carrying
+// their records into the new graph when they are. Called before the page's
+// template data is even built, so a skipped page costs no Markdown conversion.
+func (t *depTracker) skipPage(p models.Page) bool {
+	if t == nil || t.prev == nil || p.SourceFile == "" {
+		return false
+	}
+	key := sourceKey(p)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.changedSources[key] {
+		return false
+	}
+	// A note it transcludes is part of its body.
+	for _, embedded := range p.Embeds {
+		if t.changedSources[embedded] {
+			return false
+		}
+	}
+	rels := t.prevBySource[key]
+	if len(rels) == 0 {
+		return false
+	}
+	for _, rel := range rels {
+		if t.depsDirty(t.prev.Outputs[rel]) || !t.outputExists(rel) {
+			return false
+		}
+	}
+	for _, rel := range rels {
+		t.next.Outputs[rel] = t.prev.Outputs[rel]
+	}
+	t.skipped += len(rels)
+	return true
+}
+
+// skipListing is skipPage for an output that is not one document's page: an
+// index, an archive, a term listing. It is current when nothing it listed and
+// none of its templates or data changed.
+func (t *depTracker) skipListing(outputPath string) bool {
+	if t == nil || t.prev == nil {
+		return false
+	}
+	rel := t.rel(outputPath)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	d, ok := t.prev.Outputs[rel]
+	if !ok || d.Source != "" || t.depsDirty(d) || t.listingDirty(d) || !t.outputExists(rel) {
+		return false
+	}
+	t.next.Outputs[rel] = d
+	t.skipped++
+	return true
+}
+
+// listingDirty reports whether a listing shows a changed document. A listing
+// whose documents were not recorded falls back to its terms, and one with
+// neither depends on every document.
+func (t *depTracker) listingDirty(d outputDeps) bool {
+	if len(t.changedSources) == 0 {
+		return false
+	}
+	if len(d.Listed) > 0 {
+		for _, k := range d.Listed {
+			if t.changedSources[k] {
+				return true
+			}
+		}
+		return false
+	}
+	if len(d.Terms) == 0 {
+		return true
+	}
+	terms := map[string]bool{}
+	for _, term := range d.Terms {
+		terms[term] = true
+	}
+	for k := range t.changedSources {
+		for _, rel := range t.prevBySource[k] {
+			for _, term := range t.prev.Outputs[rel].Terms {
+				if terms[term] {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// record notes what an output written by this build was made from.
+func (t *depTracker) record(outputPath string, d outputDeps) {
+	if t == nil {
+		return
+	}
+	rel := t.rel(outputPath)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.next.Outputs[rel] = d
+	t.rendered = append(t.rendered, rel)
+}
+
+func (t *depTracker) rel(outputPath string) string {
+	if r, err := filepath.Rel(t.out, outputPath); err == nil {
+		return filepath.ToSlash(r)
+	}
+	return filepath.ToSlash(outputPath)
+}
+
+// recordRender is renderPageTemplate's hook: it notes what a rendered output
+// depended on. page is nil (or, for the front page, file-less) for listings,
+// whose listed documents come from the template context.
+func (g *Generator) recordRender(templateName, outputPath string, data interface{}, page *models.Page) {
+	t := g.deps
+	if t == nil {
+		return
+	}
+	d := outputDeps{}
+	if t.templates != nil {
+		d.Templates, d.Data = t.templates.closure(templateName)
+	} else {
+		// An alt engine has no parse tree to walk: any theme change is a full
+		// render (see diff), and .Data is assumed read whole.
+		d.Data = []string{"*"}
+	}
+	if page != nil && page.SourceFile != "" {
+		d.Source = sourceKey(*page)
+		d.Terms = g.pageTerms(*page)
+	} else {
+		d.Listed, d.Terms = listedSources(data)
+	}
+	t.record(outputPath, d)
+}
+
+// pageTerms names the taxonomy terms a document belongs to, in the kind:slug
+// form the archive listings record.
+func (g *Generator) pageTerms(p models.Page) []string {
+	seen := map[string]bool{}
+	var terms []string
+	add := func(term string) {
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+	for _, id := range p.Categories {
+		if cat, ok := g.siteData.Categories[id]; ok {
+			add("category:" + cat.Slug)
+		}
+	}
+	for _, tag := range p.Tags {
+		slug := g.siteData.TagSlugs[strings.ToLower(tag)]
+		if slug == "" {
+			slug = slugify(tag)
+		}
+		add("tag:" + slug)
+	}
+	for _, name := range sortedKeys(p.Taxonomies) {
+		for _, term := range p.Taxonomies[name] {
+			add(name + ":" + slugify(term))
+		}
+	}
+	return terms
+}
+
+// listedSources reads a listing's context for the documents it shows (Posts,
+// and the Pages the front page carries) and the term it lists (Kind plus the
+// Category every archive context holds). Contexts are a map for archives and
+// a struct for the index, so both shapes are read.
+func listedSources(data interface{}) (listed, terms []string) {
+	field := func(name string) interface{} {
+		if m, ok := data.(map[string]interface{}); ok {
+			return m[name]
+		}
+		v := reflect.ValueOf(data)
+		if v.Kind() == reflect.Pointer {
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return nil
+		}
+		if f := v.FieldByName(name); f.IsValid() && f.CanInterface() {
+			return f.Interface()
+		}
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, name := range []string{"Posts", "Pages"} {
+		pages, _ := field(name).([]models.Page)
+		for _, p := range pages {
+			if k := sourceKey(p); !seen[k] {
+				seen[k] = true
+				listed = append(listed, k)
+			}
+		}
+	}
+	sort.Strings(listed)
+	kind, _ := field("Kind").(string)
+	if cat, ok := field("Category").(models.Category); ok && kind != "" && cat.Slug != "" {
+		terms = []string{kind + ":" + cat.Slug}
+	}
+	return listed, terms
+}
+
+// finishIncremental stores the graph and reports what the build rendered. The
+// graph is written only for a build that completed: a failed one keeps the
+// previous record, against which everything it touched still reads as changed.
+func (g *Generator) finishIncremental() {
+	t := g.deps
+	if t == nil {
+		return
+	}
+	// Template includes are known now. A full render saw every include there
+	// is, so files nothing read any more are dropped; a partial one skipped
+	// templates, and keeps what they read last time.
+	used := map[string]bool{}
+	for _, f := range g.IncludedFiles() {
+		used[f] = true
+	}
+	var missing []string
+	for f := range used {
+		if _, ok := t.next.Includes[f]; !ok {
+			missing = append(missing, f)
+		}
+	}
+	for f, sig := range hashInputs(missing, nil, nil) {
+		t.next.Includes[f] = sig
+	}
+	if t.full {
+		for f := range t.next.Includes {
+			if !used[f] {
+				delete(t.next.Includes, f)
+			}
+		}
+	}
+	if err := t.next.save(t.path); err != nil {
+		fmt.Printf("   ⚠️  Could not store the dependency graph: %v\n", err)
+	}
+	if g.config.Quiet {
+		return
+	}
+	if t.full {
+		fmt.Printf("   ♻️  Incremental: full render — %s (%d outputs recorded)\n", t.reason, len(t.rendered))
+		return
+	}
+	fmt.Printf("   ♻️  Incremental: re-rendered %d of %d outputs\n", len(t.rendered), len(t.rendered)+t.skipped)
+}
+
+// RenderedOutputs lists the outputs the last incremental build wrote, relative
+// to the output directory. Nil when incremental builds are off.
+func (g *Generator) RenderedOutputs() []string {
+	if g.deps == nil {
+		return nil
+	}
+	g.deps.mu.Lock()
+	defer g.deps.mu.Unlock()
+	out := append([]string(nil), g.deps.rendered...)
+	sort.Strings(out)
+	return out
+}
+
+// AggregateOutputs lists the outputs the last incremental build wrote outside
+// the page render — sitemap, feeds, search index, copied assets, bundles and
+// minified stylesheets — relative to the output directory. With
+// RenderedOutputs it is everything the build touched, which is what a
+// watching dev server diffs instead of walking the output directory. ok is
+// false when that is not known: incremental builds are off, or a pass
+// (fingerprinting, versions) rewrote files across the tree.
+func (g *Generator) AggregateOutputs() (files []string, ok bool) {
+	if g.deps == nil {
+		return nil, false
+	}
+	g.deps.mu.Lock()
+	defer g.deps.mu.Unlock()
+	if g.deps.untracked != "" {
+		return nil, false
+	}
+	return sortedKeys(g.deps.written), true
+}
+
+// writeOutput writes an output file the page render does not produce and
+// notes it for AggregateOutputs.
+func (g *Generator) writeOutput(path string, data []byte) error {
+	// #nosec G306,G703 -- Web content files need to be world-readable; the CLI writes its own output
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	g.noteWritten(path)
+	return nil
+}
+
+// noteWritten records an output written outside the page render. A copy to
+// somewhere else (a version's cached snapshot) is not an output.
+func (g *Generator) noteWritten(path string) {
+	t := g.deps
+	if t == nil {
+		return
+	}
+	rel := t.rel(path)
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return
+	}
+	t.mu.Lock()
+	t.written[rel] = true
+	t.mu.Unlock()
+}
+
+// noteUntracked records a pass that rewrote outputs without naming them.
+func (g *Generator) noteUntracked(pass string) {
+	t := g.deps
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	t.untracked = pass
+	t.mu.Unlock()
+}
+
+// unionKeys returns the sorted union of two maps' keys.
+func unionKeys[V any](a, b map[string]V) []string {
+	seen := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		seen[k] = true
+	}
+	for k := range b {
+		seen[k] = true
+	}
+	return sortedKeys(seen)
+}
@@ -0,0 +1,197 @@
+package generator
+
+// Which theme files and .Data keys a template reaches (incremental builds).
+//
+// html/template can say which template was executed but not which partials it
+// pulled in, and a theme's header partial is exactly the edit that must
+// re-render every page while a single layout's edit must not. The theme is
+// parsed here a second time, statically: every {{template}} and {{block}}
+// reference is followed to the file defining it, and every .Data.<key> field
+// read on the way is collected. html/template's own trees are not used — the
+// escaper rewrites their template names once they have run.
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"text/template/parse"
+)
+
+// templateDeps resolves a template name to the theme files and data keys its
+// execution can reach. Safe for concurrent use; closures are memoized.
+type templateDeps struct {
+	mu sync.Mutex
+	// trees holds the definition that wins for each name, in the order
+	// loadTemplates parses (root, layouts/, partials/ — later wins).
+	trees map[string]*parse.Tree
+	// files maps a name to the theme file (relative, slash form) defining it.
+	files map[string]string
+	memo  map[string]tmplClosure
+}
+
+type tmplClosure struct {
+	files []string
+	data  []string
+	names []string // the templates reached, for ssg explain
+}
+
+// loadTemplateDeps parses the theme chain (child first) the way loadTemplates
+// loads it: farthest parent first, so a child's definitions win. Files are
+// named relative to the child theme; a parent's start with "../" and never
+// match a changed child file, which is right — a parent's edit re-renders
+// everything. Files that fail to parse are left out; loadTemplates reports
+// them, and a template that did not load is not one a page can depend on.
+func loadTemplateDeps(layers []themeLayer) *templateDeps {
+	td := &templateDeps{trees: map[string]*parse.Tree{}, files: map[string]string{}, memo: map[string]tmplClosure{}}
+	if len(layers) == 0 {
+		return td
+	}
+	theme := layers[0].Dir
+	for i := len(layers) - 1; i >= 0; i-- {
+		for _, sub := range []string{"", "layouts", "partials"} {
+			matches, _ := filepath.Glob(filepath.Join(layers[i].Dir, sub, htmlGlobPattern))
+			sort.Strings(matches)
+			for _, path := range matches {
+				raw, err := os.ReadFile(path) // #nosec G304 -- theme files of the project being built
+				if err != nil {
+					continue
+				}
+				rel, _ := filepath.Rel(theme, path)
+				td.add(filepath.Base(path), filepath.ToSlash(rel), string(raw))
+			}
+		}
+	}
+	return td
+}
+
+// add parses one theme file and registers every template it defines. Function
+// names are not checked: the FuncMap is the renderer's business, not ours.
+func (td *templateDeps) add(name, rel, text string) {
+	treeSet := map[string]*parse.Tree{}
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	if _, err := t.Parse(text, "{{", "}}", treeSet); err != nil {
+		return
+	}
+	for defined, tree := range treeSet {
+		// ParseGlob keeps an earlier non-empty definition over a later empty
+		// one (the file-name shell of a defines-only file), and so do we.
+		if old, ok := td.trees[defined]; ok && isEmptyTree(tree) && !isEmptyTree(old) {
+			continue
+		}
+		td.trees[defined] = tree
+		td.files[defined] = rel
+	}
+}
+
+func isEmptyTree(t *parse.Tree) bool {
+	return t == nil || t.Root == nil || strings.TrimSpace(t.Root.String()) == ""
+}
+
+// closure returns the theme files a template reaches and the top-level .Data
+// keys it reads ("*" when .Data is handed on whole), both sorted.
+func (td *templateDeps) closure(name string) (files, data []string) {
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	if c, ok := td.memo[name]; ok {
+		return c.files, c.data
+	}
+	fileSet, dataSet, nameSet := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	seen := map[string]bool{}
+	var visit func(string)
+	visit = func(n string) {
+		if seen[n] {
+			return
+		}
+		seen[n] = true
+		tree, ok := td.trees[n]
+		if !ok {
+			return
+		}
+		fileSet[td.files[n]] = true
+		nameSet[n] = true
+		walkTemplateNode(tree.Root, visit, func(key string) { dataSet[key] = true })
+	}
+	visit(name)
+	c := tmplClosure{files: sortedKeys(fileSet), data: sortedKeys(dataSet), names: sortedKeys(nameSet)}
+	td.memo[name] = c
+	return c.files, c.data
+}
+
+// reached returns the names of the templates a template's execution can
+// reach, itself included, sorted.
+func (td *templateDeps) reached(name string) []string {
+	td.closure(name)
+	td.mu.Lock()
+	defer td.mu.Unlock()
+	return td.memo[name].names
+}
+
+// walkTemplateNode visits a parse tree, reporting {{template}} targets and
+// .Data key reads.
+func walkTemplateNode(node parse.Node, tmpl func(string), data func(string)) {
+	if node == nil {
+		return
+	}
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			walkTemplateNode(c, tmpl, data)
+		}
+	case *parse.ActionNode:
+		walkTemplateNode(n.Pipe, tmpl, data)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			walkTemplateNode(c, tmpl, data)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			walkTemplateNode(a, tmpl, data)
+		}
+	case *parse.IfNode:
+		walkBranch(&n.BranchNode, tmpl, data)
+	case *parse.RangeNode:
+		walkBranch(&n.BranchNode, tmpl, data)
+	case *parse.WithNode:
+		walkBranch(&n.BranchNode, tmpl, data)
+	case *parse.TemplateNode:
+		tmpl(n.Name)
+		walkTemplateNode(n.Pipe, tmpl, data)
+	case *parse.FieldNode:
+		dataRefs(n.Ident, data)
+	case *parse.VariableNode:
+		dataRefs(n.Ident, data)
+	case *parse.ChainNode:
+		walkTemplateNode(n.Node, tmpl, data)
+		dataRefs(n.Field, data)
+	}
+}
+
+func walkBranch(b *parse.BranchNode, tmpl func(string), data func(string)) {
+	walkTemplateNode(b.Pipe, tmpl, data)
+	walkTemplateNode(b.List, tmpl, data)
+	walkTemplateNode(b.ElseList, tmpl, data)
+}
+
+// dataRefs reports the key after a Data identifier: .Data.nav reads "nav",
+// while a bare .Data (passed to index, ranged over) may read anything.
+func dataRefs(idents []string, data func(string)) {
+	for i, id := range idents {
+		if id != "Data" {
+			continue
+		}
+		if i+1 < len(idents) {
+			data(idents[i+1])
+		} else {
+			data("*")
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeIncrementalSite writes a small site whose post template pulls in a
+// partial that reads .Data.site, while the page template reads neither — so a
+// test can tell "every post" from "every page" from "one document".
+func writeIncrementalSite(t *testing.T, root string) {
+	t.Helper()
+	content := filepath.Join(root, "content", "site")
+	mustWrite(t, filepath.Join(content, "metadata.json"),
+		`{"categories":[{"id":1,"name":"News","slug":"news"}],"media":[],"users":[]}`)
+	for _, slug := range []string{"alpha", "beta"} {
+		mustWrite(t, filepath.Join(content, "posts", "news", slug+".md"),
+			"---\ntitle: "+slug+"\nslug: "+slug+"\nstatus: publish\ntype: post\ndate: 2024-01-02\n"+
+				"categories: [News]\ntags: [go]\n---\n\nBody of "+slug+".\n")
+	}
+	mustWrite(t, filepath.Join(content, "pages", "about.md"),
+		"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\nAbout us.\n")
+
+	theme := filepath.Join(root, "templates", "simple")
+	mustWrite(t, filepath.Join(theme, "post.html"), `<html>{{template "head" .}}<h1>{{.Title}}</h1>{{.Content}}</html>`)
+	mustWrite(t, filepath.Join(theme, "page.html"), `<html><h1>{{.Title}}</h1>{{.Content}}</html>`)
+	mustWrite(t, filepath.Join(theme, "index.html"), `<html>{{range .Posts}}<a>{{.Title}}</a>{{end}}</html>`)
+	mustWrite(t, filepath.Join(theme, "category.html"), `<html>{{.Name}}{{range .Posts}}<a>{{.Title}}</a>{{end}}</html>`)
+	mustWrite(t, filepath.Join(theme, "partials", "head.html"), `{{define "head"}}<head>{{.Data.site.name}}</head>{{end}}`)
+	mustWrite(t, filepath.Join(root, "data", "site.yaml"), "name: Demo\n")
+}
+
+// buildIncremental runs one incremental build of the site under root (the
+// working directory, so the graph lands in root/.ssg-cache) and returns the
+// outputs it wrote.
+func buildIncremental(t *testing.T, root string, changed ...string) (*Generator, []string) {
+	t.Helper()
+	gen, err := New(Config{
+		ChangedPaths: changed,
+		Source:       "site", Template: "simple", Domain: "example.com",
+		ContentDir:   filepath.Join(root, "content"),
+		TemplatesDir: filepath.Join(root, "templates"),
+		DataDir:      filepath.Join(root, "data"),
+		OutputDir:    filepath.Join(root, "output"),
+		Incremental:  true, Quiet: true,
+	})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return gen, gen.RenderedOutputs()
+}
+
+func containsOutput(outputs []string, want string) bool {
+	for _, o := range outputs {
+		if o == want {
+			return true
+		}
+	}
+	return false
+}
+
+// An unchanged tree renders nothing the second time; a body edit renders that
+// document and the listings that show it, and nothing else.
+func TestIncrementalBodyEdit(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+
+	first, _ := buildIncremental(t, root)
+	if !first.deps.full {
+		t.Fatalf("first build should be a full render, got incremental")
+	}
+	if _, err := os.Stat(depGraphPath(filepath.Join(root, "output"))); err != nil {
+		t.Fatalf("dependency graph not stored: %v", err)
+	}
+
+	second, rendered := buildIncremental(t, root)
+	if second.deps.full {
+		t.Fatalf("unchanged tree should not full-render: %s", second.deps.reason)
+	}
+	if len(rendered) != 0 {
+		t.Errorf("unchanged tree re-rendered %v", rendered)
+	}
+
+	alpha := filepath.Join(root, "content", "site", "posts", "news", "alpha.md")
+	raw, _ := os.ReadFile(alpha)
+	mustWrite(t, alpha, strings.Replace(string(raw), "Body of alpha.", "Body of alpha, fixed.", 1))
+
+	third, rendered := buildIncremental(t, root)
+	if third.deps.full {
+		t.Fatalf("a body edit should not full-render: %s", third.deps.reason)
+	}
+	if !containsOutput(rendered, "2024/01/02/alpha/index.html") {
+		t.Errorf("edited post not re-rendered: %v", rendered)
+	}
+	if containsOutput(rendered, "2024/01/02/beta/index.html") || containsOutput(rendered, "about/index.html") {
+		t.Errorf("untouched documents re-rendered: %v", rendered)
+	}
+	if !containsOutput(rendered, "index.html") || !containsOutput(rendered, "category/news/index.html") {
+		t.Errorf("listings showing the edited post not re-rendered: %v", rendered)
+	}
+	got, _ := os.ReadFile(filepath.Join(root, "output", "2024", "01", "02", "alpha", "index.html"))
+	if !strings.Contains(string(got), "fixed") {
+		t.Errorf("re-rendered output is stale: %s", got)
+	}
+}
+
+// A partial re-renders every output that executes it, and a data key only the
+// outputs whose templates read it; the page template uses neither.
+func TestIncrementalTemplateAndDataEdits(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	buildIncremental(t, root)
+
+	mustWrite(t, filepath.Join(root, "templates", "simple", "partials", "head.html"),
+		`{{define "head"}}<head><title>{{.Data.site.name}}</title></head>{{end}}`)
+	gen, rendered := buildIncremental(t, root)
+	if gen.deps.full {
+		t.Fatalf("a partial edit should not full-render: %s", gen.deps.reason)
+	}
+	for _, want := range []string{"2024/01/02/alpha/index.html", "2024/01/02/beta/index.html"} {
+		if !containsOutput(rendered, want) {
+			t.Errorf("%s uses the edited partial but was not re-rendered: %v", want, rendered)
+		}
+	}
+	if containsOutput(rendered, "about/index.html") {
+		t.Errorf("page.html does not use the partial, yet about/ was re-rendered")
+	}
+
+	mustWrite(t, filepath.Join(root, "data", "site.yaml"), "name: Renamed demo\n")
+	gen, rendered = buildIncremental(t, root)
+	if gen.deps.full {
+		t.Fatalf("a data edit read by a known template should not full-render: %s", gen.deps.reason)
+	}
+	if !containsOutput(rendered, "2024/01/02/beta/index.html") || containsOutput(rendered, "about/index.html") {
+		t.Errorf("data edit re-rendered the wrong outputs: %v", rendered)
+	}
+	got, _ := os.ReadFile(filepath.Join(root, "output", "2024", "01", "02", "beta", "index.html"))
+	if !strings.Contains(string(got), "Renamed demo") {
+		t.Errorf("data change not reflected: %s", got)
+	}
+}
+
+// What other pages can see of a document — here its title — changes listings,
+// navigation and links everywhere, so it renders the whole site. So does an
+// output deleted by hand, for that one output.
+func TestIncrementalFullRenderAndMissingOutput(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	buildIncremental(t, root)
+
+	if err := os.Remove(filepath.Join(root, "output", "about", "index.html")); err != nil {
+		t.Fatal(err)
+	}
+	gen, rendered := buildIncremental(t, root)
+	if gen.deps.full || !reflect.DeepEqual(rendered, []string{"about/index.html"}) {
+		t.Errorf("only the deleted output should be rendered, got full=%v %v", gen.deps.full, rendered)
+	}
+
+	beta := filepath.Join(root, "content", "site", "posts", "news", "beta.md")
+	raw, _ := os.ReadFile(beta)
+	mustWrite(t, beta, strings.Replace(string(raw), "title: beta", "title: Beta renamed", 1))
+	gen, _ = buildIncremental(t, root)
+	if !gen.deps.full || !strings.Contains(gen.deps.reason, "front matter") {
+		t.Errorf("a title change should full-render, got full=%v reason=%q", gen.deps.full, gen.deps.reason)
+	}
+}
+
+// A watcher's changed paths are re-hashed even when size and mtime claim the
+// file is untouched — an edit within one timestamp tick.
+func TestIncrementalTrustsChangedPaths(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	buildIncremental(t, root)
+
+	about := filepath.Join(root, "content", "site", "pages", "about.md")
+	info, err := os.Stat(about)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, _ := os.ReadFile(about)
+	mustWrite(t, about, strings.Replace(string(raw), "About us.", "About US.", 1))
+	if err := os.Chtimes(about, info.ModTime(), info.ModTime()); err != nil {
+		t.Fatal(err)
+	}
+	if _, rendered := buildIncremental(t, root); len(rendered) != 0 {
+		t.Fatalf("without a hint the stat check should miss the edit, rendered %v", rendered)
+	}
+	if _, rendered := buildIncremental(t, root, about); !containsOutput(rendered, "about/index.html") {
+		t.Errorf("a reported path must be re-hashed: rendered %v", rendered)
+	}
+}
+
+func TestTemplateDepsClosure(t *testing.T) {
+	theme := t.TempDir()
+	mustWrite(t, filepath.Join(theme, "post.html"), `{{template "head" .}}{{if .X}}{{template "foot" .}}{{end}}`)
+	mustWrite(t, filepath.Join(theme, "partials", "head.html"), `{{define "head"}}{{.Data.nav.main}}{{end}}`)
+	mustWrite(t, filepath.Join(theme, "partials", "foot.html"), `{{define "foot"}}{{range index .Data "x"}}{{end}}{{end}}`)
+	mustWrite(t, filepath.Join(theme, "page.html"), `{{ myFunc .Title }}`)
+
+	td := loadTemplateDeps([]themeLayer{{Name: "theme", Dir: theme}})
+	files, data := td.closure("post.html")
+	if want := []string{"partials/foot.html", "partials/head.html", "post.html"}; !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+	if want := []string{"*", "nav"}; !reflect.DeepEqual(data, want) {
+		t.Errorf("data = %v, want %v", data, want)
+	}
+	// Unknown functions do not stop the analysis; the FuncMap is not ours.
+	if files, _ := td.closure("page.html"); !reflect.DeepEqual(files, []string{"page.html"}) {
+		t.Errorf("page.html closure = %v", files)
+	}
+}
+
+// AggregateOutputs names what a build wrote besides its pages, and declines
+// when there is no graph or a pass rewrote the tree without saying what.
+func TestAggregateOutputs(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	gen, _ := buildIncremental(t, root)
+	files, ok := gen.AggregateOutputs()
+	if !ok || !containsOutput(files, "sitemap.xml") || !containsOutput(files, "robots.txt") {
+		t.Errorf("aggregate outputs = %v, %v; want the sitemap and robots.txt", files, ok)
+	}
+	for _, f := range files {
+		if !filepath.IsLocal(filepath.FromSlash(f)) {
+			t.Errorf("%q is not relative to the output directory", f)
+		}
+	}
+
+	gen.noteUntracked("fingerprinting")
+	if _, ok := gen.AggregateOutputs(); ok {
+		t.Error("an untracked pass must make the list unknown")
+	}
+	gen.deps = nil
+	if _, ok := gen.AggregateOutputs(); ok {
+		t.Error("without a dependency graph the list is unknown")
+	}
+}
@@ -0,0 +1,95 @@
+package generator
+
+// Draft preview (--drafts).
+//
+// A normal build renders `status: publish` and nothing else. Preview mode also
+// renders draft, pending and future documents — and anything the schedule
+// would hold back until its publish_date — so a reviewer can read them in
+// place, in the real theme, before they go out. Every such page is marked: it
+// reaches templates as .IsDraft, carries a visible banner and a robots noindex,
+// and is kept out of everything that announces content to the outside — the
+// sitemap, feeds, llms.txt and the search index. Listings still show it, since
+// seeing where a post will appear is half of reviewing it.
+//
+// Preview output is for review, not for publishing: the CLI refuses --deploy
+// with --drafts.
+
+import (
+	"html"
+	"regexp"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// previewStatuses are the non-published statuses preview mode renders.
+// WordPress names a scheduled post "future"; "pending" awaits review.
+var previewStatuses = map[string]bool{"draft": true, "pending": true, "future": true}
+
+// keepsStatus reports whether a document with this status is loaded.
+func (g *Generator) keepsStatus(status string) bool {
+	return status == "publish" || (g.config.Drafts && previewStatuses[status])
+}
+
+// keptByStatus filters documents loaded without keepsStatus (mddb) the way
+// loadContentDir filters files, marking the preview-only ones.
+func (g *Generator) keptByStatus(pages []models.Page) []models.Page {
+	out := pages[:0]
+	for _, p := range pages {
+		if g.keepsStatus(p.Status) {
+			p.IsDraft = p.Status != "publish"
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// published drops preview-only pages from a list about to be announced. The
+// input is returned as-is when there is nothing to drop.
+func published(pages []models.Page) []models.Page {
+	for i := range pages {
+		if pages[i].IsDraft {
+			out := make([]models.Page, 0, len(pages)-1)
+			for _, p := range pages {
+				if !p.IsDraft {
+					out = append(out, p)
+				}
+			}
+			return out
+		}
+	}
+	return pages
+}
+
+// previewBannerClass lets a theme restyle (or hide) the injected banner.
+const previewBannerClass = "ssg-preview-banner"
+
+var (
+	robotsMetaRe = regexp.MustCompile(`(?i)<meta\s+name=["']?robots["']?[^>]*>\s*`)
+	bodyOpenRe   = regexp.MustCompile(`(?i)<body[^>]*>`)
+	headCloseRe  = regexp.MustCompile(`(?i)</head>`)
+)
+
+// markPreview makes a preview page unmistakable and unindexable: any robots
+// meta the theme wrote is replaced by noindex, and a banner opens the body.
+func markPreview(s string, page models.Page) string {
+	s = robotsMetaRe.ReplaceAllString(s, "")
+	const noindex = `<meta name="robots" content="noindex">`
+	if all := headCloseRe.FindAllStringIndex(s, -1); all != nil {
+		i := all[len(all)-1][0]
+		s = s[:i] + noindex + "\n" + s[i:]
+	} else {
+		s = noindex + "\n" + s
+	}
+
+	reason := "status: " + page.Status
+	if page.Status == "publish" {
+		reason = "scheduled for " + publishAt(page).UTC().Format("2006-01-02 15:04 MST")
+	}
+	banner := `<div class="` + previewBannerClass + `" role="status" style="position:sticky;top:0;z-index:2147483647;` +
+		`margin:0;padding:.5em 1em;background:#b45309;color:#fff;font:600 14px/1.4 system-ui,sans-serif;text-align:center">` +
+		`Preview — not published (` + html.EscapeString(reason) + `)</div>`
+	if loc := bodyOpenRe.FindStringIndex(s); loc != nil {
+		return s[:loc[1]] + banner + s[loc[1]:]
+	}
+	return banner + s
+}
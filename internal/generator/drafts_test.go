@@ -0,0 +1,163 @@
+package generator
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// draftSite is the scheduled fixture plus a draft post whose theme template
+// prints .IsDraft and already sets its own robots meta.
+func draftSite(t *testing.T) string {
+	t.Helper()
+	root := scheduledSite(t)
+	mustWrite(t, filepath.Join(root, "content", "site", "posts", "news", "wip.md"),
+		"---\ntitle: Wip\nslug: wip\nstatus: draft\ntype: post\ndate: 2024-01-04\ncategories: [News]\n---\n\nNot yet.\n")
+	mustWrite(t, filepath.Join(root, "templates", "simple", "post.html"),
+		`<html><head><meta name="robots" content="index, follow"></head><body>`+
+			`<h1>{{.Title}}</h1>{{if .IsDraft}}<p>draft-flag</p>{{end}}{{.Content}}</body></html>`)
+	return root
+}
+
+func TestDraftsPreviewBuildsMarkedPages(t *testing.T) {
+	root := draftSite(t)
+	buildScheduled(t, root, func(c *Config) {
+		c.Drafts, c.Feed, c.FeedItems, c.SearchIndex, c.MarkdownPublish = true, true, 20, true, true
+	})
+	out := filepath.Join(root, "output")
+
+	for page, reason := range map[string]string{
+		"2024/01/04/wip/index.html":    "status: draft",
+		"2024/01/05/launch/index.html": "scheduled for 2024-06-10 09:00 UTC",
+	} {
+		raw, err := os.ReadFile(filepath.Join(out, page))
+		if err != nil {
+			t.Fatalf("%s not built in preview mode: %v", page, err)
+		}
+		s := string(raw)
+		if !strings.Contains(s, `<body><div class="ssg-preview-banner"`) || !strings.Contains(s, reason) {
+			t.Errorf("%s lacks the banner naming %q:\n%s", page, reason, s)
+		}
+		if !strings.Contains(s, `<meta name="robots" content="noindex">`) || strings.Contains(s, "index, follow") {
+			t.Errorf("%s: robots must be noindex alone:\n%s", page, s)
+		}
+		if !strings.Contains(s, "draft-flag") {
+			t.Errorf("%s: .IsDraft not set for the template", page)
+		}
+	}
+	alpha, err := os.ReadFile(filepath.Join(out, "2024/01/02/alpha/index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(alpha), "ssg-preview-banner") || strings.Contains(string(alpha), "draft-flag") {
+		t.Errorf("a published post was marked as a preview:\n%s", alpha)
+	}
+
+	// Listings show the draft; every outward-facing index leaves it out.
+	if raw, _ := os.ReadFile(filepath.Join(out, "index.html")); !strings.Contains(string(raw), "Wip") {
+		t.Errorf("index.html should list the draft for review:\n%s", raw)
+	}
+	for _, index := range []string{"sitemap.xml", "feed.xml", "search-index.json", "llms.txt"} {
+		raw, err := os.ReadFile(filepath.Join(out, index))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(raw), "alpha") {
+			t.Errorf("%s lost the published posts:\n%s", index, raw)
+		}
+		for _, leak := range []string{"wip", "Wip", "launch", "Launch"} {
+			if strings.Contains(string(raw), leak) {
+				t.Errorf("%s mentions preview content %q", index, leak)
+			}
+		}
+	}
+}
+
+func TestDraftsAreNotBuiltByDefault(t *testing.T) {
+	root := draftSite(t)
+	buildScheduled(t, root, nil)
+	if _, err := os.Stat(filepath.Join(root, "output", "2024/01/04/wip/index.html")); err == nil {
+		t.Error("a draft was built without --drafts")
+	}
+}
+
+func TestKeepsStatus(t *testing.T) {
+	normal, preview := &Generator{}, &Generator{config: Config{Drafts: true}}
+	for status, want := range map[string][2]bool{
+		"publish": {true, true},
+		"draft":   {false, true},
+		"pending": {false, true},
+		"future":  {false, true},
+		"private": {false, false},
+		"trash":   {false, false},
+	} {
+		if got := [2]bool{normal.keepsStatus(status), preview.keepsStatus(status)}; got != want {
+			t.Errorf("keepsStatus(%q) = %v, want %v (normal, preview)", status, got, want)
+		}
+	}
+}
+
+func TestPublishedDropsDrafts(t *testing.T) {
+	pages := []models.Page{{Slug: "a"}, {Slug: "b", IsDraft: true}, {Slug: "c"}}
+	got := published(pages)
+	if len(got) != 2 || got[0].Slug != "a" || got[1].Slug != "c" {
+		t.Errorf("published = %+v", got)
+	}
+	if clean := pages[:1]; &published(clean)[0] != &clean[0] {
+		t.Error("a list without drafts should be returned as-is")
+	}
+}
+
+// TestMarkPreviewUpperCaseHead: HTML tags are case-insensitive, so a theme
+// writing </HEAD> still gets noindex inside its head, not before <html>.
+func TestMarkPreviewUpperCaseHead(t *testing.T) {
+	got := markPreview(`<HTML><HEAD><TITLE>x</TITLE></HEAD><BODY>hi</BODY></HTML>`, models.Page{Status: "draft"})
+	if want := `<meta name="robots" content="noindex">` + "\n</HEAD>"; !strings.Contains(got, want) {
+		t.Errorf("noindex not placed before </HEAD>:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "<HTML>") {
+		t.Errorf("nothing should precede <HTML>:\n%s", got)
+	}
+}
+
+// TestDraftsFromMddb: an mddb draft is left out of a normal build and
+// previewed, marked, under --drafts — as a Markdown draft is.
+func TestDraftsFromMddb(t *testing.T) {
+	doc := `[{"id":"d1","key":"wip","lang":"en_US","contentMd":"Not yet.",` +
+		`"meta":{"title":["Wip"],"slug":["wip"],"type":["post"],"status":["draft"],"date":["2024-01-04"]}}]`
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/health":
+			w.WriteHeader(http.StatusOK)
+		case "/v1/search":
+			w.Header().Set("X-Total-Count", "1")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(doc))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	for _, drafts := range []bool{false, true} {
+		g := newTestGen(t, "")
+		g.siteData.Media = map[int]models.MediaItem{}
+		g.siteData.Authors = map[int]models.Author{}
+		g.config.Drafts = drafts
+		g.config.Mddb = MddbConfig{Enabled: true, URL: srv.URL, Protocol: "http", Collection: "content", Timeout: 5, BatchSize: 100}
+		if err := g.loadContentFromMddb(); err != nil {
+			t.Fatal(err)
+		}
+		posts := g.siteData.Posts
+		if drafts && (len(posts) != 1 || !posts[0].IsDraft) {
+			t.Errorf("--drafts: posts = %+v, want the draft, marked", posts)
+		}
+		if !drafts && len(posts) != 0 {
+			t.Errorf("posts = %+v, want none", posts)
+		}
+	}
+}
@@ -49,6 +49,7 @@ func TestScaffoldEmbeddedThemeUnknown(t *testing.T) {
 	}
 }
 
+// A bundled theme is scaffolded as a child theme extending it, not copied.
 func TestEnsureTemplatesPrefersEmbeddedTheme(t *testing.T) {
 	dir := t.TempDir()
 	tplPath := filepath.Join(dir, "simple")
@@ -56,15 +57,17 @@ func TestEnsureTemplatesPrefersEmbeddedTheme(t *testing.T) {
 	if err := g.ensureTemplates(tplPath); err != nil {
 		t.Fatalf("ensureTemplates: %v", err)
 	}
-	data, err := os.ReadFile(filepath.Join(tplPath, "index.html"))
-	if err != nil {
-		t.Fatalf("expected embedded index.html: %v", err)
+	if parent, err := readThemeExtends(tplPath); err != nil || parent != "simple" {
+		t.Fatalf("theme.yaml extends = %q, %v; want simple", parent, err)
 	}
-	if strings.Contains(string(data), "Latest articles and updates") {
-		t.Error("embedded theme expected, generic scaffold written instead")
+	for _, f := range []string{"index.html", "base.html", filepath.Join("css", "style.css")} {
+		if _, err := os.Stat(filepath.Join(tplPath, f)); !os.IsNotExist(err) {
+			t.Errorf("%s must come from the parent, not be written: %v", f, err)
+		}
 	}
-	if _, err := os.Stat(filepath.Join(tplPath, "css", "style.css")); err != nil {
-		t.Errorf("expected theme stylesheet extracted: %v", err)
+	// A second run leaves the skeleton alone.
+	if err := g.ensureTemplates(tplPath); err != nil {
+		t.Fatalf("ensureTemplates (second run): %v", err)
 	}
 }
 
@@ -0,0 +1,375 @@
+package generator
+
+// `ssg explain`: why a URL rendered the way it did. The answer is spread over
+// layoutTemplateName, the archive and taxonomy template chains, the GO-051
+// shell rule and the generatePage fallback to page.html — reading all of them
+// to guess which template won was the only way to debug a wrong layout. An
+// explaining build records, for every output, each template it looked up and
+// each it tried to render, with the reason one was passed over; Explain then
+// reports the output a URL or source file names.
+//
+// The record is taken from a real build, not a re-derivation of its rules, so
+// it cannot drift from what the renderer does. Pages render one at a time while
+// it is on: a lookup belongs to the next render, which only holds sequentially.
+
+import (
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spagu/ssg/internal/engine"
+	"github.com/spagu/ssg/internal/models"
+)
+
+// Template lookup outcomes, as Explanation reports them.
+const (
+	LookupFound    = "found"
+	LookupMissing  = "missing"
+	LookupRendered = "rendered"
+	LookupFailed   = "failed"
+)
+
+// Explanation is what one output was made from and how.
+type Explanation struct {
+	Query     string `json:"query" yaml:"query"`
+	Kind      string `json:"kind" yaml:"kind"`
+	PageNum   int    `json:"page_num,omitempty" yaml:"page_num,omitempty"`
+	Source    string `json:"source,omitempty" yaml:"source,omitempty"`
+	Permalink string `json:"permalink" yaml:"permalink"`
+	// Outputs are every file the same source (or, for a listing, the same
+	// URL) was written to, slash-separated under the output directory.
+	Outputs []string `json:"outputs" yaml:"outputs"`
+	Engine  string   `json:"engine" yaml:"engine"`
+	// Template is the template that rendered the output; Lookup is every
+	// candidate considered before it, in order, the winner last.
+	Template   string              `json:"template" yaml:"template"`
+	Lookup     []TemplateCandidate `json:"lookup" yaml:"lookup"`
+	Shortcodes []string            `json:"shortcodes,omitempty" yaml:"shortcodes,omitempty"`
+	// Theme is the theme chain, child first, when the theme extends another;
+	// Overrides are the templates and blocks the output uses that a child
+	// theme redefined (theme_layers.go).
+	Theme     []string        `json:"theme,omitempty" yaml:"theme,omitempty"`
+	Overrides []ThemeOverride `json:"overrides,omitempty" yaml:"overrides,omitempty"`
+	// Context maps each key of the template's data context to its Go type.
+	Context map[string]string `json:"context" yaml:"context"`
+}
+
+// TemplateCandidate is one template the build looked up or tried.
+type TemplateCandidate struct {
+	Name   string `json:"name" yaml:"name"`
+	Result string `json:"result" yaml:"result"`
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// explainLog collects the candidates of every output an explaining build
+// writes, keyed by output path relative to the output directory.
+type explainLog struct {
+	mu      sync.Mutex
+	pending []TemplateCandidate
+	outputs map[string]*explainRecord
+	order   []string
+}
+
+// explainRecord is one output's lookups and the render that wrote it.
+type explainRecord struct {
+	candidates []TemplateCandidate
+	template   string
+	data       interface{}
+	page       *models.Page
+	isPost     bool
+}
+
+// Explain builds the site, recording how each output was rendered, and
+// reports the output query names: a URL ("/about/", "https://example.com/tags/go/page/2/")
+// or a content file ("content/site/pages/about.md", or any path suffix of it).
+// The build writes to the configured output directory like any other.
+func (g *Generator) Explain(query string) (*Explanation, error) {
+	g.explain = &explainLog{outputs: map[string]*explainRecord{}}
+	g.config.BuildWorkers = 1
+	if err := g.Generate(); err != nil {
+		return nil, err
+	}
+	key, ok := g.explain.match(query)
+	if !ok {
+		return nil, fmt.Errorf("nothing in the build was written for %q (give a URL such as /about/ or a content file)", query)
+	}
+	return g.explanation(query, key), nil
+}
+
+// explainLookup notes a template looked up by name; the next render claims it.
+func (g *Generator) explainLookup(name string, found bool, reason string) {
+	if g.explain == nil {
+		return
+	}
+	result := LookupMissing
+	if found {
+		result = LookupFound
+	}
+	g.explain.mu.Lock()
+	g.explain.pending = append(g.explain.pending, TemplateCandidate{Name: name, Result: result, Reason: reason})
+	g.explain.mu.Unlock()
+}
+
+// explainRender records one render attempt of templateName into outputPath,
+// with the lookups that led to it. A failed attempt is kept beside the
+// fallback that followed it.
+func (g *Generator) explainRender(templateName, outputPath string, data interface{}, page *models.Page, isPost bool, err error) {
+	if g.explain == nil {
+		return
+	}
+	l := g.explain
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := g.relOutput(outputPath)
+	rec, ok := l.outputs[key]
+	if !ok {
+		rec = &explainRecord{}
+		l.outputs[key] = rec
+		l.order = append(l.order, key)
+	}
+	rec.candidates = append(rec.candidates, l.pending...)
+	l.pending = nil
+	if err != nil {
+		rec.candidates = append(rec.candidates, TemplateCandidate{Name: templateName, Result: LookupFailed, Reason: err.Error()})
+		return
+	}
+	rec.candidates = append(rec.candidates, TemplateCandidate{Name: templateName, Result: LookupRendered})
+	rec.template, rec.data, rec.isPost = templateName, data, isPost
+	if page != nil {
+		p := *page
+		rec.page = &p
+	}
+}
+
+// explainBuiltin records an output ssg writes without a template.
+func (g *Generator) explainBuiltin(outputPath, reason string) {
+	if g.explain == nil {
+		return
+	}
+	l := g.explain
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	key := g.relOutput(outputPath)
+	if _, ok := l.outputs[key]; !ok {
+		l.order = append(l.order, key)
+	}
+	l.outputs[key] = &explainRecord{candidates: []TemplateCandidate{{Name: "(built-in)", Result: LookupRendered, Reason: reason}}}
+}
+
+// match finds the output a query names: a content file first, then a URL.
+func (l *explainLog) match(query string) (string, bool) {
+	if abs, err := filepath.Abs(query); err == nil {
+		suffix := "/" + strings.TrimPrefix(filepath.ToSlash(filepath.Clean(query)), "./")
+		for _, key := range l.order {
+			rec := l.outputs[key]
+			if rec.page == nil || rec.page.SourceFile == "" {
+				continue
+			}
+			src, _ := filepath.Abs(filepath.Join(rec.page.SourceDir, rec.page.SourceFile))
+			if src == abs || strings.HasSuffix(filepath.ToSlash(src), suffix) {
+				return key, true
+			}
+		}
+	}
+	for _, want := range urlOutputCandidates(query) {
+		if _, ok := l.outputs[want]; ok {
+			return want, true
+		}
+	}
+	return "", false
+}
+
+// urlOutputCandidates are the output files a URL is served from: a directory
+// URL from its index.html, an extensionless one from either.
+func urlOutputCandidates(query string) []string {
+	p := query
+	if u, err := url.Parse(query); err == nil {
+		p = u.Path
+	}
+	p = strings.TrimPrefix(path.Clean("/"+p), "/")
+	switch {
+	case p == "" || p == ".":
+		return []string{indexHTMLName}
+	case strings.HasSuffix(query, "/"):
+		return []string{p + "/" + indexHTMLName}
+	case path.Ext(p) == "":
+		return []string{p + "/" + indexHTMLName, p}
+	}
+	return []string{p}
+}
+
+// explanation assembles the report for one recorded output.
+func (g *Generator) explanation(query, key string) *Explanation {
+	rec := g.explain.outputs[key]
+	engineName := engine.EngineGo
+	if g.engine != nil {
+		engineName = g.engine.Name()
+	}
+	ex := &Explanation{
+		Query:     query,
+		Outputs:   []string{key},
+		Engine:    engineName,
+		Template:  rec.template,
+		Lookup:    rec.candidates,
+		Context:   contextTypes(rec.data),
+		Permalink: outputPermalink(key),
+	}
+	ex.Kind, ex.PageNum = explainKind(key, rec)
+	ex.Theme, ex.Overrides = g.explainTheme(rec.template)
+	if rec.page != nil && rec.page.SourceFile != "" {
+		ex.Source = sourceKey(*rec.page)
+		ex.Permalink = rec.page.GetURL()
+		ex.Shortcodes = g.shortcodesIn(rec.page.Content)
+		ex.Outputs = nil
+		for _, k := range g.explain.order {
+			if p := g.explain.outputs[k].page; p != nil && sourceKey(*p) == ex.Source && p.Lang == rec.page.Lang {
+				ex.Outputs = append(ex.Outputs, k)
+			}
+		}
+	}
+	return ex
+}
+
+// explainTheme reports the theme chain and the overrides templateName uses.
+// Go templates are followed through {{template}} and {{block}}; another
+// engine's overrides are whole files, and all of them are reported.
+func (g *Generator) explainTheme(templateName string) ([]string, []ThemeOverride) {
+	chain := g.themeChain()
+	if len(chain) < 2 {
+		return nil, nil
+	}
+	names := make([]string, len(chain))
+	for i, l := range chain {
+		names[i] = l.Name
+		if l.Embedded {
+			names[i] += " (bundled)"
+		}
+	}
+	switch {
+	case templateName == "":
+		return names, nil
+	case g.engine != nil:
+		return names, g.themeOverrides
+	}
+	used := map[string]bool{}
+	for _, n := range loadTemplateDeps(chain).reached(templateName) {
+		used[n] = true
+	}
+	var overrides []ThemeOverride
+	for _, o := range g.themeOverrides {
+		if used[o.Name] {
+			overrides = append(overrides, o)
+		}
+	}
+	return names, overrides
+}
+
+// explainKind names what an output is, and which page of its pager.
+func explainKind(key string, rec *explainRecord) (string, int) {
+	ctx := map[string]interface{}{}
+	if rec.data != nil {
+		ctx = engine.ContextMap(rec.data)
+	}
+	num := 0
+	if pager, ok := ctx["Pager"].(Pager); ok && pager.Current > 1 {
+		num = pager.Current
+	}
+	switch {
+	case key == "404.html":
+		return "404", num
+	case rec.page != nil && rec.page.SourceFile != "" && rec.isPost:
+		return "post", num
+	case rec.page != nil && rec.page.SourceFile != "":
+		return "page", num
+	case rec.data == nil:
+		return "generated", num
+	}
+	if _, ok := ctx["Term"]; ok {
+		return "taxonomy term", num
+	}
+	if _, ok := ctx["Taxonomy"]; ok {
+		return "taxonomy index", num
+	}
+	if kind, ok := ctx["Kind"].(string); ok && kind != "" {
+		return kind + " archive", num
+	}
+	if key == indexHTMLName || strings.HasPrefix(key, "page/") {
+		return "index", num
+	}
+	return "listing", num
+}
+
+// outputPermalink is the URL an output file is served at.
+func outputPermalink(key string) string {
+	if key == indexHTMLName {
+		return "/"
+	}
+	if strings.HasSuffix(key, "/"+indexHTMLName) {
+		return "/" + strings.TrimSuffix(key, indexHTMLName)
+	}
+	return "/" + key
+}
+
+// contextTypes maps each key of a template context to its Go type: a map's
+// keys, or a struct's exported fields.
+func contextTypes(data interface{}) map[string]string {
+	out := map[string]string{}
+	if m, ok := data.(map[string]interface{}); ok {
+		for k, v := range m {
+			out[k] = typeName(v)
+		}
+		return out
+	}
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return out
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if f := t.Field(i); f.IsExported() {
+			out[f.Name] = f.Type.String()
+		}
+	}
+	return out
+}
+
+// typeName is v's Go type, or "nil".
+func typeName(v interface{}) string {
+	if v == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// shortcodesIn names the configured shortcodes content calls, in the syntaxes
+// processShortcodesWith expands.
+func (g *Generator) shortcodesIn(content string) []string {
+	seen := map[string]bool{}
+	for _, m := range shortcodeNameRe.FindAllStringSubmatch(content, -1) {
+		if _, ok := g.shortcodeMap[m[1]]; ok {
+			seen[m[1]] = true
+		}
+	}
+	if g.config.ShortcodeBrackets {
+		for name := range g.shortcodeMap {
+			res := compileBracketRes(name)
+			if res.closing.MatchString(content) || res.selfAttrs.MatchString(content) || res.simple.MatchString(content) {
+				seen[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
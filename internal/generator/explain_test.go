@@ -0,0 +1,113 @@
+package generator
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// explainSite is a Go-template site whose outputs each take a different path
+// through the lookup: a page naming a layout the theme lacks, a post, a tag
+// archive whose tag.html is only a define shell (GO-051) and the built-in 404.
+func explainSite(t *testing.T) *Generator {
+	t.Helper()
+	tmp := t.TempDir()
+	site := filepath.Join(tmp, "content", "site")
+	mustWrite(t, filepath.Join(site, "metadata.json"), `{"categories":[{"id":2,"name":"Guides","slug":"guides"}],"exported_at":"","media":[]}`)
+	mustWrite(t, filepath.Join(site, "posts", "guides", "hello.md"),
+		"---\ntitle: Hello\nslug: hello\nstatus: publish\ntype: post\ndate: 2024-03-05\ncategories: [2]\ntags: [go]\n---\n\nFirst post.\n")
+	mustWrite(t, filepath.Join(site, "pages", "about.md"),
+		"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\nlayout: wide\n---\n\nAbout us. {{promo}}\n")
+	theme := filepath.Join(tmp, "templates", "minimal")
+	for name, body := range map[string]string{
+		"index.html":    "<h1>{{ .Domain }}</h1>",
+		"page.html":     "<main>{{ .Title }}</main>",
+		"post.html":     "<article>{{ .Title }}</article>",
+		"category.html": "<h1>{{ .Name }}</h1>",
+		"tag.html":      `{{ define "tag-card" }}card{{ end }}`,
+		"promo.html":    "<aside>promo</aside>",
+	} {
+		mustWrite(t, filepath.Join(theme, name), body)
+	}
+	gen, err := New(Config{Source: "site", Template: "minimal", Domain: "example.com",
+		ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+		OutputDir: filepath.Join(tmp, "output"), Quiet: true,
+		Shortcodes: []Shortcode{{Name: "promo", Template: "promo.html"}, {Name: "unused", Template: "promo.html"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gen
+}
+
+// lookupTrail renders a lookup as name:result pairs, for compact comparison.
+func lookupTrail(ex *Explanation) string {
+	parts := make([]string, len(ex.Lookup))
+	for i, c := range ex.Lookup {
+		parts[i] = c.Name + ":" + c.Result
+	}
+	return strings.Join(parts, " ")
+}
+
+// TestExplainPageLayoutFallback: a page's layout candidates, the failed render
+// and the page.html fallback are all reported, found by URL or by source file.
+func TestExplainPageLayoutFallback(t *testing.T) {
+	for _, query := range []string{"/about/", "https://example.com/about", "pages/about.md"} {
+		ex, err := explainSite(t).Explain(query)
+		if err != nil {
+			t.Fatalf("Explain(%q): %v", query, err)
+		}
+		want := "layouts/wide.html:missing wide.html:missing layouts/wide.html:failed page.html:rendered"
+		if got := lookupTrail(ex); got != want {
+			t.Errorf("%s lookup:\n got  %s\n want %s", query, got, want)
+		}
+		if ex.Kind != "page" || ex.Template != pageHTMLName || ex.Permalink != "/about/" ||
+			!strings.HasSuffix(ex.Source, "pages/about.md") || ex.Engine != "go" {
+			t.Errorf("%s: %+v", query, ex)
+		}
+		if len(ex.Outputs) == 0 || ex.Outputs[0] != "about/index.html" {
+			t.Errorf("%s outputs = %v", query, ex.Outputs)
+		}
+		if strings.Join(ex.Shortcodes, ",") != "promo" {
+			t.Errorf("%s shortcodes = %v, want [promo]", query, ex.Shortcodes)
+		}
+		if ex.Context["Title"] != "string" || ex.Context["Menus"] == "" {
+			t.Errorf("%s context = %v", query, ex.Context)
+		}
+	}
+}
+
+// TestExplainListings: a tag archive reports its shell tag.html as absent and
+// the category.html that rendered; the front page reports its struct fields;
+// the built-in 404 says why it exists.
+func TestExplainListings(t *testing.T) {
+	ex, err := explainSite(t).Explain("/tag/go/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lookupTrail(ex); got != "tag.html:missing category.html:rendered" || ex.Kind != "tag archive" {
+		t.Errorf("tag archive: kind %q, lookup %s", ex.Kind, got)
+	}
+	if !strings.Contains(ex.Lookup[0].Reason, "GO-051") {
+		t.Errorf("a define shell must say why it counts as absent: %q", ex.Lookup[0].Reason)
+	}
+
+	ex, err = explainSite(t).Explain("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Kind != "index" || ex.Template != indexHTMLName || ex.Context["Pager"] == "" {
+		t.Errorf("front page: %+v", ex)
+	}
+
+	ex, err = explainSite(t).Explain("/404.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ex.Kind != "404" || len(ex.Lookup) != 1 || ex.Lookup[0].Name != "(built-in)" {
+		t.Errorf("404: %+v", ex)
+	}
+
+	if _, err := explainSite(t).Explain("/nowhere/"); err == nil {
+		t.Error("a URL nothing was written for must be an error")
+	}
+}
@@ -79,6 +79,11 @@ func (g *Generator) checkLinks() ([]brokenLink, error) {
 		}
 		return nil
 	})
+	// Wikilinks to no page render as plain text, not a dead href, so the walk
+	// above cannot see them; the load-time pass recorded them instead.
+	if g.wiki != nil {
+		broken = append(broken, g.wiki.misses...)
+	}
 	sort.Slice(broken, func(i, j int) bool {
 		if broken[i].from != broken[j].from {
 			return broken[i].from < broken[j].from
@@ -254,8 +259,7 @@ func (g *Generator) writeBundle(name string, sources []string) error {
 	if err := g.ensureParent(outPath); err != nil {
 		return err
 	}
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(outPath, []byte(buf.String()), 0644)
+	return g.writeOutput(outPath, []byte(buf.String()))
 }
 
 // ─── PLAT-003: per-page JSON output ─────────────────────────────────────────
@@ -282,8 +286,7 @@ func (g *Generator) writeJSONOutput(page models.Page, htmlPath string) {
 		return
 	}
 	jsonPath := strings.TrimSuffix(htmlPath, "index.html") + "index.json"
-	// #nosec G306 -- Web content files need to be world-readable
-	_ = os.WriteFile(jsonPath, data, 0644)
+	_ = g.writeOutput(jsonPath, data)
 }
 
 // pageRecord is the stable JSON representation of a page (PLAT-003 / PLAT-004).
@@ -313,7 +316,7 @@ func (g *Generator) pageRecord(page models.Page) map[string]interface{} {
 func (g *Generator) searchRecord(p models.Page) map[string]interface{} {
 	record := map[string]interface{}{
 		"title":           p.Title,
-		"url":             p.GetURL(),
+		"url":             g.sitePath(p.GetURL()),
 		"lang":            p.Lang,
 		"locale":          p.Locale,
 		"translation_key": p.TranslationKey,
@@ -343,15 +346,15 @@ func (g *Generator) generateSearchIndex() error {
 			docsByLang[p.Lang] = append(docsByLang[p.Lang], record)
 		}
 	}
-	add(g.siteData.Posts)
-	add(g.siteData.Pages)
+	add(published(g.siteData.Posts))
+	add(published(g.siteData.Pages))
 
 	if !g.config.I18n.Enabled {
 		data, err := json.Marshal(docs)
 		if err != nil {
 			return err
 		}
-		return os.WriteFile(filepath.Join(g.config.OutputDir, "search-index.json"), data, 0644) // #nosec G306
+		return g.writeOutput(filepath.Join(g.config.OutputDir, "search-index.json"), data)
 	}
 	for _, lang := range g.siteData.Languages {
 		data, err := json.Marshal(docsByLang[lang.Code])
@@ -362,7 +365,7 @@ func (g *Generator) generateSearchIndex() error {
 		if err := g.ensureDir(dir); err != nil {
 			return err
 		}
-		if err := os.WriteFile(filepath.Join(dir, "search-index.json"), data, 0644); err != nil {
+		if err := g.writeOutput(filepath.Join(dir, "search-index.json"), data); err != nil {
 			return err
 		} // #nosec G306
 	}
@@ -160,16 +160,23 @@ func (g *Generator) feedItemsFor(spec models.FeedSpec, full bool) []externalsour
 	posts := sortPostsChronologically(g.selectFeedPosts(spec))
 	out := make([]externalsource.FeedItem, 0, len(posts))
 	for _, p := range posts {
-		htmlBody, summary := g.feedBody(p, full)
-		canonical := p.GetCanonical(g.config.Domain)
-		out = append(out, externalsource.FeedItem{
-			ID: canonical, URL: canonical, Title: p.Title, Summary: summary,
-			ContentHTML: htmlBody, Published: p.Date, Updated: g.lastModFor(p), Tags: p.Tags,
-		})
+		out = append(out, g.postFeedItem(p, full))
 	}
 	return out
 }
 
+// postFeedItem is one of the site's own posts as a feed item, credited to
+// every author in its byline.
+func (g *Generator) postFeedItem(p models.Page, full bool) externalsource.FeedItem {
+	htmlBody, summary := g.feedBody(p, full)
+	canonical := p.GetCanonical(g.config.Domain)
+	return externalsource.FeedItem{
+		ID: canonical, URL: canonical, Title: p.Title, Summary: summary,
+		ContentHTML: htmlBody, Published: p.Date, Updated: g.lastModFor(p), Tags: p.Tags,
+		Author: g.bylineNames(p),
+	}
+}
+
 // feedPagePath is the file for page n: page 1 keeps the declared path, so the
 // advertised URL never moves as the archive grows.
 func feedPagePath(rel string, n int) string {
@@ -190,8 +197,7 @@ func (g *Generator) writeFeedFile(rel, body string, count int) error {
 	if err := os.MkdirAll(filepath.Dir(outPath), 0755); err != nil {
 		return err
 	}
-	// #nosec G306 -- Web content files need to be world-readable
-	if err := os.WriteFile(outPath, []byte(body), 0644); err != nil {
+	if err := g.writeOutput(outPath, []byte(body)); err != nil {
 		return err
 	}
 	if !g.config.Quiet {
@@ -212,12 +218,13 @@ func feedAltPath(spec models.FeedSpec) string {
 
 // selectFeedPosts narrows the site's content to one feed's selection. Every
 // criterion is optional and they combine with AND; a spec with none of them
-// covers everything of the requested type.
+// covers everything of the requested type. Preview-mode drafts never qualify.
 func (g *Generator) selectFeedPosts(spec models.FeedSpec) []models.Page {
 	pool := g.siteData.Posts
 	if strings.EqualFold(strings.TrimSpace(spec.Type), "page") {
 		pool = g.siteData.Pages
 	}
+	pool = published(pool)
 	source := strings.TrimSpace(spec.Source)
 	cats := lowerSet(spec.Categories)
 	tags := lowerSet(spec.Tags)
@@ -118,7 +118,7 @@ type Config struct {
 	SourceMap         bool         // Emit v3 source maps for minified JS/CSS (BLOG-007/GO-004)
 	Clean             bool         // Clean output directory before build
 	Quiet             bool         // Suppress stdout output
-	Engine            string       // Template engine: go, pongo2, mustache, handlebars
+	Engine            string       // Template engine: go, pongo2, mustache, handlebars, liquid
 	// MDDB content source
 	Mddb MddbConfig // MDDB configuration
 
@@ -137,6 +137,10 @@ type Config struct {
 	// StripMdLinkText drops ".md" from a link's visible text when that text is a
 	// bare filename, at publish time — source files are untouched (GO-075).
 	StripMdLinkText bool
+	// Wikilinks enables [[…]] links, ![[…]] embeds, .Backlinks and graph.json.
+	Wikilinks bool
+	// Callouts renders > [!NOTE] and !!! note blocks as callouts (callouts.go).
+	Callouts bool
 	// MarkdownPublish emits index.md next to each page, a text/markdown <head>
 	// alternate, and a root llms.txt index — Markdown for agents (GO-085).
 	MarkdownPublish bool
@@ -237,6 +241,11 @@ type Config struct {
 	// dates (#146). Opt-in: a site that never had these URLs should not grow
 	// them because it upgraded.
 	DateArchives bool
+	// Authors paginates author archives and picks their feeds (authors.go).
+	Authors models.AuthorsConfig
+	// Menus are the config `menus:` entries, merged with imported menus and
+	// frontmatter `menu:` into one tree per menu (menus.go).
+	Menus map[string][]models.MenuEntry
 	// TypeArchives declares which content types get a listing at /<type>/ (#165),
 	// keyed by type slug. Empty means none — a custom type's archive cannot be
 	// inferred from its documents, since a type may legitimately have none.
@@ -252,12 +261,17 @@ type Config struct {
 	SchemaDefaults map[string]map[string]interface{} // per-section structured-data defaults (#110)
 	// CheckLinks/CheckImages/CheckMeta are post-build validation modes: "" (off),
 	// "warn" or "strict"; Strict escalates any enabled one to fatal (#75, #76).
-	CheckLinks   string
-	CheckImages  string
-	CheckMarkup  string
-	CheckMeta    string
-	CheckSchema  string // validate emitted JSON-LD: "" | warn | strict (#111)
-	CheckOrphans string
+	CheckLinks  string
+	CheckImages string
+	CheckMarkup string
+	CheckProse  string            // lint the sources with Lint: "" | warn | strict (checks_prose.go)
+	Lint        models.LintConfig // the rules check_prose runs
+	// CheckSpelling spell-checks the sources: "" | warn | strict (checks_spelling.go).
+	CheckSpelling string
+	Spelling      models.SpellingConfig
+	CheckMeta     string
+	CheckSchema   string // validate emitted JSON-LD: "" | warn | strict (#111)
+	CheckOrphans  string
 	// CheckRedirects reports links the host would redirect; PrettyURLs models that
 	// host behaviour (#87).
 	CheckRedirects string
@@ -265,10 +279,15 @@ type Config struct {
 	// ContentExclude are glob patterns for Markdown files that must not be loaded
 	// as pages (#74).
 	ContentExclude []string
+	// ContentDefaults are the site-wide, glob-targeted layer of the frontmatter
+	// cascade (cascade.go).
+	ContentDefaults []models.ContentDefault
 	// StaticSources are extra verbatim passthrough roots beyond static_dir (#84).
 	StaticSources []models.StaticSource
 	// Feeds are the declared extra syndication feeds (#86).
 	Feeds []models.FeedSpec
+	// PageGenerators turn data rows into pages (page_generators.go).
+	PageGenerators []models.PageGenerator
 	// FeedAutodiscovery injects the <link rel="alternate"> elements; nil/true
 	// keeps the default behaviour (#86).
 	FeedAutodiscovery *bool
@@ -290,6 +309,27 @@ type Config struct {
 	// BuildWorkers is the resolved page/post render concurrency (>=1; 1 =
 	// sequential). Set by the CLI from --workers/build_workers (BUILD-PARALLEL).
 	BuildWorkers int
+	// Incremental records what every output was made from under .ssg-cache/deps/
+	// and, on the next build, re-renders only the outputs whose inputs changed
+	// (see depgraph.go). Set by --incremental and by every --watch rebuild.
+	Incremental bool
+	// ChangedPaths are the files a watcher saw change since the previous build.
+	// An incremental build re-hashes them even when their size and mtime look
+	// unchanged — a save within the filesystem's timestamp granularity.
+	ChangedPaths []string
+	// BuildFuture and BuildExpired keep pages whose publish_date (or date) is
+	// still ahead, or whose expiry_date has passed, in the build (see
+	// schedule.go). Set by --build-future / --build-expired.
+	BuildFuture  bool
+	BuildExpired bool
+	// Drafts is preview mode: draft, pending, future and not-yet-scheduled
+	// content is built too, bannered, noindexed and kept out of the sitemap,
+	// feeds, llms.txt and the search index (see drafts.go). Set by --drafts.
+	Drafts bool
+	// Profile, when set, is where the build writes a Chrome trace of its
+	// phases, template executions and image work, with a text summary beside
+	// it (see profile.go). Set by --profile.
+	Profile string
 	// AI answers [ai …] content shortcodes at build time (cached). nil = the
 	// feature is off; the shortcode then resolves to its fallback (#1.8.16).
 	AI *ai.Client
@@ -305,6 +345,12 @@ type Config struct {
 	// alongside (or instead of) the primary source (CONTENT-002).
 	ContentSources []ContentSource
 
+	// Versions are git refs built beside the site under /<name>/ (versions.go).
+	// CurrentVersion names the one a build is for; "" is the root build, the
+	// only one that builds the others.
+	Versions       []Version
+	CurrentVersion string
+
 	// LinkRewrites maps an href prefix to its replacement, for links that point
 	// at repository files the site never publishes (LINK-002).
 	LinkRewrites map[string]string
@@ -385,10 +431,16 @@ type Generator struct {
 	// sequentially, so every worker in a batch only ever reads it (BUILD-PARALLEL).
 	// Anything that would set it per page must take per-render context instead.
 	currentLang string
-	md          goldmark.Markdown  // configured Markdown renderer (AX-001/002/003)
-	tagSlugs    map[string]string  // tag name → slug, for sitemap/feeds (BLOG-004)
-	authorSlugs map[string]string  // author slug → slug, for sitemap (BLOG-005)
-	taxonomies  *taxonomy.Registry // generic taxonomy registry (taxonomies-feature.md)
+	md          goldmark.Markdown                 // configured Markdown renderer (AX-001/002/003)
+	wiki        *wikiIndex                        // wikilink targets, when wikilinks are on (wikilinks.go)
+	versions    []models.Version                  // .Versions, built once (versions.go)
+	tagSlugs    map[string]string                 // tag name → slug, for sitemap/feeds (BLOG-004)
+	authorSlugs map[string]string                 // author slug → slug, for sitemap (BLOG-005)
+	taxonomies  *taxonomy.Registry                // generic taxonomy registry (taxonomies-feature.md)
+	sections    *sectionTree                      // the _index.md section tree (sections.go); nil without one
+	cascade     map[string]map[string]interface{} // directory → its frontmatter defaults, per build (cascade.go)
+	gitPins     []GitSourcePin                    // the commits git content sources were built from
+	includes    map[string]bool                   // files read by include, under renderMu (include.go)
 	// External sources: .ExternalData / .ExternalDataMeta namespaces plus
 	// content-mode CMS imports merged into the site before finalize.
 	externalData map[string]interface{}
@@ -400,7 +452,16 @@ type Generator struct {
 	ownedURLs   map[string]string
 	engine      engine.Engine // non-Go template engine when configured (GO-007)
 	engineTmpls map[string]engine.Template
-	sanitizer   *bluemonday.Policy // HTML sanitizer when SanitizeHTML is on (FE-005)
+	// layers is the theme and the themes it extends, child first, and
+	// themeOverrides the definitions a child replaced (theme_layers.go).
+	// Resolved by loadTemplates.
+	layers         []themeLayer
+	themeOverrides []ThemeOverride
+	sanitizer      *bluemonday.Policy // HTML sanitizer when SanitizeHTML is on (FE-005)
+	// jekyllCache holds each page list as Jekyll documents for the Liquid
+	// engine, converted once per build (jekyll.go).
+	jekyllMu    sync.Mutex
+	jekyllCache map[jekyllDocsKey][]interface{}
 
 	shortcodeTmpls    map[string]*template.Template  // parsed shortcode templates, one parse per build (PERF-002)
 	bracketRes        map[string]bracketShortcodeRes // per-shortcode bracket regexes, compiled once (PERF-006)
@@ -497,6 +558,20 @@ type Generator struct {
 	// every template as .BuildTime. Rendering never reads a clock, so two pages
 	// of one build cannot disagree about when they were built (#186).
 	buildTime time.Time
+
+	// deps records this build's dependency graph and decides which outputs an
+	// incremental build may skip; nil when incremental builds are off.
+	deps *depTracker
+
+	// prof collects the --profile spans; nil when not profiling.
+	prof *profiler
+
+	// explain records how each output was rendered, for `ssg explain`
+	// (explain.go); nil in every other build.
+	explain *explainLog
+
+	// nextChange is the next scheduled publish or expiry (schedule.go).
+	nextChange StateChange
 }
 
 // resolveLocations loads the configured IANA zones; unknown names warn and are
@@ -599,13 +674,20 @@ func New(cfg Config) (*Generator, error) {
 		bracketRes[sc.Name] = compileBracketRes(sc.Name)
 	}
 
+	if err := validateVersions(cfg.Versions); err != nil {
+		return nil, err
+	}
+	if err := validatePageGenerators(cfg.PageGenerators); err != nil {
+		return nil, err
+	}
+
 	// Resolve variables (expand $ENV_VAR references) and export as SSG_* env vars
 	cfg.Variables = resolveVariables(cfg.Variables)
 	exportVariablesToEnv(cfg.Variables, "SSG")
 
 	siteLoc, langLocs := resolveLocations(cfg) // I18N-001
 
-	return &Generator{
+	g := &Generator{
 		config: cfg,
 		siteData: &models.SiteData{
 			Domain:     cfg.Domain,
@@ -615,7 +697,6 @@ func New(cfg Config) (*Generator, error) {
 			Tags:       make(map[int]models.Category),
 		},
 		shortcodeMap:   scMap,
-		md:             buildMarkdown(cfg),
 		sanitizer:      newSanitizer(cfg.SanitizeHTML),
 		shortcodeTmpls: make(map[string]*template.Template),
 		mdCache:        make(map[string]string),
@@ -626,7 +707,11 @@ func New(cfg Config) (*Generator, error) {
 		catalog:        catalog,
 		currentLang:    cfg.DefaultLanguage,
 		buildTime:      resolveBuildTime(time.Now),
-	}, nil
+	}
+	g.versions = g.siteVersions()
+	// Markup hooks get the image helpers, which need the generator.
+	g.md = buildMarkdownWith(cfg, newMarkupHooks(cfg, g.imageFuncs()))
+	return g, nil
 }
 
 // newSanitizer returns a bluemonday UGC policy when sanitisation is enabled, else nil
@@ -635,7 +720,11 @@ func newSanitizer(enabled bool) *bluemonday.Policy {
 	if !enabled {
 		return nil
 	}
-	return bluemonday.UGCPolicy()
+	p := bluemonday.UGCPolicy()
+	// Callout markup (callouts.go) is the renderer's own, not the author's: its
+	// classes are what a theme styles, and the policy would strip them.
+	p.AllowAttrs("class").Matching(calloutClassRe).OnElements("aside", "p", "summary")
+	return p
 }
 
 // buildMarkdown assembles the goldmark renderer from config: tables + footnotes are
@@ -643,6 +732,21 @@ func newSanitizer(enabled bool) *bluemonday.Policy {
 // back the table of contents (AX-002); Chroma syntax highlighting is added when
 // enabled (AX-001). WithUnsafe preserves the SSG contract of rendering author HTML.
 func buildMarkdown(cfg Config) goldmark.Markdown {
+	return buildMarkdownWith(cfg, newMarkupHooks(cfg, nil))
+}
+
+// buildMarkdownWith is buildMarkdown with the theme's markup hooks. When the
+// theme hooks links, images, headings or code, the hooked renderer falls back
+// to a hook-free twin for whatever a hook does not cover (render_hooks.go).
+func buildMarkdownWith(cfg Config, hooks *markupHooks) goldmark.Markdown {
+	md := newMarkdown(cfg, hooks)
+	if hooks.has(renderHookNames...) {
+		md = newMarkdown(cfg, hooks, renderHookExtension{base: md, hooks: hooks, domain: cfg.Domain})
+	}
+	return md
+}
+
+func newMarkdown(cfg Config, hooks *markupHooks, extra ...goldmark.Extender) goldmark.Markdown {
 	exts := []goldmark.Extender{extension.Table, extension.Footnote}
 	if cfg.Highlight {
 		style := cfg.HighlightStyle
@@ -655,6 +759,13 @@ func buildMarkdown(cfg Config) goldmark.Markdown {
 		}
 		exts = append(exts, highlighting.NewHighlighting(opts...))
 	}
+	if cfg.Wikilinks {
+		exts = append(exts, wikilinkExtension{})
+	}
+	if cfg.Callouts {
+		exts = append(exts, calloutExtension{hooks: hooks})
+	}
+	exts = append(exts, extra...)
 	return goldmark.New(
 		goldmark.WithExtensions(exts...),
 		goldmark.WithParserOptions(
@@ -794,15 +905,27 @@ func exportVariablesToEnv(vars map[string]interface{}, prefix string) {
 }
 
 // Generate performs the full site generation
-func (g *Generator) Generate() error {
+func (g *Generator) Generate() (err error) {
 	// Release the lazily-built related-posts mddb client when the build ends.
 	defer g.closeRelatedMddb()
 
+	if g.config.Profile != "" {
+		g.prof = newProfiler()
+		defer func() {
+			if perr := g.writeProfile(); perr != nil && err == nil {
+				err = perr
+			}
+		}()
+	}
+
 	if err := g.runHooks("pre_build", nil); err != nil {
 		return fmt.Errorf("pre_build hook: %w", err)
 	}
 
-	if err := g.cleanOutputIfRequested(); err != nil {
+	if err := g.timed(g.cleanOutputIfRequested); err != nil {
+		return err
+	}
+	if err := g.timed(g.clearVersionOutputs); err != nil {
 		return err
 	}
 
@@ -812,7 +935,7 @@ func (g *Generator) Generate() error {
 
 	// Content contracts run before rendering: a malformed page fails the build
 	// loudly (strict) instead of shipping broken output (#62).
-	if err := g.validateContentSchemas(); err != nil {
+	if err := g.timed(g.validateContentSchemas); err != nil {
 		return err
 	}
 
@@ -820,6 +943,10 @@ func (g *Generator) Generate() error {
 	// so the ifs guard sees full page context (#1.8.16).
 	g.resolveAIContent()
 
+	// Everything a page can read is loaded now, so this is the point where an
+	// incremental build can tell which outputs are still current.
+	g.planIncremental()
+
 	if err := g.runStep("🏗️  Generating site...", g.generateSite, "generating site"); err != nil {
 		return err
 	}
@@ -838,29 +965,36 @@ func (g *Generator) Generate() error {
 		return err
 	}
 
-	if err := g.generateSitemapAndRobots(); err != nil {
+	if err := g.timed(g.generateSitemapAndRobots); err != nil {
 		return err
 	}
 
-	if err := g.generateLLMsTxt(); err != nil {
+	if err := g.timed(g.generateLLMsTxt); err != nil {
 		return fmt.Errorf("generating llms.txt: %w", err)
 	}
 
-	if err := g.writeRouteManifest(); err != nil {
+	if err := g.timed(g.writeRouteManifest); err != nil {
 		return fmt.Errorf("writing route manifest: %w", err)
 	}
 
-	if err := g.generateDeclaredFeeds(); err != nil {
+	if err := g.timed(g.generateDeclaredFeeds); err != nil {
 		return err
 	}
-	if err := g.generateFeeds(); err != nil {
+	if err := g.timed(g.generateFeeds); err != nil {
 		return fmt.Errorf("generating feeds: %w", err)
 	}
+	if err := g.timed(g.generateAuthorFeeds); err != nil {
+		return fmt.Errorf("generating author feeds: %w", err)
+	}
 
-	if err := g.generateSearchIndex(); err != nil {
+	if err := g.timed(g.generateSearchIndex); err != nil {
 		return fmt.Errorf("building search index: %w", err)
 	}
 
+	if err := g.timed(g.generateLinkGraph); err != nil {
+		return fmt.Errorf("writing link graph: %w", err)
+	}
+
 	if err := g.runStep("☁️  Generating Cloudflare Pages files...", g.generateCloudflareFiles, "generating Cloudflare files"); err != nil {
 		return err
 	}
@@ -869,6 +1003,10 @@ func (g *Generator) Generate() error {
 		return err
 	}
 
+	// The output is complete and has passed its checks: only now is the record
+	// of what it was made from worth trusting.
+	g.finishIncremental()
+
 	if err := g.runHooks("post_build", nil); err != nil {
 		return fmt.Errorf("post_build hook: %w", err)
 	}
@@ -890,10 +1028,11 @@ func (g *Generator) loadPhase() error {
 			return err
 		}
 	}
-	if err := g.runStep("🔄 Loading content...", g.loadContent, "loading content"); err != nil {
+	// Data files load before content: page_generators turn their rows into pages.
+	if err := g.runStep("🗂️  Loading data files...", g.loadData, "loading data files"); err != nil {
 		return err
 	}
-	if err := g.runStep("🗂️  Loading data files...", g.loadData, "loading data files"); err != nil {
+	if err := g.runStep("🔄 Loading content...", g.loadContent, "loading content"); err != nil {
 		return err
 	}
 	if err := g.runStep("🏷️  Building taxonomies...", g.buildTaxonomies, "building taxonomies"); err != nil {
@@ -907,22 +1046,27 @@ func (g *Generator) loadPhase() error {
 // time in a single write (PERF-005); only genuinely global passes live here.
 func (g *Generator) assetPhase() error {
 	// SCSS compiles before bundling so bundles/minify/fingerprint see CSS (ASSET-003).
-	if err := g.compileSCSSIfRequested(); err != nil {
+	if err := g.timed(g.compileSCSSIfRequested); err != nil {
 		return fmt.Errorf("compiling SCSS: %w", err)
 	}
 	// Bundling concatenates asset groups before minification/fingerprinting (ASSET-002).
-	if err := g.bundleIfRequested(); err != nil {
+	if err := g.timed(g.bundleIfRequested); err != nil {
 		return fmt.Errorf("bundling assets: %w", err)
 	}
 	// CSS/JS minification must run after bundling; HTML was minified at render.
-	if err := g.minifyIfRequested(); err != nil {
+	if err := g.timed(g.minifyIfRequested); err != nil {
 		return err
 	}
 	// Fingerprinting is the terminal asset step: it must run after bundling and
 	// minification so hashes reflect final byte content (ASSET-001).
-	if err := g.fingerprintIfRequested(); err != nil {
+	if err := g.timed(g.fingerprintIfRequested); err != nil {
 		return err
 	}
+	// Versions build into subdirectories once the root's own assets are final,
+	// and before the checks, so one pass validates every version's links.
+	if err := g.timed(g.generateVersions); err != nil {
+		return fmt.Errorf("building versions: %w", err)
+	}
 	// Validation runs last, over the final output tree: links (SEO-005), image alt
 	// attributes (#75) and page metadata (#76). Each reports everything it finds
 	// before the first strict failure returns, so one build surfaces the whole
@@ -933,25 +1077,31 @@ func (g *Generator) assetPhase() error {
 	g.reportStrippedImages()
 	// Source-level, and first of the checks: it explains a page that looks
 	// wrong in the browser, so it must not be buried under the output checks.
-	if err := g.checkMarkupIfRequested(); err != nil {
+	if err := g.timed(g.checkMarkupIfRequested); err != nil {
 		return err
 	}
-	if err := g.checkLinksIfRequested(); err != nil {
+	if err := g.timed(g.checkProseIfRequested); err != nil {
 		return err
 	}
-	if err := g.checkImagesIfRequested(); err != nil {
+	if err := g.timed(g.checkSpellingIfRequested); err != nil {
 		return err
 	}
-	if err := g.checkMetaIfRequested(); err != nil {
+	if err := g.timed(g.checkLinksIfRequested); err != nil {
 		return err
 	}
-	if err := g.checkSchemaIfRequested(); err != nil {
+	if err := g.timed(g.checkImagesIfRequested); err != nil {
 		return err
 	}
-	if err := g.checkOrphansIfRequested(); err != nil {
+	if err := g.timed(g.checkMetaIfRequested); err != nil {
 		return err
 	}
-	return g.checkRedirectsIfRequested()
+	if err := g.timed(g.checkSchemaIfRequested); err != nil {
+		return err
+	}
+	if err := g.timed(g.checkOrphansIfRequested); err != nil {
+		return err
+	}
+	return g.timed(g.checkRedirectsIfRequested)
 }
 
 // hookTimeout bounds every lifecycle hook so a hung command cannot stall the build.
@@ -996,10 +1146,10 @@ func (g *Generator) log(msg string) {
 	}
 }
 
-// runStep executes a generation step with logging
+// runStep executes a generation step with logging (and a profile span)
 func (g *Generator) runStep(msg string, fn func() error, errContext string) error {
 	g.log(msg)
-	if err := fn(); err != nil {
+	if err := g.timed(fn); err != nil {
 		return fmt.Errorf("%s: %w", errContext, err)
 	}
 	return nil
@@ -1088,6 +1238,7 @@ func (g *Generator) minifyAssetByExt(path string) error {
 
 // loadContent loads all content from the source directory or mddb
 func (g *Generator) loadContent() error {
+	g.cascade = nil
 	// Check if mddb is enabled
 	var err error
 	if g.config.Mddb.Enabled {
@@ -1099,6 +1250,12 @@ func (g *Generator) loadContent() error {
 		return err
 	}
 
+	// Author profiles from data/authors/ complete the ones the loader found,
+	// before anything else resolves a byline.
+	if err := g.loadAuthorProfiles(); err != nil {
+		return err
+	}
+
 	// Extra local Markdown roots (content_sources) join the site next, so a
 	// docs/ folder elsewhere in the repository is treated like native content
 	// (CONTENT-002).
@@ -1106,17 +1263,30 @@ func (g *Generator) loadContent() error {
 		return err
 	}
 
+	// Pages generated from data rows join the same way (page_generators.go).
+	if err := g.generatePages(); err != nil {
+		return err
+	}
+
 	// Content-mode CMS imports join the site before finalize so they get the
 	// same URL/translation/taxonomy treatment as native content.
 	g.mergeCMSContent()
 
-	return g.finalizeLoadedContent()
+	if err := g.finalizeLoadedContent(); err != nil {
+		return err
+	}
+	// Menus last: a page's entry links to its final, permalinked URL.
+	return g.buildMenus()
 }
 
 // finalizeLoadedContent computes derived per-page fields once, for every content
-// source: reading stats (BLOG-006) and configured permalink paths (SEO-001).
+// source: the publish/expiry schedule, reading stats (BLOG-006) and configured
+// permalink paths (SEO-001).
 // Runs after metadata is loaded so category slugs are resolvable.
 func (g *Generator) finalizeLoadedContent() error {
+	// Scheduled-out pages leave first, so nothing below — translations,
+	// series, collision checks — ever sees them.
+	g.applySchedule()
 	languages := ssgi18n.Normalize(g.config.Languages, g.config.LanguageConfigs, g.config.LanguageTimezones)
 	g.siteData.Languages = languages
 	g.siteData.DefaultLanguage = g.config.DefaultLanguage
@@ -1159,15 +1329,26 @@ func (g *Generator) finalizeLoadedContent() error {
 			if typ == "" {
 				typ = defaultType
 			}
-			if pattern := g.config.Permalinks[typ]; pattern != "" {
+			// A page generator's own permalink is already expanded, and wins.
+			if pattern := g.config.Permalinks[typ]; pattern != "" && pages[i].PermalinkPath == "" {
 				pages[i].PermalinkPath = g.expandPermalink(pattern, pages[i])
 			}
+			g.collectResources(&pages[i])
 		}
 	}
 	finalize(g.siteData.Pages, "page")
 	finalize(g.siteData.Posts, "post")
+	// Includes splice before anything reads the content as a whole: reading
+	// time, wikilinks and section lists all see the included text.
+	if err := g.resolveIncludes(); err != nil {
+		return err
+	}
 	g.computeSeriesLinks()
 	g.computeTranslations()
+	g.computeWikilinks()
+	if err := g.buildSections(); err != nil {
+		return err
+	}
 	if g.config.I18n.Enabled {
 		if err := g.validateI18nContent(languages); err != nil {
 			return err
@@ -1487,13 +1668,9 @@ func (g *Generator) generateTags() (map[string]string, error) {
 
 // generateAuthors renders a listing per author at /author/{slug}/ using author.html
 // (fallback category.html), and returns the author→slug map for the sitemap (BLOG-005).
+// A co-written post is listed in every one of its authors' archives.
 func (g *Generator) generateAuthors() (map[string]string, error) {
-	groups := make(map[int][]models.Page)
-	for _, post := range g.siteData.Posts {
-		if post.Author != 0 {
-			groups[post.Author] = append(groups[post.Author], post)
-		}
-	}
+	groups := g.authorPosts()
 	slugs := make(map[string]string)
 	ids := make([]int, 0, len(groups))
 	for id := range groups {
@@ -1511,7 +1688,7 @@ func (g *Generator) generateAuthors() (map[string]string, error) {
 			continue
 		}
 		slugs[slug] = slug
-		if err := g.renderArchive("author", name, slug, groups[id], authorHTMLName, false); err != nil {
+		if err := g.renderAuthorArchive(id, name, slug, groups[id]); err != nil {
 			return nil, err
 		}
 	}
@@ -1724,6 +1901,7 @@ func (g *Generator) loadContentFromMddb() error {
 	if err != nil {
 		return fmt.Errorf("converting pages: %w", err)
 	}
+	pages = g.keptByStatus(pages)
 	for i := range pages {
 		pages[i].PageFormat = g.config.PageFormat
 	}
@@ -1740,6 +1918,7 @@ func (g *Generator) loadContentFromMddb() error {
 	if err != nil {
 		return fmt.Errorf("converting posts: %w", err)
 	}
+	posts = g.keptByStatus(posts)
 
 	// Set URL format and page format for posts based on config
 	for i := range posts {
@@ -1895,8 +2074,15 @@ func (g *Generator) loadMetadata(path string) error {
 	return nil
 }
 
-// loadMarkdownDir loads all markdown files from a directory (recursively)
+// loadMarkdownDir loads all content files from a directory (recursively):
+// Markdown and every other registered format.
 func (g *Generator) loadMarkdownDir(dir string) ([]models.Page, error) {
+	return g.loadContentDir(dir, false)
+}
+
+// loadContentDir is loadMarkdownDir for one directory level; nested is false
+// for the directory a caller asked for, which is never a page bundle itself.
+func (g *Generator) loadContentDir(dir string, nested bool) ([]models.Page, error) {
 	var pages []models.Page
 
 	entries, err := os.ReadDir(dir)
@@ -1907,12 +2093,16 @@ func (g *Generator) loadMarkdownDir(dir string) ([]models.Page, error) {
 		return nil, err
 	}
 
+	// A nested directory whose only content file is index.* is a page bundle:
+	// the page takes the directory's name and owns every file in it.
+	bundle := nested && g.isBundleDir(dir, entries)
+
 	for _, entry := range entries {
 		entryPath := filepath.Join(dir, entry.Name())
 
 		if entry.IsDir() {
 			// Recursively load subdirectories
-			subPages, err := g.loadMarkdownDir(entryPath)
+			subPages, err := g.loadContentDir(entryPath, true)
 			if err != nil {
 				fmt.Printf("   ⚠️  Warning: failed to load subdirectory %s: %v\n", entry.Name(), err)
 				continue
@@ -1921,7 +2111,14 @@ func (g *Generator) loadMarkdownDir(dir string) ([]models.Page, error) {
 			continue
 		}
 
-		if !strings.HasSuffix(entry.Name(), ".md") {
+		// Markdown, notebooks, Org — whatever has a registered format (see
+		// internal/parser/formats.go).
+		if !parser.IsContentFile(entry.Name()) {
+			continue
+		}
+		// _index.* describes the directory's section (sections.go); it is
+		// not a page of its own.
+		if isSectionIndex(entry.Name()) {
 			continue
 		}
 		// content_exclude opts a file out of being treated as a page (#74).
@@ -1934,12 +2131,15 @@ func (g *Generator) loadMarkdownDir(dir string) ([]models.Page, error) {
 			continue
 		}
 
-		page, err := parser.ParseMarkdownFile(entryPath)
+		// Frontmatter defaults cascade from _defaults.yaml files, section
+		// `cascade:` keys and content_defaults (cascade.go).
+		page, err := parser.ParseFileWithDefaults(entryPath, g.frontmatterDefaults(entryPath))
 		if err != nil {
 			fmt.Printf("   ⚠️  Warning: failed to parse %s: %v\n", entry.Name(), err)
 			continue
 		}
-		if page.Status == "publish" {
+		if g.keepsStatus(page.Status) {
+			page.IsDraft = page.Status != "publish"
 			page.SourceDir = dir
 			page.SourceFile = entry.Name() // original filename e.g. "AUTHENTICATION.md"
 			// auto_excerpt fills the excerpt from the opening paragraph for
@@ -1947,13 +2147,18 @@ func (g *Generator) loadMarkdownDir(dir string) ([]models.Page, error) {
 			if g.config.AutoExcerpt && page.Excerpt == "" {
 				page.Excerpt = parser.DeriveExcerpt(page.Content)
 			}
-			page.Slug = g.normalizeSlug(page.Slug, entry.Name())
+			if bundle {
+				page.Bundle = true
+				page.Slug = g.normalizeSlug(page.Slug, filepath.Base(dir))
+			} else {
+				page.Slug = g.normalizeSlug(page.Slug, entry.Name())
+			}
 
 			// Use file modification time as fallback for missing dates
 			if page.Date.IsZero() || page.Modified.IsZero() {
 				if info, err := entry.Info(); err == nil {
 					if page.Date.IsZero() {
-						page.Date = info.ModTime()
+						page.Date, page.DateInferred = info.ModTime(), true
 					}
 					if page.Modified.IsZero() {
 						page.Modified = info.ModTime()
@@ -2004,7 +2209,7 @@ func (g *Generator) loadTemplates() error {
 	pageLinks := g.buildPageLinks()
 	funcs := g.buildTemplateFuncs(pageLinks)
 
-	// Non-Go engine (pongo2/mustache/handlebars): load the theme's own templates
+	// Non-Go engine (pongo2/mustache/handlebars/liquid): load the theme's own templates
 	// through the selected engine instead of html/template. No Go defaults are
 	// scaffolded — alt-engine themes must ship templates in that engine's syntax
 	// (GO-007).
@@ -2015,31 +2220,39 @@ func (g *Generator) loadTemplates() error {
 	if err := g.ensureTemplates(templatePath); err != nil {
 		return err
 	}
+	if err := g.resolveLayers(); err != nil {
+		return err
+	}
 
-	tmpl, err := template.New("").Funcs(funcs).ParseGlob(filepath.Join(templatePath, htmlGlobPattern))
+	// The root templates, then layouts/ and partials/ when they exist, for
+	// each theme in the chain. partials/ is part of the documented theme
+	// structure and holds the {{define}} blocks a theme shares between roles
+	// (header, footer, head); it was listed in docs/TEMPLATES.md but never
+	// parsed, so those defines were silently unavailable (DOC-014).
+	tmpl, overrides, err := parseThemeLayers(g.layers, funcs)
 	if err != nil {
-		return fmt.Errorf("parsing templates: %w", err)
+		return err
 	}
 	warnShellTemplates(tmpl, g.config.Quiet)
-	g.warnTemplateScriptContext(templatePath)
-
-	// Also load templates from the layouts/ and partials/ subdirectories when
-	// they exist. partials/ is part of the documented theme structure and holds
-	// the {{define}} blocks a theme shares between roles (header, footer, head);
-	// it was listed in docs/TEMPLATES.md but never parsed, so those defines were
-	// silently unavailable (DOC-014).
-	for _, sub := range []string{"layouts", "partials"} {
-		subPath := filepath.Join(templatePath, sub, htmlGlobPattern)
-		files, _ := filepath.Glob(subPath)
-		if len(files) == 0 {
-			continue
-		}
-		if tmpl, err = tmpl.ParseGlob(subPath); err != nil {
-			return fmt.Errorf("parsing %s templates: %w", sub, err)
-		}
+	for _, dir := range g.themeDirs() {
+		g.warnTemplateScriptContext(dir)
 	}
 
 	g.tmpl = tmpl
+	g.themeOverrides = overrides
+	return nil
+}
+
+// resolveLayers resolves the theme chain for this build. A theme.yaml naming
+// a parent that cannot be found fails the build: rendering without the
+// parent would publish a site with half its templates missing.
+func (g *Generator) resolveLayers() error {
+	layers, err := resolveThemeLayers(g.config.TemplatesDir, g.config.Template)
+	if err != nil {
+		return err
+	}
+	g.layers = layers
+	g.themeOverrides = nil
 	return nil
 }
 
@@ -2052,26 +2265,59 @@ func (g *Generator) loadEngineTemplates(templatePath string, funcs template.Func
 	}
 	g.engine = eng
 	g.engineTmpls = make(map[string]engine.Template)
+	if err := g.resolveLayers(); err != nil {
+		return err
+	}
 
-	patterns := []string{
-		filepath.Join(templatePath, htmlGlobPattern),
-		filepath.Join(templatePath, "layouts", htmlGlobPattern),
+	subs := []string{"", "layouts"}
+	if g.isLiquid() {
+		// A Jekyll theme keeps its page templates in _layouts/ and takes the
+		// site-dependent Jekyll filters as helpers (jekyll.go).
+		subs = append(subs, "_layouts")
+		jekyll := make(template.FuncMap, len(funcs)+4)
+		for name, fn := range funcs {
+			jekyll[name] = fn
+		}
+		for name, fn := range g.jekyllFuncs() {
+			jekyll[name] = fn
+		}
+		funcs = jekyll
+		g.jekyllCache = nil
+	}
+	if layered, ok := eng.(interface{ SetThemeRoots([]string) }); ok {
+		layered.SetThemeRoots(g.themeDirs())
 	}
+	// A template is keyed by its file name, and the nearest theme in the
+	// chain that has the file wins; a parent's copy is never parsed.
+	owner := map[string]string{}
 	loaded := 0
-	for _, pat := range patterns {
-		files, _ := filepath.Glob(pat)
-		for _, f := range files {
-			t, perr := eng.ParseFile(f, funcs)
-			if perr != nil {
-				return fmt.Errorf("parsing %s template %s: %w", eng.Name(), filepath.Base(f), perr)
+	for _, layer := range g.layers {
+		for _, sub := range subs {
+			files, _ := filepath.Glob(filepath.Join(layer.Dir, sub, htmlGlobPattern))
+			for _, f := range files {
+				name := filepath.Base(f)
+				if winner, taken := owner[name]; taken {
+					if winner != layer.Name {
+						g.themeOverrides = append(g.themeOverrides, ThemeOverride{Name: name, Theme: winner, Overrides: layer.Name})
+					}
+					continue
+				}
+				t, perr := eng.ParseFile(f, funcs)
+				if perr != nil {
+					return fmt.Errorf("parsing %s template %s: %w", eng.Name(), name, perr)
+				}
+				g.engineTmpls[name] = t
+				owner[name] = layer.Name
+				loaded++
 			}
-			g.engineTmpls[filepath.Base(f)] = t
-			loaded++
 		}
 	}
 	if loaded == 0 {
 		return fmt.Errorf("no %s templates found in %s (alt-engine themes must ship their own templates)", eng.Name(), templatePath)
 	}
+	if g.isLiquid() {
+		g.jekyllTemplateAliases()
+	}
 	return nil
 }
 
@@ -2084,7 +2330,11 @@ func (g *Generator) renderWithEngine(templateName, outputPath string, data inter
 	}
 	// Render into memory so the per-file transforms produce one write (PERF-005).
 	var buf bytes.Buffer
-	if err := t.Execute(&buf, g.prepAltData(data)); err != nil {
+	ctx := g.prepAltData(data)
+	if g.isLiquid() {
+		ctx = g.jekyllContext(ctx, page)
+	}
+	if err := t.Execute(&buf, ctx); err != nil {
 		return err
 	}
 	out := buf.String()
@@ -2342,6 +2592,9 @@ func (g *Generator) buildTemplateFuncs(pageLinks map[string]string) template.Fun
 		"getCategorySlug":      g.tmplGetCategorySlug,
 		"isValidCategory":      tmplIsValidCategory,
 		"getAuthorName":        g.tmplGetAuthorName,
+		"getAuthor":            g.tmplGetAuthor,
+		"authorsOf":            g.tmplAuthorsOf,
+		"authorAvatar":         g.tmplAuthorAvatar,
 		"getURL":               tmplGetURL,
 		"getCanonical":         tmplGetCanonical,
 		"hasValidCategories":   tmplHasValidCategories,
@@ -2361,6 +2614,7 @@ func (g *Generator) buildTemplateFuncs(pageLinks map[string]string) template.Fun
 		"translationURL":       g.translationURL,
 		"languageURL":          g.languageURL,
 		"localizeDate":         g.localizeDate,
+		"include":              g.tmplInclude, // snippet or code file, rendered like content (include.go)
 
 		// Collection helpers (v1.8.3): the collection is the FINAL argument so
 		// helpers chain in pipelines — see docs/TEMPLATE_HELPERS.md.
@@ -2492,6 +2746,9 @@ func (g *Generator) tmplSafeHTML(pageLinks map[string]string, mdLinkMap map[stri
 		s = g.replaceTOCMarker(s)
 		s = g.convertMarkdownToHTML(s)
 		s = fixMediaPaths(s, g.siteData.Media)
+		if g.config.Wikilinks {
+			s = g.resolveWikilinks(s)
+		}
 		if g.config.RewriteMdLinks {
 			s = g.rewriteMdLinks(s, mdLinkMap)
 		}
@@ -2617,7 +2874,7 @@ func (g *Generator) convertMarkdownToHTML(s string) string {
 	}
 	md := g.md
 	if md == nil {
-		md = buildMarkdown(g.config)
+		md = buildMarkdownWith(g.config, newMarkupHooks(g.config, g.imageFuncs()))
 	}
 	// Conversion runs outside the lock so different content converts in parallel;
 	// a rare double-convert of the same string is harmless (identical output).
@@ -2823,7 +3080,7 @@ func (g *Generator) renderShortcode(sc Shortcode) string {
 		return g.shortcodeFailed(sc, fmt.Sprintf("shortcode %q has no template defined", sc.Name))
 	}
 
-	templatePath := filepath.Join(g.config.TemplatesDir, g.config.Template, sc.Template)
+	templatePath := g.themeFile(sc.Template)
 
 	g.renderMu.Lock()
 	tmpl, cached := g.shortcodeTmpls[templatePath]
@@ -2915,6 +3172,9 @@ func (g *Generator) shortcodeFuncMap() template.FuncMap {
 		"getCategorySlug": g.tmplGetCategorySlug,
 		"isValidCategory": tmplIsValidCategory,
 		"getAuthorName":   g.tmplGetAuthorName,
+		"getAuthor":       g.tmplGetAuthor,
+		"authorsOf":       g.tmplAuthorsOf,
+		"authorAvatar":    g.tmplAuthorAvatar,
 		"stripShortcodes": tmplStripShortcodes,
 		"stripHTML":       tmplStripHTML,
 		"default":         tmplDefault,
@@ -3111,12 +3371,18 @@ func (g *Generator) ensureTemplates(templatePath string) error {
 		return nil
 	}
 
-	// Bundled starter theme first (DOC-013): extract the full embedded tree
-	// (HTML + assets) so the CLI honours the README promise for simple/krowy.
-	if ok, err := scaffoldEmbeddedTheme(g.config.Template, templatePath); err != nil {
+	// A child theme may hold no templates at all: its parents supply them.
+	if hasThemeManifest(templatePath) {
+		return nil
+	}
+
+	// Bundled starter theme first (DOC-013): a child theme extending it, so
+	// the CLI honours the README promise for simple/krowy and the site keeps
+	// receiving the bundled theme's fixes instead of a frozen copy.
+	if ok, err := scaffoldChildTheme(g.config.Template, templatePath); err != nil {
 		return err
 	} else if ok {
-		fmt.Printf("   📦 Extracted embedded theme %q into %s\n", g.config.Template, templatePath)
+		fmt.Printf("   📦 Created child theme %s extending the bundled %q theme\n", templatePath, strings.ToLower(g.config.Template))
 		return nil
 	}
 
@@ -3281,6 +3547,11 @@ func (g *Generator) generateSite() error {
 		return fmt.Errorf("generating content-type archives: %w", err)
 	}
 
+	// A list page per _index.md section, at the section's own URL.
+	if err := g.generateSections(); err != nil {
+		return fmt.Errorf("generating sections: %w", err)
+	}
+
 	// Alias stubs are written last, once every real page exists. Writing them
 	// during the parallel render made the "collides with an existing page" check
 	// a race against a half-written output tree: the warning appeared or not
@@ -3467,7 +3738,10 @@ func (g *Generator) renderIndexPage(posts []models.Page, pager Pager, outPath st
 		// The front page renders from its own struct rather than the page map,
 		// so every field a theme may read has to be named here too — a footer
 		// in a shared partial is on the front page as much as anywhere (#186).
-		BuildTime time.Time
+		BuildTime      time.Time
+		Versions       []models.Version
+		CurrentVersion *models.Version
+		Menus          map[string][]models.MenuItem
 	}{
 		Site:             g.siteData,
 		Posts:            posts,
@@ -3481,6 +3755,9 @@ func (g *Generator) renderIndexPage(posts []models.Page, pager Pager, outPath st
 		HomePagesLimit:   effectiveHomeLimit(g.config.HomePagesLimit, len(pages)),
 		HomePostsLimit:   effectiveHomeLimit(g.config.HomePostsLimit, len(posts)),
 		BuildTime:        g.buildTime,
+		Versions:         g.versions,
+		CurrentVersion:   currentVersion(g.versions),
+		Menus:            g.menusFor(outPath, nil),
 	}
 	// Render with a page context so the SEO block applies (#109). Without one,
 	// `if page != nil` in the render transform skipped OpenGraph, JSON-LD and
@@ -3568,6 +3845,13 @@ func (g *Generator) generatePage(page models.Page) error {
 	// not generated) before this ran.
 	outputSubPath := page.GetOutputPath()
 
+	// Nothing this page was made from moved since the recorded build: its
+	// outputs stand, and only the alias 301s — collected per build — are redone.
+	if g.deps.skipPage(page) {
+		g.writeAliasStubs(page)
+		return nil
+	}
+
 	// Convert page to flat map with Extra fields at top level
 	data := g.pageToTemplateData(page, false)
 
@@ -3584,9 +3868,17 @@ func (g *Generator) generatePage(page models.Page) error {
 
 		// Copy co-located assets only to the directory-style path (avoid duplicates)
 		if page.SourceDir != "" && strings.HasSuffix(outputPath, indexHTMLName) {
-			if err := g.copyColocatedAssets(page.SourceDir, outputDir, page.Content); err != nil {
+			// A bundle publishes everything it owns, subdirectories included.
+			if page.Bundle {
+				if err := g.copyBundleResources(page, outputDir); err != nil {
+					fmt.Printf("   ⚠️  Warning: couldn't copy bundle resources for page %s: %v\n", page.Slug, err)
+				}
+			} else if err := g.copyColocatedAssets(page.SourceDir, outputDir, page.Content); err != nil {
 				fmt.Printf("   ⚠️  Warning: couldn't copy co-located assets for page %s: %v\n", page.Slug, err)
 			}
+			if err := g.writePageAssets(page.Assets, outputDir); err != nil {
+				fmt.Printf("   ⚠️  Warning: couldn't write extracted assets for page %s: %v\n", page.Slug, err)
+			}
 		}
 
 		// Use custom layout/template if specified, otherwise default to page.html
@@ -3640,6 +3932,11 @@ func (g *Generator) generatePost(post models.Page) error {
 		return nil
 	}
 
+	if g.deps.skipPage(post) {
+		g.writeAliasStubs(post)
+		return nil
+	}
+
 	// Convert post to flat map with Extra fields at top level
 	data := g.pageToTemplateData(post, true)
 
@@ -3656,9 +3953,17 @@ func (g *Generator) generatePost(post models.Page) error {
 
 		// Copy co-located assets only to the directory-style path (avoid duplicates)
 		if post.SourceDir != "" && strings.HasSuffix(outputPath, indexHTMLName) {
-			if err := g.copyColocatedAssets(post.SourceDir, outputDir, post.Content); err != nil {
+			// A bundle publishes everything it owns, subdirectories included.
+			if post.Bundle {
+				if err := g.copyBundleResources(post, outputDir); err != nil {
+					fmt.Printf("   ⚠️  Warning: couldn't copy bundle resources for post %s: %v\n", post.Slug, err)
+				}
+			} else if err := g.copyColocatedAssets(post.SourceDir, outputDir, post.Content); err != nil {
 				fmt.Printf("   ⚠️  Warning: couldn't copy co-located assets for post %s: %v\n", post.Slug, err)
 			}
+			if err := g.writePageAssets(post.Assets, outputDir); err != nil {
+				fmt.Printf("   ⚠️  Warning: couldn't write extracted assets for post %s: %v\n", post.Slug, err)
+			}
 		}
 
 		// Render + per-file transforms in a single write (PERF-005).
@@ -3769,8 +4074,7 @@ func (g *Generator) writeAliasStub(alias, rel, target string) {
 	// Alias stubs get the same per-file transforms as rendered pages (PERF-005),
 	// matching the former tree-walk behaviour (minify/prettify/relative links).
 	stub := g.transformHTMLPage(aliasStubHTML(target), nil, false)
-	// #nosec G306 -- Web content files need to be world-readable
-	if err := os.WriteFile(outPath, []byte(stub), 0644); err != nil {
+	if err := g.writeOutput(outPath, []byte(stub)); err != nil {
 		fmt.Printf("   ⚠️  Alias %q: %v\n", alias, err)
 	}
 }
@@ -3985,8 +4289,10 @@ func (g *Generator) layoutTemplateName(layout string) string {
 	}
 	for _, name := range []string{pathForm, layout + ".html"} {
 		if g.tmpl.Lookup(name) != nil {
+			g.explainLookup(name, true, "layout: "+layout)
 			return name
 		}
+		g.explainLookup(name, false, "layout: "+layout+"; not in the theme")
 	}
 	return pathForm // unresolved: the caller still falls back to page.html
 }
@@ -4083,6 +4389,13 @@ func (g *Generator) pageToTemplateData(page models.Page, isPost bool) map[string
 		// SOURCE_DATE_EPOCH. `© 2007-{{.BuildTime.Year}}` is the case that
 		// hits every site at once, on the same night (#186).
 		"BuildTime": g.buildTime,
+		// The site's versions and the one this page belongs to, for a version
+		// switcher; CurrentVersion is nil outside a versioned site.
+		"Versions":       g.versions,
+		"CurrentVersion": currentVersion(g.versions),
+		// The byline as profiles, in order: every `authors:` entry, or the one
+		// `author:` (authors.go).
+		"Authors": g.pageAuthors(page),
 		// The readers' comments a migration brought across, threaded and in
 		// the order they were written (#142). Empty for a page that has none.
 		"Comments":       g.commentsFor(page),
@@ -4116,7 +4429,16 @@ func (g *Generator) pageToTemplateData(page models.Page, isPost bool) map[string
 		// .Sticky lets a theme mark the pinned post the way the source CMS did
 		// (WordPress writes a `sticky` post class), so a migrated listing looks
 		// right rather than merely being ordered right (#155).
-		"Sticky":          page.Sticky,
+		"Sticky": page.Sticky,
+		"Weight": page.Weight,
+		// .IsDraft is true only in preview mode (--drafts), for a page a normal
+		// build would leave out, so a theme can flag it in its own design.
+		"IsDraft": page.IsDraft,
+		// .Resources are the files the page owns (bundle contents, referenced
+		// co-located assets): `.Resources.Get "cover"`, `.Resources.Match "*.jpg"`.
+		"Resources": page.Resources,
+		// .Backlinks are the pages whose wikilinks point here (wikilinks: true).
+		"Backlinks":       page.Backlinks,
 		"SeriesPrevURL":   page.SeriesPrevURL,
 		"SeriesPrevTitle": page.SeriesPrevTitle,
 		"SeriesNextURL":   page.SeriesNextURL,
@@ -4127,6 +4449,10 @@ func (g *Generator) pageToTemplateData(page models.Page, isPost bool) map[string
 		"OutputPath":   page.GetOutputPath(),
 	}
 
+	// The section tree around the page: .Section, .Parent, .Ancestors and
+	// .Children (sections.go).
+	sectionContext(data, g.sectionNode(page))
+
 	// Keep backward compatibility - include Page/Post struct
 	if isPost {
 		data["Post"] = page
@@ -4147,26 +4473,16 @@ func (g *Generator) pageToTemplateData(page models.Page, isPost bool) map[string
 
 // copyAssets copies static assets (CSS, JS, images) to output
 func (g *Generator) copyAssets() error {
-	templatePath := filepath.Join(g.config.TemplatesDir, g.config.Template)
-
-	// Copy CSS
-	if err := g.copyDir(filepath.Join(templatePath, "css"), filepath.Join(g.config.OutputDir, "css")); err != nil {
-		if !os.IsNotExist(err) {
-			return err
-		}
-	}
-
-	// Copy JS
-	if err := g.copyDir(filepath.Join(templatePath, "js"), filepath.Join(g.config.OutputDir, "js")); err != nil {
-		if !os.IsNotExist(err) {
-			return err
-		}
-	}
-
-	// Copy Images
-	if err := g.copyDir(filepath.Join(templatePath, "images"), filepath.Join(g.config.OutputDir, "images")); err != nil {
-		if !os.IsNotExist(err) {
-			return err
+	// CSS, JS and images of each theme in the chain, farthest parent first:
+	// a child's css/style.css is written over its parent's.
+	dirs := g.themeDirs()
+	for i := len(dirs) - 1; i >= 0; i-- {
+		for _, sub := range []string{"css", "js", "images"} {
+			if err := g.copyDir(filepath.Join(dirs[i], sub), filepath.Join(g.config.OutputDir, sub)); err != nil {
+				if !os.IsNotExist(err) {
+					return err
+				}
+			}
 		}
 	}
 
@@ -4337,8 +4653,11 @@ func (g *Generator) copyFile(src, dst string) error {
 	}
 	defer func() { _ = dstFile.Close() }()
 
-	_, err = io.Copy(dstFile, srcFile)
-	return err
+	if _, err = io.Copy(dstFile, srcFile); err != nil {
+		return err
+	}
+	g.noteWritten(dst)
+	return nil
 }
 
 // stripImageMetadata reports whether published images should lose their
@@ -4376,8 +4695,7 @@ func (g *Generator) copyStrippedJPEG(src, dst string) error {
 	if len(cleaned) != len(data) {
 		g.recordStrippedImage()
 	}
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(dst, cleaned, 0644)
+	return g.writeOutput(dst, cleaned)
 }
 
 // recordStrippedImage counts one cleaned image for the build's report.
@@ -4409,7 +4727,7 @@ func (g *Generator) copyColocatedAssets(sourceDir, outputDir, content string) er
 
 	copied := 0
 	for _, entry := range entries {
-		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".md") {
+		if entry.IsDir() || parser.IsContentFile(entry.Name()) {
 			continue
 		}
 		if !isContentAsset(entry.Name()) {
@@ -4444,6 +4762,22 @@ func (g *Generator) copyColocatedAssets(sourceDir, outputDir, content string) er
 	return nil
 }
 
+// writePageAssets writes the files a content format extracted from the source
+// — a notebook's plot outputs — into the page's output directory, where its
+// content links them by name.
+func (g *Generator) writePageAssets(assets map[string][]byte, outputDir string) error {
+	for name, data := range assets {
+		dst := filepath.Join(outputDir, filepath.Base(name))
+		if err := g.ensureWithinOutput(dst); err != nil {
+			return err
+		}
+		if err := g.writeOutput(dst, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // assetDirEntries returns sourceDir's listing, reading it from disk at most once
 // per build (PERF-012). Posts in one category share a SourceDir, so the previous
 // per-post os.ReadDir scanned the same directory once for every post in it —
@@ -4584,8 +4918,12 @@ func processWPShortcodesWith(content string, emit func(string) string) string {
 }
 
 // excludeFromSitemap returns true if a page should be excluded from sitemap.xml.
-// Excluded: pages with robots containing "noindex", layout "redirect", or sitemap "no".
+// Excluded: drafts in preview mode, pages with robots containing "noindex",
+// layout "redirect", or sitemap "no".
 func excludeFromSitemap(page models.Page) bool {
+	if page.IsDraft {
+		return true
+	}
 	if strings.Contains(strings.ToLower(page.Robots), "noindex") {
 		return true
 	}
@@ -4772,8 +5110,7 @@ func (g *Generator) generateSitemap() error {
 
 	sb.WriteString("</urlset>\n")
 
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(filepath.Join(g.config.OutputDir, "sitemap.xml"), []byte(sb.String()), 0644)
+	return g.writeOutput(filepath.Join(g.config.OutputDir, "sitemap.xml"), []byte(sb.String()))
 }
 
 func (g *Generator) writeSitemapAlternates(sb *strings.Builder, page models.Page) {
@@ -4898,7 +5235,7 @@ func (g *Generator) generateFeeds() error {
 // relPath under the output directory (BLOG-002). All text is XML-escaped.
 // Chronological, not pinned: a feed says what was published when (#155).
 func (g *Generator) writeFeed(relPath, title, altURL string, posts []models.Page, limit int) error {
-	ordered := sortPostsChronologically(posts)
+	ordered := sortPostsChronologically(published(posts))
 	if len(ordered) > limit {
 		ordered = ordered[:limit]
 	}
@@ -4935,8 +5272,7 @@ func (g *Generator) writeFeed(relPath, title, altURL string, posts []models.Page
 	if err := g.ensureParent(outPath); err != nil {
 		return err
 	}
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(outPath, []byte(sb.String()), 0644)
+	return g.writeOutput(outPath, []byte(sb.String()))
 }
 
 // writeFeedEntry appends one Atom <entry> for a post (BLOG-002).
@@ -4982,8 +5318,7 @@ type RobotsRule struct {
 
 func (g *Generator) generateRobots() error {
 	content := renderRobots(g.config.RobotsRules, g.config.Domain)
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(filepath.Join(g.config.OutputDir, "robots.txt"), []byte(content), 0644)
+	return g.writeOutput(filepath.Join(g.config.OutputDir, "robots.txt"), []byte(content))
 }
 
 // renderRobots builds robots.txt from explicit per-crawler rules, or the
@@ -5035,6 +5370,7 @@ func (g *Generator) generateNotFound() error {
 	if _, err := os.Stat(path); err == nil {
 		return nil // the site provides its own
 	}
+	g.explainBuiltin(path, "no page or template produced 404.html, so ssg wrote its own (not_found_off suppresses it)")
 	title := g.config.Domain
 	if title == "" {
 		title = "This site"
@@ -5054,8 +5390,7 @@ func (g *Generator) generateNotFound() error {
 </body>
 </html>
 `, template.HTMLEscapeString(title))
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(path, []byte(content), 0644)
+	return g.writeOutput(path, []byte(content))
 }
 
 // generateCloudflareFiles creates _headers and _redirects files for Cloudflare
@@ -5240,6 +5575,8 @@ func (g *Generator) fingerprintIfRequested() error {
 		return nil
 	}
 	g.log("🔏 Fingerprinting assets...")
+	// Renames assets and rewrites the references in every page.
+	g.noteUntracked("fingerprinting")
 	return g.fingerprintAssets()
 }
 
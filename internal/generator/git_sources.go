@@ -0,0 +1,247 @@
+package generator
+
+// Git content sources. content_sources reads local directories, so content
+// kept in another team's repository had to be cloned by a CI script before
+// every build. A source with `type: git` names the repository instead:
+//
+//	content_sources:
+//	  - type: git
+//	    url: https://github.com/acme/marketing.git
+//	    ref: main            # branch, tag or commit; default: the remote's HEAD
+//	    subdir: content      # the directory to read; default: the whole tree
+//	    auth: { type: bearer, token: $MARKETING_TOKEN }
+//
+// The repository is fetched shallowly into a bare cache repo under
+// .ssg-cache/git/, one per URL and ref, and the resolved commit checked out
+// beside it, without a .git inside the tree, so nothing else in the build
+// knows it came from git. Two sources reading the same repository at
+// different refs each keep their own tree and pin.
+// The commit is pinned in the log and in routes.json: the same ref can mean
+// different content tomorrow, and the build should say which it used.
+//
+// Credentials follow fetch.Auth — secrets only as $ENV references — and reach
+// git through GIT_CONFIG_* variables as an http.extraHeader, never through the
+// command line (where ps shows it) or a remote in the cache repo's config.
+// --offline builds from the last checkout of the same ref.
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spagu/ssg/internal/cache"
+	"github.com/spagu/ssg/internal/fetch"
+)
+
+// GitSourcePin records which commit a git content source was built from.
+type GitSourcePin struct {
+	URL    string `json:"url"` // without credentials
+	Ref    string `json:"ref,omitempty"`
+	Subdir string `json:"subdir,omitempty"`
+	Commit string `json:"commit"`
+}
+
+// validateGitSource checks a git source's settings before anything runs.
+func validateGitSource(src ContentSource) error {
+	if strings.TrimSpace(src.URL) == "" {
+		return fmt.Errorf("content_sources: a git source needs a url")
+	}
+	// A leading dash would reach git as an option.
+	if strings.HasPrefix(src.URL, "-") || strings.HasPrefix(src.Ref, "-") {
+		return fmt.Errorf("content_sources: git source %s: url and ref must not start with '-'", redactURL(src.URL))
+	}
+	if sub := filepath.Clean(filepath.FromSlash(src.Subdir)); filepath.IsAbs(sub) || sub == ".." || strings.HasPrefix(sub, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("content_sources: git source %s: subdir %q must stay inside the repository", redactURL(src.URL), src.Subdir)
+	}
+	if !contentSourceTypes[src.ContentType] {
+		return fmt.Errorf("content_sources: git source %s has unsupported content_type %q (supported: page, post)", redactURL(src.URL), src.ContentType)
+	}
+	return nil
+}
+
+// checkoutGitSource fetches a git source and returns the directory to read.
+func (g *Generator) checkoutGitSource(src ContentSource) (string, error) {
+	if err := validateGitSource(src); err != nil {
+		return "", err
+	}
+	label := redactURL(src.URL)
+	// Keyed by ref as well: sharing one tree between refs re-checks it out for
+	// each, leaves every such source reading the last ref's files and loses
+	// all but the last pin for --offline.
+	sum := sha256.Sum256([]byte(src.URL + "\x00" + refName(src.Ref)))
+	dir := cache.Dir("", filepath.Join("git", hex.EncodeToString(sum[:8])))
+	repo, tree, pinPath := filepath.Join(dir, "repo.git"), filepath.Join(dir, "tree"), filepath.Join(dir, "pin.json")
+
+	var prev GitSourcePin
+	if raw, err := os.ReadFile(pinPath); err == nil { // #nosec G304 -- our own cache file
+		_ = json.Unmarshal(raw, &prev)
+	}
+	pin := GitSourcePin{URL: label, Ref: src.Ref, Subdir: src.Subdir}
+
+	if g.config.ExternalSources.Offline {
+		if prev.Commit == "" || prev.Ref != src.Ref {
+			return "", fmt.Errorf("content_sources: %s@%s is not cached; run once without --offline", label, refName(src.Ref))
+		}
+		pin.Commit = prev.Commit
+	} else {
+		commit, err := fetchGitRef(src, repo)
+		if err != nil {
+			return "", fmt.Errorf("content_sources: %s@%s: %w", label, refName(src.Ref), err)
+		}
+		pin.Commit = commit
+		if _, err := os.Stat(tree); err != nil || prev.Commit != commit {
+			if err := checkoutGitTree(repo, tree, commit); err != nil {
+				return "", fmt.Errorf("content_sources: %s@%s: %w", label, refName(src.Ref), err)
+			}
+		}
+		raw, _ := json.MarshalIndent(pin, "", "  ")
+		// #nosec G306 -- cache metadata, no secrets (the URL is redacted)
+		if err := os.WriteFile(pinPath, append(raw, '\n'), 0644); err != nil {
+			return "", err
+		}
+	}
+
+	g.gitPins = append(g.gitPins, pin)
+	g.log(fmt.Sprintf("   🔀 %s@%s → %s", label, refName(src.Ref), pin.Commit))
+	root := filepath.Join(tree, filepath.FromSlash(src.Subdir))
+	if info, err := os.Stat(root); err != nil || !info.IsDir() {
+		return "", fmt.Errorf("content_sources: %s@%s has no directory %q", label, refName(src.Ref), src.Subdir)
+	}
+	return root, nil
+}
+
+// fetchGitRef fetches ref (default HEAD) into the bare cache repo, one commit
+// deep, and returns the commit it resolved to. The URL is passed per fetch,
+// so the repo holds no remote and no credential.
+func fetchGitRef(src ContentSource, repo string) (string, error) {
+	if _, err := os.Stat(filepath.Join(repo, "HEAD")); err != nil {
+		if err := os.MkdirAll(repo, 0750); err != nil {
+			return "", err
+		}
+		if _, err := runGitIn(repo, nil, "init", "--quiet", "--bare"); err != nil {
+			return "", err
+		}
+	}
+	env, err := gitAuthEnv(src.Auth)
+	if err != nil {
+		return "", err
+	}
+	if _, err := runGitIn(repo, env, "fetch", "--quiet", "--depth", "1", "--no-tags", src.URL, refName(src.Ref)); err != nil {
+		return "", err
+	}
+	return runGitIn(repo, nil, "rev-parse", "FETCH_HEAD^{commit}")
+}
+
+// checkoutGitTree replaces tree with commit's files, each dated to the commit.
+// A checkout's mtimes are the moment of the checkout, and a page without a
+// date takes its file's: every such page would be published "now" — after the
+// build started, so scheduled out as future — and differently on every build.
+func checkoutGitTree(repo, tree, commit string) error {
+	if err := os.RemoveAll(tree); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(tree, 0750); err != nil {
+		return err
+	}
+	absTree, err := filepath.Abs(tree)
+	if err != nil {
+		return err
+	}
+	if _, err := runGitIn(repo, []string{"GIT_WORK_TREE=" + absTree}, "checkout", "--quiet", "--force", commit, "--", "."); err != nil {
+		return err
+	}
+	stamp, err := runGitIn(repo, nil, "show", "--no-patch", "--format=%ct", commit)
+	if err != nil {
+		return err
+	}
+	secs, err := strconv.ParseInt(stamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("commit time %q: %w", stamp, err)
+	}
+	when := time.Unix(secs, 0)
+	return filepath.WalkDir(tree, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		return os.Chtimes(path, when, when)
+	})
+}
+
+// gitAuthEnv turns an auth block into the environment that makes git send it
+// as an HTTP header.
+func gitAuthEnv(a fetch.Auth) ([]string, error) {
+	a, err := fetch.ExpandAuth(a)
+	if err != nil {
+		return nil, err
+	}
+	var header string
+	switch a.Type {
+	case "", "none":
+		return nil, nil
+	case "bearer":
+		if a.Token == "" {
+			return nil, fmt.Errorf("auth type \"bearer\" needs a token")
+		}
+		header = "Authorization: Bearer " + a.Token
+	case "basic":
+		if a.Username == "" {
+			return nil, fmt.Errorf("auth type \"basic\" needs a username")
+		}
+		header = "Authorization: Basic " + base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password))
+	case "header":
+		if a.Header == "" {
+			return nil, fmt.Errorf("auth type \"header\" needs a header name")
+		}
+		header = a.Header + ": " + a.Value
+	default:
+		return nil, fmt.Errorf("unsupported auth type %q (use bearer, basic or header)", a.Type)
+	}
+	return []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=" + header}, nil
+}
+
+// runGitIn runs git with dir as its git directory and returns trimmed stdout.
+// It never prompts: a private repository without auth fails rather than
+// waiting on a terminal nobody watches.
+func runGitIn(dir string, env []string, args ...string) (string, error) {
+	// #nosec G204 -- fixed args (url and ref validated), never a shell
+	cmd := exec.Command("git", append([]string{"--git-dir=" + dir}, args...)...) // NOSONAR S4036: git is intentionally resolved from PATH (portable across systems), reviewed
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// refName is the ref fetched for an empty ref.
+func refName(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+// redactURL drops any user:password from a URL for logs and manifests.
+func redactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	u.User = nil
+	return u.String()
+}
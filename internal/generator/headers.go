@@ -6,7 +6,6 @@ package generator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -114,8 +113,7 @@ func (g *Generator) generateHeadersFile() error {
 	blocks := mergeHeaderBlocks(defaultHeaderBlocks(), g.config.Headers, g.config.HeadersDefaultsOff)
 	content := renderHeadersFile(blocks)
 	headersPath := filepath.Join(g.config.OutputDir, "_headers")
-	// #nosec G306 -- Web content files need to be world-readable
-	if err := os.WriteFile(headersPath, []byte(content), 0644); err != nil {
+	if err := g.writeOutput(headersPath, []byte(content)); err != nil {
 		return fmt.Errorf("writing _headers: %w", err)
 	}
 	return nil
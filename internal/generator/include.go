@@ -0,0 +1,428 @@
+package generator
+
+// Includes. Docs pages kept pasting the same install steps into every guide,
+// and code samples copied out of the real source drifted from it a release
+// later. A page names the file instead:
+//
+//	{{< include "snippets/install.md" >}}
+//	{{< include "cmd/ssg/main.go" lines="10-40" >}}
+//	{{< include "examples/server.go" region="setup" >}}
+//
+// A Markdown file is spliced into the page before anything renders, frontmatter
+// dropped, so it goes through the page's own pipeline — its shortcodes, links
+// and wikilinks resolve for the page, in the page's language, and a Polish page
+// reads install.pl.md when there is one. Any other file becomes a fenced code
+// block in its language, highlighted like every other fence. `lines` and
+// `region` cut a part out: a region runs from a line carrying `region:NAME`
+// (in whatever comment the language has) to the one carrying
+// `endregion:NAME`, and marker lines never show.
+//
+// Paths are relative to the project root, or to the including file when they
+// start with ./ or ../, and must resolve — symlinks included — inside the
+// project. A file that includes itself, directly or further down, fails the
+// build with the chain. Every file read is recorded on the page (Page.Includes)
+// for incremental builds, and for the watcher (IncludedFiles).
+//
+// Templates get the same as a function: {{ include "snippets/cta.md" }}, with
+// options as "key=value" strings.
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+)
+
+// includeRe matches {{< include "path" key="value"… >}}.
+var includeRe = regexp.MustCompile(`\{\{<\s*include\s+"([^"]+)"((?:\s+\w+="[^"]*")*)\s*>\}\}`)
+
+// regionMarkerRe matches any region or endregion marker line.
+var regionMarkerRe = regexp.MustCompile(`\b(?:end)?region:[\w.-]+`)
+
+// includeCtx is one expansion's state: the language it renders in, the chain
+// of files being expanded (for cycles), and every file read.
+type includeCtx struct {
+	lang  string
+	stack []string
+	files []string
+}
+
+// resolveIncludes expands the include shortcodes of every page and post. It
+// runs once languages are set and before wikilinks and sections read the
+// content, so included text counts everywhere the page's own does.
+func (g *Generator) resolveIncludes() error {
+	for _, pages := range [][]models.Page{g.siteData.Pages, g.siteData.Posts} {
+		for i := range pages {
+			p := &pages[i]
+			if !strings.Contains(p.Content, "{{<") {
+				continue
+			}
+			ctx := &includeCtx{lang: p.Lang}
+			if p.SourceFile != "" {
+				ctx.stack = []string{projectKey(filepath.Join(p.SourceDir, p.SourceFile))}
+			}
+			content, err := g.expandIncludes(p.Content, p.SourceDir, ctx)
+			if err != nil {
+				return fmt.Errorf("%s: %w", sourceKey(*p), err)
+			}
+			if len(ctx.files) == 0 {
+				continue
+			}
+			p.Content = content
+			p.Includes = ctx.files
+			p.ComputeReadingStats()
+			if g.config.Math {
+				p.HasMath = containsMath(p.Content)
+			}
+			g.noteIncludes(ctx.files)
+		}
+	}
+	return nil
+}
+
+// expandIncludes replaces the include shortcodes in Markdown outside fenced
+// code, so a page documenting the syntax can still show it.
+func (g *Generator) expandIncludes(content, dir string, ctx *includeCtx) (string, error) {
+	var firstErr error
+	expanded := outsideFences(content, func(text string) string {
+		return includeRe.ReplaceAllStringFunc(text, func(match string) string {
+			if firstErr != nil {
+				return match
+			}
+			m := includeRe.FindStringSubmatch(match)
+			out, err := g.includeFile(m[1], parseShortcodeAttrs(m[2]), dir, ctx)
+			if err != nil {
+				firstErr = err
+				return match
+			}
+			return out
+		})
+	})
+	return expanded, firstErr
+}
+
+// includeFile reads one include as the Markdown that replaces it.
+func (g *Generator) includeFile(ref string, attrs map[string]string, dir string, ctx *includeCtx) (string, error) {
+	path, err := resolveIncludePath(ref, dir, ctx.lang)
+	if err != nil {
+		return "", err
+	}
+	key := projectKey(path)
+	for i, open := range ctx.stack {
+		if open == key {
+			return "", fmt.Errorf("include cycle: %s", strings.Join(append(ctx.stack[i:], key), " → "))
+		}
+	}
+	raw, err := os.ReadFile(path) // #nosec G304 -- confined to the project by resolveIncludePath
+	if err != nil {
+		return "", fmt.Errorf("include %q: %w", ref, err)
+	}
+	ctx.note(key)
+	body := string(raw)
+	markdown := isMarkdownFile(path)
+	if markdown {
+		if _, _, rest, ok := parser.SplitFrontmatter(body); ok {
+			body = rest
+		}
+	}
+	body, err = selectLines(body, attrs)
+	if err != nil {
+		return "", fmt.Errorf("include %q: %w", ref, err)
+	}
+	if !markdown {
+		lang := attrs["lang"]
+		if lang == "" {
+			lang = fenceLanguage(path)
+		}
+		return parser.FencedBlock(lang, body), nil
+	}
+	ctx.stack = append(ctx.stack, key)
+	defer func() { ctx.stack = ctx.stack[:len(ctx.stack)-1] }()
+	return g.expandIncludes(strings.TrimSpace(body), filepath.Dir(path), ctx)
+}
+
+// note records a file read, once.
+func (ctx *includeCtx) note(key string) {
+	for _, f := range ctx.files {
+		if f == key {
+			return
+		}
+	}
+	ctx.files = append(ctx.files, key)
+}
+
+// resolveIncludePath turns an include reference into a file inside the
+// project, preferring the language's variant (install.pl.md for lang pl).
+func resolveIncludePath(ref, dir, lang string) (string, error) {
+	root, err := filepath.Abs(".")
+	if err != nil {
+		return "", err
+	}
+	base := root
+	if strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") {
+		if base, err = filepath.Abs(dir); err != nil {
+			return "", err
+		}
+	}
+	path := filepath.Join(base, filepath.FromSlash(ref))
+	if lang != "" {
+		ext := filepath.Ext(path)
+		if variant := strings.TrimSuffix(path, ext) + "." + lang + ext; isIncludeFile(variant) {
+			path = variant
+		}
+	}
+	if !isIncludeFile(path) {
+		return "", fmt.Errorf("include %q: no such file", ref)
+	}
+	inside, err := withinDir(root, path)
+	if err != nil {
+		return "", fmt.Errorf("include %q: %w", ref, err)
+	}
+	if !inside {
+		return "", fmt.Errorf("include %q: outside the project", ref)
+	}
+	return path, nil
+}
+
+// withinDir reports whether path, symlinks resolved, stays inside root.
+func withinDir(root, path string) (bool, error) {
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return false, err
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(realRoot, realPath)
+	if err != nil {
+		return false, err
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
+}
+
+// projectKey is a path as the dependency graph keys inputs: relative to the
+// working directory, slash-separated.
+func projectKey(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		if root, err := filepath.Abs("."); err == nil {
+			if rel, err := filepath.Rel(root, abs); err == nil {
+				return filepath.ToSlash(rel)
+			}
+		}
+	}
+	return filepath.ToSlash(path)
+}
+
+func isIncludeFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func isMarkdownFile(path string) bool {
+	ext := strings.ToLower(filepath.Ext(path))
+	return ext == ".md" || ext == ".markdown"
+}
+
+// fenceLanguage names a file's language for a code fence the way Chroma
+// knows it, so the highlighter picks the same lexer it would for ```go.
+func fenceLanguage(path string) string {
+	lexer := lexers.Match(filepath.Base(path))
+	if lexer == nil {
+		return ""
+	}
+	if aliases := lexer.Config().Aliases; len(aliases) > 0 {
+		return aliases[0]
+	}
+	return strings.ToLower(lexer.Config().Name)
+}
+
+// selectLines cuts a region and/or a line range out of body, drops region
+// markers and strips the indentation every remaining line shares — a region
+// from inside a function reads as a top-level sample.
+func selectLines(body string, attrs map[string]string) (string, error) {
+	lines := strings.Split(strings.TrimRight(body, "\n"), "\n")
+	if name := attrs["region"]; name != "" {
+		start, end := -1, -1
+		open := regexp.MustCompile(`(?:^|[^\w])region:` + regexp.QuoteMeta(name) + `\b`)
+		closing := regexp.MustCompile(`\bendregion:` + regexp.QuoteMeta(name) + `\b`)
+		for i, line := range lines {
+			if start < 0 && open.MatchString(line) && !closing.MatchString(line) {
+				start = i + 1
+			} else if start >= 0 && closing.MatchString(line) {
+				end = i
+				break
+			}
+		}
+		if start < 0 {
+			return "", fmt.Errorf("no region:%s marker", name)
+		}
+		if end < 0 {
+			return "", fmt.Errorf("region:%s has no endregion:%s", name, name)
+		}
+		lines = lines[start:end]
+	}
+	if spec := attrs["lines"]; spec != "" {
+		from, to, err := parseLineRange(spec, len(lines))
+		if err != nil {
+			return "", err
+		}
+		lines = lines[from-1 : to]
+	}
+	if attrs["region"] != "" || attrs["lines"] != "" {
+		kept := lines[:0:0]
+		for _, line := range lines {
+			if !regionMarkerRe.MatchString(line) {
+				kept = append(kept, line)
+			}
+		}
+		lines = dedentLines(kept)
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
+
+// parseLineRange reads "10-40", "10-", "-40" or "12" as 1-based inclusive
+// bounds within n lines.
+func parseLineRange(spec string, n int) (int, int, error) {
+	fromStr, toStr, isRange := strings.Cut(spec, "-")
+	if !isRange {
+		toStr = fromStr
+	}
+	from, to := 1, n
+	var err error
+	if fromStr = strings.TrimSpace(fromStr); fromStr != "" {
+		if from, err = strconv.Atoi(fromStr); err != nil {
+			return 0, 0, fmt.Errorf("lines=%q: %w", spec, err)
+		}
+	}
+	if toStr = strings.TrimSpace(toStr); toStr != "" {
+		if to, err = strconv.Atoi(toStr); err != nil {
+			return 0, 0, fmt.Errorf("lines=%q: %w", spec, err)
+		}
+	}
+	if to > n {
+		to = n
+	}
+	if from < 1 || from > to {
+		return 0, 0, fmt.Errorf("lines=%q is outside the file's %d lines", spec, n)
+	}
+	return from, to, nil
+}
+
+// dedentLines strips the leading whitespace all non-blank lines share.
+func dedentLines(lines []string) []string {
+	prefix := ""
+	first := true
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if first {
+			prefix, first = indent, false
+			continue
+		}
+		for !strings.HasPrefix(indent, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	if prefix == "" {
+		return lines
+	}
+	out := make([]string, len(lines))
+	for i, line := range lines {
+		out[i] = strings.TrimPrefix(line, prefix)
+	}
+	return out
+}
+
+// outsideFences applies fn to the parts of a Markdown document that are not
+// fenced code.
+func outsideFences(content string, fn func(string) string) string {
+	var out, text strings.Builder
+	fence := ""
+	flush := func() {
+		out.WriteString(fn(text.String()))
+		text.Reset()
+	}
+	for _, line := range strings.SplitAfter(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+			out.WriteString(line)
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			flush()
+			fence = trimmed[:3]
+			out.WriteString(line)
+		default:
+			text.WriteString(line)
+		}
+	}
+	flush()
+	return out.String()
+}
+
+// noteIncludes adds files to the build's include set.
+func (g *Generator) noteIncludes(files []string) {
+	g.renderMu.Lock()
+	defer g.renderMu.Unlock()
+	if g.includes == nil {
+		g.includes = map[string]bool{}
+	}
+	for _, f := range files {
+		g.includes[f] = true
+	}
+}
+
+// IncludedFiles lists every file the last build included, relative to the
+// project root, so a watcher can follow snippets outside its directories.
+func (g *Generator) IncludedFiles() []string {
+	g.renderMu.Lock()
+	defer g.renderMu.Unlock()
+	out := make([]string, 0, len(g.includes))
+	for f := range g.includes {
+		out = append(out, f)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// tmplInclude is the template form:
+//
+//	{{ include "snippets/cta.md" }}
+//	{{ include "examples/main.go" "region=setup" }}
+//
+// It renders in the language of the pages rendering, through the same
+// pipeline as .Content. Template includes are not tied to one page, so an
+// edit to one re-renders the whole site in an incremental build.
+func (g *Generator) tmplInclude(ref string, opts ...string) (template.HTML, error) {
+	attrs := map[string]string{}
+	for _, opt := range opts {
+		k, v, ok := strings.Cut(opt, "=")
+		if !ok {
+			return "", fmt.Errorf("include %q: option %q is not key=value", ref, opt)
+		}
+		attrs[k] = v
+	}
+	if strings.HasPrefix(ref, "./") || strings.HasPrefix(ref, "../") {
+		return "", fmt.Errorf("include %q: a template includes from the project root", ref)
+	}
+	ctx := &includeCtx{lang: g.currentLang}
+	md, err := g.includeFile(ref, attrs, ".", ctx)
+	g.noteIncludes(ctx.files)
+	if err != nil {
+		return "", err
+	}
+	return g.safeHTMLValue(md), nil
+}
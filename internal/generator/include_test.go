@@ -0,0 +1,157 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const includedSource = `package main
+
+func main() {
+	// region:setup
+	cfg := load()
+	run(cfg)
+	// endregion:setup
+}
+`
+
+func TestIncludeShortcode(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	mustWrite(t, filepath.Join(root, "snippets", "install.md"), "---\ntitle: ignored\n---\n\nRun `go install`.\n")
+	mustWrite(t, filepath.Join(root, "snippets", "install.pl.md"), "Uruchom `go install`.\n")
+	mustWrite(t, filepath.Join(root, "examples", "main.go"), includedSource)
+	about := filepath.Join(root, "content", "site", "pages", "about.md")
+	mustWrite(t, about, "---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\n"+
+		"{{< include \"snippets/install.md\" >}}\n\n"+
+		"{{< include \"examples/main.go\" region=\"setup\" >}}\n\n"+
+		"{{< include \"examples/main.go\" lines=\"3-3\" >}}\n\n"+
+		"```\n{{< include \"snippets/install.md\" >}}\n```\n")
+	mustWrite(t, filepath.Join(root, "content", "site", "pages", "o-nas.md"), "---\ntitle: O nas\nslug: o-nas\nstatus: publish\ntype: page\nlang: pl\n---\n\n"+
+		"{{< include \"snippets/install.md\" >}}\n")
+
+	gen := buildScheduled(t, root, nil)
+	raw, _ := os.ReadFile(filepath.Join(root, "output", "about", "index.html"))
+	page := string(raw)
+	for _, want := range []string{
+		"Run <code>go install</code>.",
+		"cfg := load()\nrun(cfg)\n",
+		`class="language-go"`,
+		"func main() {\n",
+		"{{&lt; include &quot;snippets/install.md&quot; &gt;}}", // shown, not expanded, in a fence
+	} {
+		if !strings.Contains(page, want) {
+			t.Errorf("about page lacks %q:\n%s", want, page)
+		}
+	}
+	if strings.Contains(page, "ignored") || strings.Contains(page, "region:") {
+		t.Errorf("frontmatter or region markers leaked:\n%s", page)
+	}
+	raw, _ = os.ReadFile(filepath.Join(root, "output", "o-nas", "index.html"))
+	if !strings.Contains(string(raw), "Uruchom") {
+		t.Errorf("a pl page reads the pl variant:\n%s", raw)
+	}
+	want := []string{"examples/main.go", "snippets/install.md", "snippets/install.pl.md"}
+	if got := gen.IncludedFiles(); strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("IncludedFiles = %v, want %v", got, want)
+	}
+}
+
+func TestIncludeErrors(t *testing.T) {
+	for _, tc := range []struct{ name, body, want string }{
+		{"cycle", `{{< include "snippets/a.md" >}}`, "include cycle: snippets/a.md → snippets/b.md → snippets/a.md"},
+		{"escape", `{{< include "../../../../outside.md" >}}`, "outside the project"},
+		{"missing", `{{< include "snippets/none.md" >}}`, "no such file"},
+		{"region", `{{< include "snippets/a.md" region="nope" >}}`, "no region:nope marker"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			root := filepath.Join(dir, "site")
+			t.Chdir(dir)
+			mustWrite(t, filepath.Join(dir, "outside.md"), "secret\n")
+			writeIncrementalSite(t, root)
+			t.Chdir(root)
+			mustWrite(t, filepath.Join(root, "snippets", "a.md"), `{{< include "./b.md" >}}`)
+			mustWrite(t, filepath.Join(root, "snippets", "b.md"), `{{< include "snippets/a.md" >}}`)
+			mustWrite(t, filepath.Join(root, "content", "site", "pages", "about.md"),
+				"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\n"+tc.body+"\n")
+			gen, err := New(Config{
+				Source: "site", Template: "simple", Domain: "example.com",
+				ContentDir:   filepath.Join(root, "content"),
+				TemplatesDir: filepath.Join(root, "templates"),
+				DataDir:      filepath.Join(root, "data"),
+				OutputDir:    filepath.Join(root, "output"),
+				Quiet:        true,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := gen.Generate(); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestSelectLines(t *testing.T) {
+	for _, tc := range []struct {
+		attrs map[string]string
+		want  string
+	}{
+		{map[string]string{"lines": "3-5"}, "func main() {\n\tcfg := load()\n"},
+		{map[string]string{"lines": "-1"}, "package main\n"},
+		{map[string]string{"lines": "8-"}, "}\n"},
+		{map[string]string{"region": "setup"}, "cfg := load()\nrun(cfg)\n"},
+		{map[string]string{"region": "setup", "lines": "2"}, "run(cfg)\n"},
+	} {
+		got, err := selectLines(includedSource, tc.attrs)
+		if err != nil || got != tc.want {
+			t.Errorf("selectLines(%v) = %q, %v; want %q", tc.attrs, got, err, tc.want)
+		}
+	}
+	if _, err := selectLines(includedSource, map[string]string{"lines": "9-12"}); err == nil {
+		t.Error("a range past the end must fail")
+	}
+}
+
+// A snippet edit re-renders the pages including it, and a template include
+// is followed too.
+func TestIncrementalIncludeEdit(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	snippet := filepath.Join(root, "snippets", "install.md")
+	mustWrite(t, snippet, "Install v1.\n")
+	mustWrite(t, filepath.Join(root, "snippets", "footer.md"), "Footer v1.\n")
+	mustWrite(t, filepath.Join(root, "content", "site", "pages", "about.md"),
+		"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\n{{< include \"snippets/install.md\" >}}\n")
+	mustWrite(t, filepath.Join(root, "templates", "simple", "index.html"),
+		`<html>{{range .Posts}}<a>{{.Title}}</a>{{end}}{{include "snippets/footer.md"}}</html>`)
+	buildIncremental(t, root)
+
+	mustWrite(t, snippet, "Install v2.\n")
+	gen, rendered := buildIncremental(t, root, snippet)
+	if gen.deps.full {
+		t.Fatalf("a snippet edit should not full-render: %s", gen.deps.reason)
+	}
+	if !containsOutput(rendered, "about/index.html") || containsOutput(rendered, "2024/01/02/alpha/index.html") {
+		t.Errorf("rendered = %v", rendered)
+	}
+	got, _ := os.ReadFile(filepath.Join(root, "output", "about", "index.html"))
+	if !strings.Contains(string(got), "Install v2.") {
+		t.Errorf("about page is stale: %s", got)
+	}
+
+	mustWrite(t, filepath.Join(root, "snippets", "footer.md"), "Footer v2.\n")
+	gen, _ = buildIncremental(t, root)
+	if !gen.deps.full || !strings.Contains(gen.deps.reason, "snippets/footer.md") {
+		t.Errorf("a template include edit renders everything: full=%v %q", gen.deps.full, gen.deps.reason)
+	}
+	got, _ = os.ReadFile(filepath.Join(root, "output", "index.html"))
+	if !strings.Contains(string(got), "Footer v2.") {
+		t.Errorf("index is stale: %s", got)
+	}
+}
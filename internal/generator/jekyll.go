@@ -0,0 +1,244 @@
+package generator
+
+// The Jekyll side of the Liquid engine: a theme written for Jekyll reads
+// site.posts, page.title and {{ content }}, not .Site.Posts and .Title. Every
+// render under engine: liquid gets those names beside ssg's own, so an
+// unmodified Jekyll theme renders the blog and a theme can still reach
+// anything ssg adds. The filters that need the site (relative_url,
+// absolute_url, markdownify, slugify) join the template helpers here; the
+// ones that do not live in the engine.
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/spagu/ssg/internal/engine"
+	"github.com/spagu/ssg/internal/models"
+)
+
+// jekyllDocsKey identifies one page list by its backing array, so the
+// per-language lists a build switches between are converted once each.
+type jekyllDocsKey struct {
+	first *models.Page
+	n     int
+}
+
+// isLiquid reports whether the build renders through the Liquid engine.
+func (g *Generator) isLiquid() bool {
+	return g.engine != nil && g.engine.Name() == engine.EngineLiquid
+}
+
+// jekyllFuncs are the Jekyll filters that depend on the site, as template
+// helpers the engine bridges into filters.
+func (g *Generator) jekyllFuncs() template.FuncMap {
+	relative := func(path string) string {
+		if path == "" || strings.Contains(path, "://") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "#") {
+			return path
+		}
+		return "/" + strings.TrimPrefix(path, "/")
+	}
+	return template.FuncMap{
+		// ssg serves a site from its domain root, so Jekyll's baseurl is
+		// always empty and relative_url only makes a path site-absolute.
+		"relative_url": relative,
+		"absolute_url": func(path string) string {
+			if strings.Contains(path, "://") || strings.HasPrefix(path, "//") {
+				return path
+			}
+			return httpsScheme + g.config.Domain + relative(path)
+		},
+		"markdownify": g.safeHTMLValue,
+		"slugify":     slugify,
+	}
+}
+
+// jekyllContext adds the Jekyll names to one render's data: site, page,
+// content and, on a listing, paginator. A struct context (the front page) is
+// flattened first, the same way the engine reads it.
+func (g *Generator) jekyllContext(data interface{}, page *models.Page) map[string]interface{} {
+	ctx := engine.ContextMap(data)
+	var doc map[string]interface{}
+	if content, ok := ctx["Content"]; ok && page != nil {
+		doc = g.jekyllDoc(*page)
+		doc["content"] = stringOf(content)
+		ctx["content"] = doc["content"]
+	} else {
+		// A listing has no document of its own: the front page is untitled,
+		// as a Jekyll index.md usually is, and an archive is its term's name.
+		doc = map[string]interface{}{"title": ctx["Name"]}
+		if page != nil {
+			doc["url"] = page.GetURL()
+		}
+	}
+	ctx["page"] = doc
+	ctx["site"] = g.jekyllSite()
+	if pager, ok := ctx["Pager"].(Pager); ok {
+		posts, _ := ctx["Posts"].([]models.Page)
+		ctx["paginator"] = map[string]interface{}{
+			"page":               pager.Current,
+			"per_page":           pager.PerPage,
+			"total_pages":        pager.Total,
+			"posts":              g.jekyllDocs(posts),
+			"previous_page":      pagerNumber(pager.PrevURL != "", pager.Current-1),
+			"previous_page_path": nilIfEmpty(pager.PrevURL),
+			"next_page":          pagerNumber(pager.NextURL != "", pager.Current+1),
+			"next_page_path":     nilIfEmpty(pager.NextURL),
+		}
+	}
+	return ctx
+}
+
+// jekyllSite is Jekyll's site: the config's variables (a Jekyll _config.yml
+// key reads as site.<key>), then the site's own fields over them.
+func (g *Generator) jekyllSite() map[string]interface{} {
+	site := map[string]interface{}{}
+	for k, v := range g.config.Variables {
+		site[k] = v
+	}
+	title := g.siteData.Title
+	if title == "" {
+		title = g.config.Domain
+	}
+	posts := g.jekyllDocs(g.siteData.Posts)
+	site["title"] = title
+	site["description"] = g.siteData.Description
+	site["url"] = httpsScheme + g.config.Domain
+	site["baseurl"] = ""
+	site["posts"] = posts
+	site["pages"] = g.jekyllDocs(g.siteData.Pages)
+	site["data"] = g.data
+	site["time"] = g.buildTime
+	site["menus"] = g.siteData.Menus
+	site["categories"], site["tags"] = jekyllTerms(posts)
+	return site
+}
+
+// jekyllDocs converts a page list to Jekyll documents, newest first for
+// posts as the list already is. Each list is converted once per build; the
+// documents leave out content, which only the page being rendered carries.
+func (g *Generator) jekyllDocs(pages []models.Page) []interface{} {
+	if len(pages) == 0 {
+		return []interface{}{}
+	}
+	key := jekyllDocsKey{first: &pages[0], n: len(pages)}
+	g.jekyllMu.Lock()
+	defer g.jekyllMu.Unlock()
+	if docs, ok := g.jekyllCache[key]; ok {
+		return docs
+	}
+	docs := make([]interface{}, len(pages))
+	for i := range pages {
+		docs[i] = g.jekyllDoc(pages[i])
+	}
+	if g.jekyllCache == nil {
+		g.jekyllCache = map[jekyllDocsKey][]interface{}{}
+	}
+	g.jekyllCache[key] = docs
+	return docs
+}
+
+// jekyllDoc is one page or post as Jekyll names its fields, with the page's
+// own frontmatter beside them.
+func (g *Generator) jekyllDoc(p models.Page) map[string]interface{} {
+	doc := map[string]interface{}{}
+	for k, v := range p.Extra {
+		doc[k] = v
+	}
+	var categories []interface{}
+	for _, id := range p.Categories {
+		if name := g.tmplGetCategoryName(id); name != "" {
+			categories = append(categories, name)
+		}
+	}
+	if p.Category != "" && len(categories) == 0 {
+		categories = append(categories, p.Category)
+	}
+	tags := make([]interface{}, len(p.Tags))
+	for i, t := range p.Tags {
+		tags[i] = t
+	}
+	url := p.GetURL()
+	for k, v := range map[string]interface{}{
+		"title":       p.Title,
+		"url":         url,
+		"id":          url,
+		"slug":        p.Slug,
+		"date":        g.pageDate(p, p.Date),
+		"excerpt":     p.Excerpt,
+		"description": p.Description,
+		"categories":  categories,
+		"tags":        tags,
+		"layout":      p.Layout,
+		"lang":        p.Lang,
+		"path":        p.SourceFile,
+		"author":      g.tmplGetAuthorName(p.Author),
+	} {
+		doc[k] = v
+	}
+	if doc["author"] == "" {
+		doc["author"] = p.Extra["author"]
+	}
+	return doc
+}
+
+// jekyllTerms groups the posts by category and by tag, as site.categories
+// and site.tags.
+func jekyllTerms(posts []interface{}) (map[string]interface{}, map[string]interface{}) {
+	categories, tags := map[string]interface{}{}, map[string]interface{}{}
+	for _, p := range posts {
+		doc := p.(map[string]interface{})
+		for _, field := range []struct {
+			key string
+			out map[string]interface{}
+		}{{"categories", categories}, {"tags", tags}} {
+			terms, _ := doc[field.key].([]interface{})
+			for _, t := range terms {
+				name := stringOf(t)
+				list, _ := field.out[name].([]interface{})
+				field.out[name] = append(list, doc)
+			}
+		}
+	}
+	return categories, tags
+}
+
+// jekyllTemplateAliases maps the names ssg renders to the layouts a Jekyll
+// theme ships: the front page is home.html when there is no index.html.
+func (g *Generator) jekyllTemplateAliases() {
+	if _, ok := g.engineTmpls[indexHTMLName]; !ok {
+		if home, ok := g.engineTmpls["home.html"]; ok {
+			g.engineTmpls[indexHTMLName] = home
+		}
+	}
+}
+
+// stringOf renders a context value as the string Liquid would print.
+func stringOf(v interface{}) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case template.HTML:
+		return string(s)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// pagerNumber is a paginator page number, nil where there is none.
+func pagerNumber(ok bool, n int) interface{} {
+	if !ok {
+		return nil
+	}
+	return n
+}
+
+// nilIfEmpty is nil for "", so a Jekyll theme's `{% if paginator.next_page_path %}` reads false.
+func nilIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
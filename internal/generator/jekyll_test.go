@@ -0,0 +1,59 @@
+package generator
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// jekyllTheme is a Jekyll theme as a team would bring it over, untouched:
+// layouts that wrap each other, includes with parameters, plugin tags and the
+// Jekyll names for the site's data.
+var jekyllTheme = map[string]string{
+	"_layouts/default.html": "<html><head>{%- include head.html -%}{%- seo -%}</head><body>" +
+		"{{ content }}{% include footer.html owner=site.data.owner.name %}</body></html>",
+	"_layouts/home.html": "---\nlayout: default\n---\n<h1>{{ site.title }}</h1><ul>" +
+		"{% for post in paginator.posts %}<li><a href=\"{{ post.url | relative_url }}\">{{ post.title }}</a> " +
+		"{{ post.date | date: \"%b %-d, %Y\" }}</li>{% endfor %}</ul>" +
+		"{% assign guides = site.posts | where: \"categories\", \"Guides\" %}<p>guides: {{ guides | size }}</p>",
+	"_layouts/post.html": "---\nlayout: default\n---\n<article><h1>{{ page.title | escape }}</h1>" +
+		"<time datetime=\"{{ page.date | date_to_xmlschema }}\">{{ page.date | date: \"%b %-d, %Y\" }}</time>" +
+		"<p class=\"slug\">{{ page.title | slugify }}</p>{{ content }}</article>",
+	"_layouts/page.html": "---\nlayout: default\n---\n<h1>{{ page.title }}</h1>{{ content }}" +
+		"{{ page.summary | markdownify }}<a href=\"{{ page.url | absolute_url }}\">self</a>",
+	"_includes/head.html":   "<title>{% if page.title %}{{ page.title }} | {% endif %}{{ site.title }}</title>",
+	"_includes/footer.html": "<footer>© {{ include.owner }}</footer>",
+}
+
+// TestLiquidRendersJekyllTheme: an unmodified Jekyll theme renders the front
+// page from home.html, posts and pages through post.html and page.html, each
+// wrapped in default.html, with site, page, paginator and the Jekyll filters.
+func TestLiquidRendersJekyllTheme(t *testing.T) {
+	tmp := t.TempDir()
+	site := filepath.Join(tmp, "content", "site")
+	mustWrite(t, filepath.Join(site, "metadata.json"), `{"categories":[{"id":2,"name":"Guides","slug":"guides"}],"exported_at":"","media":[]}`)
+	mustWrite(t, filepath.Join(site, "posts", "guides", "hello.md"),
+		"---\ntitle: Hello World\nslug: hello\nstatus: publish\ntype: post\ndate: 2024-03-05\ncategories: [2]\n---\n\nFirst *post*.\n")
+	mustWrite(t, filepath.Join(site, "pages", "about.md"),
+		"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\nsummary: \"We **write**.\"\n---\n\nAbout us.\n")
+	mustWrite(t, filepath.Join(tmp, "data", "owner.yaml"), "name: Jane Doe\n")
+	for name, body := range jekyllTheme {
+		mustWrite(t, filepath.Join(tmp, "templates", "minimal", filepath.FromSlash(name)), body)
+	}
+	gen, err := New(Config{Source: "site", Template: "minimal", Domain: "example.com", Engine: "liquid",
+		ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+		OutputDir: filepath.Join(tmp, "output"), DataDir: filepath.Join(tmp, "data"), Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	out := filepath.Join(tmp, "output")
+	wantContains(t, "home", mustRead(t, filepath.Join(out, "index.html")),
+		"<title>example.com</title>", "<h1>example.com</h1>", `<a href="/2024/03/05/hello/">Hello World</a> Mar 5, 2024`, "guides: 1", "<footer>© Jane Doe</footer>")
+	wantContains(t, "post", mustRead(t, filepath.Join(out, "2024", "03", "05", "hello", "index.html")),
+		"<title>Hello World | example.com</title>", "<h1>Hello World</h1>", `datetime="2024-03-05T00:00:00Z"`,
+		`<p class="slug">hello-world</p>`, "<em>post</em>", "<footer>© Jane Doe</footer>")
+	wantContains(t, "page", mustRead(t, filepath.Join(out, "about", "index.html")),
+		"<h1>About</h1>", "About us.", "<strong>write</strong>", `<a href="https://example.com/about/">self</a>`)
+}
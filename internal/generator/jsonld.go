@@ -88,8 +88,12 @@ func (g *Generator) derivedLD(page models.Page, isPost bool, canonical string) m
 	if !mod.IsZero() {
 		ld["dateModified"] = mod.UTC().Format(time.RFC3339)
 	}
-	if name := g.authorDisplayName(page); name != "" {
-		ld["author"] = map[string]interface{}{"@type": "Person", "name": name}
+	// Every author of a co-written post; one stays a single object, as it
+	// always was.
+	if people := g.authorPersons(page); len(people) == 1 {
+		ld["author"] = people[0]
+	} else if len(people) > 1 {
+		ld["author"] = people
 	}
 	if kw := keywordsOf(page); kw != "" {
 		ld["keywords"] = kw
@@ -101,6 +105,8 @@ func (g *Generator) derivedLD(page models.Page, isPost bool, canonical string) m
 // breadcrumbLD derives a BreadcrumbList from the page's URL path so agents can
 // place the page in the site hierarchy. Home (and any page whose URL is "/") has
 // no trail and returns nil; a bare domain also skips it (item URLs need a host).
+// A page in a section follows the section tree instead, whose titles are the
+// ones its _index.md files chose rather than guesses from URL segments.
 func (g *Generator) breadcrumbLD(page models.Page) map[string]interface{} {
 	if g.config.Domain == "" {
 		return nil
@@ -109,6 +115,9 @@ func (g *Generator) breadcrumbLD(page models.Page) map[string]interface{} {
 	if rel == "" {
 		return nil
 	}
+	if node := g.sectionNode(page); node != nil && node.Parent != nil {
+		return g.sectionBreadcrumbLD(page, node)
+	}
 	segs := strings.Split(rel, "/")
 	items := make([]interface{}, 0, len(segs)+1)
 	items = append(items, crumb(1, "Home", "https://"+g.config.Domain+"/"))
@@ -132,6 +141,27 @@ func (g *Generator) breadcrumbLD(page models.Page) map[string]interface{} {
 	}
 }
 
+// sectionBreadcrumbLD is the trail of a page in the section tree: Home, each
+// section above it, then the page.
+func (g *Generator) sectionBreadcrumbLD(page models.Page, node *models.SectionNode) map[string]interface{} {
+	ancestors := node.Ancestors()
+	items := make([]interface{}, 0, len(ancestors)+2)
+	items = append(items, crumb(1, "Home", "https://"+g.config.Domain+"/"))
+	for i, a := range ancestors {
+		items = append(items, crumb(i+2, a.Title, "https://"+g.config.Domain+a.URL))
+	}
+	name := node.Title
+	if name == "" {
+		name = page.Title
+	}
+	items = append(items, crumb(len(items)+1, name, g.servedCanonical(page)))
+	return map[string]interface{}{
+		"@context":        "https://schema.org",
+		"@type":           "BreadcrumbList",
+		"itemListElement": items,
+	}
+}
+
 // crumb is one BreadcrumbList ListItem.
 func crumb(pos int, name, item string) map[string]interface{} {
 	return map[string]interface{}{
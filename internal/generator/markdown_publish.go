@@ -8,7 +8,6 @@ package generator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"strings"
 
@@ -49,17 +48,14 @@ func (g *Generator) writeMarkdownOutput(page models.Page, htmlPath string) {
 	data := encodeText(g.pageMarkdown(page), g.encodingFor(&page))
 	if strings.HasSuffix(htmlPath, indexHTMLName) {
 		dir := filepath.Dir(htmlPath)
-		// #nosec G306 -- Web content files need to be world-readable
-		_ = os.WriteFile(filepath.Join(dir, "index.md"), data, 0644)
+		_ = g.writeOutput(filepath.Join(dir, "index.md"), data)
 		if filepath.Clean(dir) != filepath.Clean(g.config.OutputDir) {
-			// #nosec G306 -- Web content files need to be world-readable
-			_ = os.WriteFile(dir+".md", data, 0644)
+			_ = g.writeOutput(dir+".md", data)
 		}
 		return
 	}
 	// Flat page: /slug.html → /slug.md.
-	// #nosec G306 -- Web content files need to be world-readable
-	_ = os.WriteFile(strings.TrimSuffix(htmlPath, ".html")+".md", data, 0644)
+	_ = g.writeOutput(strings.TrimSuffix(htmlPath, ".html")+".md", data)
 }
 
 // pageMarkdown returns the clean Markdown document for a page: an H1 title
@@ -112,11 +108,10 @@ func (g *Generator) generateLLMsTxt() error {
 	var b strings.Builder
 	fmt.Fprintf(&b, "# %s\n\n", g.config.Domain)
 	b.WriteString("> Markdown copies of every page, for language models and agents.\n")
-	writeSection(&b, "Documentation", g.siteData.Pages, g.config.Domain)
-	writeSection(&b, "Posts", g.siteData.Posts, g.config.Domain)
+	writeSection(&b, "Documentation", published(g.siteData.Pages), g.config.Domain)
+	writeSection(&b, "Posts", published(g.siteData.Posts), g.config.Domain)
 	data := encodeText(g.cleanSpecialChars(b.String()), normalizeEncoding(g.config.OutputEncoding))
-	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(filepath.Join(g.config.OutputDir, "llms.txt"), data, 0644)
+	return g.writeOutput(filepath.Join(g.config.OutputDir, "llms.txt"), data)
 }
 
 // writeSection appends one "## Heading" block listing pages as links to their
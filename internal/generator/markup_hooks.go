@@ -0,0 +1,165 @@
+package generator
+
+// Markup hooks: a theme overrides the HTML the Markdown renderer emits for one
+// construct by shipping _markup/render-<name>.html. The renderer asks for the
+// hook while converting; a theme without one gets the built-in markup, so
+// every hook is optional and a missing _markup/ directory costs one stat.
+//
+// Hooks are parsed once per build, on first use, through the configured
+// template engine, with a small, page-free helper set: goldmark output is
+// memoized per Markdown source (PERF-004), so a hook's output may depend only
+// on what it is handed, never on the page being rendered.
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/spagu/ssg/internal/engine"
+)
+
+// markupHooks holds one theme's _markup/ templates.
+type markupHooks struct {
+	// dirs are the _markup/ directories of the theme chain, child first
+	// (theme_layers.go): the nearest theme with a hook wins.
+	dirs  []string
+	eng   engine.Engine
+	alt   bool // a non-Go engine: hooks get plain maps, HTML as strings (GO-007)
+	funcs template.FuncMap
+	mu    sync.Mutex
+	tmpls map[string]engine.Template // name → template; nil = the theme has none
+}
+
+// newMarkupHooks prepares the hooks of cfg's theme. extra adds helpers to the
+// built-in set — the generator's image helpers, which resolve a path the same
+// way from every page.
+func newMarkupHooks(cfg Config, extra template.FuncMap) *markupHooks {
+	eng, err := engine.New(cfg.Engine)
+	if err != nil {
+		eng = engine.NewGoEngine() // loadTemplates reports the bad name
+	}
+	funcs := markupHookFuncs()
+	for name, fn := range extra {
+		funcs[name] = fn
+	}
+	return &markupHooks{
+		dirs:  markupDirs(cfg),
+		eng:   eng,
+		alt:   eng.Name() != engine.EngineGo,
+		funcs: funcs,
+		tmpls: map[string]engine.Template{},
+	}
+}
+
+// lookup returns the theme's render-<name>.html, or nil. A template that does
+// not parse is reported once and treated as absent, so one typo in a hook
+// degrades to the built-in markup instead of failing every page.
+func (h *markupHooks) lookup(name string) engine.Template {
+	if h == nil {
+		return nil
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if t, seen := h.tmpls[name]; seen {
+		return t
+	}
+	var t engine.Template
+	for _, dir := range h.dirs {
+		path := filepath.Join(dir, "render-"+name+".html")
+		if _, err := os.Stat(path); err != nil {
+			continue
+		}
+		parsed, err := h.eng.ParseFile(path, h.funcs)
+		if err != nil {
+			fmt.Printf("   ⚠️  Warning: markup hook %s: %v\n", filepath.Base(path), err)
+		} else {
+			t = parsed
+		}
+		break
+	}
+	h.tmpls[name] = t
+	return t
+}
+
+// markupDirs lists the _markup/ directory of each theme in cfg's chain. A
+// broken chain is the build's to report; the hooks use what resolved.
+func markupDirs(cfg Config) []string {
+	layers, _ := resolveThemeLayers(cfg.TemplatesDir, cfg.Template)
+	dirs := make([]string, len(layers))
+	for i, l := range layers {
+		dirs[i] = filepath.Join(l.Dir, "_markup")
+	}
+	return dirs
+}
+
+// has reports whether the theme ships a hook for any of names.
+func (h *markupHooks) has(names ...string) bool {
+	for _, name := range names {
+		if h.lookup(name) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// render executes the hook for name, reporting false when the theme has none
+// or it failed, in which case the caller writes its built-in markup.
+func (h *markupHooks) render(name string, data any) (string, bool) {
+	t := h.lookup(name)
+	if t == nil {
+		return "", false
+	}
+	if h.alt {
+		data = altHookData(data)
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		fmt.Printf("   ⚠️  Warning: markup hook render-%s.html: %v\n", name, err)
+		return "", false
+	}
+	return buf.String(), true
+}
+
+// altHookData flattens a hook context struct into the map a non-Go engine
+// reads, with template.HTML fields as plain strings — the same shape
+// prepAltData gives page templates, so `{{{ Text }}}` / `{{ Text|safe }}`
+// work as they do there.
+func altHookData(data any) any {
+	v := reflect.ValueOf(data)
+	if v.Kind() != reflect.Struct {
+		return data
+	}
+	out := make(map[string]any, v.NumField())
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if hv, ok := f.Interface().(template.HTML); ok {
+			out[v.Type().Field(i).Name] = string(hv)
+			continue
+		}
+		out[v.Type().Field(i).Name] = f.Interface()
+	}
+	return out
+}
+
+// markupHookFuncs is the helper set of a hook: the theme helpers, under the
+// same names, that need no site or page.
+func markupHookFuncs() template.FuncMap {
+	return template.FuncMap{
+		"raw":        tmplRaw,
+		"html":       tmplRaw,
+		"safeHTML":   tmplRaw,
+		"dict":       tmplDict,
+		"slice":      tmplSliceOf,
+		"default":    tmplDefault,
+		"stripHTML":  tmplStripHTML,
+		"contains":   tmplContains,
+		"hasPrefix":  strings.HasPrefix,
+		"hasSuffix":  strings.HasSuffix,
+		"trimPrefix": strings.TrimPrefix,
+	}
+}
@@ -0,0 +1,287 @@
+package generator
+
+// Navigation from three places, merged into one tree per menu: the menus a
+// migration imported from the CMS (#132), the `menu:` a page declares in its
+// frontmatter, and the `menus:` config block for links no page can declare.
+// A page added after the migration joins the imported main navigation
+// without anyone hand-editing metadata.json, and an imported item can be the
+// parent a new page nests under.
+//
+// The merged menus land on .Site.Menus, as imported menus always did; each
+// rendered page also gets .Menus — the same trees as plain item lists, with
+// Active and Ancestor set for that page, so a theme marks the current entry
+// the same way in every engine.
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// menuEntrySource is one entry to merge, and where it was declared, for
+// messages. page is nil for a config entry.
+type menuEntrySource struct {
+	entry  models.MenuEntry
+	page   *models.Page
+	kind   string
+	origin string
+}
+
+// buildMenus merges the config and frontmatter entries into g.siteData.Menus.
+// A name that matches an imported menu's location or slug extends that menu;
+// any other name starts a new one. Two entries claiming one identifier is an
+// error — either would be a guess; a parent that names nothing is reported and
+// its entry kept at the top, as Tree treats a half-exported menu.
+func (g *Generator) buildMenus() error {
+	byMenu := map[string][]menuEntrySource{}
+	for name, entries := range g.config.Menus {
+		for i, e := range entries {
+			byMenu[name] = append(byMenu[name], menuEntrySource{entry: e, origin: fmt.Sprintf("menus.%s[%d]", name, i)})
+		}
+	}
+	for _, set := range []struct {
+		kind  string
+		pages []models.Page
+	}{{"page", g.siteData.Pages}, {"post", g.siteData.Posts}} {
+		for i := range set.pages {
+			p := &set.pages[i]
+			for name, e := range p.MenuFM {
+				origin := p.SourceFile
+				if origin == "" {
+					origin = p.Slug
+				}
+				byMenu[name] = append(byMenu[name], menuEntrySource{entry: e, page: p, kind: set.kind, origin: origin})
+			}
+		}
+	}
+	if len(byMenu) == 0 {
+		return nil
+	}
+	if g.siteData.Menus == nil {
+		g.siteData.Menus = map[string]models.Menu{}
+	}
+	names := make([]string, 0, len(byMenu))
+	for name := range byMenu {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		menu, err := g.mergeMenu(name, byMenu[name])
+		if err != nil {
+			return err
+		}
+		// The imported menu is keyed by each location and by its slug; every
+		// key sees the merged items, whichever one the entries named.
+		if prev, ok := g.siteData.Menus[name]; ok && prev.ID != 0 {
+			for key, m := range g.siteData.Menus {
+				if m.ID == prev.ID {
+					g.siteData.Menus[key] = menu
+				}
+			}
+		}
+		g.siteData.Menus[name] = menu
+	}
+	g.log(fmt.Sprintf("   🧭 Merged menu entries from frontmatter and config into %d menu(s)", len(names)))
+	return nil
+}
+
+// mergeMenu adds entries to the menu keyed name, or to a new one. New items
+// get ids past the imported ones, so the two can never collide in Tree.
+func (g *Generator) mergeMenu(name string, entries []menuEntrySource) (models.Menu, error) {
+	menu, ok := g.siteData.Menus[name]
+	if !ok {
+		menu = models.Menu{Name: name, Slug: name}
+	}
+	items := append([]models.MenuItem(nil), menu.Items...)
+	nextID := 0
+	for _, item := range items {
+		if item.ID > nextID {
+			nextID = item.ID
+		}
+	}
+
+	// Who a `parent:` can name, most specific first: an explicit identifier,
+	// a page's slug, a title, an imported item's id.
+	identifiers := map[string]int{}
+	declaredBy := map[string]string{}
+	slugs := map[string]int{}
+	titles := map[string]int{}
+	for _, item := range items {
+		if key := strings.ToLower(item.Title); key != "" {
+			if _, taken := titles[key]; !taken {
+				titles[key] = item.ID
+			}
+		}
+	}
+	added := make([]int, len(entries))
+	for i, src := range entries {
+		e := src.entry
+		nextID++
+		item := models.MenuItem{
+			ID:         nextID,
+			Title:      e.Title,
+			URL:        e.URL,
+			Order:      e.Weight,
+			Type:       "custom",
+			Identifier: e.Identifier,
+		}
+		if p := src.page; p != nil {
+			if item.Title == "" {
+				item.Title = p.Title
+			}
+			if item.URL == "" {
+				item.URL = p.GetURL()
+			}
+			item.Type, item.Object, item.ObjectID = "post_type", p.Type, p.ID
+			if item.Object == "" {
+				item.Object = src.kind
+			}
+			if _, taken := slugs[strings.ToLower(p.Slug)]; !taken && p.Slug != "" {
+				slugs[strings.ToLower(p.Slug)] = item.ID
+			}
+		}
+		if item.Title == "" {
+			item.Title = e.Identifier
+		}
+		if item.Title == "" {
+			return menu, fmt.Errorf("%s: a menu entry needs a title or an identifier", src.origin)
+		}
+		if id := strings.ToLower(e.Identifier); id != "" {
+			if first, taken := declaredBy[id]; taken {
+				return menu, fmt.Errorf("menu %q: identifier %q is declared by both %s and %s", name, e.Identifier, first, src.origin)
+			}
+			declaredBy[id] = src.origin
+			identifiers[id] = item.ID
+		}
+		if key := strings.ToLower(item.Title); key != "" {
+			if _, taken := titles[key]; !taken {
+				titles[key] = item.ID
+			}
+		}
+		added[i] = len(items)
+		items = append(items, item)
+	}
+
+	for i, src := range entries {
+		parent := strings.TrimSpace(src.entry.Parent)
+		if parent == "" {
+			continue
+		}
+		key := strings.ToLower(parent)
+		id, found := identifiers[key]
+		if !found {
+			id, found = slugs[key]
+		}
+		if !found {
+			id, found = titles[key]
+		}
+		if !found {
+			if n, err := strconv.Atoi(parent); err == nil {
+				for _, item := range menu.Items {
+					if item.ID == n {
+						id, found = n, true
+						break
+					}
+				}
+			}
+		}
+		if !found {
+			fmt.Printf("   ⚠️  %s: menu %q has no entry %q to nest under; kept at the top\n", src.origin, name, parent)
+			continue
+		}
+		items[added[i]].Parent = id
+	}
+	menu.Items = items
+	return menu, nil
+}
+
+// menusFor returns every menu's tree for one output, with the entry for that
+// page marked Active and the entries above it Ancestor. The trees are built
+// afresh per page, so no flag leaks from one page to the next.
+func (g *Generator) menusFor(outputPath string, page *models.Page) map[string][]models.MenuItem {
+	out := make(map[string][]models.MenuItem, len(g.siteData.Menus))
+	if len(g.siteData.Menus) == 0 {
+		return out
+	}
+	current := ""
+	if rel, err := filepath.Rel(g.config.OutputDir, outputPath); err == nil && !strings.HasPrefix(rel, "..") {
+		current = g.menuPath("/" + filepath.ToSlash(rel))
+	}
+	pageID := 0
+	if page != nil {
+		pageID = page.ID
+	}
+	for key, m := range g.siteData.Menus {
+		tree := m.Tree()
+		g.markActive(tree, current, pageID)
+		out[key] = tree
+	}
+	return out
+}
+
+// markActive sets Active on the items that point at the current page — by
+// path, or by the CMS object an imported item names — and Ancestor on every
+// item with an active descendant. It reports whether the level holds either.
+func (g *Generator) markActive(items []models.MenuItem, current string, pageID int) bool {
+	marked := false
+	for i := range items {
+		item := &items[i]
+		if current != "" && g.menuPath(item.URL) == current ||
+			pageID != 0 && item.Type == "post_type" && item.ObjectID == pageID {
+			item.Active = true
+		}
+		if g.markActive(item.Children, current, pageID) {
+			item.Ancestor = true
+		}
+		marked = marked || item.Active || item.Ancestor
+	}
+	return marked
+}
+
+// menuPath reduces a menu URL or an output path to the form they are compared
+// in: the site-relative path with index.html, .html and the trailing slash
+// dropped. A link to another host, a bare fragment or an unparsable URL is
+// "", and never active.
+func (g *Generator) menuPath(raw string) string {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Path == "" && u.Host == "" {
+		return ""
+	}
+	if u.Host != "" && !strings.EqualFold(strings.TrimPrefix(u.Host, "www."), strings.TrimPrefix(g.config.Domain, "www.")) {
+		return ""
+	}
+	p := u.Path
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	p = strings.TrimSuffix(p, "index.html")
+	p = strings.TrimSuffix(p, ".html")
+	if p = strings.TrimSuffix(p, "/"); p == "" {
+		return "/"
+	}
+	return p
+}
+
+// withMenus hands a page's context its .Menus. The context is copied: a
+// caller may render the same map again (a template fallback) and each output
+// needs its own flags.
+func (g *Generator) withMenus(data interface{}, outputPath string, page *models.Page) interface{} {
+	m, ok := data.(map[string]interface{})
+	if !ok {
+		return data
+	}
+	if _, set := m["Menus"]; set {
+		return data
+	}
+	out := make(map[string]interface{}, len(m)+1)
+	for k, v := range m {
+		out[k] = v
+	}
+	out["Menus"] = g.menusFor(outputPath, page)
+	return out
+}
@@ -0,0 +1,151 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// menuEngineTemplates render .Menus.primary the same way in every engine:
+// [Title*^(Child*)] — * for Active, ^ for Ancestor.
+var menuEngineTemplates = map[string]string{
+	"go": `{{define "%s"}}<html><body>{{range .Menus.primary}}[{{.Title}}{{if .Active}}*{{end}}{{if .Ancestor}}^{{end}}` +
+		`{{range .Children}}({{.Title}}{{if .Active}}*{{end}}){{end}}]{{end}}</body></html>{{end}}`,
+	"pongo2": `<html><body>{% for i in Menus.primary %}[{{ i.Title }}{% if i.Active %}*{% endif %}{% if i.Ancestor %}^{% endif %}` +
+		`{% for c in i.Children %}({{ c.Title }}{% if c.Active %}*{% endif %}){% endfor %}]{% endfor %}</body></html>`,
+	"mustache": `<html><body>{{#Menus.primary}}[{{Title}}{{#Active}}*{{/Active}}{{#Ancestor}}^{{/Ancestor}}` +
+		`{{#Children}}({{Title}}{{#Active}}*{{/Active}}){{/Children}}]{{/Menus.primary}}</body></html>`,
+	"handlebars": `<html><body>{{#each Menus.primary}}[{{Title}}{{#if Active}}*{{/if}}{{#if Ancestor}}^{{/if}}` +
+		`{{#each Children}}({{Title}}{{#if Active}}*{{/if}}){{/each}}]{{/each}}</body></html>`,
+}
+
+// TestMenusMergeFrontmatterConfigAndImported: a page added after the
+// migration nests under an imported item, a config link joins the same menu,
+// and the rendered page sees itself Active and its parent Ancestor — in each
+// of the four engines.
+func TestMenusMergeFrontmatterConfigAndImported(t *testing.T) {
+	for engine, body := range menuEngineTemplates {
+		t.Run(engine, func(t *testing.T) {
+			tmp := t.TempDir()
+			site := filepath.Join(tmp, "content", "site")
+			mustWrite(t, filepath.Join(site, "metadata.json"), `{"categories":[],"exported_at":"","media":[],"menus":[`+
+				`{"id":3,"name":"Main","slug":"main-menu","locations":["primary"],"items":[`+
+				`{"id":10,"title":"Docs","url":"/docs/","parent":0,"order":1,"type":"custom"},`+
+				`{"id":11,"title":"Home","url":"/","parent":0,"order":0,"type":"custom"}]}]}`)
+			mustWrite(t, filepath.Join(site, "pages", "install.md"),
+				"---\ntitle: Installing ssg\nslug: install\nstatus: publish\ntype: page\n"+
+					"menu:\n  primary: {weight: 2, parent: Docs, title: Install}\n---\n\nBody.\n")
+			mustWrite(t, filepath.Join(site, "pages", "about.md"),
+				"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\nmenu:\n  primary: {weight: 5}\n---\n\nBody.\n")
+			tmplDir := filepath.Join(tmp, "templates", "simple")
+			for _, name := range []string{"base.html", "index.html", "post.html", "page.html", "category.html"} {
+				if engine == "go" {
+					mustWrite(t, filepath.Join(tmplDir, name), strings.Replace(body, "%s", name, 1))
+				} else if name != "base.html" {
+					mustWrite(t, filepath.Join(tmplDir, name), body)
+				}
+			}
+			cfg := Config{Source: "site", Template: "simple", Domain: "example.com",
+				ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+				OutputDir: filepath.Join(tmp, "output"), Quiet: true,
+				Menus: map[string][]models.MenuEntry{"primary": {{Title: "GitHub", URL: "https://github.com/spagu/ssg", Weight: 9}}}}
+			if engine != "go" {
+				cfg.Engine = engine
+			}
+			gen, err := New(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := gen.Generate(); err != nil {
+				t.Fatalf("Generate: %v", err)
+			}
+			out := filepath.Join(tmp, "output")
+			wantContains(t, "install page", mustRead(t, filepath.Join(out, "install", "index.html")),
+				"[Home][Docs^(Install*)][About][GitHub]")
+			wantContains(t, "about page", mustRead(t, filepath.Join(out, "about", "index.html")),
+				"[Home][Docs(Install)][About*][GitHub]")
+			wantContains(t, "home page", mustRead(t, filepath.Join(out, "index.html")),
+				"[Home*][Docs(Install)]")
+			// The merged menu is the one .Site.Menus serves, under every key.
+			for _, key := range []string{"primary", "main-menu"} {
+				if n := len(gen.siteData.Menus[key].Items); n != 5 {
+					t.Errorf(".Site.Menus.%s has %d items, want 5", key, n)
+				}
+			}
+		})
+	}
+}
+
+// TestMenusRejectDuplicateIdentifiers: two entries claiming one identifier
+// would make every child's parent a guess.
+func TestMenusRejectDuplicateIdentifiers(t *testing.T) {
+	g := newTestGen(t, "")
+	g.config.Menus = map[string][]models.MenuEntry{"main": {
+		{Identifier: "docs", Title: "Docs"},
+		{Identifier: "Docs", Title: "More docs"},
+	}}
+	if err := g.buildMenus(); err == nil || !strings.Contains(err.Error(), `identifier "Docs"`) {
+		t.Fatalf("duplicate identifier: %v", err)
+	}
+}
+
+func TestMenuPath(t *testing.T) {
+	g := newTestGen(t, "")
+	g.config.Domain = "example.com"
+	for in, want := range map[string]string{
+		"/docs/":                        "/docs",
+		"/docs/index.html":              "/docs",
+		"/about.html":                   "/about",
+		"https://www.example.com/docs/": "/docs",
+		"/":                             "/",
+		"/index.html":                   "/",
+		"https://github.com/spagu/ssg":  "",
+		"#top":                          "",
+		"docs/install/":                 "/docs/install",
+	} {
+		if got := g.menuPath(in); got != want {
+			t.Errorf("menuPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// TestPongo2StarterIndexLinksPosts: the front page context is lifted so the
+// bundled pongo2 index reaches Menus — and Posts with it. Page has no URL
+// field, so the starter must ask each post for its link, not render href="".
+func TestPongo2StarterIndexLinksPosts(t *testing.T) {
+	index, err := os.ReadFile(filepath.Join("..", "..", "templates", "pongo2", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmp := t.TempDir()
+	site := filepath.Join(tmp, "content", "site")
+	mustWrite(t, filepath.Join(site, "metadata.json"),
+		`{"categories":[{"id":1,"name":"News","slug":"news"}],"exported_at":"","media":[]}`)
+	mustWrite(t, filepath.Join(site, "posts", "news", "hello.md"),
+		"---\ntitle: Hello\nslug: hello\nstatus: publish\ntype: post\ndate: 2024-01-02\ncategories: [News]\n---\n\nBody.\n")
+	tmplDir := filepath.Join(tmp, "templates", "pongo2")
+	mustWrite(t, filepath.Join(tmplDir, "index.html"), string(index))
+	for _, name := range []string{"post.html", "page.html", "category.html"} {
+		mustWrite(t, filepath.Join(tmplDir, name), "<html>{{ Title }}</html>")
+	}
+	gen, err := New(Config{Source: "site", Template: "pongo2", Engine: "pongo2", Domain: "example.com",
+		ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+		OutputDir: filepath.Join(tmp, "output"), Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	home := mustRead(t, filepath.Join(tmp, "output", "index.html"))
+	if strings.Contains(home, `href=""`) {
+		t.Errorf("index has an empty link:\n%s", home)
+	}
+	wantContains(t, "index", home, `>Hello</a>`)
+	if url := gen.siteData.Posts[0].GetURL(); !strings.Contains(home, `href="`+url+`"`) {
+		t.Errorf("index does not link the post at %s:\n%s", url, home)
+	}
+}
@@ -11,13 +11,14 @@ import (
 // sendNotifications announces new or changed posts to the configured webhook
 // destinations once a build has succeeded. A no-op unless --notify is set with
 // destinations; the notifier's committed state dedupes, so a post is announced
-// once — again only when its content changes (#1.8.16).
+// once — again only when its content changes (#1.8.16). Preview-mode drafts
+// are never announced: that would also spend their one announcement.
 func (g *Generator) sendNotifications() error {
 	if g.config.Notify == nil || !g.config.Notify.Enabled() {
 		return nil
 	}
 	posts := make([]notify.Post, 0, len(g.siteData.Posts))
-	for _, p := range g.siteData.Posts {
+	for _, p := range published(g.siteData.Posts) {
 		excerpt := p.Excerpt
 		if excerpt == "" {
 			excerpt = p.Description
@@ -0,0 +1,337 @@
+package generator
+
+// Page generators (page_generators): every row of a data file or an external
+// source becomes a page with its own URL, sitemap entry and search record —
+// a product per CSV line, an entry per API item. Without them a row could
+// only be listed from inside a template, through .Data or .ExternalData.
+//
+// Each row is turned into frontmatter (the generator's constant frontmatter,
+// then its field mapping) and goes through the same decoding a content file
+// does, so `tags:` or `date:` mean the same as in Markdown. Generated pages
+// join the site beside content_sources, before finalize.
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+)
+
+// pageGeneratorRoots are the namespaces a generator's source can start from.
+var pageGeneratorRoots = map[string]bool{"data": true, "external": true}
+
+// validatePageGenerators checks what can be checked before anything loads:
+// the source namespace, the type and the templates.
+func validatePageGenerators(gens []models.PageGenerator) error {
+	for _, pg := range gens {
+		root, _, _ := strings.Cut(pg.Source, ".")
+		if !pageGeneratorRoots[root] || !strings.Contains(pg.Source, ".") {
+			return fmt.Errorf("page_generators: source %q must start with data. or external.", pg.Source)
+		}
+		if !contentSourceTypes[pg.Type] {
+			return fmt.Errorf("page_generators: %s has unsupported type %q (supported: page, post)", pg.Source, pg.Type)
+		}
+		if pg.Slug == "" && pg.Fields["slug"] == "" && pg.Fields["title"] == "" && pg.Frontmatter["slug"] == nil {
+			return fmt.Errorf("page_generators: %s needs a slug, or a title to derive one from", pg.Source)
+		}
+		for _, text := range pageGeneratorTemplates(pg) {
+			if _, err := rowTemplate(text); err != nil {
+				return fmt.Errorf("page_generators: %s: %w", pg.Source, err)
+			}
+		}
+	}
+	return nil
+}
+
+// pageGeneratorTemplates lists a generator's template texts.
+func pageGeneratorTemplates(pg models.PageGenerator) []string {
+	texts := []string{pg.Slug, pg.Permalink}
+	for _, v := range pg.Fields {
+		if strings.Contains(v, "{{") {
+			texts = append(texts, v)
+		}
+	}
+	return texts
+}
+
+// rowTemplate parses a template over one row. A field the row lacks is an
+// error rather than "<no value>" in a URL.
+func rowTemplate(text string) (*template.Template, error) {
+	return template.New("row").Option("missingkey=error").Funcs(template.FuncMap{
+		"slugify": slugify,
+		"lower":   strings.ToLower,
+		"upper":   strings.ToUpper,
+		"trim":    strings.TrimSpace,
+		"default": tmplDefault,
+	}).Parse(text)
+}
+
+// generatePages appends the pages of every page generator to the site.
+// Needs .Data and .ExternalData loaded, and runs before finalize.
+func (g *Generator) generatePages() error {
+	if len(g.config.PageGenerators) == 0 {
+		return nil
+	}
+	urls := map[string]string{} // URL → the row that claimed it
+	for _, pg := range g.config.PageGenerators {
+		pages, err := g.pagesFromGenerator(pg)
+		if err != nil {
+			return fmt.Errorf("page_generators: %s: %w", pg.Source, err)
+		}
+		for i, p := range pages {
+			u := p.GetURL()
+			row := fmt.Sprintf("%s row %d", pg.Source, i+1)
+			if prev, dup := urls[u]; dup {
+				return fmt.Errorf("page_generators: %s and %s both generate %s", prev, row, u)
+			}
+			urls[u] = row
+		}
+		if pg.Type == "post" {
+			g.siteData.Posts = append(g.siteData.Posts, pages...)
+		} else {
+			g.siteData.Pages = append(g.siteData.Pages, pages...)
+		}
+		g.log(fmt.Sprintf("   🏭 %s → %d %ss", pg.Source, len(pages), sourceType(pg.Type)))
+	}
+	g.siteData.Posts = sortPostsByDate(g.siteData.Posts)
+	g.siteData.ResolveFlexibleFields()
+	return nil
+}
+
+// pagesFromGenerator builds one generator's pages, a row at a time.
+func (g *Generator) pagesFromGenerator(pg models.PageGenerator) ([]models.Page, error) {
+	root, path, _ := strings.Cut(pg.Source, ".")
+	var data interface{} = g.data
+	if root == "external" {
+		data = g.externalData
+	}
+	value, ok := lookupRowPath(data, path)
+	if !ok {
+		return nil, fmt.Errorf("no such data")
+	}
+	rows, err := generatorRows(value)
+	if err != nil {
+		return nil, err
+	}
+	pages := make([]models.Page, 0, len(rows))
+	for i, row := range rows {
+		page, err := g.pageFromRow(pg, row)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		if !g.keepsStatus(page.Status) {
+			continue
+		}
+		pages = append(pages, *page)
+	}
+	return pages, nil
+}
+
+// pageFromRow maps one row onto frontmatter and decodes it.
+func (g *Generator) pageFromRow(pg models.PageGenerator, row map[string]interface{}) (*models.Page, error) {
+	fields := map[string]interface{}{"status": "publish", "type": sourceType(pg.Type)}
+	for k, v := range pg.Frontmatter {
+		fields[k] = v
+	}
+	if pg.Layout != "" {
+		fields["layout"] = pg.Layout
+	}
+	for key, spec := range pg.Fields {
+		var v interface{}
+		if strings.Contains(spec, "{{") {
+			s, err := execRowTemplate(spec, row)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v = s
+		} else if found, ok := lookupRowPath(row, spec); ok {
+			v = found
+		} else {
+			continue
+		}
+		fields[key] = frontmatterValue(key, v)
+	}
+	body, _ := fields["content"].(string)
+	delete(fields, "content")
+	// The whole row stays reachable, as .row in the template (.Page.Extra.row).
+	if _, set := fields["row"]; !set {
+		fields["row"] = row
+	}
+
+	page, err := parser.PageFromFields(fields, body)
+	if err != nil {
+		return nil, err
+	}
+	page.IsDraft = page.Status != "publish"
+	if pg.Slug != "" {
+		s, err := execRowTemplate(pg.Slug, row)
+		if err != nil {
+			return nil, fmt.Errorf("slug: %w", err)
+		}
+		page.Slug = s
+	}
+	if page.Slug == "" {
+		page.Slug = page.Title
+	}
+	// Row values are free text: "Blue Widget" has to become a path segment.
+	if !g.config.PreserveSlugCase {
+		page.Slug = slugify(page.Slug)
+	}
+	if page.Slug == "" {
+		return nil, fmt.Errorf("empty slug")
+	}
+	if page.Date.IsZero() {
+		page.Date = g.buildTime // reproducible under SOURCE_DATE_EPOCH
+	}
+	if page.Modified.IsZero() {
+		page.Modified = page.Date
+	}
+	if g.config.AutoExcerpt && page.Excerpt == "" {
+		page.Excerpt = parser.DeriveExcerpt(page.Content)
+	}
+	page.PageFormat = g.config.PageFormat
+	if pg.Type == "post" {
+		page.URLFormat = g.config.PostURLFormat
+	}
+	if pg.Permalink != "" {
+		pattern, err := execRowTemplate(pg.Permalink, row)
+		if err != nil {
+			return nil, fmt.Errorf("permalink: %w", err)
+		}
+		page.PermalinkPath = g.expandPermalink(pattern, *page)
+	}
+	return page, nil
+}
+
+// execRowTemplate renders a row template to a trimmed string.
+func execRowTemplate(text string, row map[string]interface{}) (string, error) {
+	t, err := rowTemplate(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, row); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// lookupRowPath follows a dotted path through nested maps.
+func lookupRowPath(v interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return v, true
+	}
+	for _, key := range strings.Split(path, ".") {
+		switch m := v.(type) {
+		case map[string]interface{}:
+			next, ok := m[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		case map[string]string:
+			next, ok := m[key]
+			if !ok {
+				return nil, false
+			}
+			v = next
+		default:
+			return nil, false
+		}
+	}
+	return v, true
+}
+
+// generatorRows lists the rows of a source value: the items of a list (a CSV
+// with a header, a JSON array, an SQL result), or the entries of a map, in key
+// order, each with its key as _key.
+func generatorRows(v interface{}) ([]map[string]interface{}, error) {
+	var rows []map[string]interface{}
+	add := func(item interface{}) error {
+		switch r := item.(type) {
+		case map[string]interface{}:
+			rows = append(rows, r)
+		case map[string]string:
+			row := make(map[string]interface{}, len(r))
+			for k, s := range r {
+				row[k] = s
+			}
+			rows = append(rows, row)
+		default:
+			return fmt.Errorf("row %d is a %T, not a record", len(rows)+1, item)
+		}
+		return nil
+	}
+	switch t := v.(type) {
+	case []interface{}:
+		for _, item := range t {
+			if err := add(item); err != nil {
+				return nil, err
+			}
+		}
+	case []map[string]string:
+		for _, item := range t {
+			_ = add(item)
+		}
+	case []map[string]interface{}:
+		rows = append(rows, t...)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if err := add(t[k]); err != nil {
+				return nil, err
+			}
+			rows[len(rows)-1] = withKey(rows[len(rows)-1], k)
+		}
+	default:
+		return nil, fmt.Errorf("a %T is not a list of records", v)
+	}
+	return rows, nil
+}
+
+// withKey copies a keyed map entry's row, adding its key.
+func withKey(row map[string]interface{}, key string) map[string]interface{} {
+	out := make(map[string]interface{}, len(row)+1)
+	for k, v := range row {
+		out[k] = v
+	}
+	out["_key"] = key
+	return out
+}
+
+// frontmatterValue shapes a cell for its frontmatter key. CSV cells are all
+// strings, so a list key splits on commas and a number or flag is parsed.
+func frontmatterValue(key string, v interface{}) interface{} {
+	s, ok := v.(string)
+	if !ok {
+		return v
+	}
+	switch key {
+	case "tags", "categories", "aliases":
+		var list []interface{}
+		for _, part := range strings.Split(s, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				list = append(list, part)
+			}
+		}
+		return list
+	case "id":
+		if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+			return n
+		}
+	case "sticky", "alias_stubs":
+		if b, err := strconv.ParseBool(strings.TrimSpace(s)); err == nil {
+			return b
+		}
+	}
+	return v
+}
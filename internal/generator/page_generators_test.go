@@ -0,0 +1,117 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+func TestPageGeneratorsBuild(t *testing.T) {
+	root := t.TempDir()
+	writeIncrementalSite(t, root)
+	mustWrite(t, filepath.Join(root, "data", "shop", "products.yaml"), `
+- sku: W-1
+  name: Blue Widget
+  summary: A widget, in blue.
+  tags: "widgets, blue"
+  body: "The **best** widget."
+  price: 9
+- sku: W-2
+  name: Red Widget
+  summary: A widget, in red.
+  tags: "widgets"
+  status: draft
+`)
+	mustWrite(t, filepath.Join(root, "templates", "simple", "product.html"),
+		`<html><h1>{{.Title}}</h1><p class="d">{{.Description}}</p>{{.Content}}<b>{{.row.price}}</b></html>`)
+
+	gen := buildScheduled(t, root, func(c *Config) {
+		c.SearchIndex = true
+		c.PageGenerators = []models.PageGenerator{{
+			Source:    "data.shop.products",
+			Layout:    "product",
+			Slug:      "{{ .sku }}",
+			Permalink: "/shop/:slug/",
+			Fields: map[string]string{
+				"title": "name", "description": "summary", "tags": "tags",
+				"content": "body", "status": "status",
+			},
+		}}
+	})
+	out := filepath.Join(root, "output")
+
+	page, err := os.ReadFile(filepath.Join(out, "shop", "w-1", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"<h1>Blue Widget</h1>", `<p class="d">A widget, in blue.</p>`, "<strong>best</strong>", "<b>9</b>"} {
+		if !strings.Contains(string(page), want) {
+			t.Errorf("generated page lacks %s:\n%s", want, page)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "shop", "w-2")); err == nil {
+		t.Error("a draft row must not be built")
+	}
+	for _, file := range []string{"sitemap.xml", "search-index.json"} {
+		if raw, _ := os.ReadFile(filepath.Join(out, file)); !strings.Contains(string(raw), "/shop/w-1/") {
+			t.Errorf("%s lacks the generated page", file)
+		}
+	}
+	var tagged bool
+	for _, p := range gen.siteData.Pages {
+		if p.Slug == "w-1" && strings.Join(p.Tags, ",") == "widgets,blue" {
+			tagged = true
+		}
+	}
+	if !tagged {
+		t.Error("a comma-separated tags cell should become two tags")
+	}
+}
+
+func TestPageGeneratorErrors(t *testing.T) {
+	for _, tc := range []struct {
+		pg  models.PageGenerator
+		err string
+	}{
+		{models.PageGenerator{Source: "products", Slug: "{{ .sku }}"}, "must start with"},
+		{models.PageGenerator{Source: "data.products", Type: "note", Slug: "x"}, "unsupported type"},
+		{models.PageGenerator{Source: "data.products"}, "needs a slug"},
+		{models.PageGenerator{Source: "data.products", Slug: "{{ .sku"}, "unclosed"},
+	} {
+		err := validatePageGenerators([]models.PageGenerator{tc.pg})
+		if err == nil || !strings.Contains(err.Error(), tc.err) {
+			t.Errorf("validatePageGenerators(%+v) = %v, want %q", tc.pg, err, tc.err)
+		}
+	}
+
+	g := &Generator{siteData: &models.SiteData{}, data: map[string]interface{}{
+		"rows": []interface{}{map[string]interface{}{"sku": "a"}, map[string]interface{}{"sku": "A"}},
+	}}
+	g.config.PageGenerators = []models.PageGenerator{{Source: "data.rows", Slug: "{{ .sku }}"}}
+	if err := g.generatePages(); err == nil || !strings.Contains(err.Error(), "both generate /a/") {
+		t.Errorf("duplicate URLs: err = %v", err)
+	}
+	g.config.PageGenerators = []models.PageGenerator{{Source: "data.rows", Slug: "{{ .name }}"}}
+	if err := g.generatePages(); err == nil || !strings.Contains(err.Error(), "row 1: slug") {
+		t.Errorf("missing field: err = %v", err)
+	}
+}
+
+func TestGeneratorRows(t *testing.T) {
+	csv := []map[string]string{{"sku": "a"}, {"sku": "b"}}
+	rows, err := generatorRows(csv)
+	if err != nil || len(rows) != 2 || rows[1]["sku"] != "b" {
+		t.Errorf("CSV rows = %v, %v", rows, err)
+	}
+	keyed := map[string]interface{}{"z": map[string]interface{}{"n": 1}, "a": map[string]interface{}{"n": 2}}
+	rows, err = generatorRows(keyed)
+	if err != nil || len(rows) != 2 || rows[0]["_key"] != "a" || rows[1]["n"] != 1 {
+		t.Errorf("keyed rows = %v, %v", rows, err)
+	}
+	if _, err := generatorRows([]interface{}{"plain"}); err == nil {
+		t.Error("a list of strings is not a list of records")
+	}
+}
@@ -0,0 +1,345 @@
+package generator
+
+// Build profiling (--profile=<file>).
+//
+// A slow build used to be a single number. The profile splits it: one span
+// per build phase (every runStep plus the passes Generate calls directly), one
+// per template execution with the page it rendered and the bytes it wrote,
+// and one per image-processor request with whether the cache answered it.
+//
+// Two files come out. <file> is Chrome trace-event JSON — open it in
+// chrome://tracing or ui.perfetto.dev. Next to it, <file> with a .txt
+// extension is a plain-text summary: phases in build order, then templates and
+// pages slowest first, then the image and cache counts. CI archives both; the
+// summary is the one worth diffing between releases.
+//
+// Pages render in parallel, so template and image spans are laid out on
+// "lanes" (trace thread ids) when the trace is written: each takes the lowest
+// lane whose previous span has ended, so no two spans on a lane overlap, which
+// the trace viewer requires. Phases run one at a time on lane 0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spagu/ssg/internal/images"
+	"github.com/spagu/ssg/internal/models"
+)
+
+// Span categories, as they appear in the trace's "cat" field.
+const (
+	spanPhase    = "phase"
+	spanTemplate = "template"
+	spanImage    = "image"
+)
+
+// profileTopPages bounds the summary's slowest-pages table; the trace keeps
+// every page.
+const profileTopPages = 25
+
+type profileSpan struct {
+	name  string
+	cat   string
+	page  string // output path (templates) or image source
+	src   string // a template span's source file, when it has one
+	start time.Time
+	dur   time.Duration
+	bytes int64
+	// cached marks an image request served from the cache.
+	cached bool
+}
+
+// profiler collects spans for one build. Safe for concurrent use.
+type profiler struct {
+	origin time.Time
+
+	mu    sync.Mutex
+	spans []profileSpan
+	// caches counts hits and misses per cache, for the summary.
+	caches map[string]*[2]int
+}
+
+func newProfiler() *profiler {
+	return &profiler{origin: time.Now(), caches: map[string]*[2]int{}}
+}
+
+func (p *profiler) add(s profileSpan) {
+	p.mu.Lock()
+	p.spans = append(p.spans, s)
+	p.mu.Unlock()
+}
+
+// cache counts one lookup against the named cache.
+func (p *profiler) cache(name string, hit bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	c := p.caches[name]
+	if c == nil {
+		c = &[2]int{}
+		p.caches[name] = c
+	}
+	if hit {
+		c[0]++
+	} else {
+		c[1]++
+	}
+}
+
+// observeImage is the images.Config.Observe hook.
+func (p *profiler) observeImage(op images.Op) {
+	p.add(profileSpan{name: op.Helper, cat: spanImage, page: op.Source, start: op.Start, dur: op.Duration, bytes: op.Bytes, cached: op.Cached})
+	p.cache("images", op.Cached)
+}
+
+// stepName is the name a phase is profiled under: its function's, so the
+// profile says generateSite and checkLinksIfRequested rather than the log line.
+func stepName(fn func() error) string {
+	name := runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name()
+	name = strings.TrimSuffix(name, "-fm")
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// timed runs one build phase, recording a span for it when profiling.
+func (g *Generator) timed(fn func() error) error {
+	if g.prof == nil {
+		return fn()
+	}
+	start := time.Now()
+	err := fn()
+	g.prof.add(profileSpan{name: stepName(fn), cat: spanPhase, start: start, dur: time.Since(start)})
+	return err
+}
+
+// profileTemplate starts a template-execution span; the returned func ends it
+// with the number of bytes written. A no-op when not profiling.
+func (g *Generator) profileTemplate(templateName, outputPath string, page *models.Page) func(bytes int64) {
+	if g.prof == nil {
+		return func(int64) {}
+	}
+	var src string
+	if page != nil {
+		src = page.SourceFile
+	}
+	start := time.Now()
+	return func(bytes int64) {
+		g.prof.add(profileSpan{name: templateName, cat: spanTemplate, page: g.relOutput(outputPath), src: src, start: start, dur: time.Since(start), bytes: bytes})
+	}
+}
+
+// relOutput is outputPath relative to the output directory, slash-separated.
+func (g *Generator) relOutput(outputPath string) string {
+	if rel, err := filepath.Rel(g.config.OutputDir, outputPath); err == nil {
+		return filepath.ToSlash(rel)
+	}
+	return filepath.ToSlash(outputPath)
+}
+
+// writeProfile writes the trace and its summary. Called once, when the build
+// ends — a failed build's profile is written too, up to the failing phase.
+func (g *Generator) writeProfile() error {
+	total := time.Since(g.prof.origin)
+	if dir := filepath.Dir(g.config.Profile); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil { // #nosec G301 -- build artifacts are world-readable
+			return fmt.Errorf("writing profile: %w", err)
+		}
+	}
+	trace, err := g.prof.traceJSON()
+	if err != nil {
+		return fmt.Errorf("writing profile: %w", err)
+	}
+	// #nosec G306 -- build artifact, not a secret
+	if err := os.WriteFile(g.config.Profile, trace, 0o644); err != nil {
+		return fmt.Errorf("writing profile: %w", err)
+	}
+	summaryPath := profileSummaryPath(g.config.Profile)
+	// #nosec G306 -- build artifact, not a secret
+	if err := os.WriteFile(summaryPath, []byte(g.prof.summary(total)), 0o644); err != nil {
+		return fmt.Errorf("writing profile summary: %w", err)
+	}
+	g.log(fmt.Sprintf("⏱️  Profile: %s (summary %s)", g.config.Profile, summaryPath))
+	return nil
+}
+
+// profileSummaryPath swaps the trace file's extension for .txt.
+func profileSummaryPath(trace string) string {
+	summary := strings.TrimSuffix(trace, filepath.Ext(trace)) + ".txt"
+	if summary == trace {
+		summary += ".txt"
+	}
+	return summary
+}
+
+// traceEvent is one complete ("X") event of the Chrome trace-event format;
+// timestamps and durations are microseconds.
+type traceEvent struct {
+	Name string         `json:"name"`
+	Cat  string         `json:"cat"`
+	Ph   string         `json:"ph"`
+	Ts   int64          `json:"ts"`
+	Dur  int64          `json:"dur"`
+	Pid  int            `json:"pid"`
+	Tid  int            `json:"tid"`
+	Args map[string]any `json:"args,omitempty"`
+}
+
+func (p *profiler) traceJSON() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	spans := append([]profileSpan(nil), p.spans...)
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].start.Before(spans[j].start) })
+	events := make([]traceEvent, 0, len(spans)+1)
+	// Name lane 0, so the viewer shows "build" rather than a bare thread id.
+	events = append(events, traceEvent{Name: "thread_name", Ph: "M", Args: map[string]any{"name": "build"}})
+	var laneEnds []time.Time // per lane from 1: when its last span ended
+	for _, s := range spans {
+		lane := 0
+		if s.cat != spanPhase {
+			for lane = 1; lane <= len(laneEnds) && laneEnds[lane-1].After(s.start); lane++ {
+			}
+			if lane > len(laneEnds) {
+				laneEnds = append(laneEnds, time.Time{})
+			}
+			laneEnds[lane-1] = s.start.Add(s.dur)
+		}
+		ev := traceEvent{
+			Name: s.name,
+			Cat:  s.cat,
+			Ph:   "X",
+			Ts:   s.start.Sub(p.origin).Microseconds(),
+			Dur:  s.dur.Microseconds(),
+			Tid:  lane,
+		}
+		switch s.cat {
+		case spanTemplate:
+			ev.Args = map[string]any{"page": s.page, "bytes": s.bytes}
+			if s.src != "" {
+				ev.Args["source"] = s.src
+			}
+		case spanImage:
+			ev.Args = map[string]any{"source": s.page, "bytes": s.bytes, "cached": s.cached}
+		}
+		events = append(events, ev)
+	}
+	return json.MarshalIndent(map[string]any{"traceEvents": events, "displayTimeUnit": "ms"}, "", " ")
+}
+
+// summary renders the text report. Durations are rounded to 0.1ms so small
+// jitter does not turn every line of a diff.
+func (p *profiler) summary(total time.Duration) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var b strings.Builder
+	fmt.Fprintf(&b, "ssg build profile — total %s\n", fmtDur(total))
+
+	type tmplStat struct {
+		name       string
+		calls      int
+		total, max time.Duration
+		bytes      int64
+	}
+	var phases, pages []profileSpan
+	tmpls := map[string]*tmplStat{}
+	var imgOps, imgHits int
+	var imgTime time.Duration
+	for _, s := range p.spans {
+		switch s.cat {
+		case spanPhase:
+			phases = append(phases, s)
+		case spanTemplate:
+			pages = append(pages, s)
+			st := tmpls[s.name]
+			if st == nil {
+				st = &tmplStat{name: s.name}
+				tmpls[s.name] = st
+			}
+			st.calls++
+			st.total += s.dur
+			st.bytes += s.bytes
+			if s.dur > st.max {
+				st.max = s.dur
+			}
+		case spanImage:
+			imgOps++
+			imgTime += s.dur
+			if s.cached {
+				imgHits++
+			}
+		}
+	}
+
+	b.WriteString("\nPhases (build order)\n")
+	sort.SliceStable(phases, func(i, j int) bool { return phases[i].start.Before(phases[j].start) })
+	tw := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	for _, s := range phases {
+		fmt.Fprintf(tw, "  %s\t%s\n", s.name, fmtDur(s.dur))
+	}
+	_ = tw.Flush()
+
+	b.WriteString("\nTemplates (slowest total first)\n")
+	stats := make([]*tmplStat, 0, len(tmpls))
+	for _, st := range tmpls {
+		stats = append(stats, st)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].total != stats[j].total {
+			return stats[i].total > stats[j].total
+		}
+		return stats[i].name < stats[j].name
+	})
+	tw = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  template\tcalls\ttotal\tmax\tbytes\n")
+	for _, st := range stats {
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\t%d\n", st.name, st.calls, fmtDur(st.total), fmtDur(st.max), st.bytes)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(&b, "\nPages (slowest %d)\n", profileTopPages)
+	sort.Slice(pages, func(i, j int) bool {
+		if pages[i].dur != pages[j].dur {
+			return pages[i].dur > pages[j].dur
+		}
+		return pages[i].page < pages[j].page
+	})
+	if len(pages) > profileTopPages {
+		pages = pages[:profileTopPages]
+	}
+	tw = tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "  page\ttemplate\ttime\tbytes\n")
+	for _, s := range pages {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%d\n", s.page, s.name, fmtDur(s.dur), s.bytes)
+	}
+	_ = tw.Flush()
+
+	fmt.Fprintf(&b, "\nImages\n  %d operations, %s (%d from cache, %d processed)\n", imgOps, fmtDur(imgTime), imgHits, imgOps-imgHits)
+
+	b.WriteString("\nCaches\n")
+	names := make([]string, 0, len(p.caches))
+	for name := range p.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) == 0 {
+		b.WriteString("  (none used)\n")
+	}
+	for _, name := range names {
+		c := p.caches[name]
+		fmt.Fprintf(&b, "  %s: %d hits, %d misses\n", name, c[0], c[1])
+	}
+	return b.String()
+}
+
+func fmtDur(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d.Round(100*time.Microsecond))/float64(time.Millisecond))
+}
@@ -0,0 +1,137 @@
+package generator
+
+import (
+	"encoding/json"
+	"image"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// A profiled build writes a trace with a span per phase, per rendered page and
+// per image request, and a summary naming the same things.
+func TestProfileTraceAndSummary(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	mustWrite(t, filepath.Join(root, "templates", "simple", "page.html"),
+		`<html><h1>{{.Title}}</h1>{{with imageResize "logo.png" (dict "width" 4)}}<img src="{{.URL}}">{{end}}</html>`)
+	if err := os.MkdirAll(filepath.Join(root, "assets"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(root, "assets", "logo.png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := png.Encode(f, image.NewRGBA(image.Rect(0, 0, 8, 8))); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	profile := filepath.Join(root, "reports", "build.json")
+	gen, err := New(Config{
+		Source: "site", Template: "simple", Domain: "example.com",
+		ContentDir:   filepath.Join(root, "content"),
+		TemplatesDir: filepath.Join(root, "templates"),
+		DataDir:      filepath.Join(root, "data"),
+		OutputDir:    filepath.Join(root, "output"),
+		Profile:      profile, Quiet: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+
+	raw, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(raw, &trace); err != nil {
+		t.Fatalf("trace is not JSON: %v", err)
+	}
+	byCat := map[string]map[string]traceEvent{}
+	for _, ev := range trace.TraceEvents {
+		if byCat[ev.Cat] == nil {
+			byCat[ev.Cat] = map[string]traceEvent{}
+		}
+		key := ev.Name
+		if page, ok := ev.Args["page"].(string); ok {
+			key = page
+		}
+		byCat[ev.Cat][key] = ev
+	}
+	for _, phase := range []string{"loadContent", "loadTemplates", "generateSite", "generateSitemapAndRobots", "checkLinksIfRequested"} {
+		if ev, ok := byCat[spanPhase][phase]; !ok || ev.Ph != "X" || ev.Tid != 0 {
+			t.Errorf("phase %s: %+v (present %v)", phase, ev, ok)
+		}
+	}
+	about, ok := byCat[spanTemplate]["about/index.html"]
+	if !ok || about.Name != "page.html" || about.Args["source"] != "about.md" || about.Args["bytes"].(float64) <= 0 || about.Tid < 1 {
+		t.Errorf("about.md template span = %+v (present %v)", about, ok)
+	}
+	if _, ok := byCat[spanTemplate]["index.html"]; !ok {
+		t.Errorf("index.html not profiled; got %v", byCat[spanTemplate])
+	}
+	if img, ok := byCat[spanImage]["imageResize"]; !ok || img.Args["cached"] != false {
+		t.Errorf("image span = %+v (present %v)", img, ok)
+	}
+
+	summary, err := os.ReadFile(filepath.Join(root, "reports", "build.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Phases (build order)", "generateSite", "page.html", "about/index.html",
+		"1 operations", "images: 0 hits, 1 misses"} {
+		if !strings.Contains(string(summary), want) {
+			t.Errorf("summary lacks %q:\n%s", want, summary)
+		}
+	}
+}
+
+// Overlapping spans — parallel renders — never share a lane.
+func TestProfileLanesDoNotOverlap(t *testing.T) {
+	p := newProfiler()
+	at := func(ms int) time.Time { return p.origin.Add(time.Duration(ms) * time.Millisecond) }
+	p.add(profileSpan{name: "generateSite", cat: spanPhase, start: at(0), dur: 50 * time.Millisecond})
+	p.add(profileSpan{name: "a", cat: spanTemplate, start: at(1), dur: 10 * time.Millisecond})
+	p.add(profileSpan{name: "b", cat: spanTemplate, start: at(2), dur: 10 * time.Millisecond})
+	p.add(profileSpan{name: "c", cat: spanTemplate, start: at(12), dur: 5 * time.Millisecond})
+	raw, err := p.traceJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var trace struct {
+		TraceEvents []traceEvent `json:"traceEvents"`
+	}
+	if err := json.Unmarshal(raw, &trace); err != nil {
+		t.Fatal(err)
+	}
+	lanes := map[string]int{}
+	for _, ev := range trace.TraceEvents {
+		lanes[ev.Name] = ev.Tid
+	}
+	if lanes["generateSite"] != 0 || lanes["a"] != 1 || lanes["b"] != 2 || lanes["c"] != 1 {
+		t.Errorf("lanes = %v", lanes)
+	}
+}
+
+func TestProfileSummaryPath(t *testing.T) {
+	for in, want := range map[string]string{
+		"build.json":     "build.txt",
+		"out/trace":      "out/trace.txt",
+		"report.txt":     "report.txt.txt",
+		"a.b/trace.json": "a.b/trace.txt",
+	} {
+		if got := profileSummaryPath(in); got != want {
+			t.Errorf("profileSummaryPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -7,7 +7,6 @@ package generator
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -264,8 +263,7 @@ func (g *Generator) generateRedirectsFile() error {
 	}
 	content := renderRedirectsFile(rules)
 	redirectsPath := filepath.Join(g.config.OutputDir, "_redirects")
-	// #nosec G306 -- Web content files need to be world-readable
-	if err := os.WriteFile(redirectsPath, []byte(content), 0644); err != nil {
+	if err := g.writeOutput(redirectsPath, []byte(content)); err != nil {
 		return fmt.Errorf("writing _redirects: %w", err)
 	}
 	return nil
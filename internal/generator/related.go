@@ -93,7 +93,7 @@ func (g *Generator) tmplRelatedFromMddb(page models.Page, n int) []models.Page {
 		return nil
 	}
 	out := make([]models.Page, 0, n)
-	for _, p := range pages {
+	for _, p := range g.keptByStatus(pages) {
 		if p.Slug == page.Slug {
 			continue
 		}
@@ -0,0 +1,246 @@
+package generator
+
+// Render hooks for links, images, headings and fenced code: a theme that ships
+// _markup/render-link.html (render-image, render-heading, render-codeblock)
+// takes over that construct's markup, instead of post-processing the HTML with
+// regexes. Each hook receives a typed context below; constructs without a hook
+// keep goldmark's renderer, and so does a hooked one whose template fails.
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/url"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	gmparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// renderHookNames are the hooks this file serves; buildMarkdown adds the
+// extension only when the theme ships at least one of them.
+var renderHookNames = []string{"link", "image", "heading", "codeblock"}
+
+// linkContext is what _markup/render-link.html receives, for inline links and
+// autolinks alike.
+type linkContext struct {
+	Destination string
+	Title       string
+	Text        template.HTML // the rendered link text
+	PlainText   string
+	External    bool // an http(s) URL on another host than domain
+	Attributes  map[string]string
+}
+
+// imageContext is what _markup/render-image.html receives.
+type imageContext struct {
+	Destination string
+	Title       string
+	Text        string // the alt text
+	Attributes  map[string]string
+}
+
+// headingContext is what _markup/render-heading.html receives.
+type headingContext struct {
+	Level      int
+	ID         string // the anchor the TOC links to
+	Text       template.HTML
+	PlainText  string
+	Attributes map[string]string
+}
+
+// codeblockContext is what _markup/render-codeblock.html receives. Attributes
+// come from a `{key="value"}` block after the language on the fence line, so
+// ```go {title="main.go"} hands the hook .Attributes.title.
+type codeblockContext struct {
+	Language    string
+	Code        string        // the raw code
+	Highlighted template.HTML // the block as it renders without the hook
+	Attributes  map[string]string
+}
+
+type renderHookRenderer struct {
+	md     goldmark.Markdown // renders children, hooks included
+	base   goldmark.Markdown // the same Markdown without these hooks
+	hooks  *markupHooks
+	domain string
+}
+
+// RegisterFuncs implements renderer.NodeRenderer: only the constructs the
+// theme hooks are taken over.
+func (r renderHookRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	if r.hooks.lookup("link") != nil {
+		reg.Register(ast.KindLink, r.renderLink)
+		reg.Register(ast.KindAutoLink, r.renderLink)
+	}
+	if r.hooks.lookup("image") != nil {
+		reg.Register(ast.KindImage, r.renderImage)
+	}
+	if r.hooks.lookup("heading") != nil {
+		reg.Register(ast.KindHeading, r.renderHeading)
+	}
+	if r.hooks.lookup("codeblock") != nil {
+		reg.Register(ast.KindFencedCodeBlock, r.renderCodeblock)
+	}
+}
+
+func (r renderHookRenderer) renderLink(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	ctx := linkContext{Attributes: nodeAttributes(node)}
+	switch n := node.(type) {
+	case *ast.Link:
+		ctx.Destination = string(n.Destination)
+		ctx.Title = string(n.Title)
+		ctx.Text = r.children(source, n)
+		ctx.PlainText = nodeText(n, source)
+	case *ast.AutoLink:
+		ctx.Destination = string(n.URL(source))
+		if n.AutoLinkType == ast.AutoLinkEmail && !strings.HasPrefix(strings.ToLower(ctx.Destination), "mailto:") {
+			ctx.Destination = "mailto:" + ctx.Destination
+		}
+		ctx.PlainText = string(n.Label(source))
+		ctx.Text = template.HTML(template.HTMLEscapeString(ctx.PlainText)) // #nosec G203 -- escaped
+	}
+	ctx.External = externalURL(ctx.Destination, r.domain)
+	return r.write(w, source, node, "link", ctx)
+}
+
+func (r renderHookRenderer) renderImage(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Image)
+	return r.write(w, source, node, "image", imageContext{
+		Destination: string(n.Destination),
+		Title:       string(n.Title),
+		Text:        nodeText(n, source),
+		Attributes:  nodeAttributes(n),
+	})
+}
+
+func (r renderHookRenderer) renderHeading(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.Heading)
+	attrs := nodeAttributes(n)
+	return r.write(w, source, node, "heading", headingContext{
+		Level:      n.Level,
+		ID:         attrs["id"],
+		Text:       r.children(source, n),
+		PlainText:  nodeText(n, source),
+		Attributes: attrs,
+	})
+}
+
+func (r renderHookRenderer) renderCodeblock(w util.BufWriter, source []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.FencedCodeBlock)
+	var code strings.Builder
+	for i := 0; i < n.Lines().Len(); i++ {
+		line := n.Lines().At(i)
+		code.Write(line.Value(source))
+	}
+	var highlighted bytes.Buffer
+	if err := r.base.Renderer().Render(&highlighted, source, n); err != nil {
+		return ast.WalkStop, err
+	}
+	ctx := codeblockContext{
+		Code:        code.String(),
+		Highlighted: template.HTML(highlighted.String()), // #nosec G203 -- goldmark output
+		Attributes:  map[string]string{},
+	}
+	if n.Info != nil {
+		info := n.Info.Segment.Value(source)
+		ctx.Language = string(n.Language(source))
+		if i := bytes.IndexByte(info, '{'); i >= 0 {
+			if attrs, ok := gmparser.ParseAttributes(text.NewReader(info[i:])); ok {
+				for _, a := range attrs {
+					ctx.Attributes[string(a.Name)] = attributeString(a.Value)
+				}
+			}
+		}
+	}
+	if out, ok := r.hooks.render("codeblock", ctx); ok {
+		_, _ = w.WriteString(out)
+	} else {
+		_, _ = w.Write(highlighted.Bytes())
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// write executes hook name for node, or — when it fails — renders node with
+// the built-in renderer.
+func (r renderHookRenderer) write(w util.BufWriter, source []byte, node ast.Node, name string, data any) (ast.WalkStatus, error) {
+	if out, ok := r.hooks.render(name, data); ok {
+		_, _ = w.WriteString(out)
+		return ast.WalkSkipChildren, nil
+	}
+	return ast.WalkSkipChildren, r.base.Renderer().Render(w, source, node)
+}
+
+// children renders a node's inline content, hooks included: a link inside a
+// hooked heading still goes through render-link.html.
+func (r renderHookRenderer) children(source []byte, n ast.Node) template.HTML {
+	var buf bytes.Buffer
+	for c := n.FirstChild(); c != nil; c = c.NextSibling() {
+		if err := r.md.Renderer().Render(&buf, source, c); err != nil {
+			break
+		}
+	}
+	return template.HTML(buf.String()) // #nosec G203 -- goldmark output
+}
+
+// nodeAttributes returns a node's attributes (a heading's id, `{.class}`
+// blocks) as strings.
+func nodeAttributes(n ast.Node) map[string]string {
+	attrs := map[string]string{}
+	for _, a := range n.Attributes() {
+		attrs[string(a.Name)] = attributeString(a.Value)
+	}
+	return attrs
+}
+
+func attributeString(v any) string {
+	if b, ok := v.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(v)
+}
+
+// externalURL reports whether dest is an http(s) URL on a host other than the
+// site's domain (either may carry a "www.").
+func externalURL(dest, domain string) bool {
+	u, err := url.Parse(dest)
+	if err != nil || u.Host == "" || (u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https") {
+		return false
+	}
+	site := domain
+	if d, err := url.Parse(domain); err == nil && d.Host != "" {
+		site = d.Hostname()
+	}
+	trim := func(h string) string { return strings.TrimPrefix(strings.ToLower(h), "www.") }
+	return site == "" || trim(u.Hostname()) != trim(site)
+}
+
+type renderHookExtension struct {
+	base   goldmark.Markdown
+	hooks  *markupHooks
+	domain string
+}
+
+// Extend implements goldmark.Extender. Registered ahead of the HTML and
+// highlighting renderers, so a hook replaces both.
+func (e renderHookExtension) Extend(m goldmark.Markdown) {
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(renderHookRenderer{
+		md: m, base: e.base, hooks: e.hooks, domain: e.domain,
+	}, 100)))
+}
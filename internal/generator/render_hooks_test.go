@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// hookedMarkdown converts src with a theme whose _markup/ holds hooks.
+func hookedMarkdown(t *testing.T, cfg Config, hooks map[string]string, src string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, body := range hooks {
+		mustWrite(t, filepath.Join(root, "theme", "_markup", "render-"+name+".html"), body)
+	}
+	cfg.TemplatesDir, cfg.Template = root, "theme"
+	var buf bytes.Buffer
+	if err := buildMarkdown(cfg).Convert([]byte(src), &buf); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestRenderHooks(t *testing.T) {
+	hooks := map[string]string{
+		"link":      `<a href="{{.Destination}}"{{if .Title}} title="{{.Title}}"{{end}}{{if .External}} target="_blank" rel="noopener"{{end}}>{{.Text}}</a>`,
+		"image":     `<figure><img src="{{.Destination}}" alt="{{.Text}}">{{with .Title}}<figcaption>{{.}}</figcaption>{{end}}</figure>`,
+		"heading":   `<h{{.Level}} id="{{.ID}}">{{.Text}} <a href="#{{.ID}}">#</a></h{{.Level}}>` + "\n",
+		"codeblock": `<div class="code" data-lang="{{.Language}}">{{with .Attributes.title}}<p>{{.}}</p>{{end}}<pre>{{.Code}}</pre></div>` + "\n",
+	}
+	cfg := Config{Domain: "https://www.example.com"}
+	for _, tc := range []struct{ src, want string }{
+		{"[ext](https://go.dev \"Go\")", `<a href="https://go.dev" title="Go" target="_blank" rel="noopener">ext</a>`},
+		{"[own *site*](https://example.com/about/)", `<a href="https://example.com/about/">own <em>site</em></a>`},
+		{"[rel](/posts/)", `<a href="/posts/">rel</a>`},
+		{"<https://go.dev>", `<a href="https://go.dev" target="_blank" rel="noopener">https://go.dev</a>`},
+		{"![A cat](/media/cat.jpg \"Our cat\")", `<figure><img src="/media/cat.jpg" alt="A cat"><figcaption>Our cat</figcaption></figure>`},
+		{"## Hello [there](/x/)", `<h2 id="hello-there">Hello <a href="/x/">there</a> <a href="#hello-there">#</a></h2>`},
+		{"```go {title=\"main.go\"}\nfmt.Println(\"<hi>\")\n```", `<div class="code" data-lang="go"><p>main.go</p><pre>fmt.Println(&#34;&lt;hi&gt;&#34;)
+</pre></div>`},
+	} {
+		got := hookedMarkdown(t, cfg, hooks, tc.src)
+		if !strings.Contains(got, tc.want) {
+			t.Errorf("%q\n got: %s\nwant: %s", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestRenderHooksFallBack(t *testing.T) {
+	src := "## Title\n\n[a](/b/) ![c](d.png)\n\n```go\nx\n```\n"
+	var want bytes.Buffer
+	if err := buildMarkdown(Config{}).Convert([]byte(src), &want); err != nil {
+		t.Fatal(err)
+	}
+	// Only a code hook: everything else is goldmark's, byte for byte.
+	got := hookedMarkdown(t, Config{}, map[string]string{"codeblock": `<x>{{.Highlighted}}</x>`}, src)
+	if want := strings.Replace(want.String(), "<pre>", "<x><pre>", 1) + "</x>"; got != want {
+		t.Errorf("code hook:\n got: %s\nwant: %s", got, want)
+	}
+	// A hook that fails at execution leaves the construct's built-in markup.
+	got = hookedMarkdown(t, Config{}, map[string]string{"link": `{{index .Attributes 1}}`}, "[a](/b/)")
+	if got != "<p><a href=\"/b/\">a</a></p>\n" {
+		t.Errorf("failing hook: %s", got)
+	}
+}
+
+func TestRenderHooksAltEngine(t *testing.T) {
+	got := hookedMarkdown(t, Config{Engine: "mustache", Domain: "example.com"},
+		map[string]string{"link": `<a href="{{Destination}}"{{#External}} rel="external"{{/External}}>{{{Text}}}</a>`},
+		"[**go**](https://go.dev)")
+	if want := `<p><a href="https://go.dev" rel="external"><strong>go</strong></a></p>`; !strings.Contains(got, want) {
+		t.Errorf("mustache hook:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestExternalURL(t *testing.T) {
+	for _, tc := range []struct {
+		dest, domain string
+		want         bool
+	}{
+		{"https://go.dev/doc", "example.com", true},
+		{"https://www.example.com/x", "https://example.com", false},
+		{"//cdn.example.net/a.js", "example.com", true},
+		{"/posts/", "example.com", false},
+		{"mailto:a@b.c", "example.com", false},
+		{"http://go.dev", "", true},
+	} {
+		if got := externalURL(tc.dest, tc.domain); got != tc.want {
+			t.Errorf("externalURL(%q, %q) = %v", tc.dest, tc.domain, got)
+		}
+	}
+}
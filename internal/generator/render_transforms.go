@@ -176,6 +176,9 @@ func (g *Generator) transformHTMLPage(s string, page *models.Page, isPost bool)
 		if g.config.MarkdownPublish && page.Content != "" {
 			s = injectMarkdownAlternate(s, markdownLeaf(page.GetURL()))
 		}
+		if page.IsDraft {
+			s = markPreview(s, *page)
+		}
 	}
 	// Feed autodiscovery is injected for every page, not only those with a page
 	// context. The SEO block runs only for posts and pages, so the site homepage
@@ -207,9 +210,31 @@ func (g *Generator) transformHTMLPage(s string, page *models.Page, isPost bool)
 // renderPageTemplate renders a template into memory, applies the per-file HTML
 // transforms and writes the result in a single write (PERF-005). page carries
 // the SEO context for posts/pages; nil for listing pages.
-func (g *Generator) renderPageTemplate(templateName, outputPath string, data interface{}, page *models.Page, isPost bool) error {
+func (g *Generator) renderPageTemplate(templateName, outputPath string, data interface{}, page *models.Page, isPost bool) (err error) {
+	// Every listing, archive and page funnels through here, which makes it the
+	// one place an incremental build can both skip a current listing and record
+	// what a written output depended on. Pages decide earlier, in skipPage; the
+	// front page's synthetic SEO page has no source file and is a listing.
+	if (page == nil || page.SourceFile == "") && g.deps.skipListing(outputPath) {
+		return nil
+	}
+	// .Menus carries the active flags for this one output (menus.go).
+	data = g.withMenus(data, outputPath, page)
+	if g.explain != nil {
+		defer func() { g.explainRender(templateName, outputPath, data, page, isPost, err) }()
+	}
+	done := g.profileTemplate(templateName, outputPath, page)
 	if g.engine != nil {
-		return g.renderWithEngine(templateName, outputPath, data, page, isPost)
+		if err := g.renderWithEngine(templateName, outputPath, data, page, isPost); err != nil {
+			return err
+		}
+		var size int64
+		if info, err := os.Stat(outputPath); err == nil {
+			size = info.Size()
+		}
+		done(size)
+		g.recordRender(templateName, outputPath, data, page)
+		return nil
 	}
 	var buf bytes.Buffer
 	if err := g.tmpl.ExecuteTemplate(&buf, templateName, data); err != nil {
@@ -226,5 +251,10 @@ func (g *Generator) renderPageTemplate(templateName, outputPath string, data int
 		data2 = encodeText(out, enc)
 	}
 	// #nosec G306 -- Web content files need to be world-readable
-	return os.WriteFile(outputPath, data2, 0644)
+	if err := os.WriteFile(outputPath, data2, 0644); err != nil {
+		return err
+	}
+	done(int64(len(data2)))
+	g.recordRender(templateName, outputPath, data, page)
+	return nil
 }
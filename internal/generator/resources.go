@@ -0,0 +1,212 @@
+package generator
+
+// Page resources: the files a page owns, exposed to templates as
+// .Resources (see models/resources.go). A page bundle — a nested directory
+// whose only content file is index.* — owns every file in its directory tree
+// and publishes all of them beside its index.html. Any other page owns just the
+// co-located files its content references (PERF-007) plus whatever its content
+// format extracted, which is what copyColocatedAssets and writePageAssets
+// already publish for it.
+
+import (
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+)
+
+// isBundleDir reports whether a nested directory is a leaf page bundle: it
+// holds exactly one content file and that file is index.* — an index.md beside
+// other posts is just a post that happens to be called "index".
+func (g *Generator) isBundleDir(dir string, entries []os.DirEntry) bool {
+	index := false
+	for _, entry := range entries {
+		if entry.IsDir() || !parser.IsContentFile(entry.Name()) || g.excludedFromContent(filepath.Join(dir, entry.Name())) {
+			continue
+		}
+		if index || strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name())) != "index" {
+			return false
+		}
+		index = true
+	}
+	return index
+}
+
+// collectResources fills page.Resources. Runs once per page after its URL is
+// final (permalinks, language prefix), since every resource URL hangs off it.
+func (g *Generator) collectResources(page *models.Page) {
+	page.Resources = nil
+	if page.SourceDir != "" {
+		files := g.resourceFiles(*page)
+		root := g.contentRootOf(page.SourceDir)
+		for _, file := range files {
+			src := filepath.Join(page.SourceDir, filepath.FromSlash(file))
+			info, err := os.Stat(src)
+			if err != nil {
+				continue
+			}
+			r := models.Resource{File: file, MediaType: resourceMediaType(file), Size: info.Size()}
+			if root != "" {
+				if rel, err := filepath.Rel(root, src); err == nil {
+					r.Path = filepath.ToSlash(rel)
+				}
+			}
+			if r.Path != "" && strings.HasPrefix(r.MediaType, "image/") {
+				if img, err := g.imageProcessor().Info(r.Path); err == nil {
+					r.Image = &img
+				}
+			}
+			page.Resources = append(page.Resources, r)
+		}
+	}
+	names := make([]string, 0, len(page.Assets))
+	for name := range page.Assets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		page.Resources = append(page.Resources, models.Resource{
+			File:      filepath.Base(name),
+			MediaType: resourceMediaType(name),
+			Size:      int64(len(page.Assets[name])),
+		})
+	}
+
+	base := pageDirURL(*page)
+	for i := range page.Resources {
+		r := &page.Resources[i]
+		r.Name = r.File
+		r.URL = base + r.File
+		applyResourceMeta(r, page.ResourcesFM)
+	}
+}
+
+// resourceFiles lists the files, slash-separated and relative to SourceDir,
+// that the page owns on disk.
+func (g *Generator) resourceFiles(page models.Page) []string {
+	var files []string
+	if !page.Bundle {
+		for _, entry := range g.assetDirEntries(page.SourceDir) {
+			name := entry.Name()
+			if !entry.IsDir() && !parser.IsContentFile(name) && isContentAsset(name) && strings.Contains(page.Content, name) {
+				files = append(files, name)
+			}
+		}
+		return files
+	}
+	_ = filepath.WalkDir(page.SourceDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != page.SourceDir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || parser.IsContentFile(d.Name()) || isDefaultsFile(d.Name()) {
+			return nil
+		}
+		if rel, err := filepath.Rel(page.SourceDir, p); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
+
+// contentRootOf returns the content root — the primary source or a
+// content_sources path — that dir lives under, or "" for none. Resource paths
+// are relative to it, which is where the image processor looks them up.
+func (g *Generator) contentRootOf(dir string) string {
+	roots := []string{filepath.Join(g.config.ContentDir, g.config.Source)}
+	for _, src := range g.config.ContentSources {
+		if src.Path != "" {
+			roots = append(roots, src.Path)
+		}
+	}
+	for _, root := range roots {
+		rel, err := filepath.Rel(root, dir)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return root
+		}
+	}
+	return ""
+}
+
+// pageDirURL is the URL of the directory a page's resources are published in:
+// the page URL for directory-style output, its stem for a flat .html one.
+func pageDirURL(page models.Page) string {
+	u := page.GetURL()
+	if strings.HasSuffix(u, "/") {
+		return u
+	}
+	return strings.TrimSuffix(u, path.Ext(u)) + "/"
+}
+
+// resourceMediaType names a file's media type from its extension, without
+// parameters ("text/plain", not "text/plain; charset=utf-8").
+func resourceMediaType(name string) string {
+	t := mime.TypeByExtension(strings.ToLower(path.Ext(name)))
+	if t == "" {
+		return "application/octet-stream"
+	}
+	if i := strings.IndexByte(t, ';'); i >= 0 {
+		t = strings.TrimSpace(t[:i])
+	}
+	return t
+}
+
+// applyResourceMeta applies the `resources:` frontmatter entries whose src
+// matches r; the first entry to set a field wins.
+func applyResourceMeta(r *models.Resource, metas []models.ResourceMeta) {
+	for _, m := range metas {
+		if m.Src == "" || !models.MatchResource(m.Src, r.File) {
+			continue
+		}
+		if m.Name != "" && r.Name == r.File {
+			r.Name = m.Name
+		}
+		if r.Title == "" {
+			r.Title = m.Title
+		}
+		if r.Alt == "" {
+			r.Alt = m.Alt
+		}
+		for k, v := range m.Params {
+			if r.Params == nil {
+				r.Params = map[string]interface{}{}
+			}
+			if _, set := r.Params[k]; !set {
+				r.Params[k] = v
+			}
+		}
+	}
+}
+
+// copyBundleResources publishes a bundle's files beside its index.html,
+// keeping their layout within the bundle.
+func (g *Generator) copyBundleResources(page models.Page, outputDir string) error {
+	for _, r := range page.Resources {
+		if _, extracted := page.Assets[r.File]; extracted {
+			continue // writePageAssets has it
+		}
+		dst := filepath.Join(outputDir, filepath.FromSlash(r.File))
+		if err := g.ensureWithinOutput(dst); err != nil {
+			return err
+		}
+		if err := g.ensureDir(filepath.Dir(dst)); err != nil {
+			return err
+		}
+		if err := g.copyFile(filepath.Join(page.SourceDir, filepath.FromSlash(r.File)), dst); err != nil {
+			return err
+		}
+	}
+	return nil
+}
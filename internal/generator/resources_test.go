@@ -0,0 +1,84 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// A post kept as posts/news/hello/index.md is a bundle: it takes the
+// directory's name, templates see every file beside it as a resource, and
+// all of them are published with it.
+func TestPageBundleResources(t *testing.T) {
+	root := t.TempDir()
+	t.Chdir(root) // imageResize caches under .ssg-cache/, relative to the working directory
+	writeIncrementalSite(t, root)
+	png, err := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg==")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bundle := filepath.Join(root, "content", "site", "posts", "news", "hello")
+	mustWrite(t, filepath.Join(bundle, "index.md"), "---\ntitle: Hello\nstatus: publish\ntype: post\ndate: 2024-01-03\n"+
+		"categories: [News]\nresources:\n  - src: cover.png\n    name: cover\n    alt: The cover\n"+
+		"  - src: gallery/*\n    title: Gallery shot\n---\n\nBody.\n")
+	mustWrite(t, filepath.Join(bundle, "cover.png"), string(png))
+	mustWrite(t, filepath.Join(bundle, "gallery", "01.png"), string(png))
+	mustWrite(t, filepath.Join(bundle, "gallery", "02.png"), string(png))
+	mustWrite(t, filepath.Join(bundle, "notes.txt"), "unreferenced, still published")
+	mustWrite(t, filepath.Join(root, "templates", "simple", "post.html"),
+		`<html>{{with .Resources.Get "cover"}}<img src="{{.URL}}" alt="{{.Alt}}" width="{{.Image.Width}}">{{end}}`+
+			`{{range .Resources.Match "gallery/*"}}<g title="{{.Title}}">{{.URL}}</g>{{end}}`+
+			`{{with imageResize (.Resources.Get "cover") (dict "width" 1)}}<r>{{.Width}}</r>{{end}}</html>`)
+
+	gen := buildScheduled(t, root, nil)
+	out := filepath.Join(root, "output", "2024", "01", "03", "hello")
+
+	html, err := os.ReadFile(filepath.Join(out, "index.html"))
+	if err != nil {
+		t.Fatalf("bundle post not built under its directory name: %v", err)
+	}
+	for _, want := range []string{
+		`<img src="/2024/01/03/hello/cover.png" alt="The cover" width="1">`,
+		`<g title="Gallery shot">/2024/01/03/hello/gallery/01.png</g><g title="Gallery shot">/2024/01/03/hello/gallery/02.png</g>`,
+		`<r>1</r>`,
+	} {
+		if !strings.Contains(string(html), want) {
+			t.Errorf("page lacks %s:\n%s", want, html)
+		}
+	}
+	for _, file := range []string{"cover.png", "gallery/01.png", "gallery/02.png", "notes.txt"} {
+		data, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(file)))
+		if err != nil {
+			t.Errorf("%s not published with the bundle: %v", file, err)
+		} else if file != "notes.txt" && !bytes.Equal(data, png) {
+			t.Errorf("%s published with different bytes", file)
+		}
+	}
+
+	for _, post := range gen.siteData.Posts {
+		if post.Slug == "alpha" && (post.Bundle || len(post.Resources) != 0) {
+			t.Errorf("a flat post is not a bundle: %+v", post.Resources)
+		}
+	}
+}
+
+func TestImageSource(t *testing.T) {
+	if _, err := imageSource(42); err == nil {
+		t.Error("a number is not an image source")
+	}
+	var missing *models.Resource
+	if _, err := imageSource(missing); err == nil {
+		t.Error("a missing resource (Get found nothing) should be an error")
+	}
+	if _, err := imageSource(models.Resource{Name: "plot.png"}); err == nil || !strings.Contains(err.Error(), "plot.png") {
+		t.Errorf("an extracted resource has no file to process: %v", err)
+	}
+	if p, err := imageSource(&models.Resource{Path: "posts/a/b.png"}); err != nil || p != "posts/a/b.png" {
+		t.Errorf("imageSource(resource) = %q, %v", p, err)
+	}
+}
@@ -2,7 +2,6 @@ package generator
 
 import (
 	"encoding/json"
-	"os"
 	"path/filepath"
 	"sort"
 )
@@ -21,6 +20,8 @@ type RouteEntry struct {
 type RouteManifest struct {
 	Count  int          `json:"count"`
 	Routes []RouteEntry `json:"routes"`
+	// GitSources pins the commit each git content source was built from.
+	GitSources []GitSourcePin `json:"git_sources,omitempty"`
 }
 
 // writeRouteManifest emits routes.json — every post, page and taxonomy archive —
@@ -42,7 +43,7 @@ func (g *Generator) writeRouteManifest() error {
 	routes = dedupeRoutesByPath(routes)
 	sort.Slice(routes, func(i, j int) bool { return routes[i].Path < routes[j].Path })
 
-	doc := RouteManifest{Count: len(routes), Routes: routes}
+	doc := RouteManifest{Count: len(routes), Routes: routes, GitSources: g.gitPins}
 	data, err := json.MarshalIndent(doc, "", "  ")
 	if err != nil {
 		return err
@@ -52,8 +53,7 @@ func (g *Generator) writeRouteManifest() error {
 		return err
 	}
 	g.log("🧭 Writing route manifest...")
-	// #nosec G306 -- manifest is a public build artifact served alongside the site
-	return os.WriteFile(out, append(data, '\n'), 0644)
+	return g.writeOutput(out, append(data, '\n'))
 }
 
 // taxonomyRoutes enumerates the archive routes: the index page of each custom
@@ -0,0 +1,108 @@
+package generator
+
+// Scheduled publishing: publish_date and expiry_date.
+//
+// A page is live from its publish_date — its date when it has none, so a post
+// dated tomorrow waits for tomorrow — until its expiry_date. Only a date
+// someone wrote counts: an undated file's modification time is not a
+// schedule, and under SOURCE_DATE_EPOCH a checkout made after the pinned
+// commit would otherwise hold back every undated page. Pages outside
+// that window are dropped from the loaded content before anything derives
+// from it, which is what keeps them out of listings, taxonomies, feeds, the
+// sitemap, llms.txt and the search index without each of those having to ask.
+//
+// "Now" is the build time (#186), so SOURCE_DATE_EPOCH pins the schedule as it
+// pins every other date a build prints. A static site cannot change state by
+// itself: the build reports the next moment one of its pages would, so a
+// scheduler (CI, cron, `ssg --watch`) can rebuild then.
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// StateChange is the next moment a page enters or leaves the build.
+type StateChange struct {
+	At    time.Time
+	Title string
+	// Publishes is true when the page appears then, false when it expires.
+	Publishes bool
+}
+
+// NextStateChange reports the earliest scheduled publish or expiry after this
+// build's time; ok is false when nothing is scheduled.
+func (g *Generator) NextStateChange() (StateChange, bool) {
+	return g.nextChange, !g.nextChange.At.IsZero()
+}
+
+// publishAt is when a page becomes live; zero is always.
+func publishAt(p models.Page) time.Time {
+	if !p.PublishDate.IsZero() {
+		return p.PublishDate
+	}
+	if p.DateInferred {
+		return time.Time{}
+	}
+	return p.Date
+}
+
+// applySchedule removes future and expired pages and posts (unless
+// build_future / build_expired keep them) and records the next state change.
+// Preview mode (--drafts) keeps future pages as drafts instead.
+func (g *Generator) applySchedule() {
+	now := g.buildTime
+	if now.IsZero() {
+		now = time.Now() // a Generator not made by New
+	}
+	var future, expired int
+	g.nextChange = StateChange{}
+	consider := func(at time.Time, p models.Page, publishes bool) {
+		if at.After(now) && (g.nextChange.At.IsZero() || at.Before(g.nextChange.At)) {
+			g.nextChange = StateChange{At: at, Title: p.Title, Publishes: publishes}
+		}
+	}
+	keep := func(pages []models.Page) []models.Page {
+		out := pages[:0]
+		for _, p := range pages {
+			if at := publishAt(p); at.After(now) && !g.config.BuildFuture {
+				future++
+				consider(at, p, true)
+				if !g.config.Drafts {
+					continue
+				}
+				p.IsDraft = true // previewed, not published (drafts.go)
+			}
+			if !p.ExpiryDate.IsZero() && !g.config.BuildExpired {
+				if !p.ExpiryDate.After(now) {
+					expired++
+					continue
+				}
+				consider(p.ExpiryDate, p, false)
+			}
+			out = append(out, p)
+		}
+		return out
+	}
+	g.siteData.Pages = keep(g.siteData.Pages)
+	g.siteData.Posts = keep(g.siteData.Posts)
+	g.reportSchedule(future, expired)
+}
+
+// reportSchedule prints one line when anything is scheduled. The time is
+// RFC 3339 in UTC so a script can read it straight off the log.
+func (g *Generator) reportSchedule(future, expired int) {
+	if g.config.Quiet || (future == 0 && expired == 0 && g.nextChange.At.IsZero()) {
+		return
+	}
+	line := fmt.Sprintf("   🗓️  Scheduled: %d future, %d expired", future, expired)
+	if c := g.nextChange; !c.At.IsZero() {
+		verb := "expires"
+		if c.Publishes {
+			verb = "publishes"
+		}
+		line += fmt.Sprintf(" — next change %s (%q %s)", c.At.UTC().Format(time.RFC3339), c.Title, verb)
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,135 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// scheduledSite is the incremental fixture plus a post embargoed until after
+// the pinned build time, a page that expired before it and a post that expires
+// after it. The build runs at 2024-06-01T00:00:00Z.
+func scheduledSite(t *testing.T) string {
+	t.Helper()
+	t.Setenv(sourceDateEpoch, "1717200000")
+	root := t.TempDir()
+	writeIncrementalSite(t, root)
+	content := filepath.Join(root, "content", "site")
+	mustWrite(t, filepath.Join(content, "posts", "news", "launch.md"),
+		"---\ntitle: Launch\nslug: launch\nstatus: publish\ntype: post\ndate: 2024-01-05\n"+
+			"publish_date: 2024-06-10T09:00:00Z\ncategories: [News]\n---\n\nEmbargoed.\n")
+	mustWrite(t, filepath.Join(content, "posts", "news", "sale.md"),
+		"---\ntitle: Sale\nslug: sale\nstatus: publish\ntype: post\ndate: 2024-01-03\n"+
+			"expiry_date: 2024-07-01\ncategories: [News]\n---\n\nUntil July.\n")
+	mustWrite(t, filepath.Join(content, "pages", "old-offer.md"),
+		"---\ntitle: Old offer\nslug: old-offer\nstatus: publish\ntype: page\nexpiry_date: 2024-05-01\n---\n\nGone.\n")
+	return root
+}
+
+func buildScheduled(t *testing.T, root string, mod func(*Config)) *Generator {
+	t.Helper()
+	cfg := Config{
+		Source: "site", Template: "simple", Domain: "example.com",
+		ContentDir:   filepath.Join(root, "content"),
+		TemplatesDir: filepath.Join(root, "templates"),
+		DataDir:      filepath.Join(root, "data"),
+		OutputDir:    filepath.Join(root, "output"),
+		Quiet:        true,
+	}
+	if mod != nil {
+		mod(&cfg)
+	}
+	gen, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	return gen
+}
+
+func TestScheduledContentIsLeftOut(t *testing.T) {
+	root := scheduledSite(t)
+	gen := buildScheduled(t, root, nil)
+	out := filepath.Join(root, "output")
+
+	for _, gone := range []string{"2024/01/05/launch/index.html", "old-offer/index.html"} {
+		if _, err := os.Stat(filepath.Join(out, gone)); err == nil {
+			t.Errorf("%s was built", gone)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "2024/01/03/sale/index.html")); err != nil {
+		t.Errorf("a live post that expires later must be built: %v", err)
+	}
+	for _, listing := range []string{"index.html", "category/news/index.html", "sitemap.xml"} {
+		raw, err := os.ReadFile(filepath.Join(out, listing))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if listing != "sitemap.xml" && !strings.Contains(string(raw), "Sale") {
+			t.Errorf("%s lost the live post", listing)
+		}
+		for _, leak := range []string{"Launch", "launch/", "old-offer"} {
+			if strings.Contains(string(raw), leak) {
+				t.Errorf("%s mentions %q", listing, leak)
+			}
+		}
+	}
+
+	// The embargo lifts before the sale ends: that is the next change.
+	next, ok := gen.NextStateChange()
+	want := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC)
+	if !ok || !next.At.Equal(want) || next.Title != "Launch" || !next.Publishes {
+		t.Errorf("next change = %+v (%v), want Launch publishing at %v", next, ok, want)
+	}
+}
+
+func TestBuildFutureAndExpiredOverride(t *testing.T) {
+	root := scheduledSite(t)
+	gen := buildScheduled(t, root, func(c *Config) { c.BuildFuture, c.BuildExpired = true, true })
+	out := filepath.Join(root, "output")
+	for _, built := range []string{"2024/01/05/launch/index.html", "old-offer/index.html"} {
+		if _, err := os.Stat(filepath.Join(out, built)); err != nil {
+			t.Errorf("%s not built: %v", built, err)
+		}
+	}
+	if next, ok := gen.NextStateChange(); ok {
+		t.Errorf("with both overrides nothing changes state, got %+v", next)
+	}
+}
+
+// An undated page's date is its file's mtime, which a checkout puts after
+// the time SOURCE_DATE_EPOCH pins: it must still be built, and schedule nothing.
+func TestUndatedPageIsNotScheduled(t *testing.T) {
+	root := scheduledSite(t) // about.md has no date; the build runs in 2024
+	gen := buildScheduled(t, root, nil)
+	if _, err := os.Stat(filepath.Join(root, "output", "about", "index.html")); err != nil {
+		t.Errorf("an undated page must be built: %v", err)
+	}
+	if next, _ := gen.NextStateChange(); next.Title != "Launch" {
+		t.Errorf("next change = %+v, want Launch", next)
+	}
+	if got := publishAt(models.Page{Date: time.Now().Add(time.Hour), DateInferred: true}); !got.IsZero() {
+		t.Errorf("publishAt = %v for an inferred date, want zero", got)
+	}
+}
+
+// A post with no publish_date waits for its date; one without either is live.
+func TestPublishAtFallsBackToDate(t *testing.T) {
+	day := time.Date(2024, 6, 2, 0, 0, 0, 0, time.UTC)
+	explicit := day.Add(time.Hour)
+	if got := publishAt(models.Page{Date: day}); !got.Equal(day) {
+		t.Errorf("publishAt = %v, want the date", got)
+	}
+	if got := publishAt(models.Page{Date: day, PublishDate: explicit}); !got.Equal(explicit) {
+		t.Errorf("publishAt = %v, want publish_date", got)
+	}
+	if got := publishAt(models.Page{}); !got.IsZero() {
+		t.Errorf("publishAt = %v, want zero", got)
+	}
+}
@@ -68,6 +68,7 @@ func (g *Generator) compileSCSSIfRequested() error {
 		if err := compileSCSSFile(bin, src); err != nil {
 			return err
 		}
+		g.noteWritten(strings.TrimSuffix(src, filepath.Ext(src)) + ".css")
 	}
 	// Remove every .scss source (partials included) from the shipped output.
 	for _, src := range scssFiles {
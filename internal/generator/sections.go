@@ -0,0 +1,320 @@
+package generator
+
+// Sections (_index.md). A directory of content is only a folder to the URL
+// scheme — pages/guides/setup.md is /setup/ — and a theme that wanted a docs
+// sidebar or a "Guides › Setup" trail had to rebuild the hierarchy from
+// SourceDir strings. An _index.md in a directory declares it a section: its
+// frontmatter titles, describes and weights the section, its body introduces
+// the generated list page at /guides/, and the sections nest as the
+// directories do.
+//
+// The tree is built once, after finalize, when every page URL is known, and
+// only read afterwards, so parallel renders share it freely. A directory
+// without an _index.md is transparent: its pages belong to the nearest section
+// above. An _index.md at a content root is not a section — the root is the
+// front page's.
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+)
+
+// sectionHTMLName is the list template a theme can ship for sections;
+// category.html renders them otherwise.
+const sectionHTMLName = "section.html"
+
+// sectionTree is the built tree with the lookups rendering needs.
+type sectionTree struct {
+	roots    []*models.SectionNode
+	sections []*models.SectionNode          // every section, parents first
+	byURL    map[string]*models.SectionNode // section or page URL → node
+}
+
+// isSectionIndex reports whether a file name is a section's _index.<ext>.
+func isSectionIndex(name string) bool {
+	return strings.TrimSuffix(name, filepath.Ext(name)) == "_index" && parser.IsContentFile(name)
+}
+
+// sectionRoots are the directories whose subdirectories can be sections: the
+// source's pages/ and posts/, and every content source.
+func (g *Generator) sectionRoots() []string {
+	var roots []string
+	if g.config.Source != "" {
+		src := filepath.Join(g.config.ContentDir, g.config.Source)
+		roots = append(roots, filepath.Join(src, g.pagesPath()), filepath.Join(src, g.postsPath()))
+	}
+	for _, cs := range g.config.ContentSources {
+		if cs.Path != "" {
+			roots = append(roots, cs.Path)
+		}
+	}
+	return roots
+}
+
+// buildSections reads every _index.md, builds the tree and files each page
+// under its nearest section. A no-op for a site that has none.
+func (g *Generator) buildSections() error {
+	g.sections = nil
+	g.siteData.Sections = nil
+	byDir := map[string]*models.SectionNode{}
+	var dirs []string
+	for _, root := range g.sectionRoots() {
+		found, err := g.loadSectionIndexes(root)
+		if err != nil {
+			return err
+		}
+		for dir, node := range found {
+			if _, dup := byDir[dir]; !dup {
+				byDir[dir] = node
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	if len(dirs) == 0 {
+		return nil
+	}
+	// A parent's path is a prefix of its children's, so it sorts first and its
+	// URL is known by the time a child inherits it.
+	sort.Strings(dirs)
+	tree := &sectionTree{byURL: map[string]*models.SectionNode{}}
+	owner := map[string]string{} // section URL → the _index.md that claimed it
+	for _, dir := range dirs {
+		node := byDir[dir]
+		node.Parent = nearestSection(byDir, filepath.Dir(dir))
+		node.URL = g.sectionURL(node)
+		if prev, dup := owner[node.URL]; dup {
+			return fmt.Errorf("sections: %s and %s are both %s", prev, filepath.Join(dir, node.Page.SourceFile), node.URL)
+		}
+		owner[node.URL] = filepath.Join(dir, node.Page.SourceFile)
+		if node.Parent == nil {
+			tree.roots = append(tree.roots, node)
+		} else {
+			node.Parent.Children = append(node.Parent.Children, node)
+		}
+		tree.sections = append(tree.sections, node)
+		tree.byURL[node.URL] = node
+	}
+
+	attach := func(pages []models.Page) {
+		for i := range pages {
+			if pages[i].SourceDir == "" {
+				continue
+			}
+			parent := nearestSection(byDir, filepath.Clean(pages[i].SourceDir))
+			if parent == nil {
+				continue
+			}
+			page := pages[i]
+			node := &models.SectionNode{
+				Title:       page.Title,
+				Description: page.Description,
+				URL:         page.GetURL(),
+				Weight:      page.Weight,
+				Page:        &page,
+				Parent:      parent,
+			}
+			parent.Children = append(parent.Children, node)
+			// A page at its section's own URL is the section's landing page,
+			// and speaks for the section.
+			if _, isSection := tree.byURL[node.URL]; !isSection {
+				tree.byURL[node.URL] = node
+			}
+		}
+	}
+	attach(g.siteData.Pages)
+	attach(g.siteData.Posts)
+
+	models.SortSectionNodes(tree.roots)
+	for _, s := range tree.sections {
+		models.SortSectionNodes(s.Children)
+	}
+	g.sections = tree
+	g.siteData.Sections = tree.roots
+	return nil
+}
+
+// loadSectionIndexes finds the _index.md files below root, keyed by directory.
+func (g *Generator) loadSectionIndexes(root string) (map[string]*models.SectionNode, error) {
+	root = filepath.Clean(root)
+	found := map[string]*models.SectionNode{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == root {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() || !isSectionIndex(d.Name()) || g.excludedFromContent(path) {
+			return nil
+		}
+		dir := filepath.Dir(path)
+		if dir == root {
+			return nil
+		}
+		if _, dup := found[dir]; dup {
+			fmt.Printf("   ⚠️  Warning: %s has more than one _index file; using the first\n", dir)
+			return nil
+		}
+		page, err := parser.ParseFile(path)
+		if err != nil {
+			fmt.Printf("   ⚠️  Warning: failed to parse %s: %v\n", path, err)
+			return nil
+		}
+		// A draft section is not a section: its pages fall to the one above.
+		if page.Status != "" && !g.keepsStatus(page.Status) {
+			return nil
+		}
+		page.SourceDir = dir
+		page.SourceFile = d.Name()
+		rel, _ := filepath.Rel(root, dir)
+		title := page.Title
+		if title == "" {
+			title = titleize(filepath.Base(dir))
+		}
+		found[dir] = &models.SectionNode{
+			Title:       title,
+			Description: page.Description,
+			Weight:      page.Weight,
+			IsSection:   true,
+			Path:        filepath.ToSlash(rel),
+			Page:        page,
+		}
+		return nil
+	})
+	return found, err
+}
+
+// nearestSection walks up from dir to the first directory that is a section.
+func nearestSection(byDir map[string]*models.SectionNode, dir string) *models.SectionNode {
+	for {
+		if node, ok := byDir[dir]; ok {
+			return node
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil
+		}
+		dir = parent
+	}
+}
+
+// sectionURL is where a section's list page goes: its parent's URL plus the
+// directories in between, the last one replaced by the _index.md's slug when
+// it sets one. A `link:` overrides the lot, as it does for a page.
+func (g *Generator) sectionURL(node *models.SectionNode) string {
+	if link := strings.Trim(node.Page.Link, "/"); link != "" {
+		return "/" + link + "/"
+	}
+	base, from := "/", ""
+	if node.Parent != nil {
+		base = node.Parent.URL
+		from = node.Parent.Path
+	}
+	rel := strings.TrimPrefix(strings.TrimPrefix(node.Path, from), "/")
+	segs := strings.Split(rel, "/")
+	for i, s := range segs {
+		segs[i] = g.normalizeSlug(s, "")
+	}
+	if node.Page.Slug != "" {
+		segs[len(segs)-1] = g.normalizeSlug(node.Page.Slug, "")
+	}
+	return base + strings.Join(segs, "/") + "/"
+}
+
+// sectionNode is the tree node for a page — the section itself when the page
+// is a section's landing page — or nil outside any section.
+func (g *Generator) sectionNode(page models.Page) *models.SectionNode {
+	if g.sections == nil {
+		return nil
+	}
+	return g.sections.byURL[page.GetURL()]
+}
+
+// sectionContext adds the tree keys to a template context: .Section (the
+// top-level section), .Parent, .Ancestors and .Children. All nil outside a
+// section, so `{{with .Parent}}` is the test.
+func sectionContext(data map[string]interface{}, node *models.SectionNode) {
+	if node == nil {
+		data["Section"], data["Parent"], data["Ancestors"], data["Children"] = nil, nil, nil, nil
+		return
+	}
+	data["Section"] = node.Root()
+	data["Parent"] = node.Parent
+	data["Ancestors"] = node.Ancestors()
+	data["Children"] = node.Children
+}
+
+// generateSections writes each section's list page, paginated like an
+// archive. A section whose URL real content already owns is that page's to
+// render; the page gets the section's .Children instead.
+func (g *Generator) generateSections() error {
+	if g.sections == nil {
+		return nil
+	}
+	written := 0
+	for _, node := range g.sections.sections {
+		ok, err := g.writeSection(node)
+		if err != nil {
+			return err
+		}
+		if ok {
+			written++
+		}
+	}
+	if written > 0 && !g.config.Quiet {
+		fmt.Printf("   📚 Generated %d section page(s)\n", written)
+	}
+	return nil
+}
+
+// writeSection renders one section at its URL and /page/N/ after it.
+func (g *Generator) writeSection(node *models.SectionNode) (bool, error) {
+	slug := models.SanitizeRelPath(strings.Trim(node.URL, "/"))
+	if slug == "" {
+		return false, nil
+	}
+	if _, taken := g.archivePathOwner(slug); taken {
+		return false, nil
+	}
+	var pages []models.Page
+	for _, c := range node.Pages() {
+		pages = append(pages, *c.Page)
+	}
+	tmpl := categoryHTMLName
+	if g.hasTemplate(sectionHTMLName) {
+		tmpl = sectionHTMLName
+	}
+	root := filepath.Join(g.config.OutputDir, filepath.FromSlash(slug))
+	term := models.Category{Name: node.Title, Slug: slug}
+	for _, chunk := range paginateTerm(pages, g.archivePerPage(), "/"+slug+"/") {
+		outputPath := filepath.Join(root, indexHTMLName)
+		if chunk.Pager.Current > 1 {
+			outputPath = filepath.Join(root, "page", fmt.Sprintf("%d", chunk.Pager.Current), indexHTMLName)
+		}
+		if err := g.ensureWithinOutput(outputPath); err != nil {
+			fmt.Printf("   ⚠️  Skipping section with unsafe path: %v\n", err)
+			return false, nil
+		}
+		if err := g.ensureParent(outputPath); err != nil {
+			return false, err
+		}
+		data := g.archiveData("section", node.Title, term, chunk.Posts, chunk.Pager, g.currentLang)
+		data["Title"] = node.Title
+		data["Description"] = node.Description
+		data["URL"] = node.URL
+		data["Content"] = g.contentContextValue(node.Page.Content)
+		sectionContext(data, node)
+		if err := g.renderTemplate(tmpl, outputPath, data); err != nil {
+			fmt.Printf("   ⚠️  Warning: failed to generate section %s: %v\n", node.URL, err)
+			return false, nil
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// sectionSite adds a guides/ section with a nested advanced/ one.
+func sectionSite(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	writeIncrementalSite(t, root)
+	pages := filepath.Join(root, "content", "site", "pages")
+	page := func(rel, fm string) {
+		mustWrite(t, filepath.Join(pages, rel), "---\nstatus: publish\ntype: page\n"+fm+"---\n\nBody.\n")
+	}
+	mustWrite(t, filepath.Join(pages, "guides", "_index.md"), "---\ntitle: The Guides\ndescription: How to.\n---\n\nStart *here*.\n")
+	page("guides/setup.md", "title: Setup\nslug: setup\nweight: 2\n")
+	page("guides/install.md", "title: Install\nslug: install\nweight: 1\n")
+	mustWrite(t, filepath.Join(pages, "guides", "advanced", "_index.md"), "---\ntitle: Advanced\n---\n")
+	page("guides/advanced/tuning.md", "title: Tuning\nslug: tuning\n")
+	// A folder without _index.md is transparent.
+	page("guides/misc/faq.md", "title: FAQ\nslug: faq\n")
+
+	theme := filepath.Join(root, "templates", "simple")
+	mustWrite(t, filepath.Join(theme, "page.html"),
+		`<html><h1>{{.Title}}</h1>{{range .Ancestors}}<c href="{{.URL}}">{{.Title}}</c>{{end}}`+
+			`{{with .Section}}<s>{{.Title}}</s>{{end}}</html>`)
+	mustWrite(t, filepath.Join(theme, "section.html"),
+		`<html><h1>{{.Title}}</h1>{{.Content}}{{range .Children}}<i>{{.Title}}</i>{{end}}`+
+			`{{range .Posts}}<p>{{.Title}}</p>{{end}}{{with .Pager.NextURL}}<n>{{.}}</n>{{end}}</html>`)
+	return root
+}
+
+func TestSectionsBuild(t *testing.T) {
+	root := sectionSite(t)
+	gen := buildScheduled(t, root, func(c *Config) { c.Paginate = 2 })
+	out := filepath.Join(root, "output")
+
+	read := func(rel string) string {
+		t.Helper()
+		raw, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(raw)
+	}
+	list := read("guides/index.html")
+	for _, want := range []string{
+		"<h1>The Guides</h1>", "<em>here</em>",
+		// Weighted first, then by title; subsections and pages alike.
+		"<i>Install</i><i>Setup</i><i>Advanced</i><i>FAQ</i>",
+		"<p>Install</p><p>Setup</p><n>/guides/page/2/</n>",
+	} {
+		if !strings.Contains(list, want) {
+			t.Errorf("/guides/ lacks %s:\n%s", want, list)
+		}
+	}
+	if second := read("guides/page/2/index.html"); !strings.Contains(second, "<p>FAQ</p>") {
+		t.Errorf("/guides/page/2/ should hold the rest:\n%s", second)
+	}
+	if nested := read("guides/advanced/index.html"); !strings.Contains(nested, "<i>Tuning</i>") {
+		t.Errorf("/guides/advanced/ lacks its page:\n%s", nested)
+	}
+	// URLs of the pages themselves do not move.
+	tuning := read("tuning/index.html")
+	if want := `<c href="/guides/">The Guides</c><c href="/guides/advanced/">Advanced</c><s>The Guides</s>`; !strings.Contains(tuning, want) {
+		t.Errorf("tuning lacks its trail %s:\n%s", want, tuning)
+	}
+	if _, err := os.Stat(filepath.Join(out, "_index")); err == nil {
+		t.Error("_index.md must not build as a page")
+	}
+
+	var page models.Page
+	for _, p := range gen.siteData.Pages {
+		if p.Slug == "tuning" {
+			page = p
+		}
+	}
+	items := gen.breadcrumbLD(page)["itemListElement"].([]interface{})
+	var names []string
+	for _, it := range items {
+		names = append(names, it.(map[string]interface{})["name"].(string))
+	}
+	if got := strings.Join(names, " › "); got != "Home › The Guides › Advanced › Tuning" {
+		t.Errorf("breadcrumb = %s", got)
+	}
+}
+
+func TestSectionsLandingPageOwnsURL(t *testing.T) {
+	root := sectionSite(t)
+	mustWrite(t, filepath.Join(root, "content", "site", "pages", "guides-home.md"),
+		"---\ntitle: Guides home\nlink: /guides/\nstatus: publish\ntype: page\n---\n\nMine.\n")
+	mustWrite(t, filepath.Join(root, "templates", "simple", "page.html"),
+		`<html><h1>{{.Title}}</h1>{{range .Children}}<i>{{.Title}}</i>{{end}}</html>`)
+	buildScheduled(t, root, nil)
+	raw, err := os.ReadFile(filepath.Join(root, "output", "guides", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), "<h1>Guides home</h1><i>Install</i>") {
+		t.Errorf("the page at /guides/ should render, with the section's children:\n%s", raw)
+	}
+}
@@ -463,16 +463,30 @@ func (g *Generator) renderTaxonomyPage(chain []string, outPath string, data inte
 // such a shell writes a whitespace-only page, which is never what a theme
 // author meant (GO-051), so shells count as absent and fallbacks apply.
 func (g *Generator) hasTemplate(name string) bool {
+	ok, reason := g.templateStatus(name)
+	g.explainLookup(name, ok, reason)
+	return ok
+}
+
+// templateStatus is hasTemplate with the reason a name counts as absent.
+func (g *Generator) templateStatus(name string) (bool, string) {
 	if g.engine != nil {
-		_, ok := g.engineTmpls[name]
-		return ok
+		if _, ok := g.engineTmpls[name]; !ok {
+			return false, "not among the theme's " + g.engine.Name() + " templates"
+		}
+		return true, ""
 	}
 	if g.tmpl == nil {
-		return false
+		return false, "no templates loaded"
 	}
 	t := g.tmpl.Lookup(name)
-	return t != nil && t.Tree != nil && t.Tree.Root != nil &&
-		strings.TrimSpace(t.Tree.Root.String()) != ""
+	if t == nil {
+		return false, "not in the theme"
+	}
+	if t.Tree == nil || t.Tree.Root == nil || strings.TrimSpace(t.Tree.Root.String()) == "" {
+		return false, "the file only defines other templates, so it counts as absent (GO-051)"
+	}
+	return true, ""
 }
 
 // taxonomyIndexChain is the index-page template fallback order: explicit
@@ -92,7 +92,8 @@ func (g *Generator) pageInCategory(p models.Page, name string) bool {
 	return false
 }
 
-// tmplByAuthor keeps pages by author ID, name or slug (case-insensitive):
+// tmplByAuthor keeps pages by author ID, name or slug (case-insensitive),
+// counting every author of a co-written page:
 //
 //	{{ .Site.Posts | byAuthor "jan-kowalski" }}
 func (g *Generator) tmplByAuthor(author string, collection any) (any, error) {
@@ -104,13 +105,13 @@ func (g *Generator) tmplByAuthor(author string, collection any) (any, error) {
 	byID := idErr == nil
 	out := make([]models.Page, 0, len(pages))
 	for _, p := range pages {
-		if byID && p.Author == wantID {
-			out = append(out, p)
-			continue
-		}
-		if a, ok := g.siteData.Authors[p.Author]; ok &&
-			(strings.EqualFold(a.Name, author) || strings.EqualFold(a.Slug, author)) {
-			out = append(out, p)
+		for _, id := range authorIDs(p) {
+			a, ok := g.siteData.Authors[id]
+			if (byID && id == wantID) ||
+				(ok && (strings.EqualFold(a.Name, author) || strings.EqualFold(a.Slug, author))) {
+				out = append(out, p)
+				break
+			}
 		}
 	}
 	return out, nil
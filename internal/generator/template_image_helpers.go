@@ -6,13 +6,16 @@
 package generator
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/spagu/ssg/internal/images"
+	"github.com/spagu/ssg/internal/models"
 )
 
 // imageProcessor lazily builds the shared processor. Source lookup order per
-// the spec: assets/ → static dir → the content source dir → the theme dir.
+// the spec: assets/ → static dir → the content source dir → the theme dir
+// and the themes it extends.
 //
 // Built under a sync.Once: the image* template helpers and shortcode templates
 // reach this from the render worker pool, so a plain check-then-assign would race
@@ -32,8 +35,9 @@ func (g *Generator) imageProcessor() *images.Processor {
 			"assets",
 			staticDir,
 			filepath.Join(g.config.ContentDir, g.config.Source),
-			filepath.Join(g.config.TemplatesDir, g.config.Template),
 		}
+		// The theme, then each theme it extends (theme_layers.go).
+		dirs = append(dirs, g.themeDirs()...)
 		for _, src := range g.config.ContentSources {
 			if src.Path != "" {
 				dirs = append(dirs, src.Path)
@@ -43,6 +47,12 @@ func (g *Generator) imageProcessor() *images.Processor {
 			SourceDirs: dirs,
 			OutputDir:  g.config.OutputDir,
 			Quiet:      g.config.Quiet,
+			// Looked up per request: the processor outlives one Generate call.
+			Observe: func(op images.Op) {
+				if g.prof != nil {
+					g.prof.observeImage(op)
+				}
+			},
 		})
 	})
 	return g.images
@@ -54,32 +64,85 @@ func (g *Generator) ImagesGC(dryRun bool) (int, int64, error) {
 	return g.imageProcessor().GC(dryRun)
 }
 
-func (g *Generator) tmplImageInfo(source string) (images.ImageInfo, error) {
-	return g.imageProcessor().Info(source)
+func (g *Generator) tmplImageInfo(source any) (images.ImageInfo, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImageInfo{}, err
+	}
+	return g.imageProcessor().Info(path)
+}
+
+func (g *Generator) tmplImageResize(source any, opts map[string]any) (images.ImageResult, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImageResult{}, err
+	}
+	return g.imageProcessor().ResizeDict(path, opts)
+}
+
+func (g *Generator) tmplImageCrop(source any, opts map[string]any) (images.ImageResult, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImageResult{}, err
+	}
+	return g.imageProcessor().CropDict(path, opts)
 }
 
-func (g *Generator) tmplImageResize(source string, opts map[string]any) (images.ImageResult, error) {
-	return g.imageProcessor().ResizeDict(source, opts)
+func (g *Generator) tmplImageProcess(source any, ops []any) (images.ImageResult, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImageResult{}, err
+	}
+	return g.imageProcessor().ProcessList(path, ops)
 }
 
-func (g *Generator) tmplImageCrop(source string, opts map[string]any) (images.ImageResult, error) {
-	return g.imageProcessor().CropDict(source, opts)
+func (g *Generator) tmplImageFilter(source any, filters []any, opts map[string]any) (images.ImageResult, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImageResult{}, err
+	}
+	return g.imageProcessor().FilterDict(path, filters, opts)
 }
 
-func (g *Generator) tmplImageProcess(source string, ops []any) (images.ImageResult, error) {
-	return g.imageProcessor().ProcessList(source, ops)
+func (g *Generator) tmplImageSrcSet(source any, opts map[string]any) (images.ImageSet, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImageSet{}, err
+	}
+	return g.imageProcessor().SrcSetDict(path, opts)
 }
 
-func (g *Generator) tmplImageFilter(source string, filters []any, opts map[string]any) (images.ImageResult, error) {
-	return g.imageProcessor().FilterDict(source, filters, opts)
+func (g *Generator) tmplImagePicture(source any, opts map[string]any) (images.ImagePicture, error) {
+	path, err := imageSource(source)
+	if err != nil {
+		return images.ImagePicture{}, err
+	}
+	return g.imageProcessor().PictureDict(path, opts)
 }
 
-func (g *Generator) tmplImageSrcSet(source string, opts map[string]any) (images.ImageSet, error) {
-	return g.imageProcessor().SrcSetDict(source, opts)
+// imageSource is the source argument every image helper takes: a path, or a
+// page resource — `imageResize (.Resources.Get "cover") (dict "width" 800)` —
+// which stands for its Path.
+func imageSource(v any) (string, error) {
+	switch s := v.(type) {
+	case string:
+		return s, nil
+	case models.Resource:
+		return resourceImagePath(&s)
+	case *models.Resource:
+		if s == nil {
+			return "", fmt.Errorf("image source is a missing resource")
+		}
+		return resourceImagePath(s)
+	}
+	return "", fmt.Errorf("image source must be a path or a resource, not %T", v)
 }
 
-func (g *Generator) tmplImagePicture(source string, opts map[string]any) (images.ImagePicture, error) {
-	return g.imageProcessor().PictureDict(source, opts)
+func resourceImagePath(r *models.Resource) (string, error) {
+	if r.Path == "" {
+		return "", fmt.Errorf("resource %q has no source file to process", r.Name)
+	}
+	return r.Path, nil
 }
 
 // imageFuncs returns the helper set shared by theme and shortcode templates.
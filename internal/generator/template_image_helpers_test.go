@@ -31,9 +31,11 @@ func writeTestPNG(t *testing.T, path string, w, h int) {
 	}
 }
 
-// imageTestGen wires a Generator whose static dir holds pic.png.
+// imageTestGen wires a Generator whose static dir holds pic.png. It runs in a
+// temporary working directory: the image cache (.ssg-cache/) is relative to it.
 func imageTestGen(t *testing.T) *Generator {
 	t.Helper()
+	t.Chdir(t.TempDir())
 	g := newTestGen(t, "")
 	staticDir := t.TempDir()
 	writeTestPNG(t, filepath.Join(staticDir, "pic.png"), 64, 32)
@@ -0,0 +1,285 @@
+package generator
+
+// Theme inheritance: a theme.yaml with `extends: <theme>` makes a theme a
+// child of another — a bundled theme (simple, krowy), one under templates_dir,
+// or one online_theme installed there. Customizing simple used to mean copying
+// all of it and never seeing its fixes again; a child holds only what it
+// changes.
+//
+// Every lookup resolves child first, then up the parent chain: role templates,
+// layouts/, partials/ and the {{define}} blocks inside them (a child file that
+// only redefines "content" keeps the parent's page.html and swaps its block),
+// alt-engine templates by file name, shortcode and _markup/ templates, and
+// css/, js/ and images/, which are copied parent first so the child's files
+// land last. A bundled parent is read from the binary, extracted once per
+// binary into .ssg-cache/themes/.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	ssgroot "github.com/spagu/ssg"
+	"github.com/spagu/ssg/internal/cache"
+	"gopkg.in/yaml.v3"
+)
+
+// themeManifestName is the file a theme names its parent in.
+const themeManifestName = "theme.yaml"
+
+// themeManifest is a theme's theme.yaml.
+type themeManifest struct {
+	Extends string `yaml:"extends"`
+}
+
+// themeLayer is one theme of an inheritance chain.
+type themeLayer struct {
+	Name     string // the name it was configured or extended by
+	Dir      string
+	Embedded bool // a bundled theme, extracted from the binary
+}
+
+// ThemeOverride is a template or {{define}} block a child theme redefines.
+type ThemeOverride struct {
+	Name      string `json:"name" yaml:"name"`
+	Theme     string `json:"theme" yaml:"theme"`         // the theme whose definition wins
+	Overrides string `json:"overrides" yaml:"overrides"` // the parent it replaces
+}
+
+// resolveThemeLayers follows theme.yaml from the configured theme up its
+// parents and returns the chain, child first. A theme without a theme.yaml
+// is a chain of one.
+func resolveThemeLayers(templatesDir, theme string) ([]themeLayer, error) {
+	layers := []themeLayer{{Name: theme, Dir: filepath.Join(templatesDir, theme)}}
+	seen := map[string]bool{filepath.Clean(layers[0].Dir): true}
+	for {
+		child := layers[len(layers)-1]
+		parent, err := readThemeExtends(child.Dir)
+		if err != nil || parent == "" {
+			return layers, err
+		}
+		layer, err := resolveThemeParent(templatesDir, parent, seen)
+		if err != nil {
+			names := make([]string, len(layers))
+			for i, l := range layers {
+				names[i] = l.Name
+			}
+			return layers, fmt.Errorf("theme %s (%s): %w", child.Name, strings.Join(append(names, parent), " → "), err)
+		}
+		seen[filepath.Clean(layer.Dir)] = true
+		layers = append(layers, layer)
+	}
+}
+
+// readThemeExtends returns the parent a theme's theme.yaml names, or "".
+func readThemeExtends(dir string) (string, error) {
+	path := filepath.Join(dir, themeManifestName)
+	raw, err := os.ReadFile(path) // #nosec G304 -- the project's own theme
+	if err != nil {
+		return "", nil
+	}
+	var m themeManifest
+	if err := yaml.Unmarshal(raw, &m); err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	return strings.TrimSpace(m.Extends), nil
+}
+
+// resolveThemeParent finds the theme extends names: a directory under
+// templates_dir, unless that directory is already in the chain (a local
+// "simple" extending simple means the bundled one), then a bundled theme.
+func resolveThemeParent(templatesDir, name string, seen map[string]bool) (themeLayer, error) {
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return themeLayer{}, fmt.Errorf("extends %q: a theme is named by its directory under templates_dir", name)
+	}
+	local := filepath.Join(templatesDir, filepath.FromSlash(name))
+	if info, err := os.Stat(local); err == nil && info.IsDir() && !seen[filepath.Clean(local)] {
+		return themeLayer{Name: name, Dir: local}, nil
+	}
+	if dir, ok, err := embeddedThemeDir(name); err != nil {
+		return themeLayer{}, err
+	} else if ok {
+		if seen[filepath.Clean(dir)] {
+			return themeLayer{}, fmt.Errorf("extends %q, which is already in the chain", name)
+		}
+		return themeLayer{Name: name, Dir: dir, Embedded: true}, nil
+	}
+	if seen[filepath.Clean(local)] {
+		return themeLayer{}, fmt.Errorf("extends %q, which is already in the chain", name)
+	}
+	return themeLayer{}, fmt.Errorf("extends %q, which is neither a directory in %s nor a bundled theme", name, templatesDir)
+}
+
+var (
+	embeddedThemeMu   sync.Mutex
+	embeddedThemeDirs = map[string]string{}
+)
+
+// embeddedThemeDir extracts a bundled theme for use as a parent and returns
+// its directory. The directory is keyed by the theme's content, so a binary
+// with different bundled files never reads another's extraction.
+func embeddedThemeDir(name string) (string, bool, error) {
+	name = strings.ToLower(name)
+	root := "templates/" + name
+	if entries, err := ssgroot.EmbeddedThemes.ReadDir(root); err != nil || len(entries) == 0 {
+		return "", false, nil
+	}
+	embeddedThemeMu.Lock()
+	defer embeddedThemeMu.Unlock()
+	if dir, ok := embeddedThemeDirs[name]; ok {
+		if _, err := os.Stat(dir); err == nil {
+			return dir, true, nil
+		}
+	}
+	h := sha256.New()
+	_ = fs.WalkDir(ssgroot.EmbeddedThemes, root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			data, _ := ssgroot.EmbeddedThemes.ReadFile(path)
+			h.Write([]byte(path + "\x00"))
+			h.Write(data)
+		}
+		return nil
+	})
+	dir := cache.Dir("", filepath.Join("themes", name+"-"+hex.EncodeToString(h.Sum(nil))[:12]))
+	if _, err := os.Stat(dir); err != nil {
+		tmp := dir + ".tmp"
+		_ = os.RemoveAll(tmp)
+		if ok, err := scaffoldEmbeddedTheme(name, tmp); err != nil || !ok {
+			return "", false, err
+		}
+		if err := os.Rename(tmp, dir); err != nil {
+			return "", false, fmt.Errorf("extracting bundled theme %s: %w", name, err)
+		}
+	}
+	embeddedThemeDirs[name] = dir
+	return dir, true, nil
+}
+
+// themeChain is the build's theme chain, child first. It is resolved by
+// loadTemplates; before that, or when the chain is broken (loadTemplates
+// reports it), it is the configured theme alone.
+func (g *Generator) themeChain() []themeLayer {
+	if g.layers != nil {
+		return g.layers
+	}
+	layers, err := resolveThemeLayers(g.config.TemplatesDir, g.config.Template)
+	if err != nil {
+		return layers[:1]
+	}
+	return layers
+}
+
+// themeDirs is themeChain's directories, child first.
+func (g *Generator) themeDirs() []string {
+	chain := g.themeChain()
+	dirs := make([]string, len(chain))
+	for i, l := range chain {
+		dirs[i] = l.Dir
+	}
+	return dirs
+}
+
+// themeFile resolves a path inside the theme to the nearest layer that has
+// it, or to the child theme's path when none does.
+func (g *Generator) themeFile(rel string) string {
+	dirs := g.themeDirs()
+	for _, dir := range dirs {
+		path := filepath.Join(dir, rel)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dirs[0], rel)
+}
+
+// parseThemeLayers parses the Go templates of every layer into one set,
+// farthest parent first, so a child's definition replaces its parent's. Each
+// definition a later layer replaced is returned as an override.
+func parseThemeLayers(layers []themeLayer, funcs template.FuncMap) (*template.Template, []ThemeOverride, error) {
+	tmpl := template.New("").Funcs(funcs)
+	owner := map[string]string{}
+	var overrides []ThemeOverride
+	parsed := 0
+	for i := len(layers) - 1; i >= 0; i-- {
+		layer := layers[i]
+		before := map[string]interface{}{}
+		for _, t := range tmpl.Templates() {
+			before[t.Name()] = t.Tree
+		}
+		// layouts/ and partials/ as well as the root: partials/ holds the
+		// {{define}} blocks a theme shares between roles (DOC-014).
+		for _, sub := range []string{"", "layouts", "partials"} {
+			files, _ := filepath.Glob(filepath.Join(layer.Dir, sub, htmlGlobPattern))
+			if len(files) == 0 {
+				continue
+			}
+			var err error
+			if tmpl, err = tmpl.ParseFiles(files...); err != nil {
+				if sub == "" {
+					return nil, nil, fmt.Errorf("parsing templates: %w", err)
+				}
+				return nil, nil, fmt.Errorf("parsing %s templates: %w", sub, err)
+			}
+			parsed += len(files)
+		}
+		for _, t := range tmpl.Templates() {
+			old, existed := before[t.Name()]
+			if t.Name() == "" || (existed && old == interface{}(t.Tree)) {
+				continue
+			}
+			if existed {
+				overrides = append(overrides, ThemeOverride{Name: t.Name(), Theme: layer.Name, Overrides: owner[t.Name()]})
+			}
+			owner[t.Name()] = layer.Name
+		}
+	}
+	if parsed == 0 {
+		return nil, nil, fmt.Errorf("parsing templates: html/template: pattern matches no files: %#q", filepath.Join(layers[0].Dir, htmlGlobPattern))
+	}
+	return tmpl, overrides, nil
+}
+
+// hasThemeManifest reports whether dir is a theme that declares a theme.yaml.
+func hasThemeManifest(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, themeManifestName))
+	return err == nil
+}
+
+// childThemeManifest is the theme.yaml ensureTemplates writes for a bundled
+// theme: the site's theme starts empty and inherits everything.
+const childThemeManifest = `# A child theme: every template, partial, stylesheet, script and image not
+# in this directory comes from the theme named below. To change one, create a
+# file of the same name here (css/style.css, page.html, partials/…), or
+# redefine a single {{define}} block in a file of your own.
+extends: %s
+`
+
+// scaffoldChildTheme writes a child-theme skeleton extending a bundled theme
+// into templatePath. It reports false when name is not bundled.
+func scaffoldChildTheme(name, templatePath string) (bool, error) {
+	if _, err := ssgroot.EmbeddedThemes.ReadDir("templates/" + strings.ToLower(name)); err != nil {
+		return false, nil
+	}
+	// #nosec G306 -- Web content files need to be world-readable
+	if err := os.WriteFile(filepath.Join(templatePath, themeManifestName), []byte(fmt.Sprintf(childThemeManifest, strings.ToLower(name))), 0644); err != nil {
+		return false, fmt.Errorf("creating %s: %w", themeManifestName, err)
+	}
+	return true, nil
+}
+
+// OnlineThemeDir is where online_theme installs for theme: the parent a
+// theme.yaml extends, so a download never lands on the child theme, or the
+// theme itself.
+func OnlineThemeDir(templatesDir, theme string) string {
+	dir := filepath.Join(templatesDir, theme)
+	if parent, err := readThemeExtends(dir); err == nil && parent != "" && parent != theme && filepath.IsLocal(filepath.FromSlash(parent)) {
+		return filepath.Join(templatesDir, filepath.FromSlash(parent))
+	}
+	return dir
+}
@@ -0,0 +1,153 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// childThemeSite is a site whose "mine" theme extends a local "base": the
+// child redefines only the "content" block of page.html and the stylesheet.
+func childThemeSite(t *testing.T) (*Generator, string) {
+	t.Helper()
+	tmp := t.TempDir()
+	site := filepath.Join(tmp, "content", "site")
+	mustWrite(t, filepath.Join(site, "metadata.json"), `{"categories":[],"exported_at":"","media":[]}`)
+	mustWrite(t, filepath.Join(site, "pages", "about.md"),
+		"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\nAbout us. {{promo}} [Home](/)\n")
+	base := filepath.Join(tmp, "templates", "base")
+	for name, body := range map[string]string{
+		"index.html":               "<h1>{{ .Domain }}</h1>",
+		"page.html":                `{{ template "header" . }}<main>{{ block "content" . }}parent {{ .Title }}{{ end }}</main>`,
+		"partials/head.html":       `{{ define "header" }}<header>base</header>{{ end }}`,
+		"promo.html":               "<aside>promo</aside>",
+		"css/style.css":            "/* base */",
+		"js/main.js":               "// base",
+		"_markup/render-link.html": `<a class="base" href="{{ .Destination }}">{{ .Text }}</a>`,
+	} {
+		mustWrite(t, filepath.Join(base, name), body)
+	}
+	mine := filepath.Join(tmp, "templates", "mine")
+	mustWrite(t, filepath.Join(mine, "theme.yaml"), "extends: base\n")
+	mustWrite(t, filepath.Join(mine, "page.html"), `{{ define "content" }}child {{ .Title }}{{ .Content }}{{ end }}`)
+	mustWrite(t, filepath.Join(mine, "css", "style.css"), "/* mine */")
+	gen, err := New(Config{Source: "site", Template: "mine", Domain: "example.com",
+		ContentDir: filepath.Join(tmp, "content"), TemplatesDir: filepath.Join(tmp, "templates"),
+		OutputDir: filepath.Join(tmp, "output"), Quiet: true,
+		Shortcodes: []Shortcode{{Name: "promo", Template: "promo.html"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return gen, tmp
+}
+
+// TestChildThemeLocalParent: the child's block replaces the parent's inside
+// the parent's page.html, the parent's partials, shortcode and render-hook
+// templates are found, and assets merge with the child's winning.
+func TestChildThemeLocalParent(t *testing.T) {
+	gen, tmp := childThemeSite(t)
+	if err := gen.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(tmp, "output")
+	wantContains(t, "about", mustRead(t, filepath.Join(out, "about", "index.html")),
+		"<header>base</header>", "<main>child About", "<aside>promo</aside>", `<a class="base" href="/">Home</a>`)
+	if got := mustRead(t, filepath.Join(out, "css", "style.css")); got != "/* mine */" {
+		t.Errorf("css/style.css = %q, want the child's", got)
+	}
+	if got := mustRead(t, filepath.Join(out, "js", "main.js")); got != "// base" {
+		t.Errorf("js/main.js = %q, want the parent's", got)
+	}
+	if len(gen.themeOverrides) != 1 || gen.themeOverrides[0] != (ThemeOverride{Name: "content", Theme: "mine", Overrides: "base"}) {
+		t.Errorf("overrides = %+v", gen.themeOverrides)
+	}
+}
+
+// TestChildThemeExplain: ssg explain names the chain and the block a child
+// overrides, only for outputs whose template reaches it.
+func TestChildThemeExplain(t *testing.T) {
+	gen, _ := childThemeSite(t)
+	ex, err := gen.Explain("/about/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ex.Theme, ",") != "mine,base" {
+		t.Errorf("theme = %v", ex.Theme)
+	}
+	if len(ex.Overrides) != 1 || ex.Overrides[0].Name != "content" {
+		t.Errorf("overrides = %+v", ex.Overrides)
+	}
+
+	gen, _ = childThemeSite(t)
+	if ex, err = gen.Explain("/"); err != nil {
+		t.Fatal(err)
+	}
+	if len(ex.Overrides) != 0 {
+		t.Errorf("index.html reaches no override, got %+v", ex.Overrides)
+	}
+}
+
+// TestChildThemeBundledParent: a theme extending a bundled theme builds from
+// the bundled templates and assets, extracted outside the project's theme.
+func TestChildThemeBundledParent(t *testing.T) {
+	tmp := t.TempDir()
+	t.Chdir(tmp)
+	mustWrite(t, filepath.Join(tmp, "content", "site", "metadata.json"), `{"categories":[],"exported_at":"","media":[]}`)
+	mustWrite(t, filepath.Join(tmp, "content", "site", "pages", "about.md"),
+		"---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\nAbout us.\n")
+	mustWrite(t, filepath.Join(tmp, "templates", "mine", "theme.yaml"), "extends: simple\n")
+	gen, err := New(Config{Source: "site", Template: "mine", Domain: "example.com",
+		ContentDir: "content", TemplatesDir: "templates", OutputDir: "output", Quiet: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := gen.Generate(); err != nil {
+		t.Fatal(err)
+	}
+	wantContains(t, "about", mustRead(t, filepath.Join(tmp, "output", "about", "index.html")), "About us.")
+	if _, err := os.Stat(filepath.Join(tmp, "output", "css", "style.css")); err != nil {
+		t.Errorf("the bundled stylesheet must be published: %v", err)
+	}
+	if entries, _ := os.ReadDir(filepath.Join(tmp, "templates", "mine")); len(entries) != 1 {
+		t.Errorf("the child theme must not be filled in, has %d entries", len(entries))
+	}
+	if layers := gen.themeChain(); len(layers) != 2 || !layers[1].Embedded {
+		t.Errorf("chain = %+v", layers)
+	}
+}
+
+// TestResolveThemeLayersErrors: a cycle, a missing parent and a path that
+// leaves templates_dir are reported with the chain that led to them.
+func TestResolveThemeLayersErrors(t *testing.T) {
+	tmp := t.TempDir()
+	mustWrite(t, filepath.Join(tmp, "a", "theme.yaml"), "extends: b\n")
+	mustWrite(t, filepath.Join(tmp, "b", "theme.yaml"), "extends: a\n")
+	mustWrite(t, filepath.Join(tmp, "lost", "theme.yaml"), "extends: nowhere\n")
+	mustWrite(t, filepath.Join(tmp, "out", "theme.yaml"), "extends: ../elsewhere\n")
+	for theme, want := range map[string]string{
+		"a":    "theme b (a → b → a): extends \"a\", which is already in the chain",
+		"lost": "neither a directory in",
+		"out":  "named by its directory under templates_dir",
+	} {
+		if _, err := resolveThemeLayers(tmp, theme); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: err = %v, want %q", theme, err, want)
+		}
+	}
+	if layers, err := resolveThemeLayers(tmp, "plain"); err != nil || len(layers) != 1 {
+		t.Errorf("a theme without theme.yaml is a chain of one: %+v, %v", layers, err)
+	}
+}
+
+// TestOnlineThemeDir: a download for a child theme goes to the parent it
+// extends, so the child's own files survive it.
+func TestOnlineThemeDir(t *testing.T) {
+	tmp := t.TempDir()
+	mustWrite(t, filepath.Join(tmp, "mine", "theme.yaml"), "extends: upstream\n")
+	if got := OnlineThemeDir(tmp, "mine"); got != filepath.Join(tmp, "upstream") {
+		t.Errorf("child theme: %s", got)
+	}
+	if got := OnlineThemeDir(tmp, "plain"); got != filepath.Join(tmp, "plain") {
+		t.Errorf("plain theme: %s", got)
+	}
+}
@@ -0,0 +1,539 @@
+package generator
+
+// Versioned documentation (`versions:`): each entry names a git ref whose
+// content tree is checked out into a temporary worktree and built, with the
+// site's own theme and config, under /<name>/. An entry without a ref stands
+// for the working tree — the ordinary build at the site root — so a list can
+// say where "dev" lives and what to call it in a switcher.
+//
+// A version build is a full Generate with CurrentVersion set, into its own
+// subdirectory; root-relative URLs in its HTML and CSS are prefixed with
+// /<name> afterwards, since the build itself knows nothing of where it is
+// published. Every page outside the latest version gets a canonical link to
+// the same page in the latest one, and versions.json lists them all. A built
+// version is kept in .ssg-cache/versions/ under a key of its commit, the
+// config and the theme, so a release tag that has not moved is copied, not
+// rebuilt.
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spagu/ssg/internal/cache"
+	"github.com/spagu/ssg/internal/models"
+)
+
+// Version is one `versions:` entry.
+type Version struct {
+	Name   string // the /<name>/ path segment
+	Ref    string // tag, branch or commit; "" = the working tree, at the site root
+	Title  string // switcher label; defaults to Name
+	Latest bool   // the version canonical links point to; defaults to the first entry
+}
+
+// versionCacheFormat is bumped whenever what a cached version holds changes.
+const versionCacheFormat = "1"
+
+var versionNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// validateVersions rejects a list whose output would collide: names must be
+// unique path segments, and only one entry can be the root or the latest.
+func validateVersions(versions []Version) error {
+	seen := map[string]bool{}
+	root, latest := false, false
+	for _, v := range versions {
+		if !versionNameRe.MatchString(v.Name) {
+			return fmt.Errorf("versions: %q is not a valid name (letters, digits, '.', '_' and '-')", v.Name)
+		}
+		if seen[v.Name] {
+			return fmt.Errorf("versions: %q is listed twice", v.Name)
+		}
+		seen[v.Name] = true
+		if strings.HasPrefix(v.Ref, "-") {
+			return fmt.Errorf("versions: %s: ref %q is not a git ref", v.Name, v.Ref)
+		}
+		if v.Ref == "" {
+			if root {
+				return fmt.Errorf("versions: %s: only one entry can omit ref (the working tree)", v.Name)
+			}
+			root = true
+		}
+		if v.Latest {
+			if latest {
+				return fmt.Errorf("versions: %s: only one entry can be latest", v.Name)
+			}
+			latest = true
+		}
+	}
+	return nil
+}
+
+// latestVersion is the index of the latest entry: the one marked latest,
+// else the first.
+func latestVersion(versions []Version) int {
+	for i, v := range versions {
+		if v.Latest {
+			return i
+		}
+	}
+	return 0
+}
+
+// versionPath is where a version is published: "/" for the working tree,
+// "/<name>/" for a ref.
+func versionPath(v Version) string {
+	if v.Ref == "" {
+		return "/"
+	}
+	return "/" + v.Name + "/"
+}
+
+// rootDomain is the site's own domain — a version build runs with
+// "<domain>/<name>" so every absolute URL it writes lands in its subtree.
+func (g *Generator) rootDomain() string {
+	if g.config.CurrentVersion == "" {
+		return g.config.Domain
+	}
+	return strings.TrimSuffix(g.config.Domain, "/"+g.config.CurrentVersion)
+}
+
+// absoluteURL makes a site path absolute on the root domain, or leaves it
+// site-relative when no domain is configured.
+func (g *Generator) absoluteURL(p string) string {
+	if d := g.rootDomain(); d != "" {
+		return httpsScheme + d + p
+	}
+	return p
+}
+
+// siteVersions is .Versions: the configured list, for a version switcher.
+func (g *Generator) siteVersions() []models.Version {
+	if len(g.config.Versions) == 0 {
+		return nil
+	}
+	latest := latestVersion(g.config.Versions)
+	out := make([]models.Version, 0, len(g.config.Versions))
+	for i, v := range g.config.Versions {
+		title := v.Title
+		if title == "" {
+			title = v.Name
+		}
+		current := v.Name == g.config.CurrentVersion || (v.Ref == "" && g.config.CurrentVersion == "")
+		out = append(out, models.Version{
+			Name: v.Name, Title: title, Ref: v.Ref, URL: g.absoluteURL(versionPath(v)),
+			Latest: i == latest, Current: current,
+		})
+	}
+	return out
+}
+
+// currentVersion is .CurrentVersion: the entry being rendered, or nil when
+// the site has no versions or the root build has no entry of its own.
+func currentVersion(versions []models.Version) *models.Version {
+	for i := range versions {
+		if versions[i].Current {
+			return &versions[i]
+		}
+	}
+	return nil
+}
+
+// sitePath is a root-relative URL as it is served: under /<name> in a version
+// build. Rendered HTML gets the prefix from prefixVersionURLs; this is for the
+// JSON files (search index, link graph) that carry bare page URLs.
+func (g *Generator) sitePath(u string) string {
+	if g.config.CurrentVersion == "" || !strings.HasPrefix(u, "/") {
+		return u
+	}
+	return "/" + g.config.CurrentVersion + u
+}
+
+// clearVersionOutputs drops the version subtrees a previous build left behind
+// before anything walks the output: fingerprinting and bundling rewrite by
+// path across the whole tree, and must see only the root build's files.
+func (g *Generator) clearVersionOutputs() error {
+	if g.config.CurrentVersion != "" {
+		return nil
+	}
+	for _, v := range g.config.Versions {
+		if v.Ref == "" {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(g.config.OutputDir, v.Name)); err != nil {
+			return fmt.Errorf("clearing version %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// versionEntry is one versions.json entry.
+type versionEntry struct {
+	Name   string `json:"name"`
+	Title  string `json:"title"`
+	Ref    string `json:"ref,omitempty"`
+	Commit string `json:"commit,omitempty"`
+	URL    string `json:"url"`
+	Latest bool   `json:"latest"`
+}
+
+// generateVersions builds every ref entry under /<name>/, points canonical
+// links at the latest version and writes versions.json. Only the root build
+// does this; a version build has nothing to build below it.
+func (g *Generator) generateVersions() error {
+	if g.config.CurrentVersion != "" || len(g.config.Versions) == 0 {
+		return nil
+	}
+	g.log("🗂️  Building versions...")
+	// Writes whole sites below the root and rewrites the root's pages.
+	g.noteUntracked("versions")
+	var top string
+	commits := map[string]string{}
+	for _, v := range g.config.Versions {
+		if v.Ref == "" {
+			continue
+		}
+		if top == "" {
+			root, err := gitOutput(".", "rev-parse", "--show-toplevel")
+			if err != nil {
+				return fmt.Errorf("versions need a git repository: %w", err)
+			}
+			top = root
+		}
+		commit, err := gitOutput(top, "rev-parse", "--verify", "--quiet", v.Ref+"^{commit}")
+		if err != nil {
+			return fmt.Errorf("version %s: unknown ref %q", v.Name, v.Ref)
+		}
+		commits[v.Name] = commit
+		if err := g.buildVersion(top, v, commit); err != nil {
+			return fmt.Errorf("version %s: %w", v.Name, err)
+		}
+	}
+	if err := g.canonicalizeVersions(); err != nil {
+		return err
+	}
+	return g.writeVersionsManifest(commits)
+}
+
+// buildVersion publishes one ref under OutputDir/<name>, from the cache when
+// its key matches, else through a fresh build in a temporary worktree.
+func (g *Generator) buildVersion(top string, v Version, commit string) error {
+	out := filepath.Join(g.config.OutputDir, v.Name)
+	dir := cache.Dir("", filepath.Join("versions", v.Name))
+	key := g.versionKey(top, v, commit)
+	if stored, err := os.ReadFile(filepath.Join(dir, "key")); err == nil && string(stored) == key { // #nosec G304 -- our own cache file
+		if err := g.copyDir(filepath.Join(dir, "site"), out); err == nil {
+			g.log(fmt.Sprintf("   🏷️  %s (%s @ %.7s, cached)", v.Name, v.Ref, commit))
+			return nil
+		}
+		_ = os.RemoveAll(out) // a damaged copy is rebuilt below
+	}
+	g.log(fmt.Sprintf("   🏷️  %s (%s @ %.7s)", v.Name, v.Ref, commit))
+
+	tmp, err := os.MkdirTemp("", "ssg-version-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	tree := filepath.Join(tmp, "tree")
+	if _, err := gitOutput(top, "worktree", "add", "--detach", "--quiet", tree, commit); err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = gitOutput(top, "worktree", "remove", "--force", tree)
+		_, _ = gitOutput(top, "worktree", "prune")
+	}()
+
+	sub, err := New(g.versionConfig(top, tree, v))
+	if err != nil {
+		return err
+	}
+	if err := sub.Generate(); err != nil {
+		return err
+	}
+	if err := prefixVersionURLs(out, v.Name); err != nil {
+		return err
+	}
+
+	// The cache is an optimisation: failing to fill it costs the next build
+	// a rebuild, never this one its output.
+	_ = os.RemoveAll(dir)
+	if err := g.copyDir(out, filepath.Join(dir, "site")); err == nil {
+		_ = os.WriteFile(filepath.Join(dir, "key"), []byte(key), 0644) // #nosec G306
+	}
+	return nil
+}
+
+// versionConfig is the root config retargeted at one version: content read
+// from the worktree, output into the subtree, absolute URLs on
+// <domain>/<name>. What only the root build should do — robots.txt, host
+// files, hooks, notifications, the output checks, which run once over the
+// finished tree — is switched off.
+func (g *Generator) versionConfig(top, tree string, v Version) Config {
+	cfg := g.config
+	cfg.CurrentVersion = v.Name
+	cfg.OutputDir = filepath.Join(g.config.OutputDir, v.Name)
+	if cfg.Domain != "" {
+		cfg.Domain += "/" + v.Name
+	}
+	cfg.ContentDir = intoWorktree(cfg.ContentDir, top, tree)
+	cfg.ContentSources = append([]ContentSource(nil), g.config.ContentSources...)
+	for i := range cfg.ContentSources {
+		// A git source lives in another repository, which our refs do not
+		// version: every version reads the same checkout.
+		if cfg.ContentSources[i].Type == "git" {
+			continue
+		}
+		cfg.ContentSources[i].Path = intoWorktree(cfg.ContentSources[i].Path, top, tree)
+	}
+	cfg.Clean, cfg.Quiet = true, true
+	cfg.Incremental, cfg.ChangedPaths, cfg.Profile = false, nil, ""
+	cfg.Hooks, cfg.Notify = nil, nil
+	cfg.RobotsOff, cfg.LastmodFromGit = true, false
+	cfg.Mddb = MddbConfig{}
+	cfg.Headers, cfg.Redirects, cfg.Deploy = nil, nil, ""
+	cfg.Worker, cfg.Workers = WorkerConfig{}, nil
+	cfg.CheckLinks, cfg.CheckImages, cfg.CheckMarkup, cfg.CheckMeta = "", "", "", ""
+	cfg.CheckSchema, cfg.CheckOrphans, cfg.CheckRedirects, cfg.CheckProse, cfg.CheckSpelling = "", "", "", "", ""
+	return cfg
+}
+
+// intoWorktree maps a path inside the repository onto the same path in the
+// worktree. A path outside it is read as it is: the ref cannot hold it.
+func intoWorktree(p, top, tree string) string {
+	if p == "" || tree == "" {
+		return p
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	if real, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = real
+	}
+	rel, err := filepath.Rel(top, abs)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return p
+	}
+	return filepath.Join(tree, rel)
+}
+
+// versionKey is what a cached version is valid for: the commit, the config it
+// was built with (paths as they are outside the worktree), the theme, data and
+// static files it used, and any content read from outside the repository.
+func (g *Generator) versionKey(top string, v Version, commit string) string {
+	k := cache.NewKeyer(versionCacheFormat, 0)
+	k.WriteDelim(commit)
+	k.WriteDelim(configFingerprint(g.versionConfig(top, "", v)))
+	content, theme, data := g.inputRoots()
+	static := g.config.StaticDir
+	if static == "" {
+		static = defaultStaticDir
+	}
+	roots := []string{theme, data, static}
+	for _, root := range content {
+		if intoWorktree(root, top, "tree") == root {
+			roots = append(roots, root)
+		}
+	}
+	inputs := hashInputs(roots, nil, nil)
+	paths := make([]string, 0, len(inputs))
+	for p := range inputs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		k.WriteDelim(p)
+		k.WriteDelim(inputs[p].Hash)
+	}
+	return k.Sum()
+}
+
+var (
+	versionAttrRe   = regexp.MustCompile(`\b(href|src|action|poster|data-src)=(["']?)/([^/])`)
+	versionSrcsetRe = regexp.MustCompile(`\bsrcset=(["'])([^"']*)(["'])`)
+	versionCSSURLRe = regexp.MustCompile(`url\((["']?)/([^/])`)
+)
+
+// prefixVersionURLs moves the root-relative URLs of a finished version build
+// under /<name>: link, asset and form attributes, srcset candidates and CSS
+// url()s. Protocol-relative (//host) and absolute URLs are left alone, and so
+// is a URL already under the prefix.
+func prefixVersionURLs(dir, name string) error {
+	prefix := "/" + name
+	under := func(rest string) bool { return strings.HasPrefix("/"+rest, prefix+"/") }
+	return filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		ext := strings.ToLower(filepath.Ext(p))
+		if ext != ".html" && ext != ".htm" && ext != ".css" {
+			return nil
+		}
+		raw, err := os.ReadFile(p) // #nosec G304 -- walking our own output
+		if err != nil {
+			return err
+		}
+		src := string(raw)
+		out := versionCSSURLRe.ReplaceAllStringFunc(src, func(m string) string {
+			sub := versionCSSURLRe.FindStringSubmatch(m)
+			rest := m[len("url("+sub[1]+"/"):]
+			if under(rest) {
+				return m
+			}
+			return "url(" + sub[1] + prefix + "/" + rest
+		})
+		if ext != ".css" {
+			out = versionAttrRe.ReplaceAllStringFunc(out, func(m string) string {
+				sub := versionAttrRe.FindStringSubmatch(m)
+				rest := m[len(sub[1]+"="+sub[2]+"/"):]
+				if under(rest) {
+					return m
+				}
+				return sub[1] + "=" + sub[2] + prefix + "/" + rest
+			})
+			out = versionSrcsetRe.ReplaceAllStringFunc(out, func(m string) string {
+				sub := versionSrcsetRe.FindStringSubmatch(m)
+				candidates := strings.Split(sub[2], ",")
+				for i, c := range candidates {
+					trimmed := strings.TrimLeft(c, " \t\n")
+					if strings.HasPrefix(trimmed, "/") && !strings.HasPrefix(trimmed, "//") && !strings.HasPrefix(trimmed, prefix+"/") {
+						candidates[i] = c[:len(c)-len(trimmed)] + prefix + trimmed
+					}
+				}
+				return "srcset=" + sub[1] + strings.Join(candidates, ",") + sub[3]
+			})
+		}
+		if out == src {
+			return nil
+		}
+		return os.WriteFile(p, []byte(out), 0644) // #nosec G306 G703 -- rewriting our own output
+	})
+}
+
+var canonicalLinkRe = regexp.MustCompile(`(?i)<link\b[^>]*\brel=["']?canonical["']?[^>]*>`)
+
+// canonicalizeVersions points every page of every version but the latest at
+// the same page in the latest version, where it exists there: search engines
+// then rank one copy of the docs, and it is the current one. A page the latest
+// version dropped keeps its own canonical.
+func (g *Generator) canonicalizeVersions() error {
+	versions := g.config.Versions
+	latest := versions[latestVersion(versions)]
+	treeOf := func(v Version) string {
+		if v.Ref == "" {
+			return g.config.OutputDir
+		}
+		return filepath.Join(g.config.OutputDir, v.Name)
+	}
+	skip := map[string]bool{} // version subtrees, when walking the root tree
+	for _, v := range versions {
+		if v.Ref != "" {
+			skip[v.Name] = true
+		}
+	}
+	// The root tree is rewritten when it is not the latest, whether or not an
+	// entry names it.
+	var trees []string
+	if latest.Ref != "" {
+		trees = append(trees, g.config.OutputDir)
+	}
+	for _, v := range versions {
+		if v.Ref != "" && v.Name != latest.Name {
+			trees = append(trees, treeOf(v))
+		}
+	}
+	latestTree, latestPath := treeOf(latest), versionPath(latest)
+	for _, tree := range trees {
+		root := tree == g.config.OutputDir
+		err := filepath.WalkDir(tree, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(tree, p)
+			if d.IsDir() {
+				if root && skip[rel] {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if !strings.HasSuffix(p, ".html") {
+				return nil
+			}
+			if _, err := os.Stat(filepath.Join(latestTree, rel)); err != nil {
+				return nil //nolint:nilerr // not in the latest version: keeps its own canonical
+			}
+			target := g.absoluteURL(strings.TrimSuffix(latestPath, "/") + urlForOutputFile(rel))
+			return setCanonical(p, target)
+		})
+		if err != nil {
+			return fmt.Errorf("canonical links: %w", err)
+		}
+	}
+	return nil
+}
+
+// setCanonical replaces a page's canonical link, or adds one to its head.
+func setCanonical(file, target string) error {
+	raw, err := os.ReadFile(file) // #nosec G304 -- walking our own output
+	if err != nil {
+		return err
+	}
+	tag := `<link rel="canonical" href="` + target + `">`
+	var out []byte
+	switch {
+	case canonicalLinkRe.Match(raw):
+		out = canonicalLinkRe.ReplaceAllLiteral(raw, []byte(tag))
+	case headCloseRe.Match(raw):
+		loc := headCloseRe.FindIndex(raw)
+		out = append(append(append([]byte{}, raw[:loc[0]]...), tag...), raw[loc[0]:]...)
+	default:
+		return nil // no head to put it in
+	}
+	return os.WriteFile(file, out, 0644) // #nosec G306 G703 -- rewriting our own output
+}
+
+// writeVersionsManifest writes versions.json at the site root, for switchers
+// that load the list at runtime instead of baking it into every page.
+func (g *Generator) writeVersionsManifest(commits map[string]string) error {
+	manifest := struct {
+		Latest   string         `json:"latest"`
+		Versions []versionEntry `json:"versions"`
+	}{Versions: []versionEntry{}}
+	for _, v := range g.siteVersions() {
+		if v.Latest {
+			manifest.Latest = v.Name
+		}
+		manifest.Versions = append(manifest.Versions, versionEntry{
+			Name: v.Name, Title: v.Title, Ref: v.Ref, Commit: commits[v.Name], URL: v.URL, Latest: v.Latest,
+		})
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return g.writeOutput(filepath.Join(g.config.OutputDir, "versions.json"), data)
+}
+
+// gitOutput runs git in dir and returns its trimmed stdout; a failure carries
+// git's own message.
+func gitOutput(dir string, args ...string) (string, error) {
+	// #nosec G204 -- fixed args, never a shell
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...) // NOSONAR S4036: git is intentionally resolved from PATH (portable across systems), reviewed
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
@@ -0,0 +1,148 @@
+package generator
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runGit runs a git command in dir, failing the test on error.
+func runGit(t *testing.T, dir string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@example.com",
+		"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@example.com")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, out)
+	}
+}
+
+// versionedSite is a repository whose v1 tag says "old" about the about page
+// and whose working tree says "new", built with v1 under /v1/.
+func versionedSite(t *testing.T) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	root := t.TempDir()
+	t.Chdir(root)
+	writeIncrementalSite(t, root)
+	theme := filepath.Join(root, "templates", "simple")
+	mustWrite(t, filepath.Join(theme, "page.html"),
+		`<html><head><link rel="canonical" href="{{.CanonicalURL}}"></head><h1>{{.Title}}</h1>{{.Content}}`+
+			`<img srcset="/a.png 1x, /b.png 2x" src="/logo.png"><a href="https://cdn.example.com/x">cdn</a>`+
+			`{{range .Versions}}<v href="{{.URL}}"{{if .Current}} current{{end}}>{{.Title}}</v>{{end}}`+
+			`{{with .CurrentVersion}}<cur>{{.Name}}</cur>{{end}}</html>`)
+	about := filepath.Join(root, "content", "site", "pages", "about.md")
+	mustWrite(t, about, "---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\nOld, see [beta](/2024/01/02/beta/).\n")
+	runGit(t, root, "init", "--quiet")
+	runGit(t, root, "add", "-A")
+	runGit(t, root, "commit", "--quiet", "-m", "v1")
+	runGit(t, root, "tag", "v1.0")
+	mustWrite(t, about, "---\ntitle: About\nslug: about\nstatus: publish\ntype: page\n---\n\nNew.\n")
+	return root
+}
+
+func buildVersioned(t *testing.T, root string) {
+	t.Helper()
+	buildScheduled(t, root, func(c *Config) {
+		c.Versions = []Version{{Name: "dev", Title: "Development"}, {Name: "v1", Ref: "v1.0"}}
+	})
+}
+
+func TestVersionsBuild(t *testing.T) {
+	root := versionedSite(t)
+	buildVersioned(t, root)
+	out := filepath.Join(root, "output")
+
+	cur, err := os.ReadFile(filepath.Join(out, "about", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, err := os.ReadFile(filepath.Join(out, "v1", "about", "index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"New.", `<link rel="canonical" href="https://example.com/about/">`,
+		`<v href="https://example.com/" current>Development</v><v href="https://example.com/v1/">v1</v>`,
+		"<cur>dev</cur>",
+	} {
+		if !strings.Contains(string(cur), want) {
+			t.Errorf("root about lacks %s:\n%s", want, cur)
+		}
+	}
+	for _, want := range []string{
+		"Old, see", `<a href="/v1/2024/01/02/beta/">`, `srcset="/v1/a.png 1x, /v1/b.png 2x"`, `src="/v1/logo.png"`,
+		`href="https://cdn.example.com/x"`,
+		// The working tree is the latest (the first entry): v1 points there.
+		`<link rel="canonical" href="https://example.com/about/">`,
+		`<v href="https://example.com/">Development</v><v href="https://example.com/v1/" current>v1</v>`,
+		"<cur>v1</cur>",
+	} {
+		if !strings.Contains(string(old), want) {
+			t.Errorf("v1 about lacks %s:\n%s", want, old)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "v1", "robots.txt")); err == nil {
+		t.Error("a version build must not write its own robots.txt")
+	}
+
+	var manifest struct {
+		Latest   string         `json:"latest"`
+		Versions []versionEntry `json:"versions"`
+	}
+	data, err := os.ReadFile(filepath.Join(out, "versions.json"))
+	if err != nil || json.Unmarshal(data, &manifest) != nil {
+		t.Fatalf("versions.json: %v", err)
+	}
+	if manifest.Latest != "dev" || len(manifest.Versions) != 2 || manifest.Versions[1].Commit == "" ||
+		manifest.Versions[1].URL != "https://example.com/v1/" {
+		t.Errorf("versions.json = %s", data)
+	}
+}
+
+func TestVersionsCache(t *testing.T) {
+	root := versionedSite(t)
+	buildVersioned(t, root)
+	// A marker planted in the cached copy survives the next build: the
+	// unchanged tag was copied, not rebuilt.
+	marker := filepath.Join(root, ".ssg-cache", "versions", "v1", "site", "marker.txt")
+	mustWrite(t, marker, "cached")
+	buildVersioned(t, root)
+	if _, err := os.Stat(filepath.Join(root, "output", "v1", "marker.txt")); err != nil {
+		t.Fatalf("v1 was rebuilt instead of reused: %v", err)
+	}
+	// Moving the tag invalidates it.
+	runGit(t, root, "commit", "--quiet", "-am", "v1.1")
+	runGit(t, root, "tag", "-f", "v1.0")
+	buildVersioned(t, root)
+	if _, err := os.Stat(filepath.Join(root, "output", "v1", "marker.txt")); err == nil {
+		t.Error("a moved ref must be rebuilt")
+	}
+	if raw, _ := os.ReadFile(filepath.Join(root, "output", "v1", "about", "index.html")); !strings.Contains(string(raw), "New.") {
+		t.Errorf("v1 should now hold the retagged content:\n%s", raw)
+	}
+}
+
+func TestValidateVersions(t *testing.T) {
+	for _, tc := range []struct {
+		versions []Version
+		err      string
+	}{
+		{[]Version{{Name: "dev"}, {Name: "v1", Ref: "v1.0", Latest: true}}, ""},
+		{[]Version{{Name: "v 1", Ref: "x"}}, "not a valid name"},
+		{[]Version{{Name: "v1", Ref: "a"}, {Name: "v1", Ref: "b"}}, "listed twice"},
+		{[]Version{{Name: "a"}, {Name: "b"}}, "omit ref"},
+		{[]Version{{Name: "a", Ref: "x", Latest: true}, {Name: "b", Ref: "y", Latest: true}}, "latest"},
+		{[]Version{{Name: "a", Ref: "--upload-pack=x"}}, "not a git ref"},
+	} {
+		err := validateVersions(tc.versions)
+		if (tc.err == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tc.err)) {
+			t.Errorf("validateVersions(%+v) = %v, want %q", tc.versions, err, tc.err)
+		}
+	}
+}
@@ -0,0 +1,510 @@
+package generator
+
+// Obsidian-style wikilinks (`wikilinks: true`): [[Page]], [[Page#Heading]],
+// [[Page|label]], ![[image.png]] and ![[Note]] transclusion.
+//
+// The goldmark extension only recognises the syntax. It renders a link as an
+// unresolved placeholder, because goldmark output is memoized per Markdown
+// source (PERF-004) and is shared by every language a note renders in, while
+// the target is not: the same [[About]] is /about/ on the English page and
+// /pl/o-nas/ on the Polish one. The placeholders are resolved after
+// conversion, next to rewriteMdLinks and through the same per-language link
+// map lookup (resolveMdLink), so a wikilink honours content_fallback exactly
+// as a .md link does (i18n §13).
+//
+// Backlinks, the link graph and the unresolved-target report come from one
+// pass over the loaded content (computeWikilinks), which parses only the
+// documents that contain "[[" at all.
+
+import (
+	"encoding/json"
+	stdhtml "html"
+	"net/url"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	gmparser "github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// ─── goldmark extension ─────────────────────────────────────────────────────
+
+var kindWikilink = ast.NewNodeKind("Wikilink")
+
+// wikilinkNode is one [[…]] or ![[…]] occurrence.
+type wikilinkNode struct {
+	ast.BaseInline
+	target   string // page name, file name or "" for a same-page [[#Heading]]
+	fragment string // heading, without the "#"
+	label    string // text after "|"; for a media embed, optionally a size
+	embed    bool
+}
+
+// Kind implements ast.Node.
+func (n *wikilinkNode) Kind() ast.NodeKind { return kindWikilink }
+
+// Dump implements ast.Node.
+func (n *wikilinkNode) Dump(source []byte, level int) {
+	ast.DumpHelper(n, source, level, map[string]string{
+		"Target": n.target, "Fragment": n.fragment, "Label": n.label, "Embed": strconv.FormatBool(n.embed),
+	}, nil)
+}
+
+// ref is the target as the placeholder carries it: "Page#Heading".
+func (n *wikilinkNode) ref() string {
+	if n.fragment == "" {
+		return n.target
+	}
+	return n.target + "#" + n.fragment
+}
+
+// isFile reports whether the target names a media file or document rather
+// than a note: ![[diagram.png]] embeds an image, ![[Note]] transcludes.
+func (n *wikilinkNode) isFile() bool {
+	return !parser.IsContentFile(n.target) && isContentAsset(n.target)
+}
+
+// text is the visible link text: the label, else the target the way
+// Obsidian shows it ("Page > Heading").
+func (n *wikilinkNode) text() string {
+	switch {
+	case n.label != "":
+		return n.label
+	case n.target == "":
+		return n.fragment
+	case n.fragment != "":
+		return n.target + " > " + n.fragment
+	}
+	return n.target
+}
+
+// parseWikilink splits the inside of [[…]]. A table cell escapes the label
+// separator as \|, which means the same thing. Block references (#^id) have
+// no published anchor and link to the page itself.
+func parseWikilink(inner string, embed bool) *wikilinkNode {
+	inner = strings.ReplaceAll(inner, `\|`, "|")
+	n := &wikilinkNode{embed: embed}
+	if i := strings.IndexByte(inner, '|'); i >= 0 {
+		n.label = strings.TrimSpace(inner[i+1:])
+		inner = inner[:i]
+	}
+	if i := strings.IndexByte(inner, '#'); i >= 0 {
+		n.fragment = strings.TrimSpace(inner[i+1:])
+		inner = inner[:i]
+	}
+	if strings.HasPrefix(n.fragment, "^") {
+		n.fragment = ""
+	}
+	n.target = strings.TrimSpace(inner)
+	return n
+}
+
+type wikilinkParser struct{}
+
+// Trigger implements gmparser.InlineParser.
+func (wikilinkParser) Trigger() []byte { return []byte{'!', '['} }
+
+// Parse implements gmparser.InlineParser. It claims "[[…]]" on one line and
+// leaves anything else — [a](b), ![alt](src), a lone "[" — to the link parser,
+// which runs after it.
+func (wikilinkParser) Parse(_ ast.Node, block text.Reader, _ gmparser.Context) ast.Node {
+	line, _ := block.PeekLine()
+	embed := len(line) > 0 && line[0] == '!'
+	open := 2
+	if embed {
+		open = 3
+	}
+	if len(line) < open+2 || string(line[open-2:open]) != "[[" {
+		return nil
+	}
+	end := strings.Index(string(line[open:]), "]]")
+	if end <= 0 {
+		return nil
+	}
+	inner := string(line[open : open+end])
+	if strings.ContainsAny(inner, "[]\n") || strings.TrimSpace(inner) == "" {
+		return nil
+	}
+	n := parseWikilink(inner, embed)
+	if n.target == "" && n.fragment == "" {
+		return nil
+	}
+	block.Advance(open + end + 2)
+	return n
+}
+
+type wikilinkRenderer struct{}
+
+// RegisterFuncs implements renderer.NodeRenderer.
+func (r wikilinkRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(kindWikilink, r.render)
+}
+
+// render writes a media embed as final markup — its src is relative, and the
+// co-located asset copy publishes the file beside the page because the content
+// names it — and a link or transclusion as a placeholder for resolveWikilinks.
+func (wikilinkRenderer) render(w util.BufWriter, _ []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*wikilinkNode)
+	ref := stdhtml.EscapeString(n.ref())
+	switch {
+	case n.embed && n.isFile():
+		_, _ = w.WriteString(mediaEmbedHTML(n))
+	case n.embed:
+		_, _ = w.WriteString(`<div class="wikilink-embed" data-wikilink="` + ref + `"></div>`)
+	default:
+		_, _ = w.WriteString(`<a class="wikilink" data-wikilink="` + ref + `">` + stdhtml.EscapeString(n.text()) + `</a>`)
+	}
+	return ast.WalkSkipChildren, nil
+}
+
+// embedSizeRe is Obsidian's size label: ![[photo.jpg|300]] or |300x200.
+var embedSizeRe = regexp.MustCompile(`^(\d+)(?:x(\d+))?$`)
+
+// mediaEmbedHTML renders ![[file]]: an image, audio or video element, or a
+// plain link to a document it cannot show inline.
+func mediaEmbedHTML(n *wikilinkNode) string {
+	src := stdhtml.EscapeString((&url.URL{Path: n.target}).EscapedPath())
+	switch strings.ToLower(path.Ext(n.target)) {
+	case ".png", ".jpg", ".jpeg", ".gif", ".webp", ".svg", ".avif", ".bmp":
+		alt, size := n.label, ""
+		if m := embedSizeRe.FindStringSubmatch(n.label); m != nil {
+			alt, size = "", ` width="`+m[1]+`"`
+			if m[2] != "" {
+				size += ` height="` + m[2] + `"`
+			}
+		}
+		return `<img src="` + src + `" alt="` + stdhtml.EscapeString(alt) + `"` + size + `>`
+	case ".mp3", ".wav", ".ogg", ".m4a", ".flac":
+		return `<audio controls src="` + src + `"></audio>`
+	case ".mp4", ".webm", ".mov", ".ogv":
+		return `<video controls src="` + src + `"></video>`
+	}
+	return `<a href="` + src + `">` + stdhtml.EscapeString(n.text()) + `</a>`
+}
+
+type wikilinkExtension struct{}
+
+// Extend implements goldmark.Extender. Priority 199 runs the parser just
+// before goldmark's link parser (200), which would otherwise consume the
+// brackets as a failed reference link.
+func (wikilinkExtension) Extend(m goldmark.Markdown) {
+	m.Parser().AddOptions(gmparser.WithInlineParsers(util.Prioritized(wikilinkParser{}, 199)))
+	m.Renderer().AddOptions(renderer.WithNodeRenderers(util.Prioritized(wikilinkRenderer{}, 199)))
+}
+
+// ─── resolution ─────────────────────────────────────────────────────────────
+
+// wikiIndex is what wikilinks resolve against, built once the content is
+// final (URLs, languages and translations known).
+type wikiIndex struct {
+	links  map[string]map[string]string // normalized name → language → URL
+	docs   map[string]wikiDoc           // URL → the page there
+	misses []brokenLink                 // unresolved targets, for check_links
+	edges  [][2]string                  // from URL → to URL, for graph.json
+}
+
+// wikiDoc is a link target as transclusion needs it.
+type wikiDoc struct {
+	title   string
+	content string
+	source  string // sourceKey, for incremental rebuilds
+}
+
+// wikiKey normalizes a wikilink target the way Obsidian matches note names:
+// case-insensitive, by the last path segment, with or without an extension.
+func wikiKey(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	if parser.IsContentFile(name) {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	return name
+}
+
+// buildWikiIndex indexes every page by title, slug and file name. Like
+// buildMdLinkMap, keys are per language and enriched with the translation
+// group, and the first writer wins, so resolution is deterministic.
+func (g *Generator) buildWikiIndex() *wikiIndex {
+	idx := &wikiIndex{links: map[string]map[string]string{}, docs: map[string]wikiDoc{}}
+	add := func(key, lang, url string) {
+		if key == "" {
+			return
+		}
+		if idx.links[key] == nil {
+			idx.links[key] = map[string]string{}
+		}
+		if _, exists := idx.links[key][lang]; !exists {
+			idx.links[key][lang] = url
+		}
+	}
+	for _, pages := range [][]models.Page{g.siteData.Pages, g.siteData.Posts} {
+		for _, p := range pages {
+			url := p.GetURL()
+			if _, exists := idx.docs[url]; !exists {
+				idx.docs[url] = wikiDoc{title: p.Title, content: p.Content, source: sourceKey(p)}
+			}
+			for _, name := range []string{p.SourceFile, p.Slug, p.Title, stdhtml.UnescapeString(p.Title)} {
+				key := wikiKey(name)
+				add(key, p.Lang, url)
+				for _, tr := range p.Translations {
+					add(key, tr.Lang, tr.URL)
+				}
+			}
+		}
+	}
+	return idx
+}
+
+// resolveWikilink returns the URL of a wikilink target for a language.
+func (g *Generator) resolveWikilink(target, lang string) (string, bool) {
+	if g.wiki == nil {
+		return "", false
+	}
+	return g.resolveMdLink(g.wiki.links[wikiKey(target)], lang)
+}
+
+var (
+	wikilinkPlaceholderRe  = regexp.MustCompile(`<a class="wikilink" data-wikilink="([^"]*)">(.*?)</a>`)
+	wikiEmbedPlaceholderRe = regexp.MustCompile(
+		`<p><div class="wikilink-embed" data-wikilink="([^"]*)"></div></p>|<div class="wikilink-embed" data-wikilink="([^"]*)"></div>`)
+)
+
+// maxEmbedDepth bounds transclusion: a note that embeds a note that embeds
+// the first one stops here and links instead.
+const maxEmbedDepth = 3
+
+// resolveWikilinks replaces the extension's placeholders in converted HTML
+// with links for the rendering language (g.currentLang). A target that names
+// no page becomes a plain span, so nothing ships a dead href; check_links
+// lists it (see computeWikilinks).
+func (g *Generator) resolveWikilinks(s string) string {
+	return g.resolveWikilinksDepth(s, 0)
+}
+
+func (g *Generator) resolveWikilinksDepth(s string, depth int) string {
+	if !strings.Contains(s, `data-wikilink="`) {
+		return s
+	}
+	s = wikiEmbedPlaceholderRe.ReplaceAllStringFunc(s, func(match string) string {
+		m := wikiEmbedPlaceholderRe.FindStringSubmatch(match)
+		ref := stdhtml.UnescapeString(m[1] + m[2])
+		return g.transclude(parseWikilink(ref, true), depth)
+	})
+	return wikilinkPlaceholderRe.ReplaceAllStringFunc(s, func(match string) string {
+		m := wikilinkPlaceholderRe.FindStringSubmatch(match)
+		n := parseWikilink(stdhtml.UnescapeString(m[1]), false)
+		if href, ok := g.wikilinkHref(n); ok {
+			return `<a class="wikilink" href="` + stdhtml.EscapeString(href) + `">` + m[2] + `</a>`
+		}
+		return `<span class="wikilink wikilink-missing">` + m[2] + `</span>`
+	})
+}
+
+// wikilinkHref is a link's final href: the target's served URL plus the
+// heading anchor, or just the anchor for [[#Heading]].
+func (g *Generator) wikilinkHref(n *wikilinkNode) (string, bool) {
+	anchor := ""
+	if n.fragment != "" {
+		anchor = "#" + slugify(n.fragment)
+	}
+	if n.target == "" {
+		return anchor, true
+	}
+	url, ok := g.resolveWikilink(n.target, g.currentLang)
+	if !ok {
+		return "", false
+	}
+	return g.config.PrettyURLs.ServedURL(url) + anchor, true
+}
+
+// transclude renders ![[Note]] or ![[Note#Heading]] as the note's content (or
+// that section of it), followed by a link to the source.
+func (g *Generator) transclude(n *wikilinkNode, depth int) string {
+	url, ok := g.resolveWikilink(n.target, g.currentLang)
+	if ok {
+		_, ok = g.wiki.docs[url]
+	}
+	if !ok {
+		return `<p><span class="wikilink wikilink-missing">` + stdhtml.EscapeString(n.text()) + `</span></p>`
+	}
+	doc := g.wiki.docs[url]
+	href := stdhtml.EscapeString(g.config.PrettyURLs.ServedURL(url))
+	if depth >= maxEmbedDepth {
+		return `<p><a class="wikilink" href="` + href + `">` + stdhtml.EscapeString(n.text()) + `</a></p>`
+	}
+	body := doc.content
+	if n.fragment != "" {
+		body = markdownSection(body, n.fragment)
+	}
+	html := g.resolveWikilinksDepth(g.convertMarkdownToHTML(body), depth+1)
+	return `<div class="wikilink-embed">` + html +
+		`<p class="wikilink-embed-source"><a href="` + href + `">` + stdhtml.EscapeString(doc.title) + `</a></p></div>`
+}
+
+// markdownSection returns the section of a Markdown document under the
+// heading whose text (or slug) is heading, down to the next heading of the
+// same or a higher level. Fenced code is skipped, so a "# comment" in a shell
+// block is not a heading. The whole document is returned when none matches.
+func markdownSection(content, heading string) string {
+	want := slugify(heading)
+	lines := strings.Split(content, "\n")
+	start, level, fence := -1, 0, ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+		l := len(line) - len(strings.TrimLeft(line, "#"))
+		if l == 0 || l > 6 || (len(line) > l && line[l] != ' ') {
+			continue
+		}
+		if start >= 0 && l <= level {
+			return strings.Join(lines[start:i], "\n")
+		}
+		if start < 0 && slugify(line[l:]) == want {
+			start, level = i, l
+		}
+	}
+	if start < 0 {
+		return content
+	}
+	return strings.Join(lines[start:], "\n")
+}
+
+// ─── backlinks and graph ────────────────────────────────────────────────────
+
+// wikilinksIn returns the wikilinks in a document, in order.
+func (g *Generator) wikilinksIn(content string) []*wikilinkNode {
+	if !strings.Contains(content, "[[") {
+		return nil
+	}
+	doc := g.md.Parser().Parse(text.NewReader([]byte(content)))
+	var links []*wikilinkNode
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if wl, ok := n.(*wikilinkNode); ok && entering {
+			links = append(links, wl)
+		}
+		return ast.WalkContinue, nil
+	})
+	return links
+}
+
+// computeWikilinks builds the wiki index and, from every document's
+// wikilinks, the Backlinks of each page it links to, the Embeds it depends
+// on, the edges of graph.json and the list of targets that resolve nowhere.
+func (g *Generator) computeWikilinks() {
+	if !g.config.Wikilinks {
+		return
+	}
+	g.wiki = g.buildWikiIndex()
+	byURL := map[string]*models.Page{}
+	for _, pages := range [][]models.Page{g.siteData.Pages, g.siteData.Posts} {
+		for i := range pages {
+			if _, exists := byURL[pages[i].GetURL()]; !exists {
+				byURL[pages[i].GetURL()] = &pages[i]
+			}
+		}
+	}
+	seen := map[[2]string]bool{}
+	for _, pages := range [][]models.Page{g.siteData.Pages, g.siteData.Posts} {
+		for i := range pages {
+			p := &pages[i]
+			from := p.GetURL()
+			for _, n := range g.wikilinksIn(p.Content) {
+				if n.target == "" || n.embed && n.isFile() {
+					continue
+				}
+				to, ok := g.resolveWikilink(n.target, p.Lang)
+				if !ok {
+					g.wiki.misses = append(g.wiki.misses, brokenLink{from: from, href: "[[" + n.ref() + "]]"})
+					continue
+				}
+				if n.embed {
+					p.Embeds = append(p.Embeds, g.wiki.docs[to].source)
+				}
+				edge := [2]string{from, to}
+				if to == from || seen[edge] {
+					continue
+				}
+				seen[edge] = true
+				g.wiki.edges = append(g.wiki.edges, edge)
+				if target := byURL[to]; target != nil {
+					target.Backlinks = append(target.Backlinks, models.PageLink{Title: p.Title, URL: from})
+				}
+			}
+		}
+	}
+	for _, p := range byURL {
+		sort.SliceStable(p.Backlinks, func(a, b int) bool { return p.Backlinks[a].Title < p.Backlinks[b].Title })
+	}
+}
+
+// graphNode and graphLink are graph.json's shape — the nodes/links form
+// force-graph and d3-force read directly.
+type graphNode struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+	Type  string `json:"type,omitempty"`
+	Lang  string `json:"lang,omitempty"`
+}
+
+type graphLink struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+}
+
+// generateLinkGraph writes graph.json: every published page as a node and
+// every wikilink between two of them as a link, for a theme's graph view.
+func (g *Generator) generateLinkGraph() error {
+	if g.wiki == nil {
+		return nil
+	}
+	g.log("🕸️  Writing link graph...")
+	graph := struct {
+		Nodes []graphNode `json:"nodes"`
+		Links []graphLink `json:"links"`
+	}{Nodes: []graphNode{}, Links: []graphLink{}}
+	nodes := map[string]bool{}
+	for _, pages := range [][]models.Page{published(g.siteData.Pages), published(g.siteData.Posts)} {
+		for _, p := range pages {
+			url := p.GetURL()
+			if nodes[url] {
+				continue
+			}
+			nodes[url] = true
+			graph.Nodes = append(graph.Nodes, graphNode{ID: g.sitePath(url), Title: p.Title, Type: p.Type, Lang: p.Lang})
+		}
+	}
+	for _, e := range g.wiki.edges {
+		if nodes[e[0]] && nodes[e[1]] {
+			graph.Links = append(graph.Links, graphLink{Source: g.sitePath(e[0]), Target: g.sitePath(e[1])})
+		}
+	}
+	data, err := json.Marshal(graph)
+	if err != nil {
+		return err
+	}
+	return g.writeOutput(filepath.Join(g.config.OutputDir, "graph.json"), data)
+}
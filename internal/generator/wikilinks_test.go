@@ -0,0 +1,112 @@
+package generator
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWikilinkSyntax(t *testing.T) {
+	md := buildMarkdown(Config{Wikilinks: true})
+	for src, want := range map[string]string{
+		"[[Page Name]]":                   `<a class="wikilink" data-wikilink="Page Name">Page Name</a>`,
+		"[[Page#Set up|the setup]]":       `<a class="wikilink" data-wikilink="Page#Set up">the setup</a>`,
+		"[[#Usage]]":                      `<a class="wikilink" data-wikilink="#Usage">Usage</a>`,
+		"[[Page#^block1]]":                `<a class="wikilink" data-wikilink="Page">Page</a>`,
+		"![[diagram.png|300]]":            `<img src="diagram.png" alt="" width="300">`,
+		"![[my photo.jpg|A lake]]":        `<img src="my%20photo.jpg" alt="A lake">`,
+		"![[Note]]":                       `<div class="wikilink-embed" data-wikilink="Note"></div>`,
+		"[a](b.md) and [[x]]":             `<a href="b.md">a</a> and <a class="wikilink" data-wikilink="x">x</a>`,
+		"![img](a.png)":                   `<img src="a.png" alt="img">`,
+		"`[[code]]` and [not a wikilink]": "<code>[[code]]</code> and [not a wikilink]",
+	} {
+		var buf bytes.Buffer
+		if err := md.Convert([]byte(src), &buf); err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("%s\n got: %s\nwant: %s", src, buf.String(), want)
+		}
+	}
+
+	var plain bytes.Buffer
+	_ = buildMarkdown(Config{}).Convert([]byte("[[Page]]"), &plain)
+	if strings.Contains(plain.String(), "wikilink") {
+		t.Errorf("wikilinks are opt-in: %s", plain.String())
+	}
+}
+
+func TestMarkdownSection(t *testing.T) {
+	doc := "Intro.\n\n## Setup\n\nStep one.\n\n```sh\n# not a heading\n```\n\n### Detail\n\nMore.\n\n## Usage\n\nRun it."
+	got := markdownSection(doc, "setup")
+	if !strings.HasPrefix(got, "## Setup") || !strings.Contains(got, "### Detail") || strings.Contains(got, "Usage") {
+		t.Errorf("section:\n%s", got)
+	}
+	if markdownSection(doc, "missing") != doc {
+		t.Error("an unknown heading should transclude the whole note")
+	}
+}
+
+// Wikilinks resolve by title, slug or file name; the target learns its
+// backlinks, a transcluded note is inlined, and a dead target is a check_links
+// finding instead of a dead href.
+func TestWikilinksBuild(t *testing.T) {
+	root := t.TempDir()
+	writeIncrementalSite(t, root)
+	content := filepath.Join(root, "content", "site")
+	mustWrite(t, filepath.Join(content, "posts", "news", "alpha.md"),
+		"---\ntitle: alpha\nslug: alpha\nstatus: publish\ntype: post\ndate: 2024-01-02\ncategories: [News]\n---\n\n"+
+			"See [[About|us]], [[beta#Deep Dive]] and [[Nowhere]].\n\n![[about]]\n")
+	mustWrite(t, filepath.Join(content, "posts", "news", "beta.md"),
+		"---\ntitle: beta\nslug: beta\nstatus: publish\ntype: post\ndate: 2024-01-02\ncategories: [News]\n---\n\n"+
+			"## Deep Dive\n\nText, and back to [[alpha]].\n")
+	mustWrite(t, filepath.Join(root, "templates", "simple", "page.html"),
+		`<html><h1>{{.Title}}</h1>{{.Content}}{{range .Backlinks}}<bl href="{{.URL}}">{{.Title}}</bl>{{end}}</html>`)
+
+	gen := buildScheduled(t, root, func(c *Config) { c.Wikilinks = true })
+	out := filepath.Join(root, "output")
+
+	alpha, err := os.ReadFile(filepath.Join(out, "2024/01/02/alpha/index.html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<a class="wikilink" href="/about/">us</a>`,
+		`<a class="wikilink" href="/2024/01/02/beta/#deep-dive">beta &gt; Deep Dive</a>`,
+		`<span class="wikilink wikilink-missing">Nowhere</span>`,
+		`<div class="wikilink-embed"><p>About us.</p>`,
+		`<p class="wikilink-embed-source"><a href="/about/">About</a></p></div>`,
+	} {
+		if !strings.Contains(string(alpha), want) {
+			t.Errorf("alpha lacks %s:\n%s", want, alpha)
+		}
+	}
+
+	about, _ := os.ReadFile(filepath.Join(out, "about/index.html"))
+	if !strings.Contains(string(about), `<bl href="/2024/01/02/alpha/">alpha</bl>`) {
+		t.Errorf("about should list alpha as a backlink:\n%s", about)
+	}
+
+	broken, err := gen.checkLinks()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(broken) != 1 || broken[0].href != "[[Nowhere]]" || broken[0].from != "/2024/01/02/alpha/" {
+		t.Errorf("broken = %+v, want the one unresolved wikilink", broken)
+	}
+
+	var graph struct {
+		Nodes []graphNode `json:"nodes"`
+		Links []graphLink `json:"links"`
+	}
+	data, err := os.ReadFile(filepath.Join(out, "graph.json"))
+	if err != nil || json.Unmarshal(data, &graph) != nil {
+		t.Fatalf("graph.json: %v", err)
+	}
+	if len(graph.Nodes) != 3 || len(graph.Links) != 3 {
+		t.Errorf("graph = %d nodes, %d links; want 3 and 3 (alpha→about, alpha→beta, beta→alpha)", len(graph.Nodes), len(graph.Links))
+	}
+}
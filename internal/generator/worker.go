@@ -142,8 +142,7 @@ func (g *Generator) generateWorkerFiles() error {
 	if err != nil {
 		return err
 	}
-	// #nosec G306 -- Web content files need to be world-readable
-	if err := os.WriteFile(filepath.Join(g.config.OutputDir, "_routes.json"), routes, 0644); err != nil {
+	if err := g.writeOutput(filepath.Join(g.config.OutputDir, "_routes.json"), routes); err != nil {
 		return fmt.Errorf("worker: writing _routes.json: %w", err)
 	}
 	return nil
@@ -303,8 +302,7 @@ func (g *Generator) copyPrebuiltWorker(dir string) error {
 	if err != nil {
 		return fmt.Errorf("worker: mode %q needs a prebuilt %s: %w", workerModeWorker, src, err)
 	}
-	// #nosec G306,G703 -- Web content files need to be world-readable; path from trusted local config
-	if err := os.WriteFile(filepath.Join(g.config.OutputDir, "_worker.js"), data, 0644); err != nil {
+	if err := g.writeOutput(filepath.Join(g.config.OutputDir, "_worker.js"), data); err != nil {
 		return fmt.Errorf("worker: writing _worker.js: %w", err)
 	}
 	return nil
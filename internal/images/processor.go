@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/disintegration/imaging"
 	"github.com/spagu/ssg/internal/cache"
@@ -31,6 +32,20 @@ type Config struct {
 	MaxDimension    int // default 20_000
 	MaxVariants     int // default 20 (srcset widths per source)
 	Quiet           bool
+	// Observe, when set, is told about every request that produced an image,
+	// cache hit or not — how --profile counts image work. Called from the
+	// render workers, so it must be safe for concurrent use.
+	Observe func(Op)
+}
+
+// Op is one completed processor request as reported to Config.Observe.
+type Op struct {
+	Helper   string // imageResize, imageCrop, imageFilter or imageProcess
+	Source   string
+	Cached   bool // served from the cache: no decode, no encode
+	Bytes    int64
+	Start    time.Time
+	Duration time.Duration
 }
 
 // Processor executes image requests with a deterministic content-addressed
@@ -133,6 +148,7 @@ func (p *Processor) Process(source string, ops []request) (ImageResult, error) {
 // run resolves the source, consults the cache and — on miss — decodes,
 // applies every operation in order and publishes atomically.
 func (p *Processor) run(helper, source string, ops []request) (ImageResult, error) {
+	start := time.Now()
 	path, err := p.resolve(source)
 	if err != nil {
 		return ImageResult{}, fmt.Errorf("%s: %w", helper, err)
@@ -145,6 +161,7 @@ func (p *Processor) run(helper, source string, ops []request) (ImageResult, erro
 	defer unlock()
 
 	if res, ok := p.cached(source, path, key, ops); ok {
+		p.observe(helper, source, start, res, true)
 		return res, nil
 	}
 
@@ -162,7 +179,25 @@ func (p *Processor) run(helper, source string, ops []request) (ImageResult, erro
 	if b := img.Bounds(); b.Dx()*b.Dy() > p.cfg.MaxOutputPixels {
 		return ImageResult{}, fmt.Errorf("%s: output exceeds max_output_pixels (%d)", helper, p.cfg.MaxOutputPixels)
 	}
-	return p.publish(helper, source, path, key, ops, img, info)
+	res, err := p.publish(helper, source, path, key, ops, img, info)
+	if err == nil {
+		p.observe(helper, source, start, res, false)
+	}
+	return res, err
+}
+
+func (p *Processor) observe(helper, source string, start time.Time, res ImageResult, cached bool) {
+	if p.cfg.Observe == nil {
+		return
+	}
+	p.cfg.Observe(Op{
+		Helper:   helper,
+		Source:   source,
+		Cached:   cached,
+		Bytes:    res.FileSize,
+		Start:    start,
+		Duration: time.Since(start),
+	})
 }
 
 // decodeSource opens+decodes the image, enforcing bomb limits and normalizing
@@ -411,6 +411,40 @@ func TestProcessPipelineAndCache(t *testing.T) {
 	}
 }
 
+// Observe sees one Op per produced image — the miss that encoded it, then the
+// hit that reused it — and nothing for a request that failed validation.
+func TestObserveReportsHitsAndMisses(t *testing.T) {
+	p, src := testEnv(t)
+	writePNG(t, filepath.Join(src, "img.png"), 200, 100, false)
+	var mu sync.Mutex
+	var seen []Op
+	p.cfg.Observe = func(op Op) {
+		mu.Lock()
+		defer mu.Unlock()
+		seen = append(seen, op)
+	}
+	opts := map[string]any{"width": 50, "mode": "fit_width"}
+	for i := 0; i < 2; i++ {
+		if _, err := p.ResizeDict("img.png", opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := p.ResizeDict("img.png", map[string]any{"mode": "stretch"}); err == nil {
+		t.Fatal("bad mode must error")
+	}
+	if len(seen) != 2 {
+		t.Fatalf("ops = %+v, want 2", seen)
+	}
+	if seen[0].Cached || !seen[1].Cached {
+		t.Errorf("cached = %v, %v; want miss then hit", seen[0].Cached, seen[1].Cached)
+	}
+	for _, op := range seen {
+		if op.Helper != "imageResize" || op.Source != "img.png" || op.Bytes <= 0 || op.Start.IsZero() {
+			t.Errorf("op = %+v", op)
+		}
+	}
+}
+
 func TestConcurrentIdenticalRequests(t *testing.T) {
 	p, src := testEnv(t)
 	writePNG(t, filepath.Join(src, "img.png"), 200, 200, false)
@@ -0,0 +1,517 @@
+// Package lint reviews the writing in Markdown sources: structure a reader
+// trips over (a heading that skips a level, a second H1), prose that is hard
+// to read (sentences and paragraphs past a length, passive voice, words the
+// site has banned) and links and images that do not work for everyone (bare
+// URLs, images without alt text, links without text).
+//
+// check_markup catches markup that renders as text and content_schemas checks
+// frontmatter; neither reads the prose. The rules here run over the goldmark
+// tree of the source, so code blocks, code spans, HTML and frontmatter are
+// never mistaken for prose, and every finding carries the line in the file to
+// edit.
+//
+// A rule is switched off for part of a file with an HTML comment, which
+// renders as nothing:
+//
+//	<!-- lint-disable sentence-length -->   until lint-enable or the end
+//	<!-- lint-enable sentence-length -->
+//	<!-- lint-disable-next-line bare-url -->
+//	<!-- lint-disable -->                   every rule
+//
+// These are heuristics. A finding is a question for the author, which is why
+// every rule can be disabled per content type and per line.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+	"github.com/spagu/ssg/internal/parser"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// Rules names every rule, in report order, with what it reports.
+var Rules = []struct{ Name, Description string }{
+	{"heading-increment", "a heading more than one level below the previous one"},
+	{"single-h1", "a second level-one heading"},
+	{"sentence-length", "a sentence longer than max_sentence_words"},
+	{"paragraph-length", "a paragraph longer than max_paragraph_words"},
+	{"passive-voice", "passive voice (en, de, pl)"},
+	{"banned-words", "a word or phrase from banned_words for the page's language"},
+	{"bare-url", "a URL in running text instead of a link"},
+	{"image-alt", "an image without alt text"},
+	{"empty-link", "a link without text or without a target"},
+}
+
+// Default thresholds, when the configuration sets none.
+const (
+	DefaultMaxSentenceWords  = 35
+	DefaultMaxParagraphWords = 150
+)
+
+// Finding is one reported problem, on a 1-based line of the file.
+type Finding struct {
+	Line    int
+	Rule    string
+	Message string
+	Word    string // the unknown word, for a spelling finding
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%d: %s: %s", f.Line, f.Rule, f.Message)
+}
+
+// Validate rejects rule names the configuration disables but no rule has, so
+// a typo does not silently keep a rule on.
+func Validate(cfg models.LintConfig) error {
+	check := func(where string, names []string) error {
+		for _, name := range names {
+			if !isRule(name) {
+				return fmt.Errorf("%s: unknown lint rule %q", where, name)
+			}
+		}
+		return nil
+	}
+	if err := check("lint.disable", cfg.Disable); err != nil {
+		return err
+	}
+	for typ, t := range cfg.Types {
+		if err := check("lint.types."+typ+".disable", t.Disable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func isRule(name string) bool {
+	for _, r := range Rules {
+		if r.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// markdown parses without linkify, so a bare URL stays text for bare-url to
+// find, and with GFM tables so a table is not read as one long paragraph.
+var markdown = goldmark.New(goldmark.WithExtensions(extension.Table, extension.Strikethrough, extension.TaskList))
+
+// Check lints one Markdown document — frontmatter and all, as read from disk —
+// with rules already resolved for its content type (LintConfig.ForType). lang
+// picks the passive-voice patterns and banned-word list.
+func Check(source string, rules models.LintConfig, lang string) []Finding {
+	c := newChecker(sourceLines(source), rules, lang)
+	c.walk(markdown.Parser().Parse(text.NewReader(c.src)))
+	sort.SliceStable(c.findings, func(i, j int) bool { return c.findings[i].Line < c.findings[j].Line })
+	return c.findings
+}
+
+// sourceLines splits a document into lines with the frontmatter blanked: out
+// of the tree, but every line below keeps its number.
+func sourceLines(source string) []string {
+	lines := strings.Split(source, "\n")
+	end := parser.FrontmatterEnd(lines)
+	for i := 0; i < end; i++ {
+		lines[i] = ""
+	}
+	return lines
+}
+
+func newChecker(lines []string, rules models.LintConfig, lang string) *checker {
+	src := []byte(strings.Join(lines, "\n"))
+	c := &checker{
+		src:        src,
+		lineStarts: lineStarts(src),
+		rules:      rules,
+		lang:       lang,
+		disabled:   disabledLines(lines),
+		off:        map[string]bool{},
+	}
+	for _, name := range rules.Disable {
+		c.off[name] = true
+	}
+	return c
+}
+
+type checker struct {
+	src        []byte
+	lineStarts []int
+	rules      models.LintConfig
+	lang       string
+	disabled   []lineState
+	off        map[string]bool
+	findings   []Finding
+	lastLevel  int
+	h1s        int
+}
+
+// report records a finding unless its rule is off, for the file or the line.
+func (c *checker) report(line int, rule, format string, args ...interface{}) {
+	if c.suppressed(line, rule) {
+		return
+	}
+	c.findings = append(c.findings, Finding{Line: line, Rule: rule, Message: fmt.Sprintf(format, args...)})
+}
+
+func (c *checker) suppressed(line int, rule string) bool {
+	return c.off[rule] || line > 0 && line <= len(c.disabled) && c.disabled[line-1].covers(rule)
+}
+
+func (c *checker) walk(doc ast.Node) {
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading:
+			c.heading(node)
+			c.prose(node, false)
+		case *ast.Paragraph, *ast.TextBlock:
+			c.prose(node, true)
+		case *ast.Image:
+			if strings.TrimSpace(plainText(node, c.src)) == "" {
+				c.report(c.inlineLine(node, "]("+string(node.Destination)), "image-alt", "image %s has no alt text", node.Destination)
+			}
+		case *ast.Link:
+			c.link(node)
+		}
+		return ast.WalkContinue, nil
+	})
+}
+
+func (c *checker) heading(h *ast.Heading) {
+	line := c.blockLine(h)
+	if c.lastLevel > 0 && h.Level > c.lastLevel+1 {
+		c.report(line, "heading-increment", "heading jumps from h%d to h%d", c.lastLevel, h.Level)
+	}
+	c.lastLevel = h.Level
+	if h.Level == 1 {
+		c.h1s++
+		if c.h1s > 1 {
+			c.report(line, "single-h1", "second H1 %q; a page has one", plainText(h, c.src))
+		}
+	}
+}
+
+func (c *checker) link(l *ast.Link) {
+	hasImage := false
+	for child := l.FirstChild(); child != nil; child = child.NextSibling() {
+		if child.Kind() == ast.KindImage {
+			hasImage = true
+		}
+	}
+	label := strings.TrimSpace(plainText(l, c.src))
+	switch {
+	case strings.TrimSpace(string(l.Destination)) == "":
+		c.report(c.inlineLine(l, "]()"), "empty-link", "link %q has no target", label)
+	case label == "" && !hasImage:
+		c.report(c.inlineLine(l, "]("+string(l.Destination)), "empty-link", "link to %s has no text", l.Destination)
+	}
+}
+
+// run is one piece of a block's text, with where it came from.
+type run struct {
+	start int // offset in the block's plain text
+	off   int // offset in the source
+	code  bool
+	link  bool // link text or a URL: not checked for bare URLs
+}
+
+// blockText is a paragraph or heading as plain text, with runs mapping every
+// byte back to the source.
+type blockText struct {
+	plain string
+	runs  []run
+}
+
+// collect flattens a block's inline content. Image alt text is left out: it
+// is not part of the sentence it sits in.
+func collect(block ast.Node, src []byte) blockText {
+	var b strings.Builder
+	var runs []run
+	var visit func(n ast.Node, code, link bool)
+	visit = func(n ast.Node, code, link bool) {
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			switch node := child.(type) {
+			case *ast.Image:
+				continue
+			case *ast.CodeSpan:
+				visit(node, true, link)
+			case *ast.Link, *ast.AutoLink:
+				visit(node, code, true)
+			case *ast.Text:
+				runs = append(runs, run{start: b.Len(), off: node.Segment.Start, code: code, link: link})
+				b.Write(node.Segment.Value(src))
+				if node.SoftLineBreak() || node.HardLineBreak() {
+					b.WriteByte(' ')
+				}
+			default:
+				visit(node, code, link)
+			}
+		}
+	}
+	visit(block, false, false)
+	return blockText{plain: b.String(), runs: runs}
+}
+
+// at returns the run holding plain-text offset i.
+func (t blockText) at(i int) run {
+	k := sort.Search(len(t.runs), func(j int) bool { return t.runs[j].start > i }) - 1
+	if k < 0 {
+		return run{}
+	}
+	return t.runs[k]
+}
+
+// sourceOffset maps a plain-text offset to the source.
+func (t blockText) sourceOffset(i int) int {
+	r := t.at(i)
+	return r.off + i - r.start
+}
+
+// masked is the plain text with the runs skip rejects blanked out, so offsets
+// still line up.
+func (t blockText) masked(skip func(run) bool) string {
+	b := []byte(t.plain)
+	for k, r := range t.runs {
+		if !skip(r) {
+			continue
+		}
+		end := len(b)
+		if k+1 < len(t.runs) {
+			end = t.runs[k+1].start
+		}
+		for i := r.start; i < end; i++ {
+			b[i] = ' '
+		}
+	}
+	return string(b)
+}
+
+// sentenceEndRe ends a sentence: terminal punctuation, closing quotes or
+// brackets, then space.
+var sentenceEndRe = regexp.MustCompile(`[.!?…]+["'”’)\]]*(\s+|$)`)
+
+// bareURLRe matches a URL written straight into text.
+var bareURLRe = regexp.MustCompile(`https?://[^\s<>()\[\]]+[^\s<>()\[\].,;:!?'"]`)
+
+func (c *checker) prose(block ast.Node, lengths bool) {
+	t := collect(block, c.src)
+	if strings.TrimSpace(t.plain) == "" {
+		return
+	}
+	lineOf := func(i int) int { return c.line(t.sourceOffset(i)) }
+
+	if lengths {
+		if block.Kind() == ast.KindParagraph {
+			if n := len(strings.Fields(t.plain)); n > orDefault(c.rules.MaxParagraphWords, DefaultMaxParagraphWords) {
+				c.report(lineOf(0), "paragraph-length", "paragraph of %d words (max %d)", n, orDefault(c.rules.MaxParagraphWords, DefaultMaxParagraphWords))
+			}
+		}
+		limit := orDefault(c.rules.MaxSentenceWords, DefaultMaxSentenceWords)
+		start := 0
+		ends := append(sentenceEndRe.FindAllStringIndex(t.plain, -1), []int{len(t.plain), len(t.plain)})
+		for _, end := range ends {
+			if end[0] < start {
+				continue
+			}
+			sentence := t.plain[start:end[0]]
+			if n := len(strings.Fields(sentence)); n > limit {
+				lead := strings.TrimLeft(sentence, " ")
+				c.report(lineOf(start+len(sentence)-len(lead)), "sentence-length", "sentence of %d words (max %d)", n, limit)
+			}
+			start = end[1]
+		}
+	}
+
+	prose := t.masked(func(r run) bool { return r.code })
+	if re := passiveVoice[baseLang(c.lang)]; re != nil {
+		for _, m := range re.FindAllStringSubmatchIndex(prose, -1) {
+			c.report(lineOf(m[2]), "passive-voice", "passive voice %q", strings.Join(strings.Fields(prose[m[2]:m[3]]), " "))
+		}
+	}
+	for _, word := range c.bannedWords() {
+		re := wordRe(word)
+		for _, m := range re.FindAllStringSubmatchIndex(prose, -1) {
+			c.report(lineOf(m[2]), "banned-words", "banned word %q", prose[m[2]:m[3]])
+		}
+	}
+	text := t.masked(func(r run) bool { return r.code || r.link })
+	for _, m := range bareURLRe.FindAllStringIndex(text, -1) {
+		c.report(lineOf(m[0]), "bare-url", "bare URL %s; make it a link", text[m[0]:m[1]])
+	}
+}
+
+// bannedWords is the list for the page's language, or its base language's.
+func (c *checker) bannedWords() []string {
+	if words, ok := c.rules.BannedWords[c.lang]; ok {
+		return words
+	}
+	return c.rules.BannedWords[baseLang(c.lang)]
+}
+
+// letter is what counts as part of a word. \b in RE2 knows only ASCII, so a
+// pattern next to "ł" or "ü" needs this instead.
+const letter = `\p{L}\p{N}_`
+
+// wordRe matches word or phrase as whole words, case-insensitively; group 1
+// is the match itself.
+func wordRe(word string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(?:^|[^` + letter + `])(` + regexp.QuoteMeta(word) + `)(?:$|[^` + letter + `])`)
+}
+
+// passiveVoice holds a pattern per language; group 1 is the phrase reported.
+// English: a form of "to be" and a participle. German: werden and a ge-
+// participle later in the clause. Polish: zostać and a passive participle.
+var passiveVoice = map[string]*regexp.Regexp{
+	"en": regexp.MustCompile(`(?i)(?:^|[^` + letter + `])((?:am|is|are|was|were|be|been|being)\s+(?:\p{L}+ed|` +
+		`born|broken|built|chosen|done|drawn|driven|found|forgotten|given|grown|held|hidden|kept|known|left|lost|made|` +
+		`paid|said|seen|sent|shown|sold|spoken|taken|taught|thrown|told|understood|won|written))(?:$|[^` + letter + `])`),
+	"de": regexp.MustCompile(`(?i)(?:^|[^` + letter + `])((?:wird|werden|wirst|werdet|wurde|wurden|wurdest|worden)\s+` +
+		`(?:[^.!?;:]*?\s)?ge\p{L}+(?:t|en))(?:$|[^` + letter + `])`),
+	"pl": regexp.MustCompile(`(?i)(?:^|[^` + letter + `])((?:został|została|zostało|zostali|zostały|zostanie|zostaną|zostać)\s+` +
+		`\p{L}+(?:ny|na|ne|ni|ty|ta|te|ci))(?:$|[^` + letter + `])`),
+}
+
+func baseLang(lang string) string {
+	base, _, _ := strings.Cut(strings.ToLower(lang), "-")
+	return base
+}
+
+func orDefault(v, def int) int {
+	if v > 0 {
+		return v
+	}
+	return def
+}
+
+// plainText is the text under a node, code included.
+func plainText(n ast.Node, src []byte) string {
+	var b strings.Builder
+	_ = ast.Walk(n, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if t, ok := n.(*ast.Text); ok && entering {
+			b.Write(t.Segment.Value(src))
+		}
+		return ast.WalkContinue, nil
+	})
+	return b.String()
+}
+
+// blockLine is the line a block starts on, or that of the block containing an
+// inline node.
+func (c *checker) blockLine(n ast.Node) int {
+	for ; n != nil; n = n.Parent() {
+		if n.Type() == ast.TypeBlock && n.Lines().Len() > 0 {
+			return c.line(n.Lines().At(0).Start)
+		}
+	}
+	return 0
+}
+
+// inlineLine finds an inline node's line by searching its block's source for
+// marker, which is how links and images without text can be placed.
+func (c *checker) inlineLine(n ast.Node, marker string) int {
+	block := n.Parent()
+	for block != nil && (block.Type() != ast.TypeBlock || block.Lines().Len() == 0) {
+		block = block.Parent()
+	}
+	if block == nil {
+		return 0
+	}
+	lines := block.Lines()
+	start, stop := lines.At(0).Start, lines.At(lines.Len()-1).Stop
+	if i := strings.Index(string(c.src[start:stop]), marker); i >= 0 {
+		return c.line(start + i)
+	}
+	return c.line(start)
+}
+
+// line turns a source offset into a 1-based line number.
+func (c *checker) line(off int) int {
+	return sort.Search(len(c.lineStarts), func(i int) bool { return c.lineStarts[i] > off })
+}
+
+func lineStarts(src []byte) []int {
+	starts := []int{0}
+	for i, b := range src {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// lineState is which rules are off on one line.
+type lineState struct {
+	all   bool
+	rules map[string]bool
+}
+
+func (s lineState) covers(rule string) bool { return s.all || s.rules[rule] }
+
+// lintCommentRe matches <!-- lint-disable … -->, lint-enable and
+// lint-disable-next-line; group 2 lists the rules, empty meaning all.
+var lintCommentRe = regexp.MustCompile(`<!--\s*lint-(disable-next-line|disable|enable)((?:\s+[\w-]+)*)\s*-->`)
+
+// disabledLines works out, per line, what the lint comments switched off. A
+// comment applies from its own line; one inside a fenced code block is an
+// example, not a directive.
+func disabledLines(lines []string) []lineState {
+	out := make([]lineState, len(lines))
+	cur := lineState{rules: map[string]bool{}}
+	var carried *lineState // from a lint-disable-next-line on the line above
+	fence := ""
+	for i, line := range lines {
+		var next *lineState
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence = trimmed[:3]
+		default:
+			for _, m := range lintCommentRe.FindAllStringSubmatch(line, -1) {
+				names := strings.Fields(m[2])
+				switch m[1] {
+				case "disable":
+					cur.all = cur.all || len(names) == 0
+					for _, n := range names {
+						cur.rules[n] = true
+					}
+				case "enable":
+					if len(names) == 0 {
+						cur = lineState{rules: map[string]bool{}}
+					}
+					for _, n := range names {
+						delete(cur.rules, n)
+					}
+				case "disable-next-line":
+					next = &lineState{all: len(names) == 0, rules: map[string]bool{}}
+					for _, n := range names {
+						next.rules[n] = true
+					}
+				}
+			}
+		}
+		state := lineState{all: cur.all, rules: map[string]bool{}}
+		for n := range cur.rules {
+			state.rules[n] = true
+		}
+		if carried != nil {
+			state.all = state.all || carried.all
+			for n := range carried.rules {
+				state.rules[n] = true
+			}
+		}
+		out[i], carried = state, next
+	}
+	return out
+}
@@ -0,0 +1,144 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+func rulesOf(findings []Finding) string {
+	var out []string
+	for _, f := range findings {
+		out = append(out, f.String())
+	}
+	return strings.Join(out, "\n")
+}
+
+func TestCheckRules(t *testing.T) {
+	doc := `---
+title: Guide
+---
+
+# Guide
+
+## Setup
+
+#### Too deep
+
+See https://example.com/docs for more, or <https://example.com/ok>.
+
+The config file was written by the installer and it is simply obvious that everything here is fine.
+
+![](diagram.png) and [](/empty) and [text]() and [![logo](logo.png)](/home).
+
+` + "```\n# Not a heading https://example.com/in-code\n```" + `
+
+# Second
+`
+	cfg := models.LintConfig{MaxSentenceWords: 12, BannedWords: map[string][]string{"en": {"simply"}}}
+	got := rulesOf(Check(doc, cfg, "en-US"))
+	for _, line := range []string{
+		"9: heading-increment: heading jumps from h2 to h4",
+		"11: bare-url: bare URL https://example.com/docs; make it a link",
+		"13: sentence-length: sentence of 18 words (max 12)",
+		`13: passive-voice: passive voice "was written"`,
+		`13: banned-words: banned word "simply"`,
+		"15: image-alt: image diagram.png has no alt text",
+		"15: empty-link: link to /empty has no text",
+		`15: empty-link: link "text" has no target`,
+		`21: single-h1: second H1 "Second"; a page has one`,
+	} {
+		if !strings.Contains(got, line) {
+			t.Errorf("missing %q in:\n%s", line, got)
+		}
+	}
+	if strings.Contains(got, "in-code") || strings.Contains(got, "/ok") || strings.Contains(got, "logo") {
+		t.Errorf("code, autolinks and image links are not findings:\n%s", got)
+	}
+}
+
+func TestPassiveVoiceLanguages(t *testing.T) {
+	for lang, text := range map[string]string{
+		"de": "Der Bericht wurde gestern vom Team geschrieben.",
+		"pl": "Raport został napisany wczoraj.",
+	} {
+		got := Check(text+"\n", models.LintConfig{}, lang)
+		if len(got) != 1 || got[0].Rule != "passive-voice" {
+			t.Errorf("%s: %v", lang, got)
+		}
+	}
+	if got := Check("Raport został napisany.\n", models.LintConfig{}, "fr"); len(got) != 0 {
+		t.Errorf("no pattern for fr, no findings: %v", got)
+	}
+}
+
+func TestDisableComments(t *testing.T) {
+	doc := `Visit https://a.example today.
+
+<!-- lint-disable bare-url -->
+Visit https://b.example today.
+<!-- lint-enable bare-url -->
+
+<!-- lint-disable-next-line -->
+Visit https://c.example today.
+Visit https://d.example today.
+
+` + "```\n<!-- lint-disable -->\n```" + `
+
+Visit https://e.example today.
+`
+	var urls []string
+	for _, f := range Check(doc, models.LintConfig{}, "en") {
+		urls = append(urls, f.Message)
+	}
+	if got := strings.Join(urls, "|"); strings.Count(got, "bare URL") != 3 ||
+		!strings.Contains(got, "a.example") || !strings.Contains(got, "d.example") || !strings.Contains(got, "e.example") {
+		t.Errorf("findings = %v", urls)
+	}
+}
+
+func TestConfigForType(t *testing.T) {
+	cfg := models.LintConfig{
+		Disable:          []string{"bare-url"},
+		MaxSentenceWords: 20,
+		BannedWords:      map[string][]string{"en": {"simply"}},
+		Types: map[string]models.LintConfig{
+			"post": {Disable: []string{"passive-voice"}, MaxParagraphWords: 3, BannedWords: map[string][]string{"en": {"just"}}},
+		},
+	}
+	post := cfg.ForType("post")
+	if len(post.Disable) != 2 || post.MaxSentenceWords != 20 || post.MaxParagraphWords != 3 || len(post.BannedWords["en"]) != 2 {
+		t.Errorf("post rules = %+v", post)
+	}
+	if page := cfg.ForType(""); len(page.Disable) != 1 || page.MaxParagraphWords != 0 {
+		t.Errorf("page rules = %+v", page)
+	}
+	got := Check("One two three four, at https://x.example.\n", post, "en")
+	if len(got) != 1 || got[0].Rule != "paragraph-length" {
+		t.Errorf("findings = %v", got)
+	}
+	if err := Validate(models.LintConfig{Types: map[string]models.LintConfig{"post": {Disable: []string{"bare-urls"}}}}); err == nil ||
+		!strings.Contains(err.Error(), `lint.types.post.disable: unknown lint rule "bare-urls"`) {
+		t.Errorf("Validate = %v", err)
+	}
+}
+
+func TestSpelling(t *testing.T) {
+	words := map[string]bool{"the": true, "cat": true, "sat": true, "on": true, "mat": true, "well": true, "known": true, "see": true, "and": true, "code": true}
+	known := func(w string) bool { return words[strings.ToLower(w)] }
+	src := "---\ntitle: Teh title\n---\n\n# The cat\n\n" +
+		"The cat sat on teh mat, `teh` code, see https://teh.example and {{< teh \"x\" >}}.\n" +
+		"The well-known wel-known cat, API, GitHub, v2 and x.\n\n" +
+		"```\nteh\n```\n\n" +
+		"<!-- lint-disable-next-line spelling -->\nThe cat sat on teh mat.\n\n" +
+		"| cat | tehh |\n|---|---|\n| mat | on |\n"
+	var got []string
+	for _, f := range Spelling(src, known) {
+		got = append(got, f.String()+" "+f.Word)
+	}
+	want := []string{`7: spelling: unknown word "teh" teh`, `8: spelling: unknown word "wel" wel`, `17: spelling: unknown word "tehh" tehh`}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spagu/ssg/internal/models"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// SpellingRule names spelling findings. It is not in Rules: it needs a
+// dictionary, so it runs only when asked for (check_spelling, or
+// `ssg lint --spelling`), but lint-disable comments silence it like any rule.
+const SpellingRule = "spelling"
+
+// spellingWordRe is a token: letters and digits, joined by apostrophes and
+// hyphens ("don't", "well-known", "v2").
+var spellingWordRe = regexp.MustCompile(`[\p{L}\p{M}\p{N}_]+(?:['’-][\p{L}\p{M}\p{N}_]+)*`)
+
+// notProseRe matches what reads as text in the tree but is not prose:
+// shortcodes and template actions, URLs, e-mail addresses and HTML entities.
+var notProseRe = regexp.MustCompile(`\{\{.*?\}\}|(?:https?|ftp|mailto):\S+|www\.\S+|\S+@\S+\.\S+|&#?\w+;`)
+
+// Spelling reports the words of a Markdown document that known rejects — the
+// text a reader sees: headings, paragraphs, list items and table cells, with
+// code, URLs and shortcodes left out. Words with a digit or an underscore,
+// and words with a capital past the first letter ("API", "GitHub"), are
+// names rather than prose and are not checked. A hyphenated word is accepted
+// whole or part by part.
+func Spelling(source string, known func(word string) bool) []Finding {
+	c := newChecker(sourceLines(source), models.LintConfig{}, "")
+	doc := markdown.Parser().Parse(text.NewReader(c.src))
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.Heading, *ast.Paragraph, *ast.TextBlock, *east.TableCell:
+			c.spell(n, known)
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return c.findings
+}
+
+func (c *checker) spell(block ast.Node, known func(string) bool) {
+	t := collect(block, c.src)
+	prose := []byte(t.masked(func(r run) bool { return r.code }))
+	for _, m := range notProseRe.FindAllIndex(prose, -1) {
+		for i := m[0]; i < m[1]; i++ {
+			prose[i] = ' '
+		}
+	}
+	for _, m := range spellingWordRe.FindAllIndex(prose, -1) {
+		token := string(prose[m[0]:m[1]])
+		if known(token) {
+			continue
+		}
+		offset := m[0]
+		for _, part := range strings.Split(token, "-") {
+			if checkable(part) && !known(part) {
+				line := c.line(t.sourceOffset(offset))
+				if !c.suppressed(line, SpellingRule) {
+					c.findings = append(c.findings, Finding{Line: line, Rule: SpellingRule,
+						Message: `unknown word "` + part + `"`, Word: part})
+				}
+			}
+			offset += len(part) + 1
+		}
+	}
+}
+
+// checkable is whether a word is prose to spell-check rather than a name, a
+// number or a single letter.
+func checkable(word string) bool {
+	if utf8.RuneCountInString(word) < 2 {
+		return false
+	}
+	for i, r := range word {
+		if unicode.IsDigit(r) || r == '_' || i > 0 && unicode.IsUpper(r) {
+			return false
+		}
+	}
+	return true
+}
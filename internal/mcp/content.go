@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"strings"
+
+	"github.com/spagu/ssg/internal/parser"
 )
 
 // contentExts are the file types the content section manages.
@@ -32,7 +34,8 @@ func (s *Server) contentTools() []tool {
 		{
 			name: "content_create",
 			description: "CONTENT · Create a NEW Markdown file. Provide the full document including " +
-				"YAML frontmatter (title, date, tags, …) and the Markdown body. CAN: add new posts/" +
+				"frontmatter (title, date, tags, …) — YAML between --- lines, TOML between +++ " +
+				"lines or a leading JSON object — and the Markdown body. CAN: add new posts/" +
 				"pages under " + bases + ". CANNOT: overwrite an existing file (use content_update), " +
 				"edit templates, or write outside content. Fails if the path already exists.",
 			schema: objectSchema(map[string]any{
@@ -45,7 +48,9 @@ func (s *Server) contentTools() []tool {
 			name: "content_update",
 			description: "CONTENT · Replace an EXISTING Markdown file with corrected/updated content. " +
 				"Use this to fix typos, rewrite sections, or change frontmatter. Provide the full new " +
-				"document. Fails if the file does not exist (use content_create for new files).",
+				"document. The file keeps its frontmatter format: YAML sent for a TOML or JSON file " +
+				"is written back as TOML or JSON. Fails if the file does not exist (use " +
+				"content_create for new files).",
 			schema: objectSchema(map[string]any{
 				"path":    stringProp("Project-relative path to the existing Markdown file"),
 				"content": stringProp("The complete new document (full replacement, not a patch)"),
@@ -143,6 +148,10 @@ func (s *Server) contentUpdate(args map[string]any) toolResult {
 	if !fileExists(abs) {
 		return errResult(fmt.Sprintf("%q does not exist — use content_create for new files", rel))
 	}
+	content, err = keepFrontmatterFormat(abs, content)
+	if err != nil {
+		return errResult("update failed: " + err.Error())
+	}
 	if err := writeFile(abs, content); err != nil {
 		return errResult("update failed: " + err.Error())
 	}
@@ -164,6 +173,22 @@ func (s *Server) contentDelete(args map[string]any) toolResult {
 	return s.afterMutate("content deleted " + rel)
 }
 
+// keepFrontmatterFormat re-encodes content's frontmatter in the format the file
+// at path already uses. A model rewriting a whole document reaches for YAML, and
+// a Hugo-migrated site of +++ TOML files should not drift into a mix of both
+// one update at a time.
+func keepFrontmatterFormat(path, content string) (string, error) {
+	old, err := os.ReadFile(path) // #nosec G304 -- confined to content dirs by resolveIn
+	if err != nil {
+		return "", err
+	}
+	format, _, _, ok := parser.SplitFrontmatter(string(old))
+	if !ok {
+		return content, nil
+	}
+	return parser.ConvertFrontmatter(content, format)
+}
+
 // resolveContent resolves a content path and enforces the Markdown extension so
 // the content section cannot write arbitrary file types.
 func resolveContent(s *Server, rel string) (string, string, error) {
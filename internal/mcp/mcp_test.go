@@ -156,6 +156,17 @@ func TestContentFlow(t *testing.T) {
 	if r := call(t, s, "content_read", map[string]any{"path": "content/posts/hi.md"}); text(r) != doc+"!" {
 		t.Errorf("read = %q", text(r))
 	}
+	// A TOML file stays TOML when the update is written in YAML.
+	toml := "+++\ntitle = \"Hi\"\n+++\nbody"
+	if r := call(t, s, "content_create", map[string]any{"path": "content/posts/toml.md", "content": toml}); r.IsError {
+		t.Fatalf("create toml: %s", text(r))
+	}
+	if r := call(t, s, "content_update", map[string]any{"path": "content/posts/toml.md", "content": "---\ntitle: Hello\n---\nbody"}); r.IsError {
+		t.Errorf("update toml: %s", text(r))
+	}
+	if r := call(t, s, "content_read", map[string]any{"path": "content/posts/toml.md"}); text(r) != "+++\ntitle = \"Hello\"\n+++\nbody" {
+		t.Errorf("toml read = %q", text(r))
+	}
 	if r := call(t, s, "content_update", map[string]any{"path": "content/posts/none.md", "content": doc}); !r.IsError || !strings.Contains(text(r), "content_create") {
 		t.Errorf("update-missing must point at content_create: %s", text(r))
 	}
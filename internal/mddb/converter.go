@@ -150,6 +150,11 @@ func (d *Document) ToPage() (*models.Page, error) {
 		}
 	}
 
+	// A co-written document's `authors` list resolves the same way, later.
+	if authors, ok := d.Metadata["authors"].([]interface{}); ok {
+		page.AuthorsRaw = authors
+	}
+
 	if excerpt, ok := d.Metadata["excerpt"].(string); ok {
 		page.Excerpt = excerpt
 	}
@@ -161,7 +166,7 @@ func (d *Document) ToPage() (*models.Page, error) {
 		}
 	}
 	if page.Date.IsZero() {
-		page.Date = d.CreatedAt
+		page.Date, page.DateInferred = d.CreatedAt, true
 	}
 
 	// Parse modified date
@@ -286,7 +291,8 @@ func sortedMetaKeys(meta map[string]interface{}) []string {
 	return keys
 }
 
-// ToPages converts multiple mddb Documents to models.Page slice
+// ToPages converts multiple mddb Documents to models.Page slice. Every status
+// is returned: which ones a build keeps is the generator's call (--drafts).
 func ToPages(docs []Document) ([]models.Page, error) {
 	var pages []models.Page
 	for _, doc := range docs {
@@ -294,9 +300,7 @@ func ToPages(docs []Document) ([]models.Page, error) {
 		if err != nil {
 			return nil, fmt.Errorf("converting document %s: %w", doc.Key, err)
 		}
-		if page.Status == "publish" {
-			pages = append(pages, *page)
-		}
+		pages = append(pages, *page)
 	}
 	return pages, nil
 }
@@ -404,6 +408,18 @@ func ExtractAuthor(doc Document) models.Author {
 	if name, ok := doc.Metadata["name"].(string); ok {
 		author.Name = name
 	}
+	// The profile fields, under the names metadata.json users use too.
+	for _, key := range []string{"bio", "description"} {
+		if bio, ok := doc.Metadata[key].(string); ok && author.Bio == "" {
+			author.Bio = bio
+		}
+	}
+	if avatar, ok := doc.Metadata["avatar"].(string); ok {
+		author.Avatar = avatar
+	}
+	if u, ok := doc.Metadata["url"].(string); ok {
+		author.URL = u
+	}
 
 	return author
 }
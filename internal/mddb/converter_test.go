@@ -142,9 +142,9 @@ func TestToPages(t *testing.T) {
 		t.Fatalf("ToPages() error = %v", err)
 	}
 
-	// Should only include published pages
-	if len(pages) != 2 {
-		t.Errorf("len(pages) = %v, want 2 (only published)", len(pages))
+	// Every status: the generator decides what a build keeps (--drafts).
+	if len(pages) != 3 || pages[1].Status != "draft" {
+		t.Errorf("pages = %+v, want all three, the draft included", pages)
 	}
 }
 
@@ -370,8 +370,8 @@ func TestToPages_AllDrafts(t *testing.T) {
 	if err != nil {
 		t.Fatalf("ToPages() error = %v", err)
 	}
-	if len(pages) != 0 {
-		t.Errorf("expected 0 pages, got %d", len(pages))
+	if len(pages) != 2 {
+		t.Errorf("expected both documents, got %d", len(pages))
 	}
 }
 
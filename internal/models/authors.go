@@ -0,0 +1,16 @@
+package models
+
+// AuthorsConfig is the `authors:` block: how each author's archive at
+// /author/<slug>/ is paginated and which feeds it publishes beside it.
+//
+//	authors:
+//	  paginate: 10
+//	  feeds: [atom, rss, json]
+type AuthorsConfig struct {
+	// Paginate is posts per archive page; 0 keeps each archive one page, as
+	// it always was.
+	Paginate int `yaml:"paginate" toml:"paginate" json:"paginate,omitempty"`
+	// Feeds are the formats written per author: feed.xml (atom), rss.xml
+	// (rss) and feed.json (json). Unset, authors get no feeds of their own.
+	Feeds []string `yaml:"feeds" toml:"feeds" json:"feeds,omitempty"`
+}
@@ -25,6 +25,12 @@ type TranslationLink struct {
 	IsCurrent bool
 }
 
+// PageLink is a reference to another page of the site.
+type PageLink struct {
+	Title string
+	URL   string
+}
+
 // FlexInt is an int that can be unmarshaled from either int or string JSON
 type FlexInt int
 
@@ -68,6 +74,25 @@ type Page struct {
 	Author     int       `yaml:"author"`
 	Categories []int     `yaml:"categories,omitempty"`
 
+	// Authors is the byline of a co-written post (`authors:`), as the ids of
+	// the authors it resolved to, in order. Names without a profile stay in
+	// AuthorsRaw only: they are credited but have no archive. Author is the
+	// first of them when `author:` is not set.
+	Authors    []int         `yaml:"authors,omitempty"`
+	AuthorsRaw []interface{} `yaml:"-" json:"-"`
+
+	// PublishDate embargoes the page until then (zero: Date decides);
+	// ExpiryDate takes it down from then on (zero: never). The generator
+	// leaves scheduled-out pages out of the build unless told to build future
+	// or expired content.
+	PublishDate time.Time `yaml:"publish_date"`
+	ExpiryDate  time.Time `yaml:"expiry_date"`
+
+	// DateInferred is true when no date was written for the page and Date is
+	// a stand-in — the file's modification time, a record's creation time.
+	// Scheduling never embargoes a page on it.
+	DateInferred bool `yaml:"-" json:"-"`
+
 	// Raw fields for flexible parsing (string or int values before resolution)
 	AuthorRaw     interface{}   `yaml:"-" json:"-"` // Unresolved author (int or string)
 	CategoriesRaw []interface{} `yaml:"-" json:"-"` // Unresolved categories (int or string values)
@@ -78,6 +103,20 @@ type Page struct {
 	SourceDir     string        `yaml:"-"` // Source directory path (for co-located asset copying)
 	SourceFile    string        `yaml:"-"` // Source filename (e.g. "AUTHENTICATION.md") for .md link rewriting
 
+	// Assets are files a content format extracted from the source itself — a
+	// notebook's plot outputs and pasted images — keyed by file name. They are
+	// written next to the page's output, where the content links them.
+	Assets map[string][]byte `yaml:"-" json:"-"`
+
+	// Resources are the files that belong to the page (see resources.go):
+	// everything in its bundle directory when it is a bundle, otherwise the
+	// co-located assets its content references. Bundle is true for a page that
+	// is the only content file in its own directory, named index.*.
+	// ResourcesFM is the raw `resources:` frontmatter.
+	Resources   Resources      `yaml:"-"`
+	Bundle      bool           `yaml:"-"`
+	ResourcesFM []ResourceMeta `yaml:"-" json:"-"`
+
 	// SEO and metadata fields
 	Description    string            `yaml:"description"`
 	Keywords       string            `yaml:"keywords"`
@@ -98,6 +137,26 @@ type Page struct {
 	// themselves; everything else follows by date.
 	Sticky bool `yaml:"sticky,omitempty"`
 
+	// Weight orders the page among its siblings in the section tree (see
+	// section.go); 0 is unweighted and sorts after every weighted page.
+	Weight int `yaml:"weight,omitempty"`
+
+	// IsDraft marks a page built only because preview mode (--drafts) is on:
+	// a draft, pending or future status, or a publish_date still ahead. It is
+	// set by the generator, never read from front matter.
+	IsDraft bool `yaml:"-"`
+
+	// Backlinks are the pages whose wikilinks point here, by title; Embeds are
+	// the source keys of the notes this page transcludes with ![[…]]. Both are
+	// set by the generator when wikilinks are enabled.
+	Backlinks []PageLink `yaml:"-"`
+	Embeds    []string   `yaml:"-" json:"-"`
+
+	// Includes are the files this page's {{< include >}} shortcodes read,
+	// slash-separated and relative to the project root, so an edit to a
+	// snippet re-renders the pages that show it.
+	Includes []string `yaml:"-" json:"-"`
+
 	// Aliases are old paths that should redirect here. Each generates a
 	// meta-refresh + canonical redirect stub excluded from the sitemap (SEO-002).
 	Aliases []string `yaml:"aliases,omitempty"`
@@ -117,6 +176,10 @@ type Page struct {
 	TaxonomiesFM map[string]interface{} `yaml:"-" json:"-"`
 	Taxonomies   map[string][]string    `yaml:"-"`
 
+	// MenuFM is the frontmatter `menu:` — the menus the page joins, and where
+	// (see ParseMenuField). `menu` also stays in Extra, as it always was.
+	MenuFM map[string]MenuEntry `yaml:"-" json:"-"`
+
 	// Series groups posts into an ordered set with a landing page and prev/next
 	// navigation (AX-005). The neighbour fields are computed by the generator.
 	Series          string `yaml:"series,omitempty"`
@@ -407,11 +470,57 @@ type Category struct {
 	Parent      int    `json:"parent"`
 }
 
-// Author represents a site author
+// Author represents a site author: the metadata.json `users` entry, an mddb
+// user, or a data/authors/*.yaml profile. Everything past Slug is the
+// optional profile an author archive and a byline render.
 type Author struct {
-	ID   int    `json:"id"`
-	Name string `json:"name"`
-	Slug string `json:"slug"`
+	ID   int    `json:"id" yaml:"id"`
+	Name string `json:"name" yaml:"name"`
+	Slug string `json:"slug" yaml:"slug"`
+	// Bio is a short biography, HTML allowed (print it with safeHTML); Avatar
+	// an image path (run through the image pipeline by authorAvatar) or a
+	// remote URL.
+	Bio    string `json:"bio,omitempty" yaml:"bio"`
+	Avatar string `json:"avatar,omitempty" yaml:"avatar"`
+	// URL is the author's own website.
+	URL string `json:"url,omitempty" yaml:"url"`
+	// Social maps a network to a profile URL ("mastodon": "https://…"), for
+	// a theme to render as links.
+	Social map[string]string `json:"social,omitempty" yaml:"social"`
+	// SameAs are further URLs naming the same person, for JSON-LD.
+	SameAs []string `json:"same_as,omitempty" yaml:"same_as"`
+}
+
+// UnmarshalJSON also reads the WordPress REST shape metadata.json users
+// arrive in: `description` is the bio and `avatar_urls` holds the avatar at
+// several sizes, of which the largest is kept.
+func (a *Author) UnmarshalJSON(data []byte) error {
+	type plain Author
+	var raw struct {
+		plain
+		Description string            `json:"description"`
+		AvatarURLs  map[string]string `json:"avatar_urls"`
+		SameAsCamel []string          `json:"sameAs"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*a = Author(raw.plain)
+	if a.Bio == "" {
+		a.Bio = raw.Description
+	}
+	if a.Avatar == "" {
+		best := -1
+		for size, u := range raw.AvatarURLs {
+			if n, err := strconv.Atoi(size); err == nil && n > best && u != "" {
+				best, a.Avatar = n, u
+			}
+		}
+	}
+	if len(a.SameAs) == 0 {
+		a.SameAs = raw.SameAsCamel
+	}
+	return nil
 }
 
 // MediaItem represents a media file
@@ -571,7 +680,10 @@ type SiteData struct {
 	// Comments are the readers' comments a migration carried across, keyed by
 	// the page URL they belong to. A page's own thread reaches its template as
 	// .Comments (#142).
-	Comments        map[string][]Comment
+	Comments map[string][]Comment
+	// Sections are the top-level sections of the section tree (_index.md),
+	// each with its subsections and pages as .Children, for a sidebar.
+	Sections        []*SectionNode `json:"-"`
 	Language        i18n.LanguageConfig
 	Languages       []i18n.LanguageConfig
 	DefaultLanguage string
@@ -583,12 +695,7 @@ type SiteData struct {
 // using reverse-lookup maps built from loaded metadata.
 // Call this after all metadata (authors, categories) has been loaded.
 func (sd *SiteData) ResolveFlexibleFields() {
-	authorByName := make(map[string]int)
-	authorBySlug := make(map[string]int)
-	for _, a := range sd.Authors {
-		authorByName[strings.ToLower(a.Name)] = a.ID
-		authorBySlug[strings.ToLower(a.Slug)] = a.ID
-	}
+	authorByName, authorBySlug := sd.authorIndex()
 
 	catByName := make(map[string]int)
 	catBySlug := make(map[string]int)
@@ -603,6 +710,7 @@ func (sd *SiteData) ResolveFlexibleFields() {
 	resolvePages := func(pages []Page) {
 		for i := range pages {
 			resolveAuthor(&pages[i], authorByName, authorBySlug)
+			resolveAuthors(&pages[i], authorByName, authorBySlug)
 			resolveCategories(&pages[i], catByName, catBySlug)
 			resolveTags(&pages[i], sd.Tags, sd.TagSlugs)
 		}
@@ -612,6 +720,30 @@ func (sd *SiteData) ResolveFlexibleFields() {
 	resolvePages(sd.Posts)
 }
 
+// authorIndex maps lower-cased author names and slugs to ids.
+func (sd *SiteData) authorIndex() (byName, bySlug map[string]int) {
+	byName = make(map[string]int, len(sd.Authors))
+	bySlug = make(map[string]int, len(sd.Authors))
+	for _, a := range sd.Authors {
+		byName[strings.ToLower(a.Name)] = a.ID
+		bySlug[strings.ToLower(a.Slug)] = a.ID
+	}
+	return byName, bySlug
+}
+
+// AuthorByRef finds the author one frontmatter reference names — an id, a
+// name, a slug or a numeric string — by the rules ResolveFlexibleFields
+// applies to `author:` and `authors:`.
+func (sd *SiteData) AuthorByRef(ref interface{}) (Author, bool) {
+	byName, bySlug := sd.authorIndex()
+	id := authorRef(ref, byName, bySlug)
+	if id == 0 {
+		return Author{}, false
+	}
+	a, ok := sd.Authors[id]
+	return a, ok
+}
+
 // resolveTags replaces numeric WordPress tag ids in a page's tags with the
 // term names from metadata.json `tags`, mirroring how author ids resolve via
 // `users` (issue #27). The canonical export slug is recorded ONLY for
@@ -640,25 +772,49 @@ func resolveAuthor(p *Page, byName, bySlug map[string]int) {
 	if p.AuthorRaw == nil || p.Author != 0 {
 		return
 	}
-	switch v := p.AuthorRaw.(type) {
+	p.Author = authorRef(p.AuthorRaw, byName, bySlug)
+}
+
+// resolveAuthors resolves the `authors:` list the way resolveAuthor resolves
+// `author:`. It recomputes from AuthorsRaw every time, so a second pass after
+// more profiles are known (data/authors) picks up names the first missed.
+func resolveAuthors(p *Page, byName, bySlug map[string]int) {
+	if len(p.AuthorsRaw) == 0 {
+		return
+	}
+	var ids []int
+	for _, raw := range p.AuthorsRaw {
+		if id := authorRef(raw, byName, bySlug); id != 0 {
+			ids = append(ids, id)
+		}
+	}
+	p.Authors = ids
+	if p.Author == 0 && len(p.Authors) > 0 {
+		p.Author = p.Authors[0]
+	}
+}
+
+// authorRef resolves one author reference — an id, a name, a slug or a
+// numeric string — to an id, or 0.
+func authorRef(raw interface{}, byName, bySlug map[string]int) int {
+	switch v := raw.(type) {
 	case int:
-		p.Author = v
+		return v
 	case float64:
-		p.Author = int(v)
+		return int(v)
 	case string:
-		lower := strings.ToLower(v)
+		lower := strings.ToLower(strings.TrimSpace(v))
 		if id, ok := byName[lower]; ok {
-			p.Author = id
-		} else if id, ok := bySlug[lower]; ok {
-			p.Author = id
+			return id
 		}
-		// If still 0 — try parsing as numeric string
-		if p.Author == 0 {
-			if parsed, err := strconv.Atoi(v); err == nil {
-				p.Author = parsed
-			}
+		if id, ok := bySlug[lower]; ok {
+			return id
+		}
+		if parsed, err := strconv.Atoi(strings.TrimSpace(v)); err == nil {
+			return parsed
 		}
 	}
+	return 0
 }
 
 // resolveCategories resolves CategoriesRaw to Categories IDs
@@ -506,6 +506,41 @@ func TestResolveFlexibleFields_AuthorAlreadyResolved(t *testing.T) {
 	}
 }
 
+func TestResolveFlexibleFields_AuthorsList(t *testing.T) {
+	sd := &SiteData{
+		Authors: map[int]Author{
+			1: {ID: 1, Name: "Anna Nowak", Slug: "anna-nowak"},
+			2: {ID: 2, Name: "Jan Kowalski", Slug: "jan-kowalski"},
+		},
+		Categories: make(map[int]Category),
+		Posts: []Page{
+			{AuthorsRaw: []interface{}{"jan-kowalski", "Guest Writer", 1}},
+		},
+	}
+
+	sd.ResolveFlexibleFields()
+
+	p := sd.Posts[0]
+	if len(p.Authors) != 2 || p.Authors[0] != 2 || p.Authors[1] != 1 {
+		t.Errorf("Expected Authors=[2 1] in byline order, got %v", p.Authors)
+	}
+	if p.Author != 2 {
+		t.Errorf("Expected Author=2 (first of the list), got %d", p.Author)
+	}
+}
+
+func TestAuthorUnmarshalWordPressUser(t *testing.T) {
+	var a Author
+	raw := `{"id":3,"name":"Anna","slug":"anna","description":"Editor.",` +
+		`"avatar_urls":{"24":"https://g.test/24","96":"https://g.test/96","48":"https://g.test/48"}}`
+	if err := json.Unmarshal([]byte(raw), &a); err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != 3 || a.Bio != "Editor." || a.Avatar != "https://g.test/96" {
+		t.Errorf("Expected bio and the largest avatar, got %+v", a)
+	}
+}
+
 func TestResolveFlexibleFields_CategoriesByName(t *testing.T) {
 	sd := &SiteData{
 		Authors: make(map[int]Author),
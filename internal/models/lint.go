@@ -0,0 +1,62 @@
+package models
+
+// LintConfig is the `lint:` block: which prose and structure rules `ssg lint`
+// and check_prose run over Markdown sources, and their thresholds. The
+// top-level rules cover every content type; Types adjusts them for one
+// frontmatter `type` ("page" when a file sets none), the way content_schemas
+// is keyed.
+//
+//	lint:
+//	  disable: [passive-voice]
+//	  max_sentence_words: 30
+//	  banned_words:
+//	    en: [simply, obviously]
+//	  types:
+//	    post:
+//	      max_paragraph_words: 120
+type LintConfig struct {
+	// Disable switches rules off by name (internal/lint lists them).
+	Disable []string `yaml:"disable" toml:"disable" json:"disable,omitempty"`
+	// MaxSentenceWords and MaxParagraphWords bound sentence-length and
+	// paragraph-length; zero keeps the defaults (35 and 150).
+	MaxSentenceWords  int `yaml:"max_sentence_words" toml:"max_sentence_words" json:"max_sentence_words,omitempty"`
+	MaxParagraphWords int `yaml:"max_paragraph_words" toml:"max_paragraph_words" json:"max_paragraph_words,omitempty"`
+	// BannedWords are words and phrases reported wherever they appear, keyed
+	// by language code.
+	BannedWords map[string][]string `yaml:"banned_words" toml:"banned_words" json:"banned_words,omitempty"`
+	// Types overrides the rules above per content type. Read at the top level
+	// only.
+	Types map[string]LintConfig `yaml:"types" toml:"types" json:"types,omitempty"`
+}
+
+// ForType returns the rules for one content type: the type's Disable and
+// BannedWords add to the top-level ones, and its thresholds replace them.
+func (c LintConfig) ForType(typ string) LintConfig {
+	if typ == "" {
+		typ = "page"
+	}
+	out := LintConfig{
+		Disable:           append([]string(nil), c.Disable...),
+		MaxSentenceWords:  c.MaxSentenceWords,
+		MaxParagraphWords: c.MaxParagraphWords,
+		BannedWords:       map[string][]string{},
+	}
+	for lang, words := range c.BannedWords {
+		out.BannedWords[lang] = append([]string(nil), words...)
+	}
+	t, ok := c.Types[typ]
+	if !ok {
+		return out
+	}
+	out.Disable = append(out.Disable, t.Disable...)
+	if t.MaxSentenceWords != 0 {
+		out.MaxSentenceWords = t.MaxSentenceWords
+	}
+	if t.MaxParagraphWords != 0 {
+		out.MaxParagraphWords = t.MaxParagraphWords
+	}
+	for lang, words := range t.BannedWords {
+		out.BannedWords[lang] = append(out.BannedWords[lang], words...)
+	}
+	return out
+}
@@ -9,7 +9,11 @@ package models
 // credentials comes back with none; the migration says so rather than letting
 // a site come up silently unnavigable.
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
 
 // Menu is one navigation menu with its items in the order the site renders.
 type Menu struct {
@@ -36,8 +40,105 @@ type MenuItem struct {
 	Type     string `json:"type"`
 	Object   string `json:"object"`
 	ObjectID int    `json:"object_id"`
+	// Identifier names an entry added by frontmatter or the `menus:` config
+	// block, for another entry's `parent:` to point at.
+	Identifier string `json:"identifier,omitempty"`
 
 	Children []MenuItem `json:"-"`
+	// Active marks the entry for the page being rendered, Ancestor every entry
+	// above it — set per page on .Menus, never on .Site.Menus.
+	Active   bool `json:"-"`
+	Ancestor bool `json:"-"`
+}
+
+// MenuEntry places a page in a menu (frontmatter `menu:`) or adds any link
+// to one (the `menus:` config block):
+//
+//	menu:
+//	  main: {weight: 20, parent: docs, title: Short label}
+//
+// A page's entry defaults to the page's title and URL, and is identified by
+// its slug unless it says otherwise.
+type MenuEntry struct {
+	Identifier string `yaml:"identifier" toml:"identifier" json:"identifier,omitempty"`
+	Title      string `yaml:"title" toml:"title" json:"title,omitempty"`
+	URL        string `yaml:"url" toml:"url" json:"url,omitempty"`
+	// Parent is the identifier, page slug or title of the entry to nest
+	// under — including an imported item, by its title or id.
+	Parent string `yaml:"parent" toml:"parent" json:"parent,omitempty"`
+	// Weight orders entries: lower first, on the same scale as an imported
+	// menu's order (1, 2, 3 …).
+	Weight int `yaml:"weight" toml:"weight" json:"weight,omitempty"`
+}
+
+// ParseMenuField reads frontmatter `menu:` in any of its forms: a menu name
+// (`menu: main`), a list of names, or a map of names to entries.
+func ParseMenuField(v interface{}) (map[string]MenuEntry, error) {
+	out := map[string]MenuEntry{}
+	switch m := v.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		if name := strings.TrimSpace(m); name != "" {
+			out[name] = MenuEntry{}
+		}
+	case []interface{}:
+		for _, item := range m {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("menu: list entries must be menu names, not %T", item)
+			}
+			out[strings.TrimSpace(name)] = MenuEntry{}
+		}
+	case map[string]interface{}:
+		for name, raw := range m {
+			entry, err := parseMenuEntry(raw)
+			if err != nil {
+				return nil, fmt.Errorf("menu.%s: %w", name, err)
+			}
+			out[name] = entry
+		}
+	default:
+		return nil, fmt.Errorf("menu: must be a menu name, a list or a map, not %T", v)
+	}
+	return out, nil
+}
+
+func parseMenuEntry(raw interface{}) (MenuEntry, error) {
+	var e MenuEntry
+	if raw == nil {
+		return e, nil
+	}
+	fields, ok := raw.(map[string]interface{})
+	if !ok {
+		return e, fmt.Errorf("must be a map (weight, parent, title, identifier, url), not %T", raw)
+	}
+	for key, val := range fields {
+		switch key {
+		case "weight":
+			switch n := val.(type) {
+			case int:
+				e.Weight = n
+			case int64:
+				e.Weight = int(n)
+			case float64:
+				e.Weight = int(n)
+			default:
+				return e, fmt.Errorf("weight must be a number, not %T", val)
+			}
+		case "identifier":
+			e.Identifier = fmt.Sprint(val)
+		case "title":
+			e.Title = fmt.Sprint(val)
+		case "url":
+			e.URL = fmt.Sprint(val)
+		case "parent":
+			e.Parent = fmt.Sprint(val)
+		default:
+			return e, fmt.Errorf("unknown field %q", key)
+		}
+	}
+	return e, nil
 }
 
 // Tree returns the menu's items as a hierarchy: top-level entries in menu
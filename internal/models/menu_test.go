@@ -96,3 +96,30 @@ func TestMenusByLocation(t *testing.T) {
 		t.Fatalf("no menus → empty map, got %v", got)
 	}
 }
+
+// TestParseMenuField: frontmatter `menu:` as a name, a list of names or a map
+// of entries; a typo in an entry is an error, not a silently missing link.
+func TestParseMenuField(t *testing.T) {
+	got, err := ParseMenuField("main")
+	if err != nil || len(got) != 1 || got["main"] != (MenuEntry{}) {
+		t.Fatalf("name: %+v, %v", got, err)
+	}
+	got, err = ParseMenuField([]interface{}{"main", "footer"})
+	if err != nil || len(got) != 2 {
+		t.Fatalf("list: %+v, %v", got, err)
+	}
+	got, err = ParseMenuField(map[string]interface{}{
+		"main":   map[string]interface{}{"weight": 20, "parent": "docs", "title": "Short label"},
+		"footer": nil,
+	})
+	want := MenuEntry{Weight: 20, Parent: "docs", Title: "Short label"}
+	if err != nil || got["main"] != want || got["footer"] != (MenuEntry{}) {
+		t.Fatalf("map: %+v, %v", got, err)
+	}
+	if _, err := ParseMenuField(map[string]interface{}{"main": map[string]interface{}{"wieght": 1}}); err == nil {
+		t.Error("an unknown field is an error")
+	}
+	if got, err := ParseMenuField(nil); got != nil || err != nil {
+		t.Errorf("no menu: %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"path"
+	"strings"
+
+	"github.com/spagu/ssg/internal/images"
+)
+
+// Resource is a file that belongs to a page, as templates see it: a file in
+// its bundle directory, a co-located asset its content references, or one its
+// content format extracted (a notebook's plots).
+type Resource struct {
+	// Name is what Get finds it by: the frontmatter `name:` when one is
+	// declared, otherwise File.
+	Name string
+	// File is the path within the page's directory, slash-separated
+	// ("gallery/01.jpg"); the resource is published there beside the page.
+	File string
+	// Path is the source file relative to its content root — the form the
+	// image helpers take, so `imageResize .Path` works as well as passing the
+	// resource itself. Empty for extracted files, which have no source file.
+	Path      string
+	URL       string // site-relative URL of the published copy
+	MediaType string // "image/jpeg", "application/pdf"
+	Size      int64
+
+	// Title, Alt and Params come from the page's `resources:` frontmatter.
+	Title  string
+	Alt    string
+	Params map[string]interface{}
+
+	// Image describes a decodable image (dimensions, format, orientation);
+	// nil for anything else.
+	Image *images.ImageInfo
+}
+
+// ResourceMeta is one `resources:` frontmatter entry: metadata for every
+// resource whose file matches Src, a glob such as "gallery/*.jpg". When
+// several entries match, the first to set a field wins.
+type ResourceMeta struct {
+	Src    string                 `yaml:"src"`
+	Name   string                 `yaml:"name"`
+	Title  string                 `yaml:"title"`
+	Alt    string                 `yaml:"alt"`
+	Params map[string]interface{} `yaml:"params"`
+}
+
+// Resources is a page's resource collection, in file order.
+type Resources []Resource
+
+// Match returns the resources whose file matches a glob, case-insensitively.
+// A pattern without a "/" is matched against the file's base name, so "*.jpg"
+// finds every JPEG in the bundle, nested or not.
+func (rs Resources) Match(pattern string) Resources {
+	var out Resources
+	for _, r := range rs {
+		if MatchResource(pattern, r.File) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Get returns the resource named name — by Name, by File, or by either
+// without its extension ("cover" finds cover.jpg) — or nil.
+func (rs Resources) Get(name string) *Resource {
+	for _, exact := range []bool{true, false} {
+		for i := range rs {
+			for _, candidate := range []string{rs[i].Name, rs[i].File} {
+				if !exact {
+					candidate = strings.TrimSuffix(candidate, path.Ext(candidate))
+				}
+				if strings.EqualFold(candidate, name) {
+					return &rs[i]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ByType returns the resources of a media type: a full type ("application/pdf")
+// or a main type ("image").
+func (rs Resources) ByType(mediaType string) Resources {
+	var out Resources
+	for _, r := range rs {
+		main, _, _ := strings.Cut(r.MediaType, "/")
+		if strings.EqualFold(r.MediaType, mediaType) || strings.EqualFold(main, mediaType) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// MatchResource reports whether a resource file matches a glob the way
+// Resources.Match and `resources:` frontmatter do.
+func MatchResource(pattern, file string) bool {
+	pattern, file = strings.ToLower(pattern), strings.ToLower(file)
+	if !strings.Contains(pattern, "/") {
+		file = path.Base(file)
+	}
+	ok, err := path.Match(pattern, file)
+	return err == nil && ok
+}
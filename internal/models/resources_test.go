@@ -0,0 +1,43 @@
+package models
+
+import "testing"
+
+func TestResourcesLookup(t *testing.T) {
+	rs := Resources{
+		{Name: "cover.jpg", File: "cover.jpg", MediaType: "image/jpeg"},
+		{Name: "hero", File: "gallery/01.JPG", MediaType: "image/jpeg"},
+		{Name: "gallery/02.png", File: "gallery/02.png", MediaType: "image/png"},
+		{Name: "slides.pdf", File: "slides.pdf", MediaType: "application/pdf"},
+	}
+	if got := rs.Match("*.jpg"); len(got) != 2 {
+		t.Errorf(`Match("*.jpg") = %d, want both JPEGs, nested and any case`, len(got))
+	}
+	if got := rs.Match("gallery/*"); len(got) != 2 || got[1].File != "gallery/02.png" {
+		t.Errorf(`Match("gallery/*") = %+v`, got)
+	}
+	if got := rs.Match("[bad"); len(got) != 0 {
+		t.Errorf("a malformed pattern should match nothing, got %d", len(got))
+	}
+
+	for name, want := range map[string]string{
+		"cover":          "cover.jpg",
+		"cover.jpg":      "cover.jpg",
+		"hero":           "gallery/01.JPG",
+		"gallery/01":     "gallery/01.JPG",
+		"gallery/02.png": "gallery/02.png",
+	} {
+		if r := rs.Get(name); r == nil || r.File != want {
+			t.Errorf("Get(%q) = %+v, want %s", name, r, want)
+		}
+	}
+	if rs.Get("missing") != nil {
+		t.Error("Get on an unknown name should be nil")
+	}
+
+	if got := rs.ByType("image"); len(got) != 3 {
+		t.Errorf(`ByType("image") = %d, want 3`, len(got))
+	}
+	if got := rs.ByType("application/pdf"); len(got) != 1 {
+		t.Errorf(`ByType("application/pdf") = %d, want 1`, len(got))
+	}
+}
@@ -111,3 +111,35 @@ type FeedFilter struct {
 	Words []string `yaml:"words" toml:"words" json:"words"`
 	Tags  []string `yaml:"tags" toml:"tags" json:"tags"`
 }
+
+// PageGenerator turns every row of a data file or an external source into a
+// page of its own (page_generators). Source is a dotted path into the data:
+// "data.products" is data/products.yaml, "external.catalog.items" the items of
+// the catalog source. Rows become pages before finalize, so they get URLs,
+// taxonomies, feeds and checks exactly as Markdown does.
+type PageGenerator struct {
+	Source string `yaml:"source" toml:"source" json:"source"`
+	Type   string `yaml:"type" toml:"type" json:"type"`       // page (default) | post
+	Layout string `yaml:"layout" toml:"layout" json:"layout"` // template, as frontmatter `layout:`
+	// Slug and Permalink are templates over the row ("{{ .sku }}"). Slug is
+	// slugified and defaults to the title's; Permalink additionally takes the
+	// permalinks tokens (:year, :slug, :category) and defaults to the type's.
+	Slug      string `yaml:"slug" toml:"slug" json:"slug"`
+	Permalink string `yaml:"permalink" toml:"permalink" json:"permalink"`
+	// Fields maps a frontmatter key to the row field that fills it ("name",
+	// "meta.summary") or to a template over the row; `content` is the body.
+	Fields map[string]string `yaml:"fields" toml:"fields" json:"fields"`
+	// Frontmatter is set on every generated page; Fields win over it.
+	Frontmatter map[string]interface{} `yaml:"frontmatter" toml:"frontmatter" json:"frontmatter"`
+}
+
+// ContentDefault is one content_defaults entry: frontmatter every document it
+// targets inherits, unless the document or a nearer _defaults.yaml sets the
+// field. Path is a glob relative to the content source ("posts/recipes/**",
+// `**` crossing directories), matched like content_exclude; Type matches the
+// `type:` a document declares or inherits. An empty target matches everything.
+type ContentDefault struct {
+	Path        string                 `yaml:"path" toml:"path" json:"path"`
+	Type        string                 `yaml:"type" toml:"type" json:"type"`
+	Frontmatter map[string]interface{} `yaml:"frontmatter" toml:"frontmatter" json:"frontmatter"`
+}
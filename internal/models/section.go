@@ -0,0 +1,107 @@
+package models
+
+// The section tree. A directory of content becomes a section when it holds an
+// _index.md: the file's frontmatter titles and orders the section, its body
+// introduces it on the generated list page. Sections nest the way directories
+// do, and every page below one hangs off the nearest, so a theme can draw a
+// sidebar or a breadcrumb from the tree instead of re-deriving it from URLs.
+
+import (
+	"sort"
+	"strings"
+)
+
+// SectionNode is one entry of the section tree: a section, or a page that
+// sits in one.
+type SectionNode struct {
+	Title       string
+	Description string
+	URL         string
+	// Weight orders siblings: lower first, and anything weighted before
+	// anything that is not. Ties fall back to the title.
+	Weight    int
+	IsSection bool
+	// Path is a section's directory below its content root ("guides/advanced");
+	// empty for a page.
+	Path string
+	// Page is the page itself, or a section's _index.md — its .Content and
+	// .Extra are the section's.
+	Page *Page `json:"-"`
+	// Parent is the section above; nil at the top level.
+	Parent *SectionNode `json:"-"`
+	// Children are a section's subsections and pages, in weight order.
+	Children []*SectionNode `json:"-"`
+}
+
+// Ancestors lists the sections above n, the top-level one first — breadcrumb
+// order.
+func (n *SectionNode) Ancestors() []*SectionNode {
+	var out []*SectionNode
+	for p := n.Parent; p != nil; p = p.Parent {
+		out = append([]*SectionNode{p}, out...)
+	}
+	return out
+}
+
+// Root is the top-level section n belongs to: itself for a top-level section.
+func (n *SectionNode) Root() *SectionNode {
+	root := n
+	for root.Parent != nil {
+		root = root.Parent
+	}
+	if !root.IsSection {
+		return nil
+	}
+	return root
+}
+
+// Sections are the child sections only.
+func (n *SectionNode) Sections() []*SectionNode {
+	return n.childrenWhere(true)
+}
+
+// Pages are the child pages only.
+func (n *SectionNode) Pages() []*SectionNode {
+	return n.childrenWhere(false)
+}
+
+func (n *SectionNode) childrenWhere(section bool) []*SectionNode {
+	var out []*SectionNode
+	for _, c := range n.Children {
+		if c.IsSection == section {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Contains reports whether url is n's own or that of anything below it, so a
+// sidebar can open the branch the current page is in.
+func (n *SectionNode) Contains(url string) bool {
+	if n.URL == url {
+		return true
+	}
+	for _, c := range n.Children {
+		if c.Contains(url) {
+			return true
+		}
+	}
+	return false
+}
+
+// SortSectionNodes orders siblings by weight, then title, then URL.
+func SortSectionNodes(nodes []*SectionNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+		if a.Weight != b.Weight {
+			if a.Weight == 0 || b.Weight == 0 {
+				return b.Weight == 0
+			}
+			return a.Weight < b.Weight
+		}
+		if ta, tb := strings.ToLower(a.Title), strings.ToLower(b.Title); ta != tb {
+			return ta < tb
+		}
+		return a.URL < b.URL
+	})
+}
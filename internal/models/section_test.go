@@ -0,0 +1,39 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortSectionNodes(t *testing.T) {
+	nodes := []*SectionNode{{Title: "b"}, {Title: "z", Weight: 3}, {Title: "a"}, {Title: "y", Weight: 1}}
+	SortSectionNodes(nodes)
+	var got []string
+	for _, n := range nodes {
+		got = append(got, n.Title)
+	}
+	if strings.Join(got, "") != "yzab" {
+		t.Errorf("order = %v", got)
+	}
+}
+
+func TestSectionNodeTree(t *testing.T) {
+	top := &SectionNode{Title: "Guides", URL: "/guides/", IsSection: true}
+	sub := &SectionNode{Title: "Advanced", URL: "/guides/advanced/", IsSection: true, Parent: top}
+	page := &SectionNode{Title: "Tuning", URL: "/tuning/", Parent: sub}
+	top.Children = []*SectionNode{sub}
+	sub.Children = []*SectionNode{page}
+
+	if a := page.Ancestors(); len(a) != 2 || a[0] != top || a[1] != sub {
+		t.Errorf("Ancestors = %v", a)
+	}
+	if page.Root() != top || top.Root() != top {
+		t.Error("Root should be the top-level section")
+	}
+	if !top.Contains("/tuning/") || sub.Contains("/guides/") {
+		t.Error("Contains should look at the node and below only")
+	}
+	if len(top.Sections()) != 1 || len(top.Pages()) != 0 || len(sub.Pages()) != 1 {
+		t.Error("Sections/Pages split the children")
+	}
+}
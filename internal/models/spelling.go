@@ -0,0 +1,16 @@
+package models
+
+// SpellingConfig is the `spelling:` block: where check_spelling and
+// `ssg lint --spelling` find a Hunspell dictionary for each language. A
+// language not listed is looked up by its locale in the directories Hunspell
+// itself searches (DICPATH, /usr/share/hunspell, …).
+//
+//	spelling:
+//	  dictionaries:
+//	    en: dictionaries/en_GB        # en_GB.aff + en_GB.dic
+//	    pl: /usr/share/hunspell/pl_PL
+type SpellingConfig struct {
+	// Dictionaries maps a language code to a dictionary path, with or without
+	// the .dic or .aff extension; both files sit side by side.
+	Dictionaries map[string]string `yaml:"dictionaries" toml:"dictionaries" json:"dictionaries,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+// Version is one version of a versioned site (`versions:`), as templates see
+// it in .Versions and .CurrentVersion — enough for a version switcher.
+type Version struct {
+	Name    string // the /<name>/ path segment
+	Title   string // label for a switcher; the name unless one is set
+	Ref     string // the git ref it is built from; "" for the working tree
+	URL     string // absolute URL of the version's home page
+	Latest  bool   // the version the others' canonical links point to
+	Current bool   // the version being rendered
+}
@@ -0,0 +1,68 @@
+package parser
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/spagu/ssg/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults returns the frontmatter a document inherits — from _defaults.yaml
+// files, a section's `cascade:` and content_defaults — given the fields the
+// document sets itself, so a default can depend on them (its `type:`, say).
+// The document's own fields always win; the defaults fill only what it leaves
+// out, one top-level key at a time.
+type Defaults func(own map[string]interface{}) map[string]interface{}
+
+// cascading are the built-in formats, which decode frontmatter through
+// pageFromFrontmatter and so can take defaults. A format registered with
+// RegisterFormat parses its files as they are.
+var cascading = map[string]func(path string, defaults Defaults) (*models.Page, error){
+	".md":    parseMarkdownFile,
+	".ipynb": parseNotebookFile,
+	".org":   parseOrgFile,
+}
+
+// ParseFileWithDefaults parses path as ParseFile does, with the frontmatter
+// defaults returns filling every field the file does not set.
+func ParseFileWithDefaults(path string, defaults Defaults) (*models.Page, error) {
+	formatsMu.RLock()
+	parse, ok := cascading[normalizeExt(filepath.Ext(path))]
+	formatsMu.RUnlock()
+	if !ok || defaults == nil {
+		return ParseFile(path)
+	}
+	return parse(path, defaults)
+}
+
+// withDefaults returns the YAML frontmatter with the defaults for it merged
+// underneath, or the frontmatter untouched when there are none.
+func withDefaults(frontmatter []byte, defaults Defaults) ([]byte, error) {
+	if defaults == nil {
+		return frontmatter, nil
+	}
+	own := map[string]interface{}{}
+	if err := yaml.Unmarshal(frontmatter, &own); err != nil {
+		return nil, err
+	}
+	if own == nil {
+		own = map[string]interface{}{}
+	}
+	inherited := defaults(own)
+	if len(inherited) == 0 {
+		return frontmatter, nil
+	}
+	merged := make(map[string]interface{}, len(own)+len(inherited))
+	for k, v := range inherited {
+		merged[k] = v
+	}
+	for k, v := range own {
+		merged[k] = v
+	}
+	out, err := yaml.Marshal(merged)
+	if err != nil {
+		return nil, fmt.Errorf("frontmatter defaults: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestParseFileWithDefaults: the defaults fill what the file leaves out, see
+// the file's own fields to decide, and never override one — in YAML, TOML and
+// a file with no frontmatter at all.
+func TestParseFileWithDefaults(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"yaml.md":  "---\ntitle: Own\nlayout: mine\n---\n\nBody.\n",
+		"toml.md":  "+++\ntitle = \"Own\"\n+++\n\nBody.\n",
+		"plain.md": "# Plain\n\nBody.\n",
+	}
+	var seen []string
+	defaults := func(own map[string]interface{}) map[string]interface{} {
+		title, _ := own["title"].(string)
+		seen = append(seen, title)
+		return map[string]interface{}{"layout": "recipe", "type": "recipe", "sitemap": "no", "tags": []interface{}{"food"}}
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		page, err := ParseFileWithDefaults(path, defaults)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if page.Type != "recipe" || page.Sitemap != "no" || len(page.Tags) != 1 || page.Content != "Body." {
+			t.Errorf("%s: defaults not applied: %+v", name, page)
+		}
+		wantLayout := "recipe"
+		if name == "yaml.md" {
+			wantLayout = "mine"
+		}
+		if page.Layout != wantLayout {
+			t.Errorf("%s: layout = %q, want %q", name, page.Layout, wantLayout)
+		}
+		if name == "plain.md" && (page.Title != "Plain" || page.Status != "publish") {
+			t.Errorf("plain.md keeps its derived title and status: %q %q", page.Title, page.Status)
+		}
+	}
+	if len(seen) != 3 {
+		t.Errorf("defaults asked %d times, want once per file", len(seen))
+	}
+}
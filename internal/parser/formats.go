@@ -0,0 +1,122 @@
+package parser
+
+// Content formats.
+//
+// A content file is turned into a models.Page by the format registered for its
+// extension. Markdown is the native one; Jupyter notebooks (.ipynb) and
+// Org-mode (.org) are built in beside it. Both of those convert to the same
+// Markdown body a .md file would have and take their metadata through the same
+// frontmatter decoding, so everything downstream — Markdown rendering, code
+// highlighting, link rewriting, excerpts, Markdown publishing — treats every
+// page alike and needs no format switch of its own.
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+// Format parses one content file into a Page.
+type Format func(path string) (*models.Page, error)
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Format{
+		".md":    ParseMarkdownFile,
+		".ipynb": ParseNotebookFile,
+		".org":   ParseOrgFile,
+	}
+)
+
+// RegisterFormat makes files with extension ext (".rst", case-insensitive)
+// content, parsed by f. Registering an extension again replaces its format;
+// a replaced built-in no longer takes cascaded defaults (defaults.go).
+func RegisterFormat(ext string, f Format) {
+	ext = normalizeExt(ext)
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	formats[ext] = f
+	delete(cascading, ext)
+}
+
+// FormatFor returns the format registered for path's extension.
+func FormatFor(path string) (Format, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	f, ok := formats[normalizeExt(filepath.Ext(path))]
+	return f, ok
+}
+
+// IsContentFile reports whether path has a registered content format.
+func IsContentFile(path string) bool {
+	_, ok := FormatFor(path)
+	return ok
+}
+
+// ContentExtensions lists the registered extensions, sorted.
+func ContentExtensions() []string {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	exts := make([]string, 0, len(formats))
+	for ext := range formats {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return exts
+}
+
+// ParseFile parses a content file with the format for its extension.
+func ParseFile(path string) (*models.Page, error) {
+	f, ok := FormatFor(path)
+	if !ok {
+		return nil, fmt.Errorf("%s: no content format for %q files", path, filepath.Ext(path))
+	}
+	return f(path)
+}
+
+func normalizeExt(ext string) string {
+	ext = strings.ToLower(strings.TrimSpace(ext))
+	if ext != "" && !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return ext
+}
+
+// codeFence returns a backtick fence longer than any backtick run in body, so
+// converted code that itself contains ``` cannot close its block early.
+func codeFence(body string) string {
+	longest, run := 0, 0
+	for _, r := range body {
+		if r == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+			continue
+		}
+		run = 0
+	}
+	if longest < 3 {
+		return "```"
+	}
+	return strings.Repeat("`", longest+1)
+}
+
+// FencedBlock returns body as a fenced code block with the given info string,
+// fenced so no backtick run inside it can close the block early.
+func FencedBlock(info, body string) string {
+	var b strings.Builder
+	writeFenced(&b, info, body)
+	return b.String()
+}
+
+// writeFenced appends body as a fenced code block with the given info string.
+func writeFenced(b *strings.Builder, info, body string) {
+	body = strings.TrimRight(body, "\n")
+	fence := codeFence(body)
+	b.WriteString(fence + info + "\n" + body + "\n" + fence + "\n\n")
+}
@@ -0,0 +1,75 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+func TestFormatRegistry(t *testing.T) {
+	for _, name := range []string{"a.md", "B.MD", "nb.ipynb", "notes.org"} {
+		if !IsContentFile(name) {
+			t.Errorf("%s should be content", name)
+		}
+	}
+	for _, name := range []string{"a.txt", "a.markdown.bak", "org", "plot.png"} {
+		if IsContentFile(name) {
+			t.Errorf("%s should not be content", name)
+		}
+	}
+	if _, err := ParseFile("notes.txt"); err == nil || !strings.Contains(err.Error(), ".txt") {
+		t.Errorf("ParseFile on an unknown extension = %v", err)
+	}
+
+	RegisterFormat("RST", func(path string) (*models.Page, error) {
+		return &models.Page{Title: filepath.Base(path), Status: "publish"}, nil
+	})
+	t.Cleanup(func() {
+		formatsMu.Lock()
+		delete(formats, ".rst")
+		formatsMu.Unlock()
+	})
+	page, err := ParseFile("docs/intro.rst")
+	if err != nil || page.Title != "intro.rst" {
+		t.Errorf("registered format not used: %+v, %v", page, err)
+	}
+	if got := strings.Join(ContentExtensions(), " "); got != ".ipynb .md .org .rst" {
+		t.Errorf("ContentExtensions = %q", got)
+	}
+}
+
+// The same frontmatter means the same thing in every format.
+func TestFormatsShareFrontmatter(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"post.md": "---\ntitle: Same\nstatus: publish\ndate: 2024-03-05\ntags: [go]\nmood: calm\n---\n\nBody.\n",
+		"post.ipynb": `{"cells":[{"cell_type":"raw","source":["---\n","title: Same\n","status: publish\n","date: 2024-03-05\n",` +
+			`"tags: [go]\n","mood: calm\n","---\n"]},{"cell_type":"markdown","source":"Body."}],"metadata":{},"nbformat":4}`,
+		"post.org": "#+TITLE: Same\n#+DATE: <2024-03-05 Tue>\n#+FILETAGS: :go:\n#+MOOD: calm\n\nBody.\n",
+	}
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		p, err := ParseFile(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if p.Title != "Same" || p.Status != "publish" || p.Date.Format("2006-01-02") != "2024-03-05" ||
+			len(p.Tags) != 1 || p.Tags[0] != "go" || p.Extra["mood"] != "calm" || p.Content != "Body." {
+			t.Errorf("%s parsed as %+v", name, p)
+		}
+	}
+}
+
+func TestCodeFenceOutgrowsContent(t *testing.T) {
+	var b strings.Builder
+	writeFenced(&b, "md", "before\n```go\nx\n```\nafter")
+	if !strings.HasPrefix(b.String(), "````md\n") || !strings.HasSuffix(b.String(), "\n````\n\n") {
+		t.Errorf("fence should be longer than the inner one:\n%s", b.String())
+	}
+}
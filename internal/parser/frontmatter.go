@@ -0,0 +1,300 @@
+package parser
+
+// Frontmatter formats. YAML between "---" lines is the native one, but content
+// migrated from Hugo opens with a "+++" TOML block and some generators emit a
+// leading JSON object. Read as frontmatter-less, those files published with
+// their metadata as body text and a derived title. The opening line says which
+// format a file uses:
+//
+//	---            +++                {
+//	title: Hello   title = "Hello"      "title": "Hello"
+//	---            +++                }
+//
+// TOML and JSON are decoded to a map and go through the same PageFrontmatter
+// and Extra path as YAML, so `tags` means the same whatever the file was
+// written in. Their errors name the line in the file, not in the block.
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spagu/ssg/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// FrontmatterFormat is how a content file's frontmatter is written.
+type FrontmatterFormat string
+
+const (
+	FrontmatterNone FrontmatterFormat = ""
+	FrontmatterYAML FrontmatterFormat = "yaml"
+	FrontmatterTOML FrontmatterFormat = "toml"
+	FrontmatterJSON FrontmatterFormat = "json"
+)
+
+// frontmatterOpener returns the format a first non-blank line opens, or
+// FrontmatterNone when the file starts straight with content.
+func frontmatterOpener(line string) FrontmatterFormat {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case trimmed == "---":
+		return FrontmatterYAML
+	case trimmed == "+++":
+		return FrontmatterTOML
+	case strings.HasPrefix(trimmed, "{"):
+		// An object opens with a key or closes empty; "{{<" and "{%" are a
+		// shortcode or a tag starting a frontmatter-less body.
+		if rest := strings.TrimSpace(trimmed[1:]); rest == "" || rest[0] == '"' || rest[0] == '}' {
+			return FrontmatterJSON
+		}
+	}
+	return FrontmatterNone
+}
+
+// frontmatterFence tracks where a frontmatter block ends. YAML and TOML end
+// at their delimiter line; JSON ends where its object's braces balance, so the
+// fence follows strings and escapes to not count a "}" inside a value.
+type frontmatterFence struct {
+	format   FrontmatterFormat
+	depth    int
+	inString bool
+	escaped  bool
+}
+
+// closes reports whether line ends the block. For JSON every line — the
+// opening one included — is part of the block and must be fed in order.
+func (f *frontmatterFence) closes(line string) bool {
+	switch f.format {
+	case FrontmatterYAML:
+		return isFrontmatterDelimiter(line)
+	case FrontmatterTOML:
+		return strings.TrimSpace(line) == "+++"
+	}
+	for _, r := range line {
+		switch {
+		case f.escaped:
+			f.escaped = false
+		case f.inString && r == '\\':
+			f.escaped = true
+		case r == '"':
+			f.inString = !f.inString
+		case f.inString:
+		case r == '{':
+			f.depth++
+		case r == '}':
+			f.depth--
+			if f.depth == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// closer names what an unclosed block is missing, for the error.
+func (f *frontmatterFence) closer() string {
+	switch f.format {
+	case FrontmatterTOML:
+		return `"+++"`
+	case FrontmatterJSON:
+		return `"}"`
+	}
+	return `"---"`
+}
+
+// frontmatterSpan locates a document's frontmatter block: the line it opens
+// on, the first line after it, and whether it closes at all.
+func frontmatterSpan(lines []string) (format FrontmatterFormat, start, end int, closed bool) {
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) {
+		return FrontmatterNone, 0, 0, false
+	}
+	fence := &frontmatterFence{format: frontmatterOpener(lines[start])}
+	switch fence.format {
+	case FrontmatterNone:
+		return FrontmatterNone, 0, 0, false
+	case FrontmatterJSON:
+		if fence.closes(lines[start]) {
+			return fence.format, start, start + 1, true
+		}
+	}
+	for i := start + 1; i < len(lines); i++ {
+		if fence.closes(lines[i]) {
+			return fence.format, start, i + 1, true
+		}
+	}
+	return fence.format, start, len(lines), false
+}
+
+// FrontmatterEnd returns the index of the first body line of a document split
+// into lines: 0 without frontmatter, len(lines) when the block never closes.
+// Tools that rewrite the body (ssg repair) use it to leave the block alone.
+func FrontmatterEnd(lines []string) int {
+	_, _, end, _ := frontmatterSpan(lines)
+	return end
+}
+
+// SplitFrontmatter separates a document into its frontmatter format, the
+// block's content and the body after it. JSON keeps its braces; YAML and TOML
+// lose their delimiter lines. ok is false for a document without a closed
+// block.
+func SplitFrontmatter(content string) (format FrontmatterFormat, frontmatter, body string, ok bool) {
+	lines := strings.Split(content, "\n")
+	format, start, end, closed := frontmatterSpan(lines)
+	if !closed {
+		return FrontmatterNone, "", content, false
+	}
+	block := lines[start:end]
+	if format != FrontmatterJSON {
+		block = block[1 : len(block)-1]
+	}
+	return format, strings.Join(block, "\n") + "\n", strings.Join(lines[end:], "\n"), true
+}
+
+// decodeFrontmatter turns a block in any format into a Page and its fields.
+// line is the file line the block's first line sits on, for error messages.
+func decodeFrontmatter(format FrontmatterFormat, raw []byte, line int, defaults Defaults) (*models.Page, map[string]interface{}, error) {
+	if format == FrontmatterNone || format == FrontmatterYAML {
+		return pageFromFrontmatter(raw, defaults)
+	}
+	fields, err := decodeFields(format, raw, line)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Through YAML, as Org keywords and page_generators rows go: one decoder
+	// for PageFrontmatter, whatever the file was written in.
+	frontmatter, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pageFromFrontmatter(frontmatter, defaults)
+}
+
+// decodeFields decodes a TOML or JSON block into plain values.
+func decodeFields(format FrontmatterFormat, raw []byte, line int) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	switch format {
+	case FrontmatterTOML:
+		if err := toml.Unmarshal(raw, &fields); err != nil {
+			var pe toml.ParseError
+			if errors.As(err, &pe) {
+				return nil, fmt.Errorf("TOML frontmatter: line %d: %s", line+pe.Position.Line-1, pe.Message)
+			}
+			return nil, fmt.Errorf("TOML frontmatter: %w", err)
+		}
+	case FrontmatterJSON:
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil {
+			var syntax *json.SyntaxError
+			var typ *json.UnmarshalTypeError
+			switch {
+			case errors.As(err, &syntax):
+				return nil, fmt.Errorf("JSON frontmatter: line %d: %v", line+bytes.Count(raw[:syntax.Offset], []byte("\n")), err)
+			case errors.As(err, &typ):
+				return nil, fmt.Errorf("JSON frontmatter: line %d: %v", line+bytes.Count(raw[:typ.Offset], []byte("\n")), err)
+			}
+			return nil, fmt.Errorf("JSON frontmatter: %w", err)
+		}
+	}
+	return plainFields(fields).(map[string]interface{}), nil
+}
+
+// plainFields rewrites decoder-specific values into what YAML would have
+// produced: JSON numbers become ints where they are whole, and TOML's local
+// dates and times — time.Time values in a marker zone — become the strings
+// they were written as, so `date = 2024-01-02` parses like `date: 2024-01-02`.
+func plainFields(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, x := range val {
+			val[k] = plainFields(x)
+		}
+		return val
+	case []interface{}:
+		for i, x := range val {
+			val[i] = plainFields(x)
+		}
+		return val
+	case []map[string]interface{}: // a TOML array of tables
+		out := make([]interface{}, len(val))
+		for i, x := range val {
+			out[i] = plainFields(x)
+		}
+		return out
+	case json.Number:
+		if n, err := strconv.ParseInt(string(val), 10, 64); err == nil {
+			return n
+		}
+		f, _ := val.Float64()
+		return f
+	case time.Time:
+		switch val.Location().String() {
+		case "date-local":
+			return val.Format("2006-01-02")
+		case "datetime-local":
+			return val.Format("2006-01-02T15:04:05")
+		case "time-local":
+			return val.Format("15:04:05")
+		}
+	}
+	return v
+}
+
+// ConvertFrontmatter re-encodes a document's frontmatter block in format,
+// leaving the body byte-for-byte as it was. Keys come out sorted: the fields
+// survive the trip, their order and comments do not. A document without a
+// block, or already in format, is returned unchanged.
+func ConvertFrontmatter(content string, format FrontmatterFormat) (string, error) {
+	from, block, body, ok := SplitFrontmatter(content)
+	if !ok || from == format || format == FrontmatterNone {
+		return content, nil
+	}
+	fields := map[string]interface{}{}
+	if from == FrontmatterYAML {
+		if err := yaml.Unmarshal([]byte(block), &fields); err != nil {
+			return "", fmt.Errorf("YAML frontmatter: %w", err)
+		}
+	} else {
+		decoded, err := decodeFields(from, []byte(block), 1)
+		if err != nil {
+			return "", err
+		}
+		fields = decoded
+	}
+
+	var b strings.Builder
+	switch format {
+	case FrontmatterYAML:
+		out, err := yaml.Marshal(fields)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString("---\n" + string(out) + "---\n")
+	case FrontmatterTOML:
+		b.WriteString("+++\n")
+		if err := toml.NewEncoder(&b).Encode(fields); err != nil {
+			return "", err
+		}
+		b.WriteString("+++\n")
+	case FrontmatterJSON:
+		out, err := json.MarshalIndent(fields, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		b.Write(out)
+		b.WriteString("\n")
+	default:
+		return "", fmt.Errorf("unknown frontmatter format %q", format)
+	}
+	b.WriteString(body)
+	return b.String(), nil
+}
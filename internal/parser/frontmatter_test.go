@@ -0,0 +1,117 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTOMLFrontmatter(t *testing.T) {
+	path := writeTemp(t, "hugo.md", `+++
+title = "From Hugo"
+date = 2024-03-05
+status = "publish"
+tags = ["go", "ssg"]
+weight = 3
+subtitle = "Kept"
+
+[params]
+color = "red"
++++
+
+Body with a "+++" line below.
+
++++
+`)
+	p, err := ParseMarkdownFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "From Hugo" || p.Status != "publish" || p.Weight != 3 {
+		t.Errorf("fields = %q %q %d", p.Title, p.Status, p.Weight)
+	}
+	if want := time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC); !p.Date.Equal(want) {
+		t.Errorf("date = %v, want %v", p.Date, want)
+	}
+	if len(p.Tags) != 2 || p.Tags[1] != "ssg" {
+		t.Errorf("tags = %v", p.Tags)
+	}
+	if p.Extra["subtitle"] != "Kept" {
+		t.Errorf("extra = %v", p.Extra)
+	}
+	if params, _ := p.Extra["params"].(map[string]interface{}); params["color"] != "red" {
+		t.Errorf("nested table = %v", p.Extra["params"])
+	}
+	if !strings.HasSuffix(p.Content, "+++") {
+		t.Errorf("a later +++ is content: %q", p.Content)
+	}
+}
+
+func TestParseJSONFrontmatter(t *testing.T) {
+	path := writeTemp(t, "gen.md", `{
+  "title": "Generated",
+  "id": 1000000,
+  "status": "publish",
+  "note": "a } inside a \" string",
+  "meta": {"rating": 4.5}
+}
+
+Body.
+`)
+	p, err := ParseMarkdownFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Generated" || p.ID != 1000000 || p.Content != "Body." {
+		t.Errorf("page = %q %d %q", p.Title, p.ID, p.Content)
+	}
+	if p.Extra["note"] != `a } inside a " string` {
+		t.Errorf("note = %v", p.Extra["note"])
+	}
+	if meta, _ := p.Extra["meta"].(map[string]interface{}); meta["rating"] != 4.5 {
+		t.Errorf("meta = %v", p.Extra["meta"])
+	}
+
+	// A shortcode opening a plain file is not a JSON object.
+	plain := writeTemp(t, "plain.md", "{{< youtube abc >}}\n\nText.\n")
+	if p, err := ParseMarkdownFile(plain); err != nil || p.Status != "publish" || !strings.HasPrefix(p.Content, "{{<") {
+		t.Errorf("shortcode file = %+v, %v", p, err)
+	}
+}
+
+func TestFrontmatterErrorLines(t *testing.T) {
+	for _, tc := range []struct{ name, src, want string }{
+		{"toml.md", "\n+++\ntitle = \"ok\"\ntitle = \"twice\"\n+++\nBody\n", "TOML frontmatter: line 4"},
+		{"json.md", "{\n  \"title\": \"ok\",\n  \"status\" \"publish\"\n}\nBody\n", "JSON frontmatter: line 3"},
+		{"unclosed.md", "+++\ntitle = \"x\"\n", `missing closing "+++"`},
+	} {
+		_, err := ParseMarkdownFile(writeTemp(t, tc.name, tc.src))
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: err = %v, want %q", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestConvertFrontmatter(t *testing.T) {
+	yamlDoc := "---\ntitle: Hi\ntags: [a, b]\n---\n\nBody.\n"
+	toml, err := ConvertFrontmatter(yamlDoc, FrontmatterTOML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "+++\ntags = [\"a\", \"b\"]\ntitle = \"Hi\"\n+++\n\nBody.\n"; toml != want {
+		t.Errorf("toml =\n%s\nwant\n%s", toml, want)
+	}
+	json, err := ConvertFrontmatter(toml, FrontmatterJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if format, _, body, ok := SplitFrontmatter(json); !ok || format != FrontmatterJSON || body != "\nBody.\n" {
+		t.Errorf("json = %q", json)
+	}
+	if same, _ := ConvertFrontmatter(yamlDoc, FrontmatterYAML); same != yamlDoc {
+		t.Errorf("same format must be unchanged: %q", same)
+	}
+	if end := FrontmatterEnd(strings.Split(json, "\n")); end != 7 {
+		t.Errorf("FrontmatterEnd = %d, want 7", end)
+	}
+}
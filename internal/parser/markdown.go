@@ -1,4 +1,5 @@
-// Package parser handles parsing of content files (Markdown with YAML frontmatter)
+// Package parser handles parsing of content files (Markdown with YAML, TOML
+// or JSON frontmatter, and the other formats registered in formats.go)
 package parser
 
 import (
@@ -34,17 +35,27 @@ type markdownParser struct {
 	inFence          bool   // inside a fenced code block (GO-027)
 	fence            string // marker that opened the current fence: "```" or "~~~"
 	firstH1          string // first "# " heading, the title fallback (GO-057)
+
+	fm     frontmatterFence // the block's format and where it ends
+	lineNo int              // lines read so far
+	fmLine int              // file line of the block's first line, for decode errors
+
+	defaults Defaults // cascaded frontmatter under the file's own (defaults.go)
 }
 
-// ParseMarkdownFile parses a markdown file with YAML frontmatter
+// ParseMarkdownFile parses a markdown file with YAML, TOML or JSON frontmatter
 func ParseMarkdownFile(filepath string) (*models.Page, error) {
+	return parseMarkdownFile(filepath, nil)
+}
+
+func parseMarkdownFile(filepath string, defaults Defaults) (*models.Page, error) {
 	file, err := os.Open(filepath) // #nosec G304 -- CLI tool reads user's content files
 	if err != nil {
 		return nil, err
 	}
 	defer func() { _ = file.Close() }()
 
-	p := &markdownParser{}
+	p := &markdownParser{defaults: defaults}
 	scanner := bufio.NewScanner(file)
 	// GO-039: raise the per-line limit above the 64KB bufio default so long
 	// lines (e.g. base64 data URIs) do not fail the whole file.
@@ -61,7 +72,7 @@ func ParseMarkdownFile(filepath string) (*models.Page, error) {
 	// GO-039: an opening "---" without a closing one would silently swallow
 	// the whole body into the frontmatter, yielding an empty page.
 	if p.inFrontmatter {
-		return nil, fmt.Errorf("%s: unclosed frontmatter (missing closing \"---\")", filepath)
+		return nil, fmt.Errorf("%s: unclosed frontmatter (missing closing %s)", filepath, p.fm.closer())
 	}
 
 	return p.buildPage()
@@ -69,28 +80,37 @@ func ParseMarkdownFile(filepath string) (*models.Page, error) {
 
 // processLine handles a single line during parsing
 func (p *markdownParser) processLine(line string) {
+	p.lineNo++
 	// On the first non-blank line decide whether the file opens with frontmatter.
 	// A file that does not start with "---" would otherwise have every line
 	// dropped, silently yielding empty content (GO-009); instead treat the whole
 	// file as content. Leading blank lines before "---" are tolerated as before.
+	// "+++" opens TOML and "{" a JSON object, the other two formats.
 	if !p.decided {
 		if strings.TrimSpace(line) == "" {
 			return
 		}
 		p.decided = true
-		if !isFrontmatterDelimiter(line) {
+		p.fm.format = frontmatterOpener(line)
+		switch p.fm.format {
+		case FrontmatterNone:
 			p.noFrontmatter = true
 			p.frontmatterEnded = true
 			p.processContentLine(line)
 			return
+		case FrontmatterJSON:
+			// The opening brace is part of the object.
+			p.inFrontmatter = true
+			p.fmLine = p.lineNo
+		default:
+			p.inFrontmatter = true
+			p.fmLine = p.lineNo + 1
+			return
 		}
 	}
 
-	if p.handleFrontmatterDelimiter(line) {
-		return
-	}
 	if p.inFrontmatter {
-		p.frontmatter.WriteString(line + "\n")
+		p.frontmatterLine(line)
 		return
 	}
 	if p.frontmatterEnded {
@@ -105,18 +125,17 @@ func isFrontmatterDelimiter(line string) bool {
 	return strings.TrimSpace(line) == "---"
 }
 
-// handleFrontmatterDelimiter handles --- delimiters
-func (p *markdownParser) handleFrontmatterDelimiter(line string) bool {
-	if !isFrontmatterDelimiter(line) || p.frontmatterEnded {
-		return false
+// frontmatterLine collects a line of the open block, ending the block at its
+// closing delimiter — or, for JSON, at the brace that balances the object.
+func (p *markdownParser) frontmatterLine(line string) {
+	closes := p.fm.closes(line)
+	if !closes || p.fm.format == FrontmatterJSON {
+		p.frontmatter.WriteString(line + "\n")
 	}
-	if !p.inFrontmatter {
-		p.inFrontmatter = true
-	} else {
+	if closes {
 		p.inFrontmatter = false
 		p.frontmatterEnded = true
 	}
-	return true
 }
 
 // fenceMarker returns the code-fence marker ("```" or "~~~") that starts the
@@ -192,19 +211,74 @@ func (p *markdownParser) writeContentLine(line string) {
 
 // buildPage creates a Page from parsed content
 func (p *markdownParser) buildPage() (*models.Page, error) {
-	pf := &PageFrontmatter{}
-	if err := yaml.Unmarshal([]byte(p.frontmatter.String()), pf); err != nil {
+	page, allFields, err := decodeFrontmatter(p.fm.format, []byte(p.frontmatter.String()), p.fmLine, p.defaults)
+	if err != nil {
 		return nil, err
 	}
+	page.Excerpt = strings.TrimSpace(p.excerpt.String())
+	page.Content = strings.TrimSpace(p.content.String())
+	completePage(page, allFields, !p.noFrontmatter, p.derivedTitle)
+	return page, nil
+}
+
+// pageFromFrontmatter decodes YAML frontmatter into a Page, keeping the fields
+// PageFrontmatter does not model in Extra. Every content format goes through
+// it, so `tags:` means the same in a notebook or an Org file as in Markdown —
+// and cascaded defaults fill the same fields whatever the format.
+func pageFromFrontmatter(frontmatter []byte, defaults Defaults) (*models.Page, map[string]interface{}, error) {
+	frontmatter, err := withDefaults(frontmatter, defaults)
+	if err != nil {
+		return nil, nil, err
+	}
+	pf := &PageFrontmatter{}
+	if err := yaml.Unmarshal(frontmatter, pf); err != nil {
+		return nil, nil, err
+	}
 
 	// Parse all frontmatter into a map for Extra fields
 	var allFields map[string]interface{}
-	if err := yaml.Unmarshal([]byte(p.frontmatter.String()), &allFields); err != nil {
-		return nil, err
+	if err := yaml.Unmarshal(frontmatter, &allFields); err != nil {
+		return nil, nil, err
 	}
 
 	page := pf.ToPage()
-	page.Excerpt = strings.TrimSpace(p.excerpt.String())
+	if page.PublishDate, err = scheduleDate("publish_date", pf.PublishDate); err != nil {
+		return nil, nil, err
+	}
+	if page.ExpiryDate, err = scheduleDate("expiry_date", pf.ExpiryDate); err != nil {
+		return nil, nil, err
+	}
+	menus, err := models.ParseMenuField(pf.Menu)
+	if err != nil {
+		return nil, nil, err
+	}
+	page.MenuFM = menus
+	// Copy extra fields (those not in the struct)
+	page.Extra = extractExtraFields(allFields)
+	return page, allFields, nil
+}
+
+// PageFromFields builds a Page from frontmatter that is already decoded and a
+// Markdown body, as a content file holding them would parse — for pages made
+// from data rows rather than files (page_generators).
+func PageFromFields(fields map[string]interface{}, content string) (*models.Page, error) {
+	frontmatter, err := yaml.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+	page, allFields, err := pageFromFrontmatter(frontmatter, nil)
+	if err != nil {
+		return nil, err
+	}
+	page.Content = strings.TrimSpace(content)
+	completePage(page, allFields, true, func() string { return "" })
+	return page, nil
+}
+
+// completePage applies the rules every format shares once its Excerpt and
+// Content are set. title is the document's own title, asked for only when the
+// frontmatter names none.
+func completePage(page *models.Page, allFields map[string]interface{}, hasFrontmatter bool, title func() string) {
 	// A frontmatter `excerpt:` fills the summary when no "## Excerpt" section
 	// did — the section wins, then frontmatter, then auto_excerpt. WordPress
 	// exporters write the excerpt as frontmatter, and without this it silently
@@ -214,12 +288,11 @@ func (p *markdownParser) buildPage() (*models.Page, error) {
 			page.Excerpt = strings.TrimSpace(ex)
 		}
 	}
-	page.Content = strings.TrimSpace(p.content.String())
 
 	// A file with no frontmatter has no status and would be skipped by the
 	// generator (which keeps only status == "publish"). Treat such a plain
 	// content file as published instead of silently dropping it (GO-009).
-	if p.noFrontmatter && page.Status == "" {
+	if !hasFrontmatter && page.Status == "" {
 		page.Status = "publish"
 	}
 
@@ -227,15 +300,10 @@ func (p *markdownParser) buildPage() (*models.Page, error) {
 	// <title> for that page blank. Fall back to the document's own first level-1
 	// heading, the way a reader would name it (GO-057).
 	if page.Title == "" {
-		page.Title = p.derivedTitle()
+		page.Title = title()
 	}
 
-	// Copy extra fields (those not in the struct)
-	page.Extra = extractExtraFields(allFields)
-
 	decodeTextEntities(page)
-
-	return page, nil
 }
 
 // decodeTextEntities turns HTML entities in PLAIN-TEXT frontmatter fields into
@@ -256,13 +324,14 @@ func decodeTextEntities(page *models.Page) {
 // knownFields lists all fields that are handled by PageFrontmatter struct
 var knownFields = map[string]bool{
 	"id": true, "title": true, "slug": true, "date": true, "modified": true,
-	"status": true, "type": true, "link": true, "author": true, "categories": true,
+	"status": true, "type": true, "link": true, "author": true, "authors": true, "categories": true,
 	"excerpt":     true,
 	"description": true, "keywords": true, "lang": true, "canonical": true,
 	"translation_key": true,
 	"robots":          true, "featured_image": true, "tags": true, "category": true,
 	"layout": true, "template": true, "sitemap": true, "aliases": true, "series": true,
 	"taxonomies": true, "sticky": true,
+	"publish_date": true, "expiry_date": true, "resources": true,
 }
 
 // extractExtraFields returns fields not in knownFields
@@ -290,8 +359,14 @@ type PageFrontmatter struct {
 	Type     string `yaml:"type"`
 	Link     string `yaml:"link"`
 
+	// Scheduling: the page is built from publish_date (default: date) until
+	// expiry_date. Same formats as date.
+	PublishDate string `yaml:"publish_date"`
+	ExpiryDate  string `yaml:"expiry_date"`
+
 	// Flexible fields: accept int or string for author and categories
 	Author     interface{}   `yaml:"author"`
+	Authors    []interface{} `yaml:"authors,omitempty"`
 	Categories []interface{} `yaml:"categories,omitempty"`
 
 	// SEO and metadata fields
@@ -308,6 +383,12 @@ type PageFrontmatter struct {
 	Aliases        []string `yaml:"aliases,omitempty"` // old paths that redirect here (SEO-002)
 	Series         string   `yaml:"series,omitempty"`  // series grouping (AX-005)
 	Sticky         bool     `yaml:"sticky,omitempty"`  // pinned to the top of date-ordered listings (#155)
+	// Weight orders the page in its section. Deliberately not in knownFields:
+	// themes read {{.weight}} from Extra since before it had a meaning.
+	Weight int `yaml:"weight,omitempty"`
+	// Menu places the page in navigation menus; kept out of knownFields for
+	// the same reason as weight.
+	Menu interface{} `yaml:"menu,omitempty"`
 
 	// AliasStubs overrides the site-wide alias_stubs default per page: false =
 	// 301 only (no duplicate copy), true = force a stub (#65).
@@ -321,6 +402,9 @@ type PageFrontmatter struct {
 	// priority over direct fields and legacy category/tags/series.
 	Taxonomies map[string]interface{} `yaml:"taxonomies,omitempty"`
 
+	// Resources attaches title, alt and params to bundle files by glob.
+	Resources []models.ResourceMeta `yaml:"resources,omitempty"`
+
 	// Template selection
 	Layout   string `yaml:"layout"`
 	Template string `yaml:"template"`
@@ -405,6 +489,17 @@ func parseFlexibleDate(dateStr string) time.Time {
 	return time.Time{}
 }
 
+// scheduleDate parses publish_date or expiry_date. Unlike date, a value that
+// does not parse is an error rather than no date: read as none, it would lift
+// an embargo or cancel a take-down without a word.
+func scheduleDate(field, value string) (time.Time, error) {
+	t := parseFlexibleDate(value)
+	if t.IsZero() && value != "" {
+		return time.Time{}, fmt.Errorf("%s: %q is not a date (want 2006-01-02 or RFC 3339)", field, value)
+	}
+	return t, nil
+}
+
 // ParseFrontmatterWithDates handles date parsing from frontmatter
 func (pf *PageFrontmatter) ToPage() *models.Page {
 	date := parseFlexibleDate(pf.Date)
@@ -424,6 +519,7 @@ func (pf *PageFrontmatter) ToPage() *models.Page {
 		Link:          pf.Link,
 		Author:        authorID,
 		AuthorRaw:     authorRaw,
+		AuthorsRaw:    pf.Authors,
 		Categories:    catIDs,
 		CategoriesRaw: catRaw,
 		// SEO and metadata fields
@@ -441,8 +537,10 @@ func (pf *PageFrontmatter) ToPage() *models.Page {
 		AliasStubs:     pf.AliasStubs,
 		Series:         pf.Series,
 		Sticky:         pf.Sticky,
+		Weight:         pf.Weight,
 		Schema:         pf.Schema,
 		TaxonomiesFM:   pf.Taxonomies,
+		ResourcesFM:    pf.Resources,
 		// Template selection
 		Layout:   pf.Layout,
 		Template: pf.Template,
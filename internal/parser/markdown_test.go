@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 // TestFrontmatterExcerpt covers #115: a frontmatter `excerpt:` reaches
@@ -168,6 +169,42 @@ Post content here.
 	}
 }
 
+func TestParseMarkdownFileSchedule(t *testing.T) {
+	testFile := filepath.Join(t.TempDir(), "sale.md")
+	content := "---\ntitle: Sale\nstatus: publish\npublish_date: 2024-06-10T09:00:00Z\nexpiry_date: 2024-07-01\n---\n\nBody\n"
+	if err := os.WriteFile(testFile, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	page, err := ParseMarkdownFile(testFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 6, 10, 9, 0, 0, 0, time.UTC); !page.PublishDate.Equal(want) {
+		t.Errorf("PublishDate = %v, want %v", page.PublishDate, want)
+	}
+	if want := time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC); !page.ExpiryDate.Equal(want) {
+		t.Errorf("ExpiryDate = %v, want %v", page.ExpiryDate, want)
+	}
+	if _, leaked := page.Extra["publish_date"]; leaked {
+		t.Error("publish_date is a known field, not an Extra")
+	}
+}
+
+// A schedule date that does not parse fails the page: taken as no date, the
+// embargo or take-down would silently not happen.
+func TestParseMarkdownFileBadScheduleDate(t *testing.T) {
+	for _, fm := range []string{"publish_date: 2025-13-01", "expiry_date: tomorrow"} {
+		testFile := filepath.Join(t.TempDir(), "sale.md")
+		if err := os.WriteFile(testFile, []byte("---\ntitle: Sale\n"+fm+"\n---\n\nBody\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		field := strings.SplitN(fm, ":", 2)[0]
+		if _, err := ParseMarkdownFile(testFile); err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("%s: err = %v, want one naming %s", fm, err, field)
+		}
+	}
+}
+
 func TestParseMarkdownFileNotFound(t *testing.T) {
 	_, err := ParseMarkdownFile("/nonexistent/file.md")
 	if err == nil {
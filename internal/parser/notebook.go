@@ -0,0 +1,255 @@
+package parser
+
+// Jupyter notebooks (.ipynb).
+//
+// A notebook becomes the Markdown page a reader would expect from it: markdown
+// cells verbatim, code cells as fenced blocks in the kernel's language (so
+// --highlight colours them like any other code), and what the cells printed
+// below them. Text outputs become plain blocks; PNG outputs and images pasted
+// into markdown cells are extracted into Page.Assets and linked by file name,
+// so they are published next to the page like co-located assets.
+//
+// Metadata comes from a leading raw cell holding YAML frontmatter between
+// "---" lines — the convention Quarto and nbconvert templates share. Without
+// one the notebook is published, titled by its first "# " heading, as a plain
+// Markdown file would be (GO-009, GO-057). Cells tagged remove-cell,
+// remove-input or remove-output (Jupyter Book's tags) drop that part.
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+)
+
+type notebook struct {
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+	} `json:"metadata"`
+}
+
+type notebookCell struct {
+	CellType string          `json:"cell_type"`
+	Source   json.RawMessage `json:"source"`
+	Metadata struct {
+		Tags []string `json:"tags"`
+	} `json:"metadata"`
+	Outputs     []notebookOutput                      `json:"outputs"`
+	Attachments map[string]map[string]json.RawMessage `json:"attachments"`
+}
+
+type notebookOutput struct {
+	OutputType string                     `json:"output_type"`
+	Text       json.RawMessage            `json:"text"`
+	Data       map[string]json.RawMessage `json:"data"`
+	Ename      string                     `json:"ename"`
+	Evalue     string                     `json:"evalue"`
+	Traceback  []string                   `json:"traceback"`
+}
+
+// ParseNotebookFile parses a Jupyter notebook into a Page.
+func ParseNotebookFile(path string) (*models.Page, error) {
+	return parseNotebookFile(path, nil)
+}
+
+func parseNotebookFile(path string, defaults Defaults) (*models.Page, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- CLI tool reads user's content files
+	if err != nil {
+		return nil, err
+	}
+	var nb notebook
+	if err := json.Unmarshal(raw, &nb); err != nil {
+		return nil, fmt.Errorf("%s: not a notebook: %w", path, err)
+	}
+	lang := nb.Metadata.Kernelspec.Language
+	if lang == "" {
+		lang = nb.Metadata.LanguageInfo.Name
+	}
+
+	cells := nb.Cells
+	var frontmatter string
+	if len(cells) > 0 && cells[0].CellType == "raw" {
+		if fm, ok := rawFrontmatter(notebookText(cells[0].Source)); ok {
+			frontmatter, cells = fm, cells[1:]
+		}
+	}
+	page, allFields, err := pageFromFrontmatter([]byte(frontmatter), defaults)
+	if err != nil {
+		return nil, fmt.Errorf("%s: frontmatter: %w", path, err)
+	}
+
+	c := notebookConverter{stem: strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)), lang: lang}
+	for i, cell := range cells {
+		if err := c.cell(i+1, cell); err != nil {
+			return nil, fmt.Errorf("%s: cell %d: %w", path, i+1, err)
+		}
+	}
+	page.Content = strings.TrimSpace(c.b.String())
+	if len(c.assets) > 0 {
+		page.Assets = c.assets
+	}
+	if c.firstH1 != "" && (page.Title == "" || page.Title == c.firstH1) {
+		// The theme prints the title: the heading that names the page is cut,
+		// as a .md file's "# " heading is.
+		page.Content = strings.TrimSpace(page.Content[c.h1Len:])
+	}
+	completePage(page, allFields, frontmatter != "", func() string { return c.firstH1 })
+	return page, nil
+}
+
+// rawFrontmatter returns the YAML between a raw cell's "---" lines.
+func rawFrontmatter(src string) (string, bool) {
+	lines := strings.Split(strings.TrimSpace(src), "\n")
+	if len(lines) < 2 || !isFrontmatterDelimiter(lines[0]) {
+		return "", false
+	}
+	for i := 1; i < len(lines); i++ {
+		if isFrontmatterDelimiter(lines[i]) {
+			return strings.Join(lines[1:i], "\n") + "\n", true
+		}
+	}
+	return "", false
+}
+
+// notebookText joins a notebook string field, stored either as one string or
+// as a list of lines.
+func notebookText(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var lines []string
+	if json.Unmarshal(raw, &lines) == nil {
+		return strings.Join(lines, "")
+	}
+	return ""
+}
+
+type notebookConverter struct {
+	stem   string
+	lang   string
+	b      strings.Builder
+	assets map[string][]byte
+	// firstH1 is the notebook's opening "# " heading, the title fallback;
+	// h1Len is the length of its line at the start of b.
+	firstH1 string
+	h1Len   int
+}
+
+var (
+	attachmentRefRe = regexp.MustCompile(`attachment:([^\s)"']+)`)
+	// ansiRe matches the terminal colour codes IPython writes into tracebacks.
+	ansiRe = regexp.MustCompile("\x1b\\[[0-9;]*[A-Za-z]")
+)
+
+func (c *notebookConverter) cell(n int, cell notebookCell) error {
+	tags := map[string]bool{}
+	for _, t := range cell.Metadata.Tags {
+		tags[strings.ReplaceAll(t, "_", "-")] = true
+	}
+	if tags["remove-cell"] {
+		return nil
+	}
+	src := strings.TrimSpace(notebookText(cell.Source))
+	switch cell.CellType {
+	case "markdown":
+		if src == "" {
+			return nil
+		}
+		for name, data := range cell.Attachments {
+			asset := fmt.Sprintf("%s-cell%d-%s", c.stem, n, filepath.Base(name))
+			for mime, b64 := range data {
+				if !strings.HasPrefix(mime, "image/") {
+					continue
+				}
+				if err := c.addAsset(asset, b64); err != nil {
+					return fmt.Errorf("attachment %s: %w", name, err)
+				}
+				src = strings.ReplaceAll(src, "attachment:"+name, asset)
+				break
+			}
+		}
+		src = attachmentRefRe.ReplaceAllString(src, "$1")
+		if c.firstH1 == "" && c.b.Len() == 0 {
+			line, _, _ := strings.Cut(src, "\n")
+			if strings.HasPrefix(line, "# ") {
+				c.firstH1 = strings.TrimSpace(strings.Trim(strings.TrimPrefix(line, "# "), "#"))
+				c.h1Len = len(line)
+			}
+		}
+		c.b.WriteString(src + "\n\n")
+	case "code":
+		if src != "" && !tags["remove-input"] {
+			writeFenced(&c.b, c.lang, src)
+		}
+		if !tags["remove-output"] {
+			for k, out := range cell.Outputs {
+				if err := c.output(fmt.Sprintf("%s-cell%d-%d", c.stem, n, k+1), out); err != nil {
+					return err
+				}
+			}
+		}
+	case "raw":
+		// Raw cells target a specific nbconvert exporter; they carry nothing a
+		// reader of the page should see.
+	}
+	return nil
+}
+
+// output appends one code-cell output. A rich output shows its richest form
+// this page can carry: an image, else Markdown, else plain text. HTML outputs
+// (script-driven widgets, mostly) fall back to their text form.
+func (c *notebookConverter) output(name string, out notebookOutput) error {
+	switch out.OutputType {
+	case "stream":
+		if text := notebookText(out.Text); strings.TrimSpace(text) != "" {
+			writeFenced(&c.b, "text", ansiRe.ReplaceAllString(text, ""))
+		}
+	case "error":
+		text := strings.Join(out.Traceback, "\n")
+		if text == "" {
+			text = out.Ename + ": " + out.Evalue
+		}
+		writeFenced(&c.b, "text", ansiRe.ReplaceAllString(text, ""))
+	case "execute_result", "display_data":
+		if png, ok := out.Data["image/png"]; ok {
+			if err := c.addAsset(name+".png", png); err != nil {
+				return fmt.Errorf("output %s: %w", name, err)
+			}
+			c.b.WriteString("![](" + name + ".png)\n\n")
+			return nil
+		}
+		if md, ok := out.Data["text/markdown"]; ok {
+			c.b.WriteString(strings.TrimSpace(notebookText(md)) + "\n\n")
+			return nil
+		}
+		if text, ok := out.Data["text/plain"]; ok {
+			writeFenced(&c.b, "text", notebookText(text))
+		}
+	}
+	return nil
+}
+
+// addAsset decodes a base64 notebook payload into Assets under name.
+func (c *notebookConverter) addAsset(name string, b64 json.RawMessage) error {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(notebookText(b64)), ""))
+	if err != nil {
+		return err
+	}
+	if c.assets == nil {
+		c.assets = map[string][]byte{}
+	}
+	c.assets[name] = data
+	return nil
+}
@@ -0,0 +1,110 @@
+package parser
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// onePixelPNG is a valid 1×1 PNG, base64-encoded the way notebooks store it.
+const onePixelPNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNk+M9QDwADhgGAWjR9awAAAABJRU5ErkJggg=="
+
+func writeNotebook(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "analysis.ipynb")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseNotebookFile(t *testing.T) {
+	path := writeNotebook(t, `{
+ "cells": [
+  {"cell_type": "markdown", "source": ["# Sales analysis\n", "\n", "Quarterly numbers, see ![chart](attachment:image.png)."],
+   "attachments": {"image.png": {"image/png": "`+onePixelPNG+`"}}},
+  {"cell_type": "code", "source": ["total = sum(sales)\n", "print(total)"], "outputs": [
+   {"output_type": "stream", "name": "stdout", "text": ["42\n"]},
+   {"output_type": "display_data", "data": {"image/png": "`+onePixelPNG+`", "text/plain": ["<Figure>"]}},
+   {"output_type": "execute_result", "data": {"text/plain": ["'done'"]}}
+  ]},
+  {"cell_type": "code", "source": "1/0", "outputs": [
+   {"output_type": "error", "ename": "ZeroDivisionError", "evalue": "division by zero",
+    "traceback": ["\u001b[0;31mZeroDivisionError\u001b[0m: division by zero"]}
+  ]},
+  {"cell_type": "code", "metadata": {"tags": ["remove-input"]}, "source": "secret_setup()", "outputs": [
+   {"output_type": "stream", "name": "stdout", "text": "shown\n"}]},
+  {"cell_type": "code", "metadata": {"tags": ["remove_cell"]}, "source": "hidden()", "outputs": []},
+  {"cell_type": "raw", "source": "\\newpage"}
+ ],
+ "metadata": {"kernelspec": {"language": "python", "name": "python3"}},
+ "nbformat": 4
+}`)
+	p, err := ParseNotebookFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Sales analysis" || p.Status != "publish" {
+		t.Errorf("title/status = %q/%q, want the heading and publish", p.Title, p.Status)
+	}
+	if strings.Contains(p.Content, "# Sales analysis") {
+		t.Errorf("the title heading should be cut from the body:\n%s", p.Content)
+	}
+	for _, want := range []string{
+		"![chart](analysis-cell1-image.png)",
+		"```python\ntotal = sum(sales)\nprint(total)\n```",
+		"```text\n42\n```",
+		"![](analysis-cell2-2.png)",
+		"```text\n'done'\n```",
+		"```text\nZeroDivisionError: division by zero\n```",
+		"```text\nshown\n```",
+	} {
+		if !strings.Contains(p.Content, want) {
+			t.Errorf("content lacks %q:\n%s", want, p.Content)
+		}
+	}
+	for _, gone := range []string{"<Figure>", "secret_setup", "hidden()", "newpage", "\x1b["} {
+		if strings.Contains(p.Content, gone) {
+			t.Errorf("content should not contain %q:\n%s", gone, p.Content)
+		}
+	}
+	if len(p.Assets) != 2 {
+		t.Fatalf("assets = %d, want the attachment and the plot", len(p.Assets))
+	}
+	for name, data := range p.Assets {
+		if !bytes.HasPrefix(data, []byte("\x89PNG")) {
+			t.Errorf("%s is not a decoded PNG", name)
+		}
+	}
+}
+
+// A leading raw cell of YAML is the notebook's frontmatter; a heading that
+// differs from its title stays in the body.
+func TestParseNotebookFrontmatter(t *testing.T) {
+	path := writeNotebook(t, `{"cells":[
+  {"cell_type":"raw","source":"---\ntitle: Q3 report\nstatus: draft\nslug: q3\n---"},
+  {"cell_type":"markdown","source":"# Summary\n\nText."}
+ ],"metadata":{"language_info":{"name":"julia"}},"nbformat":4}`)
+	p, err := ParseNotebookFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Q3 report" || p.Status != "draft" || p.Slug != "q3" {
+		t.Errorf("frontmatter not applied: %+v", p)
+	}
+	if !strings.HasPrefix(p.Content, "# Summary") {
+		t.Errorf("a heading that is not the title must stay:\n%s", p.Content)
+	}
+}
+
+func TestParseNotebookInvalid(t *testing.T) {
+	if _, err := ParseNotebookFile(writeNotebook(t, `{"cells": [`)); err == nil {
+		t.Error("expected an error for malformed JSON")
+	}
+	bad := `{"cells":[{"cell_type":"code","source":"x","outputs":[{"output_type":"display_data","data":{"image/png":"%%%"}}]}]}`
+	if _, err := ParseNotebookFile(writeNotebook(t, bad)); err == nil {
+		t.Error("expected an error for an undecodable image")
+	}
+}
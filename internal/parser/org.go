@@ -0,0 +1,379 @@
+package parser
+
+// Org-mode (.org).
+//
+// An Org file is converted to the Markdown body a .md file would have. The
+// subset covered is what a blog post uses: headings (one level down, so a
+// top-level "*" is an <h2> under the theme's title), plain and ordered lists,
+// description lists, checkboxes, [[links]] and images, *bold* /italic/ =code=
+// ~code~ +strike+, tables, quotes, fixed-width ": " lines and src, example
+// and HTML export blocks. Drawers, comments and export settings are dropped.
+//
+// In-buffer keywords are the frontmatter: "#+TITLE: x" is `title: x`, and
+// every other keyword maps by lowercased name, so #+DATE, #+SLUG, #+STATUS,
+// #+AUTHOR, #+DESCRIPTION, #+PUBLISH_DATE and friends mean what their YAML
+// namesakes do and unknown ones land in .Extra. #+FILETAGS / #+TAGS,
+// #+CATEGORIES and #+ALIASES are lists; Org timestamps (<2024-01-02 Tue>) are
+// accepted as dates. An Org file is published unless it says otherwise —
+// #+STATUS, or "#+DRAFT: t" for status draft — since Org has no frontmatter
+// block whose presence could signal intent.
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/spagu/ssg/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// ParseOrgFile parses an Org-mode file into a Page.
+func ParseOrgFile(path string) (*models.Page, error) {
+	return parseOrgFile(path, nil)
+}
+
+func parseOrgFile(path string, defaults Defaults) (*models.Page, error) {
+	file, err := os.Open(path) // #nosec G304 -- CLI tool reads user's content files
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = file.Close() }()
+
+	c := &orgConverter{keywords: map[string]interface{}{}}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	for scanner.Scan() {
+		c.line(strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	c.flush()
+
+	if _, ok := c.keywords["status"]; !ok {
+		c.keywords["status"] = "publish"
+		if d, _ := c.keywords["draft"].(string); orgTrue(d) {
+			c.keywords["status"] = "draft"
+		}
+	}
+	delete(c.keywords, "draft")
+	frontmatter, err := yaml.Marshal(c.keywords)
+	if err != nil {
+		return nil, fmt.Errorf("%s: keywords: %w", path, err)
+	}
+	page, allFields, err := pageFromFrontmatter(frontmatter, defaults)
+	if err != nil {
+		return nil, fmt.Errorf("%s: keywords: %w", path, err)
+	}
+	page.Content = strings.TrimSpace(c.b.String())
+	completePage(page, allFields, true, func() string { return "" })
+	return page, nil
+}
+
+// orgListKeywords are the keywords whose value is a list.
+var orgListKeywords = map[string]bool{"tags": true, "categories": true, "aliases": true}
+
+// orgDroppedKeywords configure Org's own exporters or annotate the next
+// element; none of them is page metadata.
+var orgDroppedKeywords = map[string]bool{
+	"options": true, "startup": true, "setupfile": true, "include": true, "property": true,
+	"html_head": true, "html_head_extra": true, "latex_class": true, "latex_header": true,
+	"export_file_name": true, "caption": true, "name": true, "attr_html": true,
+	"attr_latex": true, "results": true, "call": true, "tblfm": true, "toc": true,
+	"todo": true, "seq_todo": true, "typ_todo": true, "bind": true, "macro": true,
+}
+
+var (
+	orgKeywordRe   = regexp.MustCompile(`^\s*#\+([A-Za-z_]+):\s*(.*)$`)
+	orgBeginRe     = regexp.MustCompile(`(?i)^\s*#\+BEGIN_(\w+)\s*(.*)$`)
+	orgHeadingRe   = regexp.MustCompile(`^(\*+)\s+(.*)$`)
+	orgTodoRe      = regexp.MustCompile(`^(?:TODO|DONE)\s+`)
+	orgPriorityRe  = regexp.MustCompile(`^\[#[A-Z]\]\s*`)
+	orgTagsRe      = regexp.MustCompile(`\s+:[\w@#%:]+:\s*$`)
+	orgDrawerRe    = regexp.MustCompile(`^\s*:[A-Za-z_]+:\s*$`)
+	orgListRe      = regexp.MustCompile(`^(\s*)([-+]|\s\*|\d+[.)])\s+(.*)$`)
+	orgCheckboxRe  = regexp.MustCompile(`^\[([ xX-])\]\s+`)
+	orgFixedRe     = regexp.MustCompile(`^\s*:(?: (.*))?$`)
+	orgTableSepRe  = regexp.MustCompile(`^\s*\|[-+|]+\|?\s*$`)
+	orgRuleRe      = regexp.MustCompile(`^\s*-{5,}\s*$`)
+	orgTimestampRe = regexp.MustCompile(`^[<\[](\d{4}-\d{2}-\d{2})(?:\s+[^\d\s>\]]+)?(?:\s+(\d{1,2}:\d{2}))?[^>\]]*[>\]]$`)
+)
+
+type orgConverter struct {
+	b        strings.Builder
+	keywords map[string]interface{}
+
+	block    string   // open #+BEGIN_ block, lowercased; "" outside one
+	blockArg string   // its first argument: a src language, an export backend
+	body     []string // its lines
+	fixed    []string // consecutive ": " fixed-width lines
+	drawer   bool     // inside :PROPERTIES: … :END:
+	inList   bool     // the last non-blank line belonged to a list
+}
+
+func (c *orgConverter) line(line string) {
+	if c.block != "" {
+		if strings.EqualFold(strings.TrimSpace(line), "#+END_"+c.block) {
+			c.endBlock()
+		} else {
+			c.body = append(c.body, line)
+		}
+		return
+	}
+	if c.drawer {
+		c.drawer = !strings.EqualFold(strings.TrimSpace(line), ":END:")
+		return
+	}
+	if m := orgFixedRe.FindStringSubmatch(line); m != nil && !orgDrawerRe.MatchString(line) {
+		c.fixed = append(c.fixed, m[1])
+		return
+	}
+	c.flushFixed()
+
+	switch {
+	case orgDrawerRe.MatchString(line):
+		c.drawer = true
+	case orgBeginRe.MatchString(line):
+		m := orgBeginRe.FindStringSubmatch(line)
+		c.block = strings.ToLower(m[1])
+		c.blockArg, _, _ = strings.Cut(strings.TrimSpace(m[2]), " ")
+		c.body = nil
+	case orgKeywordRe.MatchString(line):
+		m := orgKeywordRe.FindStringSubmatch(line)
+		c.keyword(strings.ToLower(m[1]), strings.TrimSpace(m[2]))
+	case strings.HasPrefix(strings.TrimSpace(line), "#") &&
+		(strings.TrimSpace(line) == "#" || strings.HasPrefix(strings.TrimSpace(line), "# ")):
+		// A comment line.
+	case orgHeadingRe.MatchString(line):
+		m := orgHeadingRe.FindStringSubmatch(line)
+		title := orgPriorityRe.ReplaceAllString(orgTodoRe.ReplaceAllString(m[2], ""), "")
+		title = orgTagsRe.ReplaceAllString(title, "")
+		c.inList = false
+		c.b.WriteString("\n" + strings.Repeat("#", min(len(m[1])+1, 6)) + " " + orgInline(title) + "\n\n")
+	case orgRuleRe.MatchString(line):
+		c.b.WriteString("\n* * *\n\n")
+	case orgTableSepRe.MatchString(line):
+		c.b.WriteString(strings.TrimSpace(strings.ReplaceAll(line, "+", "|")) + "\n")
+	case strings.HasPrefix(strings.TrimSpace(line), "|"):
+		c.b.WriteString(orgInline(strings.TrimSpace(line)) + "\n")
+	case orgListRe.MatchString(line):
+		c.inList = true
+		c.b.WriteString(orgListItem(orgListRe.FindStringSubmatch(line)) + "\n")
+	case strings.TrimSpace(line) == "":
+		c.b.WriteString("\n")
+	default:
+		// Indentation is Org's way of attaching a line to a list item; outside
+		// a list it is cosmetic and, kept, would turn the paragraph into code.
+		if !c.inList || strings.TrimSpace(line) == line {
+			c.inList = false
+			line = strings.TrimSpace(line)
+		}
+		c.b.WriteString(orgInline(line) + "\n")
+	}
+}
+
+func (c *orgConverter) keyword(key, value string) {
+	switch key {
+	case "filetags":
+		key = "tags"
+	case "language":
+		key = "lang"
+	}
+	if orgDroppedKeywords[key] {
+		return
+	}
+	if orgListKeywords[key] {
+		sep := func(r rune) bool { return r == ':' || r == ',' || r == ' ' || r == '\t' }
+		if key == "categories" {
+			sep = func(r rune) bool { return r == ',' }
+		}
+		list, _ := c.keywords[key].([]string)
+		for _, v := range strings.FieldsFunc(value, sep) {
+			if v = strings.TrimSpace(v); v != "" {
+				list = append(list, v)
+			}
+		}
+		c.keywords[key] = list
+		return
+	}
+	if m := orgTimestampRe.FindStringSubmatch(value); m != nil {
+		value = m[1]
+		if clock := m[2]; clock != "" {
+			if len(clock) == 4 {
+				clock = "0" + clock
+			}
+			value += " " + clock + ":00"
+		}
+	}
+	c.keywords[key] = value
+}
+
+func (c *orgConverter) endBlock() {
+	body := dedent(c.body)
+	switch c.block {
+	case "src":
+		writeFenced(&c.b, c.blockArg, orgUnescape(body))
+	case "example":
+		writeFenced(&c.b, "", orgUnescape(body))
+	case "quote":
+		for _, l := range strings.Split(body, "\n") {
+			c.b.WriteString(strings.TrimRight("> "+orgInline(strings.TrimSpace(l)), " ") + "\n")
+		}
+		c.b.WriteString("\n")
+	case "export":
+		if strings.EqualFold(c.blockArg, "html") {
+			c.b.WriteString(body + "\n\n")
+		}
+	default:
+		// center, verse and custom blocks: their content, as paragraphs.
+		for _, l := range strings.Split(body, "\n") {
+			c.b.WriteString(orgInline(strings.TrimSpace(l)) + "\n")
+		}
+		c.b.WriteString("\n")
+	}
+	c.block, c.blockArg, c.body = "", "", nil
+}
+
+func (c *orgConverter) flushFixed() {
+	if len(c.fixed) > 0 {
+		writeFenced(&c.b, "text", strings.Join(c.fixed, "\n"))
+		c.fixed = nil
+	}
+}
+
+// flush closes whatever the file left open. An unterminated block keeps its
+// lines, as Org itself renders them.
+func (c *orgConverter) flush() {
+	c.flushFixed()
+	if c.block != "" {
+		c.endBlock()
+	}
+}
+
+// orgListItem converts one list item; m is an orgListRe match.
+func orgListItem(m []string) string {
+	indent, bullet, text := m[1], strings.TrimSpace(m[2]), m[3]
+	switch {
+	case bullet == "-" || bullet == "+" || bullet == "*":
+		bullet = "-"
+	default:
+		bullet = strings.TrimRight(bullet, ".)") + "."
+	}
+	checkbox := ""
+	if cb := orgCheckboxRe.FindStringSubmatch(text); cb != nil {
+		checkbox = "[ ] "
+		if cb[1] == "x" || cb[1] == "X" {
+			checkbox = "[x] "
+		}
+		text = text[len(cb[0]):]
+	}
+	if term, desc, ok := strings.Cut(text, " :: "); ok {
+		text = "**" + orgInline(strings.TrimSpace(term)) + "**: " + orgInline(desc)
+	} else {
+		text = orgInline(text)
+	}
+	return indent + bullet + " " + checkbox + text
+}
+
+// orgUnescape removes the comma Org puts before a code line that would
+// otherwise read as a heading or a keyword.
+func orgUnescape(body string) string {
+	lines := strings.Split(body, "\n")
+	for i, l := range lines {
+		trimmed := strings.TrimLeft(l, " \t")
+		if strings.HasPrefix(trimmed, ",*") || strings.HasPrefix(trimmed, ",#+") {
+			lines[i] = l[:len(l)-len(trimmed)] + trimmed[1:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dedent removes the indentation every non-blank line shares.
+func dedent(lines []string) string {
+	common := -1
+	for _, l := range lines {
+		if strings.TrimSpace(l) == "" {
+			continue
+		}
+		n := len(l) - len(strings.TrimLeft(l, " \t"))
+		if common < 0 || n < common {
+			common = n
+		}
+	}
+	out := make([]string, len(lines))
+	for i, l := range lines {
+		if len(l) >= common && common > 0 {
+			l = l[common:]
+		}
+		out[i] = strings.TrimRight(l, " \t")
+	}
+	return strings.Join(out, "\n")
+}
+
+func orgTrue(v string) bool {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "t", "true", "yes":
+		return true
+	}
+	return false
+}
+
+var (
+	orgLinkRe = regexp.MustCompile(`\[\[([^\]]+)\](?:\[([^\]]+)\])?\]`)
+	orgCodeRe = regexp.MustCompile(`(^|[\s\-('"{])([=~])([^\s=~](?:.*?[^\s])?)[=~]([\s\-.,:;!?'")}\]]|$)`)
+	// Emphasis needs a border on both sides, so "a/b/c" and "2*3*4" stay text.
+	orgEmphasisRe = map[string]*regexp.Regexp{
+		"**": regexp.MustCompile(`(^|[\s\-('"{])\*([^\s*](?:[^*]*?[^\s*])?)\*([\s\-.,:;!?'")}\]]|$)`),
+		"*":  regexp.MustCompile(`(^|[\s\-('"{])/([^\s/](?:[^/]*?[^\s/])?)/([\s\-.,:;!?'")}\]]|$)`),
+		"~~": regexp.MustCompile(`(^|[\s\-('"{])\+([^\s+](?:[^+]*?[^\s+])?)\+([\s\-.,:;!?'")}\]]|$)`),
+	}
+	orgImageExtRe = regexp.MustCompile(`(?i)\.(png|jpe?g|gif|svg|webp|avif)$`)
+)
+
+// orgInline converts inline markup. Links and code are converted first and
+// set aside, so emphasis markers inside a URL or a code span stay as written.
+func orgInline(s string) string {
+	var held []string
+	hold := func(v string) string {
+		held = append(held, v)
+		return fmt.Sprintf("\x00%d\x00", len(held)-1)
+	}
+	s = orgLinkRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := orgLinkRe.FindStringSubmatch(m)
+		target := strings.TrimPrefix(sub[1], "file:")
+		if sub[2] == "" {
+			if orgImageExtRe.MatchString(target) {
+				return hold("![](" + target + ")")
+			}
+			return hold("[" + target + "](" + target + ")")
+		}
+		return hold("[" + sub[2] + "](" + target + ")")
+	})
+	// Each pattern consumes the border after a match, so a second pass picks
+	// up a match that shared its border with the one before it.
+	for pass := 0; pass < 2; pass++ {
+		s = orgCodeRe.ReplaceAllStringFunc(s, func(m string) string {
+			sub := orgCodeRe.FindStringSubmatch(m)
+			code := "`" + sub[3] + "`"
+			if strings.Contains(sub[3], "`") {
+				code = "`` " + sub[3] + " ``"
+			}
+			return sub[1] + hold(code) + sub[4]
+		})
+		for _, mark := range []string{"**", "*", "~~"} {
+			re := orgEmphasisRe[mark]
+			// The markers are held too: a converted /italic/ reads *italic*,
+			// which the bold pattern would otherwise take a second time.
+			s = re.ReplaceAllStringFunc(s, func(m string) string {
+				sub := re.FindStringSubmatch(m)
+				return sub[1] + hold(mark) + sub[2] + hold(mark) + sub[3]
+			})
+		}
+	}
+	for i, v := range held {
+		s = strings.Replace(s, fmt.Sprintf("\x00%d\x00", i), v, 1)
+	}
+	return s
+}
@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeOrg(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "post.org")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseOrgFile(t *testing.T) {
+	path := writeOrg(t, `#+TITLE: Hello Org
+#+DATE: <2024-03-05 Tue 9:30>
+#+FILETAGS: :go:emacs:
+#+CATEGORIES: News, Deep Dives
+#+DESCRIPTION: A tour.
+#+OPTIONS: toc:nil
+#+MOOD: calm
+
+Intro with *bold*, /italic/, =code=, ~verb~, +gone+ and a [[https://example.com/a/b_c][link]].
+Paths like a/b/c and sums like 2*3*4 stay as written.
+
+* TODO [#A] Setup :tag:
+  :PROPERTIES:
+  :CUSTOM_ID: setup
+  :END:
+  Indented paragraph.
+
+- one
+- [X] two
+  continued
+- term :: meaning
+1. first
+2) second
+
+#+BEGIN_SRC go :results output
+  func main() {
+  ,* not a heading
+  }
+#+END_SRC
+
+#+RESULTS:
+: hello
+
+| a | b |
+|---+---|
+| 1 | 2 |
+
+#+begin_quote
+Quoted *text*.
+#+end_quote
+# a comment
+[[./plot.png]]
+** Deeper
+`)
+	p, err := ParseOrgFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.Title != "Hello Org" || p.Status != "publish" || p.Description != "A tour." {
+		t.Errorf("keywords not mapped: %+v", p)
+	}
+	if want := time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC); !p.Date.Equal(want) {
+		t.Errorf("date = %v, want %v", p.Date, want)
+	}
+	if strings.Join(p.Tags, ",") != "go,emacs" || len(p.CategoriesRaw) != 2 || p.CategoriesRaw[1] != "Deep Dives" {
+		t.Errorf("tags/categories = %v / %v", p.Tags, p.CategoriesRaw)
+	}
+	if p.Extra["mood"] != "calm" || p.Extra["options"] != nil {
+		t.Errorf("extra = %v: unknown keywords kept, export settings dropped", p.Extra)
+	}
+	for _, want := range []string{
+		"Intro with **bold**, *italic*, `code`, `verb`, ~~gone~~ and a [link](https://example.com/a/b_c).",
+		"Paths like a/b/c and sums like 2*3*4 stay as written.",
+		"## Setup\n\nIndented paragraph.",
+		"- one\n- [x] two\n  continued\n- **term**: meaning\n1. first\n2. second",
+		"```go\nfunc main() {\n* not a heading\n}\n```",
+		"```text\nhello\n```",
+		"| a | b |\n|---|---|\n| 1 | 2 |",
+		"> Quoted **text**.",
+		"![](./plot.png)",
+		"### Deeper",
+	} {
+		if !strings.Contains(p.Content, want) {
+			t.Errorf("content lacks %q:\n%s", want, p.Content)
+		}
+	}
+	for _, gone := range []string{"PROPERTIES", "CUSTOM_ID", "a comment", "TODO", "[#A]", ":tag:", "#+"} {
+		if strings.Contains(p.Content, gone) {
+			t.Errorf("content should not contain %q:\n%s", gone, p.Content)
+		}
+	}
+}
+
+func TestParseOrgStatus(t *testing.T) {
+	for body, want := range map[string]string{
+		"#+TITLE: x\n\nText.\n":                    "publish",
+		"#+TITLE: x\n#+DRAFT: t\n\nText.\n":        "draft",
+		"#+TITLE: x\n#+STATUS: pending\n\nText.\n": "pending",
+	} {
+		p, err := ParseOrgFile(writeOrg(t, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.Status != want {
+			t.Errorf("%q: status = %q, want %q", body, p.Status, want)
+		}
+		if _, leaked := p.Extra["draft"]; leaked {
+			t.Errorf("%q: draft should not reach Extra", body)
+		}
+	}
+}
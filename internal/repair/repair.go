@@ -16,6 +16,8 @@ package repair
 import (
 	"regexp"
 	"strings"
+
+	"github.com/spagu/ssg/internal/parser"
 )
 
 // tabWidth is CommonMark's: a tab advances to the next multiple of four, and
@@ -32,6 +34,10 @@ var markupRe = regexp.MustCompile(`</?[a-zA-Z][a-zA-Z0-9-]*(\s[^<>]*)?/?>`)
 // legitimately indented and must never be dedented.
 var listMarkerRe = regexp.MustCompile(`^\s*([-*+]|\d+[.)])\s`)
 
+// admonitionRe matches an MkDocs admonition header (`!!! note`, `??? tip`),
+// whose body is indented four columns by design — a callout, not code.
+var admonitionRe = regexp.MustCompile(`^ {0,3}(!!!|\?\?\?\+?)[ \t]+[A-Za-z]`)
+
 // Finding is one repairable block: where it starts and what it looks like.
 type Finding struct {
 	// Line is the 1-based line number in the file, front matter included, so
@@ -53,7 +59,7 @@ const sampleWidth = 60
 
 // Scan reports every block of raw markup a Markdown source renders as literal
 // text: indented four columns or more, or wrapped in a code fence (#166).
-// Front matter is never reported — YAML indentation is structural — and a fence
+// Front matter is never reported — its indentation is structural — and a fence
 // an author meant is left alone; see scanFences for what separates the two.
 func Scan(content string) []Finding {
 	var findings []Finding
@@ -151,6 +157,7 @@ func scanBlocks(content string, visit func(start, count int, lines []string)) {
 
 	inFence := false
 	prevBlockIsList := false
+	inAdmonition := false
 
 	for i := body; i < len(lines); {
 		line := lines[i]
@@ -170,30 +177,25 @@ func scanBlocks(content string, visit func(start, count int, lines []string)) {
 		}
 		block := lines[start:i]
 
-		if !prevBlockIsList && indentColumns(block[0]) >= tabWidth && containsMarkup(block) {
+		indented := indentColumns(block[0]) >= tabWidth
+		admonitionBody := inAdmonition && indented
+		if !prevBlockIsList && !admonitionBody && indented && containsMarkup(block) {
 			visit(start, len(block), lines)
 		}
 		// A list's continuation lines are indented on purpose; a block that
 		// follows one may be part of it, so it is left alone even if it looks
-		// like markup.
+		// like markup. The same goes for the indented blocks of an admonition.
 		prevBlockIsList = listMarkerRe.MatchString(block[0])
+		inAdmonition = admonitionRe.MatchString(block[0]) || admonitionBody
 	}
 }
 
 // frontMatterEnd returns the index of the first body line, skipping a leading
-// `---` front-matter block. Its indentation is YAML structure, not prose.
+// front-matter block — `---` YAML, `+++` TOML or a JSON object. Its indentation
+// is structure, not prose, and an unterminated block counts as the whole file
+// rather than have what may be structured data rewritten.
 func frontMatterEnd(lines []string) int {
-	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
-		return 0
-	}
-	for i := 1; i < len(lines); i++ {
-		if strings.TrimSpace(lines[i]) == "---" {
-			return i + 1
-		}
-	}
-	// Unterminated front matter: treat the whole file as front matter rather
-	// than rewriting what may be structured data.
-	return len(lines)
+	return parser.FrontmatterEnd(lines)
 }
 
 // fenceMarker reports whether a line opens or closes a fenced code block.
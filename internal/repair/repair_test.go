@@ -118,6 +118,17 @@ func TestScan_IgnoresListContinuation(t *testing.T) {
 	}
 }
 
+func TestScan_IgnoresAdmonitionBody(t *testing.T) {
+	// An MkDocs admonition's body is indented four columns, paragraphs and
+	// all; a block after the admonition ends is scanned again.
+	src := "!!! note \"Heads up\"\n\n    <b>Bold</b> claim.\n\n    <em>More</em>\n\nAfter.\n\n    <div>stray</div>\n"
+
+	f := Scan(src)
+	if len(f) != 1 || f[0].Line != 9 {
+		t.Errorf("only the block after the admonition is a finding: %+v", f)
+	}
+}
+
 func TestScan_UnterminatedFrontMatterIsLeftAlone(t *testing.T) {
 	src := "---\ntitle: \"Broken\"\n\t\t<div>never closed</div>\n"
 
@@ -181,3 +192,14 @@ func TestIndentColumns_TabStops(t *testing.T) {
 		}
 	}
 }
+
+func TestScan_SkipsTOMLAndJSONFrontMatter(t *testing.T) {
+	for name, src := range map[string]string{
+		"toml": "+++\ntitle = \"Post\"\nsummary = \"\"\"\n\n\t\t<div>kept</div>\n\"\"\"\n+++\n\nBody.\n",
+		"json": "{\n  \"title\": \"Post\",\n\n\t\t\"html\": \"<div>kept</div>\"\n}\n\nBody.\n",
+	} {
+		if f := Scan(src); len(f) != 0 {
+			t.Errorf("%s front matter must not be rewritten: %+v", name, f)
+		}
+	}
+}
@@ -0,0 +1,554 @@
+// Package spell checks words against Hunspell dictionaries — the .aff/.dic
+// pairs LibreOffice, Firefox and every Linux distribution ship per language —
+// so spell checking runs offline, in the build, with the dictionaries an
+// editor already uses.
+//
+// It reads the part of the format a word list needs: prefixes and suffixes
+// (with cross products and conditions), flag aliases, the FLAG and SET
+// variants, NEEDAFFIX, FORBIDDENWORD and the compounding flags. It makes no
+// suggestions and does no morphological analysis; a finding names the word and
+// the author fixes it or adds it to the project word list.
+package spell
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Dictionary is one loaded language.
+type Dictionary struct {
+	flagMode string // "" (one character), "UTF-8", "long" (two) or "num"
+	flagIDs  map[string]uint16
+	aliases  [][]uint16 // AF, numbered from 1
+	ignore   string
+
+	words     map[string][][]uint16 // stem → the flags of each homonym
+	prefixes  map[string][]*affix   // by the text the affix adds
+	suffixes  map[string][]*affix
+	maxPrefix int // longest added text, in bytes
+	maxSuffix int
+
+	needAffix, forbidden, onlyInCompound                     uint16
+	compoundFlag, compoundBegin, compoundMiddle, compoundEnd uint16
+	compoundMin                                              int
+
+	cache map[string]bool
+}
+
+type affix struct {
+	flag      uint16
+	cross     bool
+	strip     string
+	add       string
+	condition []charClass
+}
+
+// charClass is one position of an affix condition: ".", a literal, or a
+// bracketed set.
+type charClass struct {
+	any    bool
+	negate bool
+	runes  string
+}
+
+func (c charClass) match(r rune) bool {
+	if c.any {
+		return true
+	}
+	return strings.ContainsRune(c.runes, r) != c.negate
+}
+
+// Load reads a dictionary from its .aff and .dic files.
+func Load(affPath, dicPath string) (*Dictionary, error) {
+	aff, err := os.ReadFile(affPath) // #nosec G304 -- a dictionary the operator configured or installed
+	if err != nil {
+		return nil, err
+	}
+	dic, err := os.ReadFile(dicPath) // #nosec G304 -- as above
+	if err != nil {
+		return nil, err
+	}
+	decode, err := decoder(aff)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", affPath, err)
+	}
+	d := &Dictionary{
+		flagIDs:     map[string]uint16{},
+		words:       map[string][][]uint16{},
+		prefixes:    map[string][]*affix{},
+		suffixes:    map[string][]*affix{},
+		compoundMin: 3,
+		cache:       map[string]bool{},
+	}
+	text, err := decode(aff)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", affPath, err)
+	}
+	if err := d.parseAff(text); err != nil {
+		return nil, fmt.Errorf("%s: %w", affPath, err)
+	}
+	if text, err = decode(dic); err != nil {
+		return nil, fmt.Errorf("%s: %w", dicPath, err)
+	}
+	d.parseDic(text)
+	return d, nil
+}
+
+// decoder turns the .aff's SET encoding into a converter to UTF-8. The
+// encoding names Hunspell uses are nearly all WHATWG labels too.
+func decoder(aff []byte) (func([]byte) (string, error), error) {
+	set := ""
+	sc := bufio.NewScanner(bytes.NewReader(aff))
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	for sc.Scan() {
+		if f := strings.Fields(sc.Text()); len(f) >= 2 && f[0] == "SET" {
+			set = strings.ToLower(f[1])
+			break
+		}
+	}
+	switch set {
+	case "", "utf-8", "utf8":
+		return func(b []byte) (string, error) { return string(bytes.TrimPrefix(b, []byte("\ufeff"))), nil }, nil
+	case "microsoft-cp1251":
+		set = "windows-1251"
+	}
+	enc, err := htmlindex.Get(set)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported encoding SET %s", set)
+	}
+	return func(b []byte) (string, error) {
+		out, err := enc.NewDecoder().Bytes(b)
+		return string(out), err
+	}, nil
+}
+
+func (d *Dictionary) parseAff(text string) error {
+	remaining := map[string]int{} // "SFX A" → entries still to read
+	cross := map[string]bool{}
+	aliasHeader := false
+	for _, line := range strings.Split(text, "\n") {
+		f := strings.Fields(line)
+		if len(f) == 0 || strings.HasPrefix(f[0], "#") {
+			continue
+		}
+		switch f[0] {
+		case "FLAG":
+			if len(f) > 1 {
+				d.flagMode = f[1]
+			}
+		case "AF":
+			if !aliasHeader {
+				aliasHeader = true // "AF <count>" comes first
+				continue
+			}
+			if len(f) > 1 {
+				d.aliases = append(d.aliases, d.parseFlags(f[1], false))
+			}
+		case "IGNORE":
+			if len(f) > 1 {
+				d.ignore = f[1]
+			}
+		case "NEEDAFFIX", "PSEUDOROOT":
+			d.needAffix = d.singleFlag(f)
+		case "FORBIDDENWORD":
+			d.forbidden = d.singleFlag(f)
+		case "ONLYINCOMPOUND":
+			d.onlyInCompound = d.singleFlag(f)
+		case "COMPOUNDFLAG":
+			d.compoundFlag = d.singleFlag(f)
+		case "COMPOUNDBEGIN":
+			d.compoundBegin = d.singleFlag(f)
+		case "COMPOUNDMIDDLE":
+			d.compoundMiddle = d.singleFlag(f)
+		case "COMPOUNDEND", "COMPOUNDLAST":
+			d.compoundEnd = d.singleFlag(f)
+		case "COMPOUNDMIN":
+			if len(f) > 1 {
+				if n, err := strconv.Atoi(f[1]); err == nil && n > 0 {
+					d.compoundMin = n
+				}
+			}
+		case "PFX", "SFX":
+			if len(f) < 4 {
+				return fmt.Errorf("malformed affix line %q", line)
+			}
+			key := f[0] + " " + f[1]
+			if remaining[key] == 0 {
+				n, err := strconv.Atoi(f[3])
+				if err != nil {
+					return fmt.Errorf("malformed affix header %q", line)
+				}
+				remaining[key], cross[key] = n, f[2] == "Y"
+				continue
+			}
+			remaining[key]--
+			d.addAffix(f, cross[key])
+		}
+	}
+	return nil
+}
+
+func (d *Dictionary) singleFlag(f []string) uint16 {
+	if len(f) < 2 {
+		return 0
+	}
+	return d.flagID(f[1])
+}
+
+func (d *Dictionary) addAffix(f []string, cross bool) {
+	a := &affix{flag: d.flagID(f[1]), cross: cross, strip: f[2]}
+	if a.strip == "0" {
+		a.strip = ""
+	}
+	a.add, _, _ = strings.Cut(f[3], "/") // continuation classes are not followed
+	if a.add == "0" {
+		a.add = ""
+	}
+	a.add = d.stripIgnored(a.add)
+	if len(f) > 4 {
+		a.condition = parseCondition(f[4])
+	}
+	if f[0] == "PFX" {
+		d.prefixes[a.add] = append(d.prefixes[a.add], a)
+		d.maxPrefix = max(d.maxPrefix, len(a.add))
+	} else {
+		d.suffixes[a.add] = append(d.suffixes[a.add], a)
+		d.maxSuffix = max(d.maxSuffix, len(a.add))
+	}
+}
+
+func parseCondition(s string) []charClass {
+	if s == "." {
+		return nil
+	}
+	var out []charClass
+	for s != "" {
+		r, n := utf8.DecodeRuneInString(s)
+		switch r {
+		case '.':
+			out = append(out, charClass{any: true})
+			s = s[n:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				end = len(s)
+			}
+			set := s[1:end]
+			c := charClass{negate: strings.HasPrefix(set, "^")}
+			c.runes = strings.TrimPrefix(set, "^")
+			out = append(out, c)
+			s = s[min(end+1, len(s)):]
+		default:
+			out = append(out, charClass{runes: string(r)})
+			s = s[n:]
+		}
+	}
+	return out
+}
+
+// matchStart and matchEnd test a condition against the start (prefixes) or
+// end (suffixes) of a stem.
+func matchStart(cond []charClass, stem string) bool {
+	for _, c := range cond {
+		r, n := utf8.DecodeRuneInString(stem)
+		if n == 0 || !c.match(r) {
+			return false
+		}
+		stem = stem[n:]
+	}
+	return true
+}
+
+func matchEnd(cond []charClass, stem string) bool {
+	for i := len(cond) - 1; i >= 0; i-- {
+		r, n := utf8.DecodeLastRuneInString(stem)
+		if n == 0 || !cond[i].match(r) {
+			return false
+		}
+		stem = stem[:len(stem)-n]
+	}
+	return true
+}
+
+func (d *Dictionary) parseDic(text string) {
+	lines := strings.Split(text, "\n")
+	if len(lines) > 0 {
+		if _, err := strconv.Atoi(strings.TrimSpace(lines[0])); err == nil {
+			lines = lines[1:] // the approximate word count
+		}
+	}
+	for _, line := range lines {
+		line, _, _ = strings.Cut(strings.TrimRight(line, "\r"), "\t") // morphological fields
+		if i := strings.Index(line, " "); i >= 0 && strings.Contains(line[i:], ":") {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		word, flags := splitEntry(line)
+		d.addWord(d.stripIgnored(word), d.parseFlags(flags, len(d.aliases) > 0))
+	}
+}
+
+// splitEntry splits word/flags at the first slash not escaped as \/.
+func splitEntry(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '/':
+			if i > 0 {
+				return strings.ReplaceAll(line[:i], `\/`, "/"), line[i+1:]
+			}
+		}
+	}
+	return strings.ReplaceAll(line, `\/`, "/"), ""
+}
+
+func (d *Dictionary) addWord(word string, flags []uint16) {
+	if word != "" {
+		d.words[word] = append(d.words[word], flags)
+	}
+}
+
+// Add accepts a word as written, the way a personal word list does: with no
+// affixes, and in other capitalisations only as Check allows for any word.
+func (d *Dictionary) Add(word string) {
+	d.addWord(strings.ReplaceAll(word, "’", "'"), nil)
+	d.cache = map[string]bool{}
+}
+
+// parseFlags reads a flag field; with aliases defined it is an AF number.
+func (d *Dictionary) parseFlags(s string, aliased bool) []uint16 {
+	if s == "" {
+		return nil
+	}
+	if aliased {
+		if n, err := strconv.Atoi(s); err == nil && n >= 1 && n <= len(d.aliases) {
+			return d.aliases[n-1]
+		}
+		return nil
+	}
+	var out []uint16
+	switch d.flagMode {
+	case "num":
+		for _, n := range strings.Split(s, ",") {
+			out = append(out, d.flagID(strings.TrimSpace(n)))
+		}
+	case "long":
+		r := []rune(s)
+		for i := 0; i+1 < len(r); i += 2 {
+			out = append(out, d.flagID(string(r[i:i+2])))
+		}
+	default:
+		for _, r := range s {
+			out = append(out, d.flagID(string(r)))
+		}
+	}
+	return out
+}
+
+// flagID numbers flags as they are met, from 1: zero means "not set".
+func (d *Dictionary) flagID(name string) uint16 {
+	if id, ok := d.flagIDs[name]; ok {
+		return id
+	}
+	id := uint16(len(d.flagIDs) + 1)
+	d.flagIDs[name] = id
+	return id
+}
+
+func (d *Dictionary) stripIgnored(s string) string {
+	if d.ignore == "" {
+		return s
+	}
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(d.ignore, r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+func has(flags []uint16, f uint16) bool {
+	if f == 0 {
+		return false
+	}
+	for _, x := range flags {
+		if x == f {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports whether word is spelled correctly. A capitalised word is also
+// accepted in lower case ("The"), and one in capitals in lower or title case
+// ("PARIS" for "Paris"); a lower-case word must match as written, so "paris"
+// is rejected.
+func (d *Dictionary) Check(word string) bool {
+	word = d.stripIgnored(strings.ReplaceAll(word, "’", "'"))
+	if ok, seen := d.cache[word]; seen {
+		return ok
+	}
+	ok := d.valid(word)
+	if !ok {
+		lower := strings.ToLower(word)
+		switch {
+		case lower == word:
+		case word == title(lower):
+			ok = d.valid(lower)
+		case word == strings.ToUpper(word):
+			ok = d.valid(lower) || d.valid(title(lower))
+		}
+	}
+	d.cache[word] = ok
+	return ok
+}
+
+func title(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToTitle(r)) + s[n:]
+}
+
+func (d *Dictionary) valid(word string) bool {
+	for _, flags := range d.words[word] {
+		if has(flags, d.forbidden) {
+			return false
+		}
+		if !has(flags, d.needAffix) && !has(flags, d.onlyInCompound) {
+			return true
+		}
+	}
+	return d.affixed(word, 0) || d.compound(word, 0)
+}
+
+// stemWith reports whether stem is a dictionary word carrying every flag in
+// want, ignoring the unset ones.
+func (d *Dictionary) stemWith(stem string, want ...uint16) bool {
+	for _, flags := range d.words[stem] {
+		if has(flags, d.forbidden) {
+			continue
+		}
+		ok := true
+		for _, f := range want {
+			if f != 0 && !has(flags, f) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// affixed reports whether word is a stem with a suffix, a prefix, or both
+// (when both allow cross products). need, when set, is one more flag the stem
+// must carry, which is how a compound's last part is checked.
+func (d *Dictionary) affixed(word string, need uint16) bool {
+	for _, a := range d.affixesAt(word, false) {
+		stem := word[:len(word)-len(a.add)] + a.strip
+		if matchEnd(a.condition, stem) && d.stemWith(stem, a.flag, need) {
+			return true
+		}
+	}
+	for _, p := range d.affixesAt(word, true) {
+		stem := p.strip + word[len(p.add):]
+		if !matchStart(p.condition, stem) {
+			continue
+		}
+		if d.stemWith(stem, p.flag, need) {
+			return true
+		}
+		if !p.cross {
+			continue
+		}
+		for _, s := range d.affixesAt(stem, false) {
+			root := stem[:len(stem)-len(s.add)] + s.strip
+			if s.cross && matchEnd(s.condition, root) && d.stemWith(root, p.flag, s.flag, need) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// affixesAt lists the prefixes or suffixes whose added text word starts or
+// ends with, leaving at least one character of stem.
+func (d *Dictionary) affixesAt(word string, prefix bool) []*affix {
+	var out []*affix
+	longest, table := d.maxSuffix, d.suffixes
+	if prefix {
+		longest, table = d.maxPrefix, d.prefixes
+	}
+	for n := 0; n <= longest && n < len(word); n++ {
+		var key string
+		if prefix {
+			if !utf8.RuneStart(word[n]) {
+				continue
+			}
+			key = word[:n]
+		} else {
+			if n > 0 && !utf8.RuneStart(word[len(word)-n]) {
+				continue
+			}
+			key = word[len(word)-n:]
+		}
+		out = append(out, table[key]...)
+	}
+	return out
+}
+
+// compound reports whether word splits into dictionary words allowed in
+// compounds: COMPOUNDFLAG anywhere, or COMPOUNDBEGIN, COMPOUNDMIDDLE and
+// COMPOUNDEND in place. Only the last part may carry affixes. A part after
+// the first also matches a capitalised entry, the way German nouns join.
+func (d *Dictionary) compound(word string, part int) bool {
+	if d.compoundFlag == 0 && d.compoundBegin == 0 || part > 3 {
+		return false
+	}
+	runes := []rune(word)
+	for i := d.compoundMin; i <= len(runes)-d.compoundMin; i++ {
+		head, tail := string(runes[:i]), string(runes[i:])
+		position := d.compoundBegin
+		if part > 0 {
+			position = d.compoundMiddle
+		}
+		if !d.compoundPart(head, position, part > 0, false) {
+			continue
+		}
+		if d.compoundPart(tail, d.compoundEnd, true, true) || d.compound(tail, part+1) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dictionary) compoundPart(s string, position uint16, capitalised, last bool) bool {
+	forms := []string{s}
+	if capitalised {
+		forms = append(forms, title(s))
+	}
+	for _, form := range forms {
+		for _, f := range []uint16{d.compoundFlag, position} {
+			if f == 0 {
+				continue
+			}
+			if d.stemWith(form, f) || last && d.affixed(form, f) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package spell
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	ssgi18n "github.com/spagu/ssg/internal/i18n"
+	"github.com/spagu/ssg/internal/models"
+)
+
+const testAff = `SET UTF-8
+TRY esianrtolcdugmphbyfvkwz
+
+PFX U Y 1
+PFX U   0     un         .
+
+SFX S Y 3
+SFX S   y     ies        [^aeiou]y
+SFX S   0     s          [aeiou]y
+SFX S   0     s          [^y]
+
+SFX D Y 2
+SFX D   0     d          e
+SFX D   0     ed         [^e]
+
+NEEDAFFIX N
+FORBIDDENWORD !
+COMPOUNDFLAG C
+`
+
+const testDic = `9
+city/S
+play/SDU
+kind/U
+Paris
+bake/D
+gather/ND
+colour/!
+Haus/C
+tür/C
+`
+
+func writeDict(t *testing.T, dir, name, aff, dic string) string {
+	t.Helper()
+	base := filepath.Join(dir, name)
+	if err := os.WriteFile(base+".aff", []byte(aff), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(base+".dic", []byte(dic), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return base
+}
+
+func TestCheck(t *testing.T) {
+	base := writeDict(t, t.TempDir(), "xx", testAff, testDic)
+	d, err := Load(base+".aff", base+".dic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for word, want := range map[string]bool{
+		"city": true, "cities": true, "citys": false,
+		"plays": true, "played": true, "unplayed": true, "unplay": true,
+		"unkind": true, "kinds": false,
+		"baked": true, "bakeed": false,
+		"gather": false, "gathered": true, // NEEDAFFIX: the stem alone is not a word
+		"colour": false, // FORBIDDENWORD
+		"Paris":  true, "PARIS": true, "paris": false,
+		"City": true, "CITIES": true,
+		"Haustür": true, "Haustürtür": true, "Hau": false,
+	} {
+		if got := d.Check(word); got != want {
+			t.Errorf("Check(%q) = %v, want %v", word, got, want)
+		}
+	}
+	d.Add("ssg")
+	if !d.Check("ssg") || !d.Check("Ssg") {
+		t.Error("an added word is accepted, and capitalised")
+	}
+}
+
+func TestLoadLegacyEncodingAndLongFlags(t *testing.T) {
+	aff := "SET ISO8859-2\nFLAG long\nSFX Aa Y 1\nSFX Aa 0 \xb1 .\n" // ą
+	dic := "1\nkot/Aa\n"
+	base := writeDict(t, t.TempDir(), "pl", aff, dic)
+	d, err := Load(base+".aff", base+".dic")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Check("kotą") || d.Check("kota") {
+		t.Errorf("ISO8859-2 suffix: kotą=%v kota=%v", d.Check("kotą"), d.Check("kota"))
+	}
+}
+
+func TestSetLooksUpAndAddsWords(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DICPATH", dir)
+	writeDict(t, dir, "en_GB", testAff, testDic)
+	data := filepath.Join(dir, "data")
+	if n, err := AppendWords(WordsFile(data, "en"), []string{"zine", "blog", "zine"}); err != nil || n != 2 {
+		t.Fatalf("AppendWords = %d, %v", n, err)
+	}
+	if n, _ := AppendWords(WordsFile(data, "en"), []string{"blog"}); n != 0 {
+		t.Errorf("a word already listed is not added again (%d)", n)
+	}
+	raw, _ := os.ReadFile(WordsFile(data, "en"))
+	if string(raw) != "blog\nzine\n" {
+		t.Errorf("word list = %q", raw)
+	}
+
+	set := NewSet(models.SpellingConfig{}, data, []ssgi18n.LanguageConfig{{Code: "en", Locale: "en-GB"}})
+	d, err := set.For("en")
+	if err != nil || d == nil {
+		t.Fatalf("For(en) = %v, %v", d, err)
+	}
+	if !d.Check("zine") || !d.Check("cities") {
+		t.Error("the dictionary and the project word list both apply")
+	}
+	if d, err := set.For("de"); d != nil || err != nil {
+		t.Errorf("no dictionary for de: %v, %v", d, err)
+	}
+	set = NewSet(models.SpellingConfig{Dictionaries: map[string]string{"de": filepath.Join(dir, "none.dic")}}, data, nil)
+	if _, err := set.For("de"); err == nil {
+		t.Error("a configured dictionary that is missing is an error")
+	}
+}
@@ -0,0 +1,177 @@
+package spell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ssgi18n "github.com/spagu/ssg/internal/i18n"
+	"github.com/spagu/ssg/internal/models"
+)
+
+// SearchDirs are where a language without a configured dictionary is looked
+// for, after DICPATH: the directories Hunspell, LibreOffice and Homebrew
+// install into.
+var SearchDirs = []string{
+	"/usr/share/hunspell",
+	"/usr/share/myspell",
+	"/usr/share/myspell/dicts",
+	"/usr/local/share/hunspell",
+	"/opt/homebrew/share/hunspell",
+	"/Library/Spelling",
+	"~/Library/Spelling",
+}
+
+// Set loads each language's dictionary once, with the project word lists
+// added: <data_dir>/spelling/words.txt for every language and
+// <data_dir>/spelling/<lang>.txt for one.
+type Set struct {
+	cfg     models.SpellingConfig
+	dataDir string
+	locales map[string]string
+	dicts   map[string]*Dictionary
+}
+
+// NewSet prepares dictionaries for the configured languages; their locales
+// ("pl_PL") name the installed dictionary to look for.
+func NewSet(cfg models.SpellingConfig, dataDir string, languages []ssgi18n.LanguageConfig) *Set {
+	if dataDir == "" {
+		dataDir = "data"
+	}
+	s := &Set{cfg: cfg, dataDir: dataDir, locales: map[string]string{}, dicts: map[string]*Dictionary{}}
+	for _, l := range languages {
+		if l.Locale != "" {
+			s.locales[l.Code] = l.Locale
+		}
+	}
+	return s
+}
+
+// For returns the dictionary for lang, or nil when none is configured or
+// installed: a language nobody has a dictionary for is not spell-checked. A
+// configured dictionary that cannot be read is an error.
+func (s *Set) For(lang string) (*Dictionary, error) {
+	if d, ok := s.dicts[lang]; ok {
+		return d, nil
+	}
+	base, err := s.locate(lang)
+	if err != nil || base == "" {
+		return nil, err
+	}
+	d, err := Load(base+".aff", base+".dic")
+	if err != nil {
+		return nil, fmt.Errorf("spelling dictionary for %s: %w", lang, err)
+	}
+	for _, path := range []string{filepath.Join(s.dataDir, "spelling", "words.txt"), WordsFile(s.dataDir, lang)} {
+		words, err := ReadWords(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, w := range words {
+			d.Add(w)
+		}
+	}
+	s.dicts[lang] = d
+	return d, nil
+}
+
+// locate finds lang's dictionary, as a path without extension.
+func (s *Set) locate(lang string) (string, error) {
+	short, _, _ := strings.Cut(lang, "-")
+	for _, key := range []string{lang, short} {
+		if path, ok := s.cfg.Dictionaries[key]; ok {
+			base := strings.TrimSuffix(strings.TrimSuffix(path, ".dic"), ".aff")
+			if _, err := os.Stat(base + ".dic"); err != nil {
+				return "", fmt.Errorf("spelling.dictionaries.%s: %w", key, err)
+			}
+			return base, nil
+		}
+	}
+	names := []string{lang, short}
+	if locale := s.locales[lang]; locale != "" {
+		names = append([]string{locale}, names...)
+	}
+	dirs := append(filepath.SplitList(os.Getenv("DICPATH")), SearchDirs...)
+	for _, dir := range dirs {
+		if strings.HasPrefix(dir, "~/") {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				continue
+			}
+			dir = filepath.Join(home, dir[2:])
+		}
+		for _, name := range names {
+			for _, variant := range []string{name, strings.ReplaceAll(name, "-", "_")} {
+				base := filepath.Join(dir, variant)
+				if _, err := os.Stat(base + ".dic"); err == nil {
+					return base, nil
+				}
+			}
+		}
+	}
+	return "", nil
+}
+
+// WordsFile is the project word list for one language, the file
+// `ssg lint --spelling --add-words` appends to.
+func WordsFile(dataDir, lang string) string {
+	if dataDir == "" {
+		dataDir = "data"
+	}
+	return filepath.Join(dataDir, "spelling", lang+".txt")
+}
+
+// ReadWords reads a word list: one word per line, # starts a comment. A
+// missing file is an empty list.
+func ReadWords(path string) ([]string, error) {
+	raw, err := os.ReadFile(path) // #nosec G304 -- the project's own data directory
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		if w := strings.TrimSpace(line); w != "" {
+			out = append(out, w)
+		}
+	}
+	return out, nil
+}
+
+// AppendWords adds words to the list at path, sorted, skipping those already
+// there, and returns how many were new.
+func AppendWords(path string, words []string) (int, error) {
+	have, err := ReadWords(path)
+	if err != nil {
+		return 0, err
+	}
+	seen := map[string]bool{}
+	for _, w := range have {
+		seen[w] = true
+	}
+	var add []string
+	for _, w := range words {
+		if !seen[w] {
+			seen[w] = true
+			add = append(add, w)
+		}
+	}
+	if len(add) == 0 {
+		return 0, nil
+	}
+	sort.Strings(add)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil { // #nosec G301 -- a project directory
+		return 0, err
+	}
+	raw, _ := os.ReadFile(path) // #nosec G304 -- as above
+	if len(raw) > 0 && raw[len(raw)-1] != '\n' {
+		raw = append(raw, '\n')
+	}
+	raw = append(raw, strings.Join(add, "\n")+"\n"...)
+	return len(add), os.WriteFile(path, raw, 0o644) // #nosec G306 -- a tracked project file, like the content beside it
+}
@@ -0,0 +1,198 @@
+package watch
+
+// inotify: one watch per directory, since inotify is not recursive. New
+// directories are watched as they appear, and the files already inside one by
+// the time its watch is in place are reported, because their creation events
+// were never delivered. A file root is watched through its parent directory,
+// filtered to its own name: editors save by writing a temporary file and
+// renaming it over the original, which would silently end a watch placed on
+// the file's inode.
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+const inotifyMask = unix.IN_CREATE | unix.IN_DELETE | unix.IN_MODIFY | unix.IN_CLOSE_WRITE |
+	unix.IN_MOVED_FROM | unix.IN_MOVED_TO | unix.IN_DELETE_SELF | unix.IN_MOVE_SELF
+
+// watchedDir is what an inotify watch descriptor stands for.
+type watchedDir struct {
+	path string
+	// recursive is set for directories under a directory root; a file root's
+	// parent reports only the names in files.
+	recursive bool
+	files     map[string]bool
+}
+
+type inotify struct {
+	f     *os.File
+	fd    int
+	roots []string
+
+	mu    sync.Mutex
+	byWd  map[int]*watchedDir
+	byDir map[string]int
+}
+
+// newNative sets up inotify on every existing root. It fails — and the caller
+// polls instead — when inotify is unavailable or a directory cannot be watched;
+// a site half-watched by events is worse than one fully watched by polling.
+func newNative(roots []string) (backend, error) {
+	fd, err := unix.InotifyInit1(unix.IN_CLOEXEC | unix.IN_NONBLOCK)
+	if err != nil {
+		return nil, err
+	}
+	// A non-blocking descriptor wrapped in an *os.File parks its reads on the
+	// runtime poller, so close() can interrupt the one in flight.
+	in := &inotify{
+		f:     os.NewFile(uintptr(fd), "inotify"),
+		fd:    fd,
+		roots: roots,
+		byWd:  map[int]*watchedDir{},
+		byDir: map[string]int{},
+	}
+	for _, root := range roots {
+		info, err := os.Stat(root)
+		switch {
+		case err != nil:
+			continue // not there yet; see New
+		case info.IsDir():
+			err = in.addTree(root, nil)
+		default:
+			err = in.addFile(root)
+		}
+		if err != nil {
+			_ = in.f.Close()
+			if errors.Is(err, unix.ENOSPC) {
+				return nil, errors.New("inotify watch limit reached (raise fs.inotify.max_user_watches)")
+			}
+			return nil, err
+		}
+	}
+	return in, nil
+}
+
+// add registers one directory. recursive upgrades an existing file-root watch;
+// files adds a name to watch in a non-recursive one.
+func (in *inotify) add(dir string, recursive bool, file string) error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	if wd, ok := in.byDir[dir]; ok {
+		w := in.byWd[wd]
+		w.recursive = w.recursive || recursive
+		if file != "" {
+			w.files[file] = true
+		}
+		return nil
+	}
+	wd, err := unix.InotifyAddWatch(in.fd, dir, inotifyMask)
+	if err != nil {
+		return err
+	}
+	w := &watchedDir{path: dir, recursive: recursive, files: map[string]bool{}}
+	if file != "" {
+		w.files[file] = true
+	}
+	in.byWd[wd] = w
+	in.byDir[dir] = wd
+	return nil
+}
+
+// addTree watches dir and everything under it. With found set, the files met
+// on the way are reported: a directory created or moved in arrives with its
+// contents already there.
+func (in *inotify) addTree(dir string, found func(string)) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil // vanished mid-walk; its deletion is reported separately
+		}
+		if d.IsDir() {
+			return in.add(path, true, "")
+		}
+		if found != nil {
+			found(path)
+		}
+		return nil
+	})
+}
+
+func (in *inotify) addFile(file string) error {
+	return in.add(filepath.Dir(file), false, filepath.Base(file))
+}
+
+func (in *inotify) run(out chan<- string, stop <-chan struct{}) {
+	send := func(path string) bool {
+		select {
+		case out <- path:
+			return true
+		case <-stop:
+			return false
+		}
+	}
+	buf := make([]byte, 64*(unix.SizeofInotifyEvent+unix.NAME_MAX+1))
+	for {
+		n, err := in.f.Read(buf)
+		if err != nil {
+			return // closed
+		}
+		for off := 0; off+unix.SizeofInotifyEvent <= n; {
+			wd := int(int32(binary.NativeEndian.Uint32(buf[off:])))
+			mask := binary.NativeEndian.Uint32(buf[off+4:])
+			nameLen := int(binary.NativeEndian.Uint32(buf[off+12:]))
+			nameStart := off + unix.SizeofInotifyEvent
+			name := string(trimNUL(buf[nameStart : nameStart+nameLen]))
+			off = nameStart + nameLen
+			for _, p := range in.handle(wd, mask, name) {
+				if !send(p) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// handle turns one event into the paths it changed.
+func (in *inotify) handle(wd int, mask uint32, name string) []string {
+	if mask&unix.IN_Q_OVERFLOW != 0 {
+		// Events were lost: every root may have changed.
+		return in.roots
+	}
+	in.mu.Lock()
+	w, ok := in.byWd[wd]
+	if ok && mask&unix.IN_IGNORED != 0 {
+		delete(in.byWd, wd)
+		delete(in.byDir, w.path)
+	}
+	in.mu.Unlock()
+	if !ok || name == "" {
+		return nil // the directory itself: its parent reports it by name
+	}
+	if !w.recursive && !w.files[name] {
+		return nil
+	}
+	path := filepath.Join(w.path, name)
+	changed := []string{path}
+	if w.recursive && mask&unix.IN_ISDIR != 0 && mask&(unix.IN_CREATE|unix.IN_MOVED_TO) != 0 {
+		// A directory arrived. Failing to watch it now (the limit) leaves that
+		// subtree unwatched; its files are still reported this once.
+		_ = in.addTree(path, func(p string) { changed = append(changed, p) })
+	}
+	return changed
+}
+
+func (in *inotify) close() { _ = in.f.Close() }
+
+func trimNUL(b []byte) []byte {
+	for i, c := range b {
+		if c == 0 {
+			return b[:i]
+		}
+	}
+	return b
+}
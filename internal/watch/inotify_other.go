@@ -0,0 +1,10 @@
+//go:build !linux
+
+package watch
+
+import "errors"
+
+// newNative has no event source outside Linux yet; New polls instead.
+func newNative([]string) (backend, error) {
+	return nil, errors.New("no native file events on this platform")
+}
@@ -0,0 +1,84 @@
+package watch
+
+// The poller: the watch loops' original strategy, kept as the fallback. It
+// walks every root on each tick and compares size and mtime with the previous
+// walk, which is enough to name the files that changed; content comparison is
+// left to the caller, as it was.
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+type stamp struct {
+	size    int64
+	modTime time.Time
+}
+
+type poller struct {
+	roots    []string
+	interval time.Duration
+	last     map[string]stamp
+}
+
+func newPoller(roots []string, interval time.Duration) *poller {
+	p := &poller{roots: roots, interval: interval}
+	p.last = p.snapshot()
+	return p
+}
+
+// snapshot stamps every file under the roots. Unreadable entries are skipped,
+// as the old walk skipped them.
+func (p *poller) snapshot() map[string]stamp {
+	snap := map[string]stamp{}
+	for _, root := range p.roots {
+		_ = filepath.Walk(root, func(path string, info os.FileInfo, err error) error { // #nosec G703 -- best-effort walk of the watched roots
+			if err != nil || info.IsDir() {
+				return nil
+			}
+			snap[path] = stamp{size: info.Size(), modTime: info.ModTime()}
+			return nil
+		})
+	}
+	return snap
+}
+
+// scan reports the files added, removed or restamped since the last scan.
+func (p *poller) scan() []string {
+	next := p.snapshot()
+	var changed []string
+	for path, st := range next {
+		if old, ok := p.last[path]; !ok || old.size != st.size || !old.modTime.Equal(st.modTime) {
+			changed = append(changed, path)
+		}
+	}
+	for path := range p.last {
+		if _, ok := next[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	p.last = next
+	return changed
+}
+
+func (p *poller) run(out chan<- string, stop <-chan struct{}) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		for _, path := range p.scan() {
+			select {
+			case out <- path:
+			case <-stop:
+				return
+			}
+		}
+	}
+}
+
+func (p *poller) close() {}
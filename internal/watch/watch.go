@@ -0,0 +1,172 @@
+// Package watch reports which files under a set of roots changed.
+//
+// The watch loops used to wake every second and walk every watched tree,
+// stat-ing and hashing their way to a yes/no: visible CPU on a large site, up
+// to a second of latency on every save, and no answer to the question an
+// incremental build actually asks — *what* changed. On Linux this package
+// listens to inotify instead and reports exact paths; elsewhere, or when
+// inotify cannot be used (the per-user watch limit, a filesystem that does not
+// deliver events, an explicit request), it falls back to the poller it
+// replaces, which reports paths too.
+//
+// Either way the paths come out debounced: an editor's save is a burst —
+// truncate, write, write, rename, chmod — and one batch per burst is what a
+// rebuild wants. Whether a reported file's bytes really changed is the
+// caller's business; a touch is reported like an edit.
+package watch
+
+import (
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Defaults for Options fields left zero.
+const (
+	DefaultInterval = 1 * time.Second
+	DefaultQuiet    = 100 * time.Millisecond
+	DefaultMaxWait  = 1 * time.Second
+)
+
+// Options tune a Watcher. The zero value is the normal configuration.
+type Options struct {
+	// Poll skips inotify and polls, for filesystems that do not deliver
+	// events (network mounts, some container bind mounts).
+	Poll bool
+	// Interval is the poller's period.
+	Interval time.Duration
+	// Quiet is how long a burst must be silent before it is delivered.
+	Quiet time.Duration
+	// MaxWait caps how long a burst that never goes quiet is held back.
+	MaxWait time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Interval <= 0 {
+		o.Interval = DefaultInterval
+	}
+	if o.Quiet <= 0 {
+		o.Quiet = DefaultQuiet
+	}
+	if o.MaxWait <= 0 {
+		o.MaxWait = DefaultMaxWait
+	}
+	return o
+}
+
+// backend produces raw change paths until stopped. A directory path means
+// "something under here changed and the backend cannot say what" — an inotify
+// queue overflow.
+type backend interface {
+	run(out chan<- string, stop <-chan struct{})
+	close()
+}
+
+// Watcher delivers batches of changed paths on Changes.
+type Watcher struct {
+	changes chan []string
+	stop    chan struct{}
+	done    chan struct{}
+	be      backend
+	name    string
+	reason  error
+	once    sync.Once
+}
+
+// New watches roots: directories recursively, and single files (a config
+// file) by themselves. Paths are reported as they were given — a relative
+// root yields relative paths. A root that does not exist is not an error; the
+// poller notices it appearing, inotify does not (see Backend).
+func New(roots []string, opts Options) *Watcher {
+	opts = opts.withDefaults()
+	clean := make([]string, 0, len(roots))
+	for _, r := range roots {
+		if r != "" {
+			clean = append(clean, filepath.Clean(r))
+		}
+	}
+	w := &Watcher{
+		changes: make(chan []string),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	if !opts.Poll {
+		be, err := newNative(clean)
+		if err == nil {
+			w.be, w.name = be, "inotify"
+		} else {
+			w.reason = err
+		}
+	}
+	if w.be == nil {
+		w.be, w.name = newPoller(clean, opts.Interval), "polling"
+	}
+	raw := make(chan string, 256)
+	go w.be.run(raw, w.stop)
+	go w.debounce(raw, opts)
+	return w
+}
+
+// Changes delivers one sorted, de-duplicated batch per burst of activity.
+// Changes that arrive while the receiver is busy are merged into the next
+// batch, never dropped, so an edit saved mid-build is picked up afterwards.
+func (w *Watcher) Changes() <-chan []string { return w.changes }
+
+// Backend names how changes are detected: "inotify" or "polling".
+func (w *Watcher) Backend() string { return w.name }
+
+// FallbackReason is why inotify is not in use, or nil when it is (or when
+// polling was asked for).
+func (w *Watcher) FallbackReason() error { return w.reason }
+
+// Close stops watching and releases the backend. Safe to call twice.
+func (w *Watcher) Close() {
+	w.once.Do(func() {
+		close(w.stop)
+		w.be.close()
+		<-w.done
+	})
+}
+
+// debounce folds raw paths into batches. A batch is sent once the stream has
+// been quiet for opts.Quiet, or opts.MaxWait after its first path, whichever
+// comes first; while the receiver is not ready the batch keeps growing.
+func (w *Watcher) debounce(raw <-chan string, opts Options) {
+	defer close(w.done)
+	pending := map[string]bool{}
+	var quiet, deadline <-chan time.Time
+	var out chan<- []string // nil until a batch is ready: that case never fires
+	var ready []string
+	for {
+		select {
+		case <-w.stop:
+			return
+		case p := <-raw:
+			pending[p] = true
+			quiet = time.After(opts.Quiet)
+			if deadline == nil {
+				deadline = time.After(opts.MaxWait)
+			}
+			continue
+		case <-quiet:
+		case <-deadline:
+		case out <- ready:
+			ready, out = nil, nil
+			continue
+		}
+		// A timer fired: hand the burst over, merged with anything the
+		// receiver has not taken yet.
+		quiet, deadline = nil, nil
+		for _, p := range ready {
+			pending[p] = true
+		}
+		ready = make([]string, 0, len(pending))
+		for p := range pending {
+			ready = append(ready, p)
+		}
+		sort.Strings(ready)
+		pending = map[string]bool{}
+		out = w.changes
+	}
+}
@@ -0,0 +1,173 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// fast keeps the tests quick without changing what they observe.
+var fast = Options{Interval: 20 * time.Millisecond, Quiet: 30 * time.Millisecond, MaxWait: 300 * time.Millisecond}
+
+func write(t *testing.T, path, body string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// next waits for one batch, failing the test after a generous timeout.
+func next(t *testing.T, w *Watcher) []string {
+	t.Helper()
+	select {
+	case batch := <-w.Changes():
+		return batch
+	case <-time.After(5 * time.Second):
+		t.Fatalf("no change reported (%s)", w.Backend())
+		return nil
+	}
+}
+
+// quiet asserts that nothing is reported for a while.
+func quiet(t *testing.T, w *Watcher) {
+	t.Helper()
+	select {
+	case batch := <-w.Changes():
+		t.Fatalf("unexpected batch %v", batch)
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// both runs a test against every backend this platform has.
+func both(t *testing.T, fn func(t *testing.T, opts Options)) {
+	t.Run("polling", func(t *testing.T) {
+		opts := fast
+		opts.Poll = true
+		fn(t, opts)
+	})
+	if runtime.GOOS == "linux" {
+		t.Run("inotify", func(t *testing.T) { fn(t, fast) })
+	}
+}
+
+func TestReportsExactPathsOncePerBurst(t *testing.T) {
+	both(t, func(t *testing.T, opts Options) {
+		root := t.TempDir()
+		write(t, filepath.Join(root, "a.md"), "a")
+		write(t, filepath.Join(root, "sub", "b.md"), "b")
+		w := New([]string{root}, opts)
+		defer w.Close()
+		if !opts.Poll && w.Backend() != "inotify" {
+			t.Fatalf("backend = %s (%v)", w.Backend(), w.FallbackReason())
+		}
+
+		// An editor's save burst, across two files.
+		for i := 0; i < 5; i++ {
+			write(t, filepath.Join(root, "sub", "b.md"), "b"+string(rune('0'+i)))
+		}
+		write(t, filepath.Join(root, "a.md"), "a2")
+		got := next(t, w)
+		want := []string{filepath.Join(root, "a.md"), filepath.Join(root, "sub", "b.md")}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("batch = %v, want %v", got, want)
+		}
+		quiet(t, w)
+	})
+}
+
+func TestNewDirectoriesAndDeletions(t *testing.T) {
+	both(t, func(t *testing.T, opts Options) {
+		root := t.TempDir()
+		write(t, filepath.Join(root, "old.md"), "x")
+		w := New([]string{root}, opts)
+		defer w.Close()
+
+		// Built elsewhere and moved in whole, as a checkout or an unpack does:
+		// no creation event is ever delivered for the file inside.
+		staging := t.TempDir()
+		write(t, filepath.Join(staging, "new", "deep", "c.md"), "c")
+		if err := os.Rename(filepath.Join(staging, "new"), filepath.Join(root, "new")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Remove(filepath.Join(root, "old.md")); err != nil {
+			t.Fatal(err)
+		}
+		seen := map[string]bool{}
+		for _, p := range next(t, w) {
+			seen[p] = true
+		}
+		for _, p := range []string{filepath.Join(root, "new", "deep", "c.md"), filepath.Join(root, "old.md")} {
+			if !seen[p] {
+				t.Errorf("%s not reported in %v", p, seen)
+			}
+		}
+
+		// The moved-in tree is watched from now on.
+		write(t, filepath.Join(root, "new", "deep", "c.md"), "c2")
+		if got := next(t, w); !reflect.DeepEqual(got, []string{filepath.Join(root, "new", "deep", "c.md")}) {
+			t.Errorf("edit in the new tree = %v", got)
+		}
+	})
+}
+
+// A file root (the config file) reports itself and nothing else in its
+// directory, and survives being replaced by a rename — how editors save.
+func TestFileRootSurvivesRenameSave(t *testing.T) {
+	both(t, func(t *testing.T, opts Options) {
+		dir := t.TempDir()
+		cfg := filepath.Join(dir, ".ssg.yaml")
+		write(t, cfg, "a: 1\n")
+		w := New([]string{cfg}, opts)
+		defer w.Close()
+
+		write(t, filepath.Join(dir, "unrelated.txt"), "x")
+		quiet(t, w)
+		for i := 0; i < 2; i++ {
+			tmp := filepath.Join(dir, ".ssg.yaml.swp")
+			write(t, tmp, "a: "+string(rune('2'+i))+"\n")
+			if err := os.Rename(tmp, cfg); err != nil {
+				t.Fatal(err)
+			}
+			if got := next(t, w); !reflect.DeepEqual(got, []string{cfg}) {
+				t.Errorf("save %d: batch = %v, want just the config file", i, got)
+			}
+		}
+	})
+}
+
+// Changes made while nobody is receiving are held, merged and delivered once
+// the receiver is back — an edit saved mid-build is not lost.
+func TestChangesWaitForABusyReceiver(t *testing.T) {
+	both(t, func(t *testing.T, opts Options) {
+		root := t.TempDir()
+		w := New([]string{root}, opts)
+		defer w.Close()
+
+		write(t, filepath.Join(root, "one.md"), "1")
+		time.Sleep(opts.MaxWait + 100*time.Millisecond) // first burst delivered-ready
+		write(t, filepath.Join(root, "two.md"), "2")
+		time.Sleep(opts.MaxWait + 100*time.Millisecond)
+
+		got := map[string]bool{}
+		for len(got) < 2 {
+			for _, p := range next(t, w) {
+				got[p] = true
+			}
+		}
+		if !got[filepath.Join(root, "one.md")] || !got[filepath.Join(root, "two.md")] {
+			t.Errorf("held changes = %v", got)
+		}
+	})
+}
+
+func TestCloseIsIdempotentAndMissingRootsAreFine(t *testing.T) {
+	w := New([]string{filepath.Join(t.TempDir(), "absent"), ""}, fast)
+	w.Close()
+	w.Close()
+}